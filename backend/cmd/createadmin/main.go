@@ -0,0 +1,124 @@
+// Command createadmin creates a back-office user with the admin role, for bootstrapping the first
+// admin of an environment. The password is read from ADMIN_PASSWORD so it stays out of the shell
+// history and the process list, and must satisfy the configured password policy. It exits with
+// status 1 if the user could not be created as asked, and 2 if it could not run.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"banking-platform/config"
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/internal/repo"
+	"banking-platform/internal/service"
+	"banking-platform/pkg/hash"
+	"banking-platform/pkg/logger"
+	"github.com/google/uuid"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	email := flag.String("email", "", "email address of the new admin (required)")
+	firstName := flag.String("first-name", "Admin", "first name")
+	lastName := flag.String("last-name", "User", "last name")
+	flag.Parse()
+
+	log := logger.NewJSON(os.Stderr, slog.LevelInfo)
+
+	address := strings.ToLower(strings.TrimSpace(*email))
+	if address == "" {
+		fmt.Fprintln(os.Stderr, "-email is required")
+		return 2
+	}
+	password := os.Getenv("ADMIN_PASSWORD")
+	if password == "" {
+		fmt.Fprintln(os.Stderr, "ADMIN_PASSWORD is not set")
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load config: %v\n", err)
+		return 2
+	}
+	var denylist []string
+	if cfg.PasswordDenylistFile != "" {
+		if denylist, err = service.LoadPasswordDenylist(cfg.PasswordDenylistFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 2
+		}
+	}
+	policy, err := service.NewPasswordPolicy(cfg.PasswordMinLength, cfg.PasswordRequiredClasses, denylist)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "password policy: %v\n", err)
+		return 2
+	}
+	if err := policy.Validate(password, address); err != nil {
+		fmt.Fprintf(os.Stderr, "ADMIN_PASSWORD: %v\n", err)
+		return 1
+	}
+	passwords, err := hash.NewPasswordHasher(cfg.PasswordHashAlgorithm, cfg.BcryptCost, hash.Argon2Params{
+		Memory:      uint32(cfg.Argon2MemoryKiB),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+		SaltLength:  hash.DefaultArgon2Params.SaltLength,
+		KeyLength:   hash.DefaultArgon2Params.KeyLength,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "password hasher: %v\n", err)
+		return 2
+	}
+	passwordHash, err := passwords.Hash(password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hash password: %v\n", err)
+		return 2
+	}
+
+	db, err := repo.NewDB(cfg.DatabaseURL())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open database: %v\n", err)
+		return 2
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	users := repo.NewUserRepository(db)
+	if _, err := users.GetByEmail(ctx, address); err == nil {
+		fmt.Fprintf(os.Stderr, "a user with email %s already exists\n", address)
+		return 1
+	} else if !errors.Is(err, apperr.ErrUserNotFound) {
+		fmt.Fprintf(os.Stderr, "get user: %v\n", err)
+		return 2
+	}
+
+	now := time.Now()
+	admin := &domain.User{
+		ID:           uuid.New(),
+		Email:        address,
+		PasswordHash: passwordHash,
+		FirstName:    *firstName,
+		LastName:     *lastName,
+		Role:         domain.RoleAdmin,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := users.Create(ctx, admin); err != nil {
+		fmt.Fprintf(os.Stderr, "create user: %v\n", err)
+		return 2
+	}
+
+	log.Info("Admin user created", "user_id", admin.ID, "email", admin.Email)
+	fmt.Printf("admin %s created with id %s\n", admin.Email, admin.ID)
+	return 0
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -18,18 +19,92 @@ type Config struct {
 	JWTSecret  string
 	Port       string
 
+	// JWTPrivateKeyFile switches access tokens from HS256 with JWTSecret to RS256 or EdDSA,
+	// depending on the key type. JWTVerifyKeyFiles are also accepted and published, for rotation.
+	JWTPrivateKeyFile string
+	JWTVerifyKeyFiles []string
+	// JWTAcceptHS256 keeps accepting HS256 tokens signed with JWTSecret after switching to a key
+	// pair, until the ones in flight have expired. They are verified but never issued.
+	JWTAcceptHS256 bool
+
 	ConsistencyCronEnabled  bool
 	ConsistencyCronInterval time.Duration
 	ConsistencyCronTimeout  time.Duration
 	CronStopTimeout         time.Duration
 
+	ScheduledTransfersEnabled   bool
+	ScheduledTransfersInterval  time.Duration
+	ScheduledTransfersBatchSize int
+	ScheduledTransfersAttempts  int
+	ScheduledTransfersRetry     time.Duration
+	ScheduledTransfersLease     time.Duration
+
+	// AuthorizationTTL is how long an authorized transfer holds funds before it expires.
+	AuthorizationTTL            time.Duration
+	AuthorizationExpiryInterval time.Duration
+
+	// PaymentRequestTTL is how long a payment request can be accepted.
+	PaymentRequestTTL time.Duration
+
 	ShutdownTimeout time.Duration
 
+	TokenRevocationSyncInterval time.Duration
+
+	MFAIssuer               string
+	MFAStepUpThresholdCents int64
+
+	// RiskRulesFile holds the rules transfers and exchanges are screened with; empty disables screening.
+	RiskRulesFile string
+
+	// AppBaseURL is the frontend origin that links in emails point to.
+	AppBaseURL string
+	MailDriver string
+	MailFrom   string
+	MailFile   string
+
+	PasswordMinLength       int
+	PasswordRequiredClasses []string
+	// PasswordDenylistFile lists common or breached passwords, one per line; empty disables the check.
+	PasswordDenylistFile  string
+	PasswordHashAlgorithm string
+	BcryptCost            int
+	Argon2MemoryKiB       int
+	Argon2Iterations      int
+	Argon2Parallelism     int
+
+	// RateLimitStore is memory (per replica) or postgres (shared by every replica).
+	RateLimitStore   string
 	RateLimitEnabled bool
 	RateLimitRPS     int
 	RateLimitBurst   int
 
+	// AuthRateLimitPerMinute limits each client IP on the public auth routes; 0 disables it.
+	AuthRateLimitPerMinute int
+	AuthRateLimitBurst     int
+	// UserRateLimitPerMinute limits each authenticated user across the API; 0 disables it.
+	UserRateLimitPerMinute int
+	UserRateLimitBurst     int
+	// MoneyRateLimitPerMinute limits each user on the routes that move money; 0 disables it.
+	MoneyRateLimitPerMinute int
+	MoneyRateLimitBurst     int
+
+	LoginFreeAttempts     int
+	LoginDelayBase        time.Duration
+	LoginDelayMax         time.Duration
+	LoginLockoutThreshold int
+	LoginLockoutDuration  time.Duration
+	LoginFailureWindow    time.Duration
+	LoginIPMaxFailures    int
+
 	ExchangeRateUSDtoEUR string
+
+	ExchangeRateProvider         string
+	ExchangeRateFile             string
+	ExchangeRateFilePollInterval time.Duration
+	ExchangeRatePivotCurrency    string
+
+	ExchangeSpreadBps int
+	ExchangeQuoteTTL  time.Duration
 }
 
 func Load() (*Config, error) {
@@ -44,20 +119,174 @@ func Load() (*Config, error) {
 		JWTSecret:  getEnv("JWT_SECRET", "bank"),
 		Port:       getEnv("PORT", "8080"),
 
+		JWTPrivateKeyFile: getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		JWTVerifyKeyFiles: getEnvList("JWT_VERIFY_KEY_FILES"),
+		JWTAcceptHS256:    getEnvBool("JWT_ACCEPT_HS256", false),
+
 		ConsistencyCronEnabled:  getEnvBool("CONSISTENCY_CRON_ENABLED", false),
 		ConsistencyCronInterval: getEnvDurationSeconds("CONSISTENCY_CRON_INTERVAL_SECONDS", 10),
 		ConsistencyCronTimeout:  getEnvDurationSeconds("CONSISTENCY_CRON_TIMEOUT_SECONDS", 3),
 		CronStopTimeout:         getEnvDurationSeconds("CRON_STOP_TIMEOUT_SECONDS", 1),
 		ShutdownTimeout:         getEnvDurationSeconds("SHUTDOWN_TIMEOUT_SECONDS", 3),
 
+		TokenRevocationSyncInterval: getEnvDurationSeconds("TOKEN_REVOCATION_SYNC_SECONDS", 5),
+
+		MFAIssuer:               getEnv("MFA_ISSUER", "Mini Banking Platform"),
+		MFAStepUpThresholdCents: int64(getEnvInt("MFA_STEP_UP_THRESHOLD_CENTS", 0)),
+
+		AppBaseURL: getEnv("APP_BASE_URL", "http://localhost:5173"),
+		MailDriver: getEnv("MAIL_DRIVER", "log"),
+		MailFrom:   getEnv("MAIL_FROM", "Mini Banking Platform <no-reply@localhost>"),
+		MailFile:   getEnv("MAIL_FILE", ""),
+
+		RiskRulesFile: getEnv("RISK_RULES_FILE", ""),
+
+		PasswordMinLength:       getEnvInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordRequiredClasses: getEnvList("PASSWORD_REQUIRED_CLASSES"),
+		PasswordDenylistFile:    getEnv("PASSWORD_DENYLIST_FILE", ""),
+		PasswordHashAlgorithm:   getEnv("PASSWORD_HASH_ALGORITHM", "bcrypt"),
+		BcryptCost:              getEnvInt("BCRYPT_COST", 12),
+		Argon2MemoryKiB:         getEnvInt("ARGON2_MEMORY_KIB", 64*1024),
+		Argon2Iterations:        getEnvInt("ARGON2_ITERATIONS", 3),
+		Argon2Parallelism:       getEnvInt("ARGON2_PARALLELISM", 4),
+
+		ScheduledTransfersEnabled:   getEnvBool("SCHEDULED_TRANSFERS_ENABLED", true),
+		ScheduledTransfersInterval:  getEnvDurationSeconds("SCHEDULED_TRANSFERS_INTERVAL_SECONDS", 10),
+		ScheduledTransfersBatchSize: getEnvInt("SCHEDULED_TRANSFERS_BATCH_SIZE", 50),
+		ScheduledTransfersAttempts:  getEnvInt("SCHEDULED_TRANSFERS_MAX_ATTEMPTS", 3),
+		ScheduledTransfersRetry:     getEnvDurationSeconds("SCHEDULED_TRANSFERS_RETRY_SECONDS", 300),
+		ScheduledTransfersLease:     getEnvDurationSeconds("SCHEDULED_TRANSFERS_LEASE_SECONDS", 120),
+
+		AuthorizationTTL:            getEnvDurationSeconds("AUTHORIZATION_TTL_SECONDS", 7*24*60*60),
+		AuthorizationExpiryInterval: getEnvDurationSeconds("AUTHORIZATION_EXPIRY_INTERVAL_SECONDS", 60),
+
+		PaymentRequestTTL: getEnvDurationSeconds("PAYMENT_REQUEST_TTL_SECONDS", 7*24*60*60),
+
+		RateLimitStore:   getEnv("RATE_LIMIT_STORE", "memory"),
 		RateLimitEnabled: getEnvBool("RATE_LIMIT_ENABLED", false),
 		RateLimitRPS:     getEnvInt("RATE_LIMIT_RPS", 10),
 		RateLimitBurst:   getEnvInt("RATE_LIMIT_BURST", 20),
 
+		AuthRateLimitPerMinute:  getEnvInt("AUTH_RATE_LIMIT_PER_MINUTE", 30),
+		AuthRateLimitBurst:      getEnvInt("AUTH_RATE_LIMIT_BURST", 10),
+		UserRateLimitPerMinute:  getEnvInt("USER_RATE_LIMIT_PER_MINUTE", 600),
+		UserRateLimitBurst:      getEnvInt("USER_RATE_LIMIT_BURST", 100),
+		MoneyRateLimitPerMinute: getEnvInt("MONEY_RATE_LIMIT_PER_MINUTE", 20),
+		MoneyRateLimitBurst:     getEnvInt("MONEY_RATE_LIMIT_BURST", 5),
+
+		LoginFreeAttempts:     getEnvInt("LOGIN_FREE_ATTEMPTS", 3),
+		LoginDelayBase:        getEnvDurationSeconds("LOGIN_DELAY_BASE_SECONDS", 1),
+		LoginDelayMax:         getEnvDurationSeconds("LOGIN_DELAY_MAX_SECONDS", 60),
+		LoginLockoutThreshold: getEnvInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+		LoginLockoutDuration:  getEnvDurationSeconds("LOGIN_LOCKOUT_SECONDS", 900),
+		LoginFailureWindow:    getEnvDurationSeconds("LOGIN_FAILURE_WINDOW_SECONDS", 3600),
+		LoginIPMaxFailures:    getEnvInt("LOGIN_IP_MAX_FAILURES", 100),
+
 		ExchangeRateUSDtoEUR: getEnv("EXCHANGE_RATE_USD_TO_EUR", "0.92"),
+
+		ExchangeRateProvider:         getEnv("EXCHANGE_RATE_PROVIDER", "static"),
+		ExchangeRateFile:             getEnv("EXCHANGE_RATE_FILE", ""),
+		ExchangeRateFilePollInterval: getEnvDurationSeconds("EXCHANGE_RATE_FILE_POLL_SECONDS", 60),
+		ExchangeRatePivotCurrency:    getEnv("EXCHANGE_RATE_PIVOT_CURRENCY", "EUR"),
+
+		ExchangeSpreadBps: getEnvInt("EXCHANGE_SPREAD_BPS", 0),
+		ExchangeQuoteTTL:  getEnvDurationSeconds("EXCHANGE_QUOTE_TTL_SECONDS", 30),
+	}
+
+	if config.ExchangeSpreadBps < 0 || config.ExchangeSpreadBps >= 10_000 {
+		return nil, fmt.Errorf("EXCHANGE_SPREAD_BPS must be in [0, 10000)")
+	}
+
+	if config.ScheduledTransfersBatchSize < 1 {
+		return nil, fmt.Errorf("SCHEDULED_TRANSFERS_BATCH_SIZE must be at least 1")
+	}
+	if config.ScheduledTransfersAttempts < 1 {
+		return nil, fmt.Errorf("SCHEDULED_TRANSFERS_MAX_ATTEMPTS must be at least 1")
+	}
+	if config.ScheduledTransfersLease <= 0 {
+		return nil, fmt.Errorf("SCHEDULED_TRANSFERS_LEASE_SECONDS must be positive")
+	}
+
+	if config.AuthorizationTTL <= 0 {
+		return nil, fmt.Errorf("AUTHORIZATION_TTL_SECONDS must be positive")
+	}
+	if config.AuthorizationExpiryInterval <= 0 {
+		return nil, fmt.Errorf("AUTHORIZATION_EXPIRY_INTERVAL_SECONDS must be positive")
+	}
+	if config.PaymentRequestTTL <= 0 {
+		return nil, fmt.Errorf("PAYMENT_REQUEST_TTL_SECONDS must be positive")
+	}
+
+	if config.TokenRevocationSyncInterval <= 0 {
+		return nil, fmt.Errorf("TOKEN_REVOCATION_SYNC_SECONDS must be positive")
+	}
+	if config.MFAStepUpThresholdCents < 0 {
+		return nil, fmt.Errorf("MFA_STEP_UP_THRESHOLD_CENTS must not be negative")
+	}
+
+	switch config.RateLimitStore {
+	case "memory", "postgres":
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE %q (expected memory or postgres)", config.RateLimitStore)
+	}
+	if config.AuthRateLimitPerMinute < 0 || config.AuthRateLimitBurst < 1 {
+		return nil, fmt.Errorf("AUTH_RATE_LIMIT_PER_MINUTE must not be negative and AUTH_RATE_LIMIT_BURST must be at least 1")
+	}
+	if config.UserRateLimitPerMinute < 0 || config.UserRateLimitBurst < 1 {
+		return nil, fmt.Errorf("USER_RATE_LIMIT_PER_MINUTE must not be negative and USER_RATE_LIMIT_BURST must be at least 1")
+	}
+	if config.MoneyRateLimitPerMinute < 0 || config.MoneyRateLimitBurst < 1 {
+		return nil, fmt.Errorf("MONEY_RATE_LIMIT_PER_MINUTE must not be negative and MONEY_RATE_LIMIT_BURST must be at least 1")
+	}
+	if config.LoginFreeAttempts < 0 || config.LoginLockoutThreshold <= config.LoginFreeAttempts {
+		return nil, fmt.Errorf("LOGIN_LOCKOUT_THRESHOLD must be greater than LOGIN_FREE_ATTEMPTS")
+	}
+	if config.LoginFailureWindow <= config.LoginLockoutDuration {
+		return nil, fmt.Errorf("LOGIN_FAILURE_WINDOW_SECONDS must be longer than LOGIN_LOCKOUT_SECONDS")
+	}
+	if config.LoginIPMaxFailures < config.LoginLockoutThreshold {
+		return nil, fmt.Errorf("LOGIN_IP_MAX_FAILURES must be at least LOGIN_LOCKOUT_THRESHOLD")
+	}
+
+	switch config.MailDriver {
+	case "log":
+	case "file":
+		if config.MailFile == "" {
+			return nil, fmt.Errorf("MAIL_FILE is required when MAIL_DRIVER=file")
+		}
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q (expected log or file)", config.MailDriver)
 	}
 
-	if config.JWTSecret == "bank" {
+	switch config.PasswordHashAlgorithm {
+	case "bcrypt", "argon2id":
+	default:
+		return nil, fmt.Errorf("unknown PASSWORD_HASH_ALGORITHM %q (expected bcrypt or argon2id)", config.PasswordHashAlgorithm)
+	}
+	if config.Argon2MemoryKiB < 8*config.Argon2Parallelism || config.Argon2Iterations < 1 || config.Argon2Parallelism < 1 || config.Argon2Parallelism > 255 {
+		return nil, fmt.Errorf("ARGON2_MEMORY_KIB must be at least 8 per lane, ARGON2_ITERATIONS at least 1 and ARGON2_PARALLELISM between 1 and 255")
+	}
+
+	switch config.ExchangeRateProvider {
+	case "static", "db":
+	case "file":
+		if config.ExchangeRateFile == "" {
+			return nil, fmt.Errorf("EXCHANGE_RATE_FILE is required when EXCHANGE_RATE_PROVIDER=file")
+		}
+	default:
+		return nil, fmt.Errorf("unknown EXCHANGE_RATE_PROVIDER %q (expected static, db or file)", config.ExchangeRateProvider)
+	}
+
+	if len(config.JWTVerifyKeyFiles) > 0 && config.JWTPrivateKeyFile == "" {
+		return nil, fmt.Errorf("JWT_VERIFY_KEY_FILES requires JWT_PRIVATE_KEY_FILE")
+	}
+	if config.JWTAcceptHS256 && config.JWTPrivateKeyFile == "" {
+		return nil, fmt.Errorf("JWT_ACCEPT_HS256 requires JWT_PRIVATE_KEY_FILE")
+	}
+	if config.JWTAcceptHS256 && config.JWTSecret == "bank" {
+		return nil, fmt.Errorf("JWT_ACCEPT_HS256 cannot be used with the default JWT_SECRET")
+	}
+	if config.JWTPrivateKeyFile == "" && config.JWTSecret == "bank" {
 		fmt.Println("WARNING: Using default JWT_SECRET")
 	}
 
@@ -100,6 +329,17 @@ func getEnvDurationSeconds(key string, defaultSeconds int) time.Duration {
 	return time.Duration(sec) * time.Second
 }
 
+// getEnvList splits a comma-separated value, dropping empty entries.
+func getEnvList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	raw := os.Getenv(key)
 	if raw == "" {
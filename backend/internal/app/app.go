@@ -2,12 +2,14 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"time"
 
 	"banking-platform/config"
 	"banking-platform/internal/cron"
+	"banking-platform/internal/domain"
 	"banking-platform/internal/jwt"
 	"banking-platform/internal/repo"
 	"banking-platform/internal/server"
@@ -17,9 +19,14 @@ import (
 )
 
 type App struct {
-	cfg    *config.Config
-	server *server.Server
-	cron   *cron.ConsistencyCron
+	cfg          *config.Config
+	server       *server.Server
+	cron         *cron.ConsistencyCron
+	scheduler    *cron.ScheduledTransferWorker
+	expiry       *cron.AuthorizationExpiryWorker
+	rateFilePoll *service.FileRateProvider
+	revocations  *service.TokenRevocationStore
+	rateLimiter  *service.RateLimiter
 }
 
 func NewApp() (*App, error) {
@@ -36,19 +43,114 @@ func NewApp() (*App, error) {
 		return nil, err
 	}
 
+	currencyRepo := repo.NewCurrencyRepository(db)
+	currencyList, err := currencyRepo.List(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("load currencies: %w", err)
+	}
+	currencies := domain.NewCurrencyRegistry(currencyList)
+	logger.Info("Currencies loaded", "count", len(currencyList), "enabled", len(currencies.Enabled()))
+
 	userRepo := repo.NewUserRepository(db)
-	accountRepo := repo.NewAccountRepository(db)
-	transactionRepo := repo.NewTransactionRepository(db)
-	ledgerRepo := repo.NewLedgerRepository(db)
+	accountRepo := repo.NewAccountRepository(db, currencies)
+	transactionRepo := repo.NewTransactionRepository(db, currencies)
+	ledgerRepo := repo.NewLedgerRepository(db, currencies)
 	refreshTokenRepo := repo.NewRefreshTokenRepository(db)
+	tokenRevocationRepo := repo.NewTokenRevocationRepository(db)
+	idempotencyRepo := repo.NewIdempotencyRepository(db)
+	exchangeRateRepo := repo.NewExchangeRateRepository(db)
+	exchangeQuoteRepo := repo.NewExchangeQuoteRepository(db, currencies)
+	scheduledTransferRepo := repo.NewScheduledTransferRepository(db, currencies)
+	paymentRequestRepo := repo.NewPaymentRequestRepository(db, currencies)
+	auditEventRepo := repo.NewAuditEventRepository(db)
+	mfaRepo := repo.NewMFARepository(db)
+	mfaChallengeRepo := repo.NewMFAChallengeRepository(db)
+	accountTokenRepo := repo.NewAccountTokenRepository(db)
+	loginThrottleRepo := repo.NewLoginThrottleRepository(db)
+	transferLimitRepo := repo.NewTransferLimitRepository(db, currencies)
+	riskSignalRepo := repo.NewRiskSignalRepository(db)
+	riskReviewRepo := repo.NewRiskReviewRepository(db, currencies)
 
 	ledgerConsistencyService := service.NewLedgerConsistencyService(ledgerRepo, logger)
+	auditLog := service.NewAuditLog(auditEventRepo, db, logger)
 
 	accessTokenTTL := 15 * time.Minute
 	refreshTokenTTL := 7 * 24 * time.Hour
-	tokenManager := jwt.NewTokenManager(cfg.JWTSecret, accessTokenTTL, refreshTokenTTL)
+	revocations := service.NewTokenRevocationStore(tokenRevocationRepo, accessTokenTTL, cfg.TokenRevocationSyncInterval, logger)
+	if err := revocations.Load(context.Background()); err != nil {
+		return nil, err
+	}
+	revocations.Start()
+	keys := jwt.NewHMACKeySet(cfg.JWTSecret)
+	if cfg.JWTPrivateKeyFile != "" {
+		var extra []*jwt.Key
+		if cfg.JWTAcceptHS256 {
+			extra = append(extra, jwt.NewHMACVerifyKey(cfg.JWTSecret))
+		}
+		keys, err = jwt.LoadKeySet(cfg.JWTPrivateKeyFile, cfg.JWTVerifyKeyFiles, extra...)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt keys: %w", err)
+		}
+	}
+	logger.Info("Access token signing configured", "alg", keys.SigningKey().Algorithm(), "kid", keys.SigningKey().ID)
+	tokenManager := jwt.NewTokenManager(keys, accessTokenTTL, refreshTokenTTL, revocations)
 
 	hasher := hash.NewHasher()
+	passwords, err := hash.NewPasswordHasher(cfg.PasswordHashAlgorithm, cfg.BcryptCost, hash.Argon2Params{
+		Memory:      uint32(cfg.Argon2MemoryKiB),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+		SaltLength:  hash.DefaultArgon2Params.SaltLength,
+		KeyLength:   hash.DefaultArgon2Params.KeyLength,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("password hasher: %w", err)
+	}
+	var denylist []string
+	if cfg.PasswordDenylistFile != "" {
+		if denylist, err = service.LoadPasswordDenylist(cfg.PasswordDenylistFile); err != nil {
+			return nil, err
+		}
+	}
+	passwordPolicy, err := service.NewPasswordPolicy(cfg.PasswordMinLength, cfg.PasswordRequiredClasses, denylist)
+	if err != nil {
+		return nil, fmt.Errorf("password policy: %w", err)
+	}
+	logger.Info("Password hashing configured", "algorithm", cfg.PasswordHashAlgorithm, "denylist_entries", len(denylist))
+	mfaService := service.NewMFAService(mfaRepo, mfaChallengeRepo, userRepo, db, hasher, cfg.MFAIssuer, auditLog, logger)
+
+	var mailer service.Mailer
+	switch cfg.MailDriver {
+	case service.MailDriverFile:
+		mailer = service.NewFileMailer(cfg.MailFrom, cfg.MailFile)
+	default:
+		mailer = service.NewLogMailer(cfg.MailFrom, logger)
+	}
+	logger.Info("Mailer configured", "driver", cfg.MailDriver)
+	credentialService := service.NewCredentialService(
+		userRepo,
+		accountTokenRepo,
+		refreshTokenRepo,
+		revocations,
+		db,
+		mailer,
+		hasher,
+		passwords,
+		passwordPolicy,
+		cfg.AppBaseURL,
+		auditLog,
+		logger,
+	)
+
+	loginThrottle := service.NewLoginThrottle(loginThrottleRepo, db, service.LoginThrottlePolicy{
+		FreeAttempts:     cfg.LoginFreeAttempts,
+		BaseDelay:        cfg.LoginDelayBase,
+		MaxDelay:         cfg.LoginDelayMax,
+		LockoutThreshold: cfg.LoginLockoutThreshold,
+		LockoutDuration:  cfg.LoginLockoutDuration,
+		Window:           cfg.LoginFailureWindow,
+		IPMaxFailures:    cfg.LoginIPMaxFailures,
+	}, logger)
 
 	authService := service.NewAuthService(
 		userRepo,
@@ -57,35 +159,153 @@ func NewApp() (*App, error) {
 		transactionRepo,
 		ledgerRepo,
 		refreshTokenRepo,
+		revocations,
+		mfaService,
+		credentialService,
+		loginThrottle,
 		tokenManager,
 		hasher,
+		passwords,
+		passwordPolicy,
+		currencies,
+		auditLog,
 		logger,
 	)
-	accountService := service.NewAccountService(accountRepo, logger)
+	accountService := service.NewAccountService(accountRepo, ledgerRepo, db, currencies, logger)
+
+	var rateProvider service.RateProvider
+	var rateFilePoll *service.FileRateProvider
+	switch cfg.ExchangeRateProvider {
+	case "db":
+		rateProvider = service.NewDBRateProvider(exchangeRateRepo)
+	case "file":
+		rateFilePoll, err = service.NewFileRateProvider(cfg.ExchangeRateFile, cfg.ExchangeRateFilePollInterval, logger)
+		if err != nil {
+			return nil, err
+		}
+		rateFilePoll.Start()
+		rateProvider = rateFilePoll
+	default:
+		rateProvider = service.NewStaticRateProvider(cfg.ExchangeRateUSDtoEUR)
+	}
+	rateProvider = service.NewCrossRateProvider(rateProvider, domain.Currency(cfg.ExchangeRatePivotCurrency))
+	logger.Info("Exchange rate provider configured", "provider", cfg.ExchangeRateProvider, "pivot_currency", cfg.ExchangeRatePivotCurrency)
+
+	transferLimiter := service.NewTransferLimiter(transferLimitRepo, db, currencies)
+
+	var riskEngine service.RiskEngine
+	if cfg.RiskRulesFile != "" {
+		rules, err := service.LoadRiskRules(cfg.RiskRulesFile)
+		if err != nil {
+			return nil, err
+		}
+		engine, err := service.NewRuleRiskEngine(rules, riskSignalRepo, userRepo)
+		if err != nil {
+			return nil, fmt.Errorf("risk rules: %w", err)
+		}
+		riskEngine = engine
+		logger.Info("Risk screening configured", "rules", len(rules))
+	}
+
 	transactionService := service.NewTransactionService(
 		db,
 		accountRepo,
 		transactionRepo,
 		ledgerRepo,
 		userRepo,
-		cfg.ExchangeRateUSDtoEUR,
+		idempotencyRepo,
+		exchangeQuoteRepo,
+		rateProvider,
+		currencies,
+		int64(cfg.ExchangeSpreadBps),
+		cfg.ExchangeQuoteTTL,
+		cfg.AuthorizationTTL,
+		mfaService,
+		cfg.MFAStepUpThresholdCents,
+		transferLimiter,
+		riskEngine,
+		riskReviewRepo,
+		auditLog,
 		logger,
 	)
+	if err := transactionService.CheckExchangeLiquidity(context.Background()); err != nil {
+		return nil, err
+	}
+
+	scheduledTransferService := service.NewScheduledTransferService(
+		scheduledTransferRepo,
+		accountRepo,
+		userRepo,
+		transactionService,
+		db,
+		currencies,
+		cfg.ScheduledTransfersAttempts,
+		cfg.ScheduledTransfersRetry,
+		cfg.ScheduledTransfersLease,
+		mfaService,
+		cfg.MFAStepUpThresholdCents,
+		logger,
+	)
+	paymentRequestService := service.NewPaymentRequestService(
+		paymentRequestRepo,
+		accountRepo,
+		userRepo,
+		transactionService,
+		currencies,
+		cfg.PaymentRequestTTL,
+		logger,
+	)
+	riskReviewQueue := service.NewRiskReviewQueue(riskReviewRepo, transactionService, paymentRequestService, logger)
+
+	adminService := service.NewAdminService(
+		userRepo,
+		accountRepo,
+		db,
+		transactionService,
+		ledgerConsistencyService,
+		loginThrottle,
+		transferLimiter,
+		riskReviewQueue,
+		auditLog,
+		logger,
+	)
+
+	var rateLimitStore service.RateLimitStore = service.NewMemoryRateLimitStore()
+	if cfg.RateLimitStore == service.RateLimitStorePostgres {
+		rateLimitStore = repo.NewRateLimitRepository(db)
+	}
+	rateLimiter := service.NewRateLimiter(rateLimitStore, time.Minute, logger)
+	rateLimiter.Start()
+	logger.Info("Rate limiter configured", "store", cfg.RateLimitStore)
 
 	cronJob := cron.StartConsistencyCron(cfg, logger, ledgerConsistencyService)
+	scheduler := cron.StartScheduledTransferWorker(cfg, logger, scheduledTransferService)
+	expiry := cron.StartAuthorizationExpiryWorker(cfg, logger, transactionService)
 
 	srv := server.NewServer(
 		cfg,
 		db,
+		keys,
+		rateLimiter,
 		authService,
+		mfaService,
+		credentialService,
 		accountService,
 		transactionService,
+		scheduledTransferService,
+		paymentRequestService,
+		adminService,
 	)
 
 	return &App{
-		cfg:    cfg,
-		server: srv,
-		cron:   cronJob,
+		cfg:          cfg,
+		server:       srv,
+		cron:         cronJob,
+		scheduler:    scheduler,
+		expiry:       expiry,
+		rateFilePoll: rateFilePoll,
+		revocations:  revocations,
+		rateLimiter:  rateLimiter,
 	}, nil
 }
 
@@ -99,6 +319,31 @@ func (a *App) Close() error {
 		a.cron.Stop(ctx)
 		cancel()
 	}
+	if a.scheduler != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.CronStopTimeout)
+		a.scheduler.Stop(ctx)
+		cancel()
+	}
+	if a.expiry != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.CronStopTimeout)
+		a.expiry.Stop(ctx)
+		cancel()
+	}
+	if a.rateFilePoll != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.CronStopTimeout)
+		a.rateFilePoll.Stop(ctx)
+		cancel()
+	}
+	if a.revocations != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.CronStopTimeout)
+		a.revocations.Stop(ctx)
+		cancel()
+	}
+	if a.rateLimiter != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.CronStopTimeout)
+		a.rateLimiter.Stop(ctx)
+		cancel()
+	}
 	return a.server.Close()
 }
 
@@ -106,6 +351,21 @@ func (a *App) Shutdown(ctx context.Context) error {
 	if a.cron != nil {
 		a.cron.Stop(ctx)
 	}
+	if a.scheduler != nil {
+		a.scheduler.Stop(ctx)
+	}
+	if a.expiry != nil {
+		a.expiry.Stop(ctx)
+	}
+	if a.rateFilePoll != nil {
+		a.rateFilePoll.Stop(ctx)
+	}
+	if a.revocations != nil {
+		a.revocations.Stop(ctx)
+	}
+	if a.rateLimiter != nil {
+		a.rateLimiter.Stop(ctx)
+	}
 	shutdownErr := a.server.Shutdown(ctx)
 	closeErr := a.server.Close()
 	if shutdownErr != nil {
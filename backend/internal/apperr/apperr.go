@@ -1,6 +1,9 @@
 package apperr
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrUserNotFound         = errors.New("user not found")
@@ -16,6 +19,54 @@ var (
 	ErrCurrenciesMustDiffer = errors.New("from and to currencies must be different")
 	ErrCannotTransferToSelf = errors.New("cannot transfer to self")
 	ErrLiquidityUnavailable = errors.New("exchange liquidity unavailable")
+	ErrRateUnavailable      = errors.New("exchange rate unavailable")
+
+	ErrIdempotencyKeyConflict = errors.New("idempotency key already used with a different request")
+
+	ErrQuoteNotFound    = errors.New("exchange quote not found")
+	ErrQuoteExpired     = errors.New("exchange quote has expired")
+	ErrQuoteAlreadyUsed = errors.New("exchange quote has already been used")
+
+	ErrAccountFrozen         = errors.New("account is frozen")
+	ErrAccountClosed         = errors.New("account is closed")
+	ErrAccountBalanceNotZero = errors.New("account balance must be zero to close it")
+
+	ErrTransactionNotReversible   = errors.New("only posted transfers can be reversed")
+	ErrTransactionAlreadyReversed = errors.New("transaction has already been fully reversed")
+	ErrReversalExceedsRemaining   = errors.New("reversal amount exceeds the amount not yet reversed")
+	ErrBankTransferNotReversible  = errors.New("transfers from the bank can only be reversed by an operator")
+
+	ErrScheduledTransferNotFound       = errors.New("scheduled transfer not found")
+	ErrScheduledTransferNotCancellable = errors.New("scheduled transfer is no longer active")
+
+	ErrSessionNotFound = errors.New("session not found")
+
+	ErrMFAAlreadyEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled         = errors.New("two-factor authentication is not enabled")
+	ErrInvalidMFACode        = errors.New("invalid two-factor code")
+	ErrInvalidMFAChallenge   = errors.New("invalid or expired two-factor challenge")
+	ErrMFACodeRequired       = errors.New("two-factor code required for this transfer")
+	ErrMFAEnrollmentRequired = errors.New("enable two-factor authentication to make this transfer")
+
+	ErrInvalidAccountToken  = errors.New("invalid or expired link")
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	ErrIncorrectPassword    = errors.New("current password is incorrect")
+	ErrPasswordUnchanged    = errors.New("new password must differ from the current one")
+	ErrWeakPassword         = errors.New("password does not meet the password policy")
+
+	ErrLoginThrottled = errors.New("too many failed login attempts; try again later")
+
+	ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+	ErrTransactionDeclined  = errors.New("transaction declined")
+	ErrHeldForReview        = errors.New("transaction held for review")
+	ErrRiskReviewNotFound   = errors.New("risk review not found")
+	ErrRiskReviewNotPending = errors.New("risk review has already been decided")
+
+	ErrAuthorizationNotPending = errors.New("authorization is no longer pending")
+
+	ErrPaymentRequestNotFound   = errors.New("payment request not found")
+	ErrPaymentRequestNotPending = errors.New("payment request is no longer pending")
 )
 
 // PublicError is a client-facing error with an associated HTTP status code.
@@ -32,6 +83,38 @@ func BadRequest(message string) error {
 	return &PublicError{Status: 400, Message: message}
 }
 
+// ThrottledError is ErrLoginThrottled with the time the caller has to wait before trying again.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string { return ErrLoginThrottled.Error() }
+func (e *ThrottledError) Unwrap() error { return ErrLoginThrottled }
+
+// TransferLimitError is ErrTransferLimitExceeded with the limit that was hit. Limit is "single",
+// "daily", "monthly" or "hourly_count"; Max is in minor units, or a number of transfers for
+// hourly_count. ResetsAt is nil for the single-transfer limit, which never resets.
+type TransferLimitError struct {
+	Limit    string
+	Currency string
+	Max      int64
+	ResetsAt *time.Time
+}
+
+func (e *TransferLimitError) Error() string {
+	return ErrTransferLimitExceeded.Error() + ": " + e.Limit + " " + e.Currency
+}
+func (e *TransferLimitError) Unwrap() error { return ErrTransferLimitExceeded }
+
+// HeldForReviewError is ErrHeldForReview with the review the movement is waiting in. It is not a
+// failure: the movement is booked if an operator approves it.
+type HeldForReviewError struct {
+	ReviewID string
+}
+
+func (e *HeldForReviewError) Error() string { return ErrHeldForReview.Error() + ": " + e.ReviewID }
+func (e *HeldForReviewError) Unwrap() error { return ErrHeldForReview }
+
 // RootCause unwraps err until it cannot be unwrapped any further.
 func RootCause(err error) error {
 	if err == nil {
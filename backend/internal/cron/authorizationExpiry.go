@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"banking-platform/config"
+	"banking-platform/internal/service"
+)
+
+// AuthorizationExpiryWorker voids pending authorizations once their hold has expired.
+// Running it on several API instances at once is harmless; voiding is a single conditional update.
+type AuthorizationExpiryWorker struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartAuthorizationExpiryWorker starts the worker.
+func StartAuthorizationExpiryWorker(cfg *config.Config, logger *slog.Logger, transactions *service.TransactionService) *AuthorizationExpiryWorker {
+	interval := cfg.AuthorizationExpiryInterval
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	runOnce := func() {
+		runCtx, runCancel := context.WithTimeout(ctx, interval)
+		_, err := transactions.ExpireAuthorizations(runCtx, time.Now().UTC())
+		runCancel()
+		if err != nil {
+			logger.Error("Authorization expiry run failed", "error", err)
+		}
+	}
+
+	go func() {
+		defer close(done)
+		logger.Info("Authorization expiry worker started", "interval", interval.String())
+		runOnce()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				runOnce()
+			case <-ctx.Done():
+				logger.Info("Authorization expiry worker stopped")
+				return
+			}
+		}
+	}()
+
+	return &AuthorizationExpiryWorker{cancel: cancel, done: done}
+}
+
+// Stop signals the worker to stop and waits until it finishes (or ctx is done).
+func (w *AuthorizationExpiryWorker) Stop(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if w.cancel != nil {
+		w.cancel()
+	}
+	if w.done == nil {
+		return
+	}
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,87 @@
+package cron
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"banking-platform/config"
+	"banking-platform/internal/service"
+)
+
+// ScheduledTransferWorker executes due scheduled transfers in background.
+// Several API instances may run it at once; claims are coordinated in the database.
+type ScheduledTransferWorker struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartScheduledTransferWorker starts the worker if enabled in config.
+func StartScheduledTransferWorker(cfg *config.Config, logger *slog.Logger, scheduler *service.ScheduledTransferService) *ScheduledTransferWorker {
+	if !cfg.ScheduledTransfersEnabled {
+		logger.Info("Scheduled transfer worker disabled")
+		return nil
+	}
+
+	interval := cfg.ScheduledTransfersInterval
+	batchSize := cfg.ScheduledTransfersBatchSize
+	// A batch must finish before its leases expire, otherwise another instance could claim the same rows.
+	timeout := cfg.ScheduledTransfersLease
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	runOnce := func() {
+		for {
+			runCtx, runCancel := context.WithTimeout(ctx, timeout)
+			n, err := scheduler.RunDue(runCtx, time.Now().UTC(), batchSize)
+			runCancel()
+			if err != nil {
+				logger.Error("Scheduled transfer run failed", "error", err)
+				return
+			}
+			// A full batch means more work may be due; keep draining until a partial batch.
+			if n < batchSize || ctx.Err() != nil {
+				return
+			}
+		}
+	}
+
+	go func() {
+		defer close(done)
+		logger.Info("Scheduled transfer worker started", "interval", interval.String(), "batch_size", batchSize)
+		runOnce()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				runOnce()
+			case <-ctx.Done():
+				logger.Info("Scheduled transfer worker stopped")
+				return
+			}
+		}
+	}()
+
+	return &ScheduledTransferWorker{cancel: cancel, done: done}
+}
+
+// Stop signals the worker to stop and waits until it finishes (or ctx is done).
+func (w *ScheduledTransferWorker) Stop(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	if w.cancel != nil {
+		w.cancel()
+	}
+	if w.done == nil {
+		return
+	}
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+	}
+}
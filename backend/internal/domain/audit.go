@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// AuditTimestamp normalizes t to what a TIMESTAMP column round-trips: UTC, microsecond precision.
+func AuditTimestamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// ComputeHash returns the chain hash of e on top of prevHash. Every stored column except Seq and
+// Hash is covered, so editing any field or removing a row breaks the chain from that point on.
+func (e *AuditEvent) ComputeHash(prevHash string) string {
+	actorID := ""
+	if e.ActorID != nil {
+		actorID = e.ActorID.String()
+	}
+	// A JSON array of strings is an unambiguous encoding of the fields.
+	payload, _ := json.Marshal([]string{
+		prevHash,
+		e.ID.String(),
+		AuditTimestamp(e.CreatedAt).Format(time.RFC3339Nano),
+		actorID,
+		string(e.ActorRole),
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		e.RequestID,
+		e.IP,
+		string(e.Before),
+		string(e.After),
+		string(e.Metadata),
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Seal links e to the previous event of the chain.
+func (e *AuditEvent) Seal(prevHash string) {
+	e.CreatedAt = AuditTimestamp(e.CreatedAt)
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash(prevHash)
+}
+
+// AuditChainBreak describes the first event whose links do not verify.
+type AuditChainBreak struct {
+	Seq    int64
+	ID     string
+	Reason string
+}
+
+// AuditVerification is the outcome of walking the audit chain. Break is nil when the chain is intact.
+type AuditVerification struct {
+	Checked int64
+	LastSeq int64
+	Break   *AuditChainBreak
+}
+
+// VerifyAuditChain checks events in Seq order, starting after an event with hash prevHash ("" for the
+// first event). It returns the hash of the last event and the first break, if any.
+func VerifyAuditChain(prevHash string, events []*AuditEvent) (string, *AuditChainBreak) {
+	for _, e := range events {
+		if e.PrevHash != prevHash {
+			return prevHash, &AuditChainBreak{Seq: e.Seq, ID: e.ID.String(), Reason: "prev_hash does not match the preceding event"}
+		}
+		if e.ComputeHash(prevHash) != e.Hash {
+			return prevHash, &AuditChainBreak{Seq: e.Seq, ID: e.ID.String(), Reason: "hash does not match the event contents"}
+		}
+		prevHash = e.Hash
+	}
+	return prevHash, nil
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func sealedChain(n int) []*AuditEvent {
+	prev := ""
+	events := make([]*AuditEvent, n)
+	for i := range events {
+		actor := uuid.New()
+		e := &AuditEvent{
+			ID:         uuid.New(),
+			Seq:        int64(i + 1),
+			ActorID:    &actor,
+			ActorRole:  RoleCustomer,
+			Action:     "transaction.transfer",
+			TargetType: "transaction",
+			TargetID:   uuid.NewString(),
+			RequestID:  "req-1",
+			IP:         "10.0.0.1",
+			After:      json.RawMessage(`{"amount_cents":100}`),
+			CreatedAt:  time.Date(2026, 1, 1, 12, 0, i, 123456789, time.UTC),
+		}
+		e.Seal(prev)
+		prev = e.Hash
+		events[i] = e
+	}
+	return events
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(events []*AuditEvent) []*AuditEvent
+		wantSeq int64
+	}{
+		{name: "intact", tamper: func(e []*AuditEvent) []*AuditEvent { return e }},
+		{name: "edited_field", tamper: func(e []*AuditEvent) []*AuditEvent {
+			e[1].After = json.RawMessage(`{"amount_cents":1}`)
+			return e
+		}, wantSeq: 2},
+		{name: "edited_actor", tamper: func(e []*AuditEvent) []*AuditEvent {
+			e[2].ActorID = nil
+			return e
+		}, wantSeq: 3},
+		{name: "deleted_row", tamper: func(e []*AuditEvent) []*AuditEvent {
+			return append(e[:1], e[2:]...)
+		}, wantSeq: 3},
+		{name: "rehashed_row", tamper: func(e []*AuditEvent) []*AuditEvent {
+			e[0].Action = "auth.login.succeeded"
+			e[0].Seal("")
+			return e
+		}, wantSeq: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := tt.tamper(sealedChain(4))
+			_, brk := VerifyAuditChain("", events)
+			if tt.wantSeq == 0 {
+				if brk != nil {
+					t.Fatalf("unexpected break at seq=%d: %s", brk.Seq, brk.Reason)
+				}
+				return
+			}
+			if brk == nil {
+				t.Fatalf("got no break want seq=%d", tt.wantSeq)
+			}
+			if brk.Seq != tt.wantSeq {
+				t.Fatalf("got=%d want=%d", brk.Seq, tt.wantSeq)
+			}
+		})
+	}
+}
+
+func TestAuditHashSurvivesTimestampRoundTrip(t *testing.T) {
+	e := sealedChain(1)[0]
+	// A TIMESTAMP column returns microseconds in a zero-offset location.
+	e.CreatedAt = e.CreatedAt.In(time.FixedZone("", 0))
+	if got := e.ComputeHash(""); got != e.Hash {
+		t.Fatalf("got=%s want=%s", got, e.Hash)
+	}
+}
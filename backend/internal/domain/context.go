@@ -0,0 +1,35 @@
+package domain
+
+import "context"
+
+// RequestMeta describes the HTTP request a service call originates from. It is attached to the
+// context by the request ID middleware and copied into audit events.
+type RequestMeta struct {
+	RequestID string
+	IP        string
+	UserAgent string
+}
+
+type requestMetaContextKey struct{}
+
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaContextKey{}, meta)
+}
+
+// RequestMetaFrom returns the request metadata in ctx, or the zero value for background work.
+func RequestMetaFrom(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaContextKey{}).(RequestMeta)
+	return meta
+}
+
+type principalContextKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, p)
+}
+
+// PrincipalFrom returns the authenticated caller stored in ctx, or nil.
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return p
+}
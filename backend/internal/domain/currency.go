@@ -0,0 +1,69 @@
+package domain
+
+import "sort"
+
+// DefaultMinorUnits is the exponent assumed for currencies missing from the registry.
+const DefaultMinorUnits = 2
+
+// CurrencyInfo describes an ISO 4217 currency known to the platform.
+type CurrencyInfo struct {
+	Code       Currency
+	Name       string
+	MinorUnits int
+	Enabled    bool
+}
+
+// CurrencyRegistry is the read-only set of currencies loaded from the currencies table at startup.
+type CurrencyRegistry struct {
+	byCode map[Currency]CurrencyInfo
+}
+
+func NewCurrencyRegistry(currencies []CurrencyInfo) *CurrencyRegistry {
+	byCode := make(map[Currency]CurrencyInfo, len(currencies))
+	for _, c := range currencies {
+		byCode[c.Code] = c
+	}
+	return &CurrencyRegistry{byCode: byCode}
+}
+
+// Get returns the currency with the given code, enabled or not.
+func (r *CurrencyRegistry) Get(code Currency) (CurrencyInfo, bool) {
+	c, ok := r.byCode[code]
+	return c, ok
+}
+
+// IsEnabled reports whether new accounts and money movements are allowed in the currency.
+func (r *CurrencyRegistry) IsEnabled(code Currency) bool {
+	c, ok := r.byCode[code]
+	return ok && c.Enabled
+}
+
+// Enabled lists enabled currencies ordered by code.
+func (r *CurrencyRegistry) Enabled() []CurrencyInfo {
+	out := make([]CurrencyInfo, 0, len(r.byCode))
+	for _, c := range r.byCode {
+		if c.Enabled {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Code < out[j].Code })
+	return out
+}
+
+// MinorUnits returns the currency exponent (2 for USD, 0 for JPY).
+func (r *CurrencyRegistry) MinorUnits(code Currency) int {
+	if c, ok := r.byCode[code]; ok {
+		return c.MinorUnits
+	}
+	return DefaultMinorUnits
+}
+
+// Format renders an amount in minor units as a decimal string in major units.
+func (r *CurrencyRegistry) Format(code Currency, amount int64) string {
+	return FormatMinorUnits(amount, r.MinorUnits(code))
+}
+
+// Parse reads a decimal string in major units into minor units.
+func (r *CurrencyRegistry) Parse(code Currency, s string) (int64, error) {
+	return ParseMinorUnits(s, r.MinorUnits(code))
+}
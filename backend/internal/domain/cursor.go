@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionCursor is the keyset position (created_at, id) of the last transaction on a page.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Encode renders the cursor as an opaque URL-safe token.
+func (c TransactionCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTransactionCursor parses a token produced by TransactionCursor.Encode.
+func DecodeTransactionCursor(s string) (*TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &TransactionCursor{CreatedAt: createdAt, ID: parsed}, nil
+}
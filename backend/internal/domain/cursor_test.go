@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTransactionCursorRoundTrip(t *testing.T) {
+	in := TransactionCursor{
+		CreatedAt: time.Date(2024, 3, 1, 12, 30, 45, 123456000, time.UTC),
+		ID:        uuid.MustParse("2f1c2d1e-6e1f-4a4b-9d55-0b7f0f9e7a11"),
+	}
+
+	got, err := DecodeTransactionCursor(in.Encode())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !got.CreatedAt.Equal(in.CreatedAt) || got.ID != in.ID {
+		t.Fatalf("got=%+v want=%+v", got, in)
+	}
+}
+
+func TestDecodeTransactionCursorRejectsGarbage(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+	}{
+		{name: "not_base64", in: "%%%"},
+		{name: "no_separator", in: "bm9wZQ"},
+		{name: "bad_time", in: "eHx4"},
+		{name: "empty", in: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DecodeTransactionCursor(tc.in); err == nil {
+				t.Fatalf("expected error for %q", tc.in)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,17 +13,31 @@ type User struct {
 	PasswordHash string
 	FirstName    string
 	LastName     string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	Role         Role
+	// EmailVerifiedAt is nil until the user follows a verification link.
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 type Account struct {
 	ID           uuid.UUID
 	UserID       uuid.UUID
 	Currency     Currency
+	Name         string
+	Status       AccountStatus
+	IsDefault    bool
 	BalanceCents int64
+	HeldCents    int64
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+	ClosedAt     *time.Time
+}
+
+// AvailableCents is what can still be spent. BalanceCents is the ledger balance; HeldCents is the
+// sum of unexpired pending authorizations from the account, which have no ledger entries yet.
+func (a *Account) AvailableCents() int64 {
+	return a.BalanceCents - a.HeldCents
 }
 
 type Transaction struct {
@@ -34,14 +49,36 @@ type Transaction struct {
 	Currency             Currency
 	ExchangeRate         *float64
 	ConvertedAmountCents *int64
-	Description          string
-	CreatedAt            time.Time
+	ToCurrency           Currency
+	RateSource           *string
+	RateVersion          *string
+	QuoteID              *uuid.UUID
+	// ReversesTransactionID links a reversal to the transaction it undoes.
+	ReversesTransactionID *uuid.UUID
+	Description           string
+	Status                TransactionStatus
+	// ExpiresAt is when a pending authorization is voided if not captured.
+	ExpiresAt *time.Time
+	// CapturedAmountCents is what a captured authorization posted; AmountCents stays the authorized amount.
+	CapturedAmountCents *int64
+
+	CreatedAt time.Time
+}
+
+// PostedAmountCents is the amount that moved between the accounts: the captured amount of a captured
+// authorization, AmountCents otherwise.
+func (t *Transaction) PostedAmountCents() int64 {
+	if t.CapturedAmountCents != nil {
+		return *t.CapturedAmountCents
+	}
+	return t.AmountCents
 }
 
 type LedgerEntry struct {
 	ID            uuid.UUID
 	TransactionID uuid.UUID
 	AccountID     uuid.UUID
+	Currency      Currency
 	AmountCents   int64
 	CreatedAt     time.Time
 }
@@ -54,3 +91,89 @@ type AccountBalanceMismatch struct {
 	LedgerSumCents int64
 	DiffCents      int64
 }
+
+// IdempotencyKey binds a client-supplied key to the transaction it produced.
+type IdempotencyKey struct {
+	UserID        uuid.UUID
+	Scope         string
+	Key           string
+	RequestHash   string
+	TransactionID *uuid.UUID
+	CreatedAt     time.Time
+}
+
+// ExchangeQuote is a locked exchange price a user may execute once before it expires.
+type ExchangeQuote struct {
+	ID                   uuid.UUID
+	UserID               uuid.UUID
+	FromCurrency         Currency
+	ToCurrency           Currency
+	AmountCents          int64
+	ConvertedAmountCents int64
+	RateNum              int64
+	RateDen              int64
+	SpreadBps            int64
+	RateSource           string
+	RateVersion          string
+	ExpiresAt            time.Time
+	UsedAt               *time.Time
+	TransactionID        *uuid.UUID
+	CreatedAt            time.Time
+}
+
+// ScheduledTransfer is a standing order executed by the scheduler through the regular transfer path.
+type ScheduledTransfer struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	FromAccountID *uuid.UUID
+	ToUserID      *uuid.UUID
+	ToAccountID   *uuid.UUID
+	Currency      Currency
+	AmountCents   int64
+	Description   string
+	Schedule      Schedule
+	Status        ScheduledTransferStatus
+
+	// NextRunAt is the occurrence being executed; NextAttemptAt is when to try it (later after failures).
+	NextRunAt         time.Time
+	NextAttemptAt     time.Time
+	Attempts          int
+	LastError         *string
+	LastTransactionID *uuid.UUID
+	LockedUntil       *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ScheduledTransferRun records one execution attempt of a scheduled transfer.
+type ScheduledTransferRun struct {
+	ID                  uuid.UUID
+	ScheduledTransferID uuid.UUID
+	OccurrenceAt        time.Time
+	Attempt             int
+	Status              ScheduledTransferRunStatus
+	TransactionID       *uuid.UUID
+	Error               *string
+	CreatedAt           time.Time
+}
+
+// AuditEvent is one row of the append-only audit log. Before, After and Metadata hold JSON exactly as
+// stored, since the hash chain covers their bytes. Seq, PrevHash and Hash are set when the event is appended.
+type AuditEvent struct {
+	ID         uuid.UUID
+	Seq        int64
+	ActorID    *uuid.UUID
+	ActorRole  Role
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	IP         string
+	Before     json.RawMessage
+	After      json.RawMessage
+	Metadata   json.RawMessage
+	CreatedAt  time.Time
+	PrevHash   string
+	Hash       string
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "testing"
+
+func TestAccountAvailableCents(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance int64
+		held    int64
+		want    int64
+	}{
+		{name: "no_holds", balance: 100_00, want: 100_00},
+		{name: "partly_held", balance: 100_00, held: 30_00, want: 70_00},
+		{name: "fully_held", balance: 100_00, held: 100_00, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Account{BalanceCents: tt.balance, HeldCents: tt.held}
+			if got := a.AvailableCents(); got != tt.want {
+				t.Fatalf("got=%d want=%d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionPostedAmountCents(t *testing.T) {
+	captured := int64(40_00)
+	tests := []struct {
+		name string
+		tx   Transaction
+		want int64
+	}{
+		{name: "transfer", tx: Transaction{AmountCents: 100_00}, want: 100_00},
+		{name: "partly_captured", tx: Transaction{AmountCents: 100_00, CapturedAmountCents: &captured}, want: 40_00},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tx.PostedAmountCents(); got != tt.want {
+				t.Fatalf("got=%d want=%d", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,21 +2,51 @@ package domain
 
 import "github.com/google/uuid"
 
+// ClientInfo describes the device a session is opened or refreshed from.
+type ClientInfo struct {
+	UserAgent   string
+	IP          string
+	DeviceLabel string
+}
+
 // RegisterInput is the input for user registration.
 type RegisterInput struct {
 	Email     string
 	Password  string
 	FirstName string
 	LastName  string
+	Client    ClientInfo
 }
 
 // LoginInput is the input for user login.
 type LoginInput struct {
 	Email    string
 	Password string
+	Client   ClientInfo
+}
+
+// LoginMFAInput completes a login that returned an MFA challenge. Exactly one of Code and
+// RecoveryCode is expected.
+type LoginMFAInput struct {
+	ChallengeToken string
+	Code           string
+	RecoveryCode   string
+	Client         ClientInfo
 }
 
 // LogoutInput is the input for logout.
+// ChangePasswordInput is the input for a signed-in password change.
+type ChangePasswordInput struct {
+	CurrentPassword string
+	NewPassword     string
+}
+
+// ResetPasswordInput sets a new password with the token of a reset email.
+type ResetPasswordInput struct {
+	Token       string
+	NewPassword string
+}
+
 type LogoutInput struct {
 	AccessToken  string
 	RefreshToken string
@@ -28,6 +58,30 @@ type TransferInput struct {
 	ToUserEmail *string
 	Currency    Currency
 	AmountCents int64
+
+	// FromAccountID and ToAccountID pick specific accounts instead of the default one for Currency.
+	FromAccountID *uuid.UUID
+	ToAccountID   *uuid.UUID
+
+	IdempotencyKey string
+
+	// MFACode is the TOTP code required for transfers above the step-up threshold.
+	MFACode string
+	// Scheduled marks transfers run by the standing order worker; step-up ran when the order was
+	// created, so no code is asked for.
+	Scheduled bool
+	// RiskReviewID is set when an operator approved a held transfer. Screening and step-up ran when
+	// it was held and are skipped.
+	RiskReviewID *uuid.UUID
+	// Authorize holds the amount on the sender's account instead of moving it. The transfer stays
+	// pending until the recipient captures it, either party releases it, or it expires.
+	Authorize bool
+}
+
+// OpenAccountInput is the input for opening an additional account.
+type OpenAccountInput struct {
+	Currency Currency
+	Name     string
 }
 
 // ExchangeInput is the input for currency exchange.
@@ -35,4 +89,62 @@ type ExchangeInput struct {
 	FromCurrency Currency
 	ToCurrency   Currency
 	AmountCents  int64
+
+	IdempotencyKey string
+
+	// RiskReviewID is set when an operator approved a held exchange; it is not screened again.
+	RiskReviewID *uuid.UUID
+	// QuoteID is set on the request of a held quote execution; approving it executes that quote.
+	QuoteID *uuid.UUID
+}
+
+// ExecuteQuoteInput is the input for executing a previously issued exchange quote.
+type ExecuteQuoteInput struct {
+	QuoteID uuid.UUID
+
+	IdempotencyKey string
+
+	// RiskReviewID is set when an operator approved a held execution; it is not screened again, and
+	// the quote is executed even if it expired while under review.
+	RiskReviewID *uuid.UUID
+}
+
+// ReverseInput is the input for reversing (refunding) a transfer, fully or partially.
+type ReverseInput struct {
+	TransactionID uuid.UUID
+	// AmountCents is the amount to refund; nil refunds everything not yet reversed.
+	AmountCents *int64
+	Reason      string
+	// AsOperator lets back-office staff reverse any transfer; otherwise only the recipient may.
+	AsOperator bool
+
+	IdempotencyKey string
+}
+
+// CaptureInput is the input for capturing a pending authorization, fully or partially.
+type CaptureInput struct {
+	TransactionID uuid.UUID
+	// AmountCents is the amount to post; nil captures the whole authorized amount. The rest is released.
+	AmountCents *int64
+}
+
+// CreateScheduledTransferInput is the input for creating a standing order. Exactly one recipient is required.
+type CreateScheduledTransferInput struct {
+	FromAccountID *uuid.UUID
+	ToUserID      *uuid.UUID
+	ToUserEmail   *string
+	ToAccountID   *uuid.UUID
+	Currency      Currency
+	AmountCents   int64
+	Description   string
+	Schedule      Schedule
+
+	// MFACode is the TOTP code required for orders above the step-up threshold.
+	MFACode string
+}
+
+// SetTransferLimitsInput puts a user on a tier and replaces all of the user's limit overrides.
+type SetTransferLimitsInput struct {
+	Tier      string
+	Overrides []*TransferLimits
 }
@@ -8,16 +8,32 @@ import (
 
 // Format cents as "12.34".
 func CentsToDecimalString(cents int64) string {
+	return FormatMinorUnits(cents, 2)
+}
+
+// Parse a decimal string into cents (strict: max 2 decimals).
+func DecimalStringToCents(s string) (int64, error) {
+	return ParseMinorUnits(s, 2)
+}
+
+// FormatMinorUnits formats an amount in minor units for a currency with the given exponent,
+// e.g. (1234, 2) -> "12.34" and (1234, 0) -> "1234".
+func FormatMinorUnits(amount int64, minorUnits int) string {
 	sign := ""
-	if cents < 0 {
+	if amount < 0 {
 		sign = "-"
-		cents = -cents
+		amount = -amount
+	}
+	if minorUnits <= 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
 	}
-	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+	scale := pow10(minorUnits)
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, minorUnits, amount%scale)
 }
 
-// Parse a decimal string into cents (strict: max 2 decimals).
-func DecimalStringToCents(s string) (int64, error) {
+// ParseMinorUnits parses a decimal string into minor units of a currency with the given exponent.
+// Trailing zeros past the exponent are accepted ("100.00" is 100 for a 0-decimal currency), other digits are not.
+func ParseMinorUnits(s string, minorUnits int) (int64, error) {
 	raw := strings.TrimSpace(s)
 	if raw == "" {
 		return 0, fmt.Errorf("empty amount")
@@ -60,27 +76,32 @@ func DecimalStringToCents(s string) (int64, error) {
 		if strings.HasPrefix(fp, "+") || strings.HasPrefix(fp, "-") {
 			return 0, fmt.Errorf("invalid amount")
 		}
-		if len(fp) > 2 {
-			return 0, fmt.Errorf("amount has more than 2 decimals")
+		if len(fp) > minorUnits {
+			if strings.Trim(fp[minorUnits:], "0") != "" {
+				return 0, fmt.Errorf("amount has more than %d decimals", minorUnits)
+			}
+			fp = fp[:minorUnits]
 		}
-		if fp == "" {
-			fp = "0"
+		fp += strings.Repeat("0", minorUnits-len(fp))
+		if fp != "" {
+			frac, err = strconv.ParseInt(fp, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid amount")
+			}
 		}
-		if len(fp) == 1 {
-			fp = fp + "0"
-		}
-		frac, err = strconv.ParseInt(fp, 10, 64)
-		if err != nil {
-			return 0, fmt.Errorf("invalid amount")
-		}
-	} else {
-		frac = 0
 	}
 
 	if whole < 0 {
 		return 0, fmt.Errorf("invalid amount")
 	}
 
-	return sign * (whole*100 + frac), nil
+	return sign * (whole*pow10(minorUnits) + frac), nil
 }
 
+func pow10(n int) int64 {
+	out := int64(1)
+	for i := 0; i < n; i++ {
+		out *= 10
+	}
+	return out
+}
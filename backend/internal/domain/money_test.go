@@ -68,3 +68,56 @@ func TestDecimalStringToCents(t *testing.T) {
 	}
 }
 
+func TestFormatMinorUnits(t *testing.T) {
+	testCases := []struct {
+		name       string
+		in         int64
+		minorUnits int
+		want       string
+	}{
+		{name: "two_decimals", in: 1025, minorUnits: 2, want: "10.25"},
+		{name: "zero_decimals", in: 1500, minorUnits: 0, want: "1500"},
+		{name: "one_decimal", in: 15, minorUnits: 1, want: "1.5"},
+		{name: "negative_zero_decimals", in: -7, minorUnits: 0, want: "-7"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FormatMinorUnits(tc.in, tc.minorUnits); got != tc.want {
+				t.Fatalf("got=%q want=%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseMinorUnits(t *testing.T) {
+	testCases := []struct {
+		name       string
+		in         string
+		minorUnits int
+		want       int64
+		wantErr    bool
+	}{
+		{name: "zero_decimals_integer", in: "1500", minorUnits: 0, want: 1500},
+		{name: "zero_decimals_db_scale", in: "1500.00", minorUnits: 0, want: 1500},
+		{name: "zero_decimals_reject_fraction", in: "1500.50", minorUnits: 0, wantErr: true},
+		{name: "two_decimals_padded", in: "10.250", minorUnits: 2, want: 1025},
+		{name: "two_decimals_reject_third", in: "10.251", minorUnits: 2, wantErr: true},
+		{name: "one_decimal", in: "1.5", minorUnits: 1, want: 15},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseMinorUnits(tc.in, tc.minorUnits)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got != tc.want {
+				t.Fatalf("got=%d want=%d", got, tc.want)
+			}
+		})
+	}
+}
@@ -8,12 +8,51 @@ import (
 
 // UserInfo is a safe user representation without password hash.
 type UserInfo struct {
-	ID        uuid.UUID
-	Email     string
-	FirstName string
-	LastName  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            uuid.UUID
+	Email         string
+	FirstName     string
+	LastName      string
+	Role          Role
+	EmailVerified bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Principal is the authenticated caller as carried by the access token. SessionID is uuid.Nil for
+// tokens issued before sessions were tracked.
+type Principal struct {
+	UserID    uuid.UUID
+	Role      Role
+	SessionID uuid.UUID
+}
+
+// Session is a signed-in device: one refresh token family. LastUsedAt is the last login or refresh.
+type Session struct {
+	ID          uuid.UUID
+	DeviceLabel string
+	UserAgent   string
+	IP          string
+	CreatedAt   time.Time
+	LastUsedAt  time.Time
+	ExpiresAt   time.Time
+	Current     bool
+}
+
+// LoginLockout is the failed-login state of an email address. UserID is nil when no user has the
+// address; LockedUntil is nil unless logins are currently refused.
+type LoginLockout struct {
+	Email        string
+	UserID       *uuid.UUID
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
+
+// ConsistencyReport lists ledger problems found by an on-demand consistency check.
+type ConsistencyReport struct {
+	UnbalancedTransactionIDs []uuid.UUID
+	BalanceMismatches        []*AccountBalanceMismatch
+	CheckedAt                time.Time
 }
 
 // TokenPair holds access/refresh tokens.
@@ -22,25 +61,109 @@ type TokenPair struct {
 	RefreshToken string
 }
 
-// AuthResult is returned by register/login.
+// AuthResult is returned by register/login. When MFAChallenge is set the password was accepted but
+// a second factor is still required and Tokens and User are empty.
 type AuthResult struct {
-	Tokens TokenPair
-	User   UserInfo
+	Tokens       TokenPair
+	User         UserInfo
+	MFAChallenge *MFAChallenge
+}
+
+// MFAChallenge is exchanged for a token pair together with a TOTP or recovery code.
+type MFAChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// MFAEnrollment is a pending TOTP secret; it becomes active once a code generated from it is confirmed.
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// MFAStatus describes the caller's second factor.
+type MFAStatus struct {
+	Enabled                bool
+	EnabledAt              *time.Time
+	RecoveryCodesRemaining int
 }
 
 // TransactionInfo is a transaction representation used for API responses.
 type TransactionInfo struct {
-	ID                   uuid.UUID
-	Type                 TransactionType
-	FromAccountID        *uuid.UUID
-	ToAccountID          uuid.UUID
-	AmountCents          int64
-	Currency             Currency
-	ExchangeRate         *float64
-	ConvertedAmountCents *int64
-	Description          string
-	CreatedAt            time.Time
-	FromUserEmail        *string
-	ToUserEmail          *string
+	ID                    uuid.UUID
+	Type                  TransactionType
+	FromAccountID         *uuid.UUID
+	ToAccountID           uuid.UUID
+	AmountCents           int64
+	Currency              Currency
+	ExchangeRate          *float64
+	ConvertedAmountCents  *int64
+	ToCurrency            Currency
+	RateSource            *string
+	RateVersion           *string
+	QuoteID               *uuid.UUID
+	ReversesTransactionID *uuid.UUID
+	Description           string
+	Status                TransactionStatus
+	ExpiresAt             *time.Time
+	CapturedAmountCents   *int64
+	CreatedAt             time.Time
+	FromUserEmail         *string
+	ToUserEmail           *string
+}
+
+// AccountBalance is an account's ledger balance and the part of it that can be spent.
+type AccountBalance struct {
+	AccountID      uuid.UUID
+	Currency       Currency
+	LedgerCents    int64
+	HeldCents      int64
+	AvailableCents int64
+}
+
+// Statement is an account statement for a period. Balances are derived from ledger sums.
+type Statement struct {
+	AccountID           uuid.UUID
+	AccountName         string
+	Currency            Currency
+	MinorUnits          int
+	From                time.Time
+	To                  time.Time
+	OpeningBalanceCents int64
+	ClosingBalanceCents int64
+	Lines               []*StatementLine
+	GeneratedAt         time.Time
 }
 
+// StatementLine is a single ledger entry of an account with the balance after it was booked.
+type StatementLine struct {
+	EntryID       uuid.UUID
+	TransactionID uuid.UUID
+	Type          TransactionType
+	Description   string
+	AmountCents   int64
+	BalanceCents  int64
+	BookedAt      time.Time
+}
+
+// TransactionDetail is a transaction with its ledger postings as seen by one participant.
+type TransactionDetail struct {
+	Transaction  TransactionInfo
+	FromUserName *string
+	ToUserName   *string
+	Postings     []*TransactionPosting
+}
+
+// TransactionPosting is one ledger entry of a transaction. BalanceAfterCents is the account's
+// ledger balance right after the entry and is only disclosed for the viewer's own accounts.
+type TransactionPosting struct {
+	EntryID           uuid.UUID
+	AccountID         uuid.UUID
+	AccountName       string
+	OwnerID           uuid.UUID
+	OwnerName         string
+	Currency          Currency
+	AmountCents       int64
+	BalanceAfterCents *int64
+	CreatedAt         time.Time
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestPending PaymentRequestStatus = "pending"
+	// PaymentRequestProcessing is an accepted request whose transfer is being booked or is held for review.
+	PaymentRequestProcessing PaymentRequestStatus = "processing"
+	PaymentRequestPaid       PaymentRequestStatus = "paid"
+	PaymentRequestDeclined   PaymentRequestStatus = "declined"
+	PaymentRequestCancelled  PaymentRequestStatus = "cancelled"
+	// PaymentRequestExpired is also reported for pending requests past ExpiresAt that were not
+	// settled yet.
+	PaymentRequestExpired PaymentRequestStatus = "expired"
+)
+
+// PaymentRequest asks the payer to send money to the requester. Accepting it books a regular
+// transfer, referenced by TransactionID.
+type PaymentRequest struct {
+	ID             uuid.UUID
+	RequesterID    uuid.UUID
+	RequesterEmail string
+	PayerID        uuid.UUID
+	PayerEmail     string
+	Currency       Currency
+	AmountCents    int64
+	Memo           string
+	Status         PaymentRequestStatus
+	TransactionID  *uuid.UUID
+	// ReviewID is the risk review a processing request's transfer is held in.
+	ReviewID  *uuid.UUID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsExpired reports whether a request still pending can no longer be accepted.
+func (r *PaymentRequest) IsExpired(now time.Time) bool {
+	return r.Status == PaymentRequestPending && !r.ExpiresAt.After(now)
+}
+
+// CreatePaymentRequestInput is the input for requesting money from another user by email.
+type CreatePaymentRequestInput struct {
+	PayerEmail  string
+	Currency    Currency
+	AmountCents int64
+	Memo        string
+}
+
+// AcceptPaymentRequestInput pays a request. FromAccountID and MFACode are passed to the transfer.
+type AcceptPaymentRequestInput struct {
+	RequestID     uuid.UUID
+	FromAccountID *uuid.UUID
+	MFACode       string
+}
+
+// PaymentRequestFilter narrows a user's payment requests. Incoming requests are those the user is
+// asked to pay; an empty direction lists both.
+type PaymentRequestFilter struct {
+	Direction TransactionDirection
+	Status    PaymentRequestStatus
+}
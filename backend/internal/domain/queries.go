@@ -1,9 +1,33 @@
 package domain
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransactionFilter narrows the transaction history of a user. Nil/zero fields do not filter.
 type TransactionFilter struct {
-	Type  TransactionType
-	Page  int
-	Limit int
+	Type         TransactionType
+	Currency     Currency
+	From         *time.Time
+	To           *time.Time
+	MinAmount    *int64
+	MaxAmount    *int64
+	Counterparty string
+	Direction    TransactionDirection
+	Status       TransactionStatus
+
+	// Cursor is the opaque next_cursor of a previous page; empty for the first page.
+	Cursor string
+	Limit  int
+}
+
+// TransactionPage is one keyset page of transaction history.
+type TransactionPage struct {
+	Items      []*TransactionInfo
+	NextCursor string
+	TotalCount int64
 }
 
 type TransactionWithEmails struct {
@@ -12,3 +36,9 @@ type TransactionWithEmails struct {
 	ToUserEmail   *string
 }
 
+// StatementQuery selects the ledger lines of one account booked in [From, To).
+type StatementQuery struct {
+	AccountID uuid.UUID
+	From      time.Time
+	To        time.Time
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExchangeRate is an exact conversion rate: 1 unit of Base buys Num/Den units of Quote.
+type ExchangeRate struct {
+	Base      Currency
+	Quote     Currency
+	Num       int64
+	Den       int64
+	Source    string
+	Version   string
+	ValidFrom time.Time
+	ValidTo   *time.Time
+}
+
+// Inverse returns the same rate quoted in the opposite direction.
+func (r ExchangeRate) Inverse() ExchangeRate {
+	inv := r
+	inv.Base, inv.Quote = r.Quote, r.Base
+	inv.Num, inv.Den = r.Den, r.Num
+	return inv
+}
+
+const maxRateDecimals = 10
+
+// ParseRateFraction parses a positive decimal rate such as "1.0876" into an exact fraction.
+func ParseRateFraction(s string) (int64, int64, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" {
+		return 0, 0, fmt.Errorf("empty rate")
+	}
+	parts := strings.Split(raw, ".")
+	if len(parts) > 2 {
+		return 0, 0, fmt.Errorf("invalid rate %q", s)
+	}
+	frac := ""
+	if len(parts) == 2 {
+		frac = strings.TrimRight(parts[1], "0")
+	}
+	if len(frac) > maxRateDecimals {
+		return 0, 0, fmt.Errorf("rate %q has more than %d decimals", s, maxRateDecimals)
+	}
+	digits := parts[0] + frac
+	if digits == "" || strings.ContainsAny(digits, "+-") {
+		return 0, 0, fmt.Errorf("invalid rate %q", s)
+	}
+	num, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid rate %q", s)
+	}
+	if num <= 0 {
+		return 0, 0, fmt.Errorf("rate must be positive")
+	}
+	den := int64(1)
+	for i := 0; i < len(frac); i++ {
+		den *= 10
+	}
+	return num, den, nil
+}
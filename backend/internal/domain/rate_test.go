@@ -0,0 +1,41 @@
+package domain
+
+import "testing"
+
+func TestParseRateFraction(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      string
+		wantNum int64
+		wantDen int64
+		wantErr bool
+	}{
+		{name: "integer", in: "2", wantNum: 2, wantDen: 1},
+		{name: "decimal", in: "0.92", wantNum: 92, wantDen: 100},
+		{name: "ecb_style", in: "1.0876", wantNum: 10876, wantDen: 10000},
+		{name: "trailing_zeros_trimmed", in: "1.5000", wantNum: 15, wantDen: 10},
+		{name: "spaces", in: "  0.5 ", wantNum: 5, wantDen: 10},
+		{name: "leading_dot", in: ".25", wantNum: 25, wantDen: 100},
+		{name: "reject_empty", in: "", wantErr: true},
+		{name: "reject_zero", in: "0.000", wantErr: true},
+		{name: "reject_negative", in: "-1.2", wantErr: true},
+		{name: "reject_multiple_dots", in: "1.2.3", wantErr: true},
+		{name: "reject_too_precise", in: "0.12345678901", wantErr: true},
+		{name: "reject_non_number", in: "abc", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			num, den, err := ParseRateFraction(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if num != tc.wantNum || den != tc.wantDen {
+				t.Fatalf("got=%d/%d want=%d/%d", num, den, tc.wantNum, tc.wantDen)
+			}
+		})
+	}
+}
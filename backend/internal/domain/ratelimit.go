@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// RateLimit allows Requests per Period on average, with up to Burst requests back to back.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// RateLimitDecision is the outcome of one request against a RateLimit. Remaining and Reset describe
+// the bucket after the request: how many more requests fit right now, and when the full burst is
+// available again. RetryAfter is set when the request was refused.
+type RateLimitDecision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RiskAction is what a RiskEngine wants done with a money movement.
+type RiskAction string
+
+const (
+	RiskAllow RiskAction = "allow"
+	RiskDeny  RiskAction = "deny"
+	RiskHold  RiskAction = "hold"
+)
+
+// RiskInput describes a transfer or exchange about to be booked. ToUserID is nil for exchanges.
+type RiskInput struct {
+	Type        TransactionType
+	UserID      uuid.UUID
+	ToUserID    *uuid.UUID
+	Currency    Currency
+	ToCurrency  Currency
+	AmountCents int64
+}
+
+// RiskDecision is a RiskEngine's verdict. Rule and Reason say why a movement was denied or held;
+// they are for operators and are not shown to the customer.
+type RiskDecision struct {
+	Action RiskAction
+	Rule   string
+	Reason string
+}
+
+type RiskReviewStatus string
+
+const (
+	RiskReviewPending RiskReviewStatus = "pending"
+	// RiskReviewProcessing is an approved review whose movement is being booked.
+	RiskReviewProcessing RiskReviewStatus = "processing"
+	RiskReviewApproved   RiskReviewStatus = "approved"
+	RiskReviewRejected   RiskReviewStatus = "rejected"
+)
+
+// RiskReview is a held transfer or exchange waiting for an operator. Exactly one of Transfer and
+// Exchange holds the original request, which is replayed on approval.
+type RiskReview struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Type        TransactionType
+	Currency    Currency
+	AmountCents int64
+	ToUserID    *uuid.UUID
+	Rule        string
+	Reason      string
+
+	Transfer       *TransferInput
+	Exchange       *ExchangeInput
+	IdempotencyKey string
+
+	Status        RiskReviewStatus
+	ReviewerID    *uuid.UUID
+	ReviewNote    string
+	TransactionID *uuid.UUID
+	CreatedAt     time.Time
+	ReviewedAt    *time.Time
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type ScheduleKind string
+
+const (
+	ScheduleOnce    ScheduleKind = "once"
+	ScheduleWeekly  ScheduleKind = "weekly"
+	ScheduleMonthly ScheduleKind = "monthly"
+)
+
+// Schedule describes when a scheduled transfer runs. All runs happen at the clock time of StartAt (UTC).
+// Weekly schedules run on StartAt's weekday; monthly schedules run on DayOfMonth, moved to the last
+// day of shorter months.
+type Schedule struct {
+	Kind       ScheduleKind
+	StartAt    time.Time
+	DayOfMonth int
+	EndAt      *time.Time
+}
+
+// Validate checks the schedule shape; it does not look at the current time.
+func (s Schedule) Validate() error {
+	switch s.Kind {
+	case ScheduleOnce, ScheduleWeekly:
+		if s.DayOfMonth != 0 {
+			return fmt.Errorf("day_of_month is only valid for monthly schedules")
+		}
+	case ScheduleMonthly:
+		if s.DayOfMonth < 1 || s.DayOfMonth > 31 {
+			return fmt.Errorf("day_of_month must be between 1 and 31")
+		}
+	default:
+		return fmt.Errorf("unknown schedule kind %q", s.Kind)
+	}
+	if s.StartAt.IsZero() {
+		return fmt.Errorf("start_at is required")
+	}
+	if s.EndAt != nil && s.EndAt.Before(s.StartAt) {
+		return fmt.Errorf("end_at must not be before start_at")
+	}
+	return nil
+}
+
+// FirstRun returns the first occurrence at or after StartAt.
+func (s Schedule) FirstRun() time.Time {
+	start := s.StartAt.UTC()
+	switch s.Kind {
+	case ScheduleMonthly:
+		first := monthlyOccurrence(start.Year(), start.Month(), s.DayOfMonth, start)
+		if first.Before(start) {
+			first = monthlyOccurrence(start.Year(), start.Month()+1, s.DayOfMonth, start)
+		}
+		return first
+	default:
+		return start
+	}
+}
+
+// NextAfter returns the occurrence following prev, or false when the schedule is exhausted.
+func (s Schedule) NextAfter(prev time.Time) (time.Time, bool) {
+	prev = prev.UTC()
+	var next time.Time
+	switch s.Kind {
+	case ScheduleWeekly:
+		next = prev.AddDate(0, 0, 7)
+	case ScheduleMonthly:
+		next = monthlyOccurrence(prev.Year(), prev.Month()+1, s.DayOfMonth, s.StartAt.UTC())
+	default:
+		return time.Time{}, false
+	}
+	if s.EndAt != nil && next.After(*s.EndAt) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// monthlyOccurrence builds day `day` of the given month (clamped to the month length) at clock's time of day.
+func monthlyOccurrence(year int, month time.Month, day int, clock time.Time) time.Time {
+	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, clock.Hour(), clock.Minute(), clock.Second(), 0, time.UTC)
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func utc(y int, m time.Month, d, h int) time.Time {
+	return time.Date(y, m, d, h, 0, 0, 0, time.UTC)
+}
+
+func TestScheduleFirstRun(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    Schedule
+		want time.Time
+	}{
+		{name: "once", s: Schedule{Kind: ScheduleOnce, StartAt: utc(2024, 3, 5, 9)}, want: utc(2024, 3, 5, 9)},
+		{name: "weekly_starts_on_start", s: Schedule{Kind: ScheduleWeekly, StartAt: utc(2024, 3, 5, 9)}, want: utc(2024, 3, 5, 9)},
+		{name: "monthly_later_this_month", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2024, 3, 5, 9), DayOfMonth: 20}, want: utc(2024, 3, 20, 9)},
+		{name: "monthly_same_day", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2024, 3, 5, 9), DayOfMonth: 5}, want: utc(2024, 3, 5, 9)},
+		{name: "monthly_next_month", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2024, 3, 5, 9), DayOfMonth: 1}, want: utc(2024, 4, 1, 9)},
+		{name: "monthly_clamped_to_february", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2023, 2, 1, 9), DayOfMonth: 31}, want: utc(2023, 2, 28, 9)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.s.FirstRun(); !got.Equal(tc.want) {
+				t.Fatalf("got=%s want=%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNextAfter(t *testing.T) {
+	end := utc(2024, 3, 1, 0)
+	testCases := []struct {
+		name   string
+		s      Schedule
+		prev   time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "once_has_no_next", s: Schedule{Kind: ScheduleOnce, StartAt: utc(2024, 1, 1, 9)}, prev: utc(2024, 1, 1, 9), wantOK: false},
+		{name: "weekly", s: Schedule{Kind: ScheduleWeekly, StartAt: utc(2024, 1, 1, 9)}, prev: utc(2024, 1, 29, 9), want: utc(2024, 2, 5, 9), wantOK: true},
+		{name: "monthly_31_into_february_leap", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2024, 1, 1, 9), DayOfMonth: 31}, prev: utc(2024, 1, 31, 9), want: utc(2024, 2, 29, 9), wantOK: true},
+		{name: "monthly_recovers_after_clamp", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2024, 1, 1, 9), DayOfMonth: 31}, prev: utc(2024, 2, 29, 9), want: utc(2024, 3, 31, 9), wantOK: true},
+		{name: "monthly_year_rollover", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2024, 1, 1, 9), DayOfMonth: 15}, prev: utc(2024, 12, 15, 9), want: utc(2025, 1, 15, 9), wantOK: true},
+		{name: "stops_after_end", s: Schedule{Kind: ScheduleWeekly, StartAt: utc(2024, 2, 1, 9), EndAt: &end}, prev: utc(2024, 2, 29, 9), wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.s.NextAfter(tc.prev)
+			if ok != tc.wantOK {
+				t.Fatalf("ok=%v want=%v", ok, tc.wantOK)
+			}
+			if ok && !got.Equal(tc.want) {
+				t.Fatalf("got=%s want=%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	before := utc(2023, 1, 1, 0)
+	testCases := []struct {
+		name    string
+		s       Schedule
+		wantErr bool
+	}{
+		{name: "once_ok", s: Schedule{Kind: ScheduleOnce, StartAt: utc(2024, 1, 1, 9)}},
+		{name: "monthly_ok", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2024, 1, 1, 9), DayOfMonth: 31}},
+		{name: "monthly_missing_day", s: Schedule{Kind: ScheduleMonthly, StartAt: utc(2024, 1, 1, 9)}, wantErr: true},
+		{name: "weekly_with_day", s: Schedule{Kind: ScheduleWeekly, StartAt: utc(2024, 1, 1, 9), DayOfMonth: 3}, wantErr: true},
+		{name: "unknown_kind", s: Schedule{Kind: "daily", StartAt: utc(2024, 1, 1, 9)}, wantErr: true},
+		{name: "missing_start", s: Schedule{Kind: ScheduleOnce}, wantErr: true},
+		{name: "end_before_start", s: Schedule{Kind: ScheduleWeekly, StartAt: utc(2024, 1, 1, 9), EndAt: &before}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.s.Validate(); (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
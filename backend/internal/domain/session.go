@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxUserAgentLength   = 512
+	MaxDeviceLabelLength = 100
+)
+
+// Normalize trims client details to what the session columns hold and derives a device label from
+// the user agent when the client did not name the device.
+func (c ClientInfo) Normalize() ClientInfo {
+	c.UserAgent = truncate(strings.TrimSpace(c.UserAgent), maxUserAgentLength)
+	c.DeviceLabel = truncate(strings.TrimSpace(c.DeviceLabel), MaxDeviceLabelLength)
+	if c.DeviceLabel == "" {
+		c.DeviceLabel = DeviceLabel(c.UserAgent)
+	}
+	return c
+}
+
+// DeviceLabel returns a short "Browser on OS" description of a user agent, e.g. "Firefox on Linux".
+// It recognises common browsers only; anything else is labelled by OS or "Unknown device".
+func DeviceLabel(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	browser := ""
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/"), strings.Contains(ua, "crios/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.HasPrefix(ua, "curl/"):
+		browser = "curl"
+	}
+
+	os := ""
+	switch {
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"):
+		os = "iOS"
+	case strings.Contains(ua, "android"):
+		os = "Android"
+	case strings.Contains(ua, "windows"):
+		os = "Windows"
+	case strings.Contains(ua, "mac os"), strings.Contains(ua, "macintosh"):
+		os = "macOS"
+	case strings.Contains(ua, "linux"):
+		os = "Linux"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os + " device"
+	default:
+		return "Unknown device"
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// Do not cut a multi-byte rune in half.
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
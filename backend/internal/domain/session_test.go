@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeviceLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{name: "empty", userAgent: "", want: "Unknown device"},
+		{name: "chrome_mac", userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", want: "Chrome on macOS"},
+		{name: "edge_windows", userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", want: "Edge on Windows"},
+		{name: "firefox_linux", userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", want: "Firefox on Linux"},
+		{name: "safari_iphone", userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", want: "Safari on iOS"},
+		{name: "chrome_android", userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", want: "Chrome on Android"},
+		{name: "curl", userAgent: "curl/8.4.0", want: "curl"},
+		{name: "unknown", userAgent: "banking-cli/1.0", want: "Unknown device"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeviceLabel(tt.userAgent); got != tt.want {
+				t.Fatalf("got=%q want=%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientInfoNormalize(t *testing.T) {
+	got := ClientInfo{UserAgent: "curl/8.4.0", DeviceLabel: "  " + strings.Repeat("é", MaxDeviceLabelLength) + " "}.Normalize()
+	if len(got.DeviceLabel) > MaxDeviceLabelLength || !strings.HasPrefix(got.DeviceLabel, "é") || strings.HasSuffix(got.DeviceLabel, "\xc3") {
+		t.Fatalf("got=%q", got.DeviceLabel)
+	}
+
+	got = ClientInfo{UserAgent: "curl/8.4.0"}.Normalize()
+	if got.DeviceLabel != "curl" {
+		t.Fatalf("got=%q want=%q", got.DeviceLabel, "curl")
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferLimitKind names the limit a transfer ran into.
+type TransferLimitKind string
+
+const (
+	TransferLimitSingle      TransferLimitKind = "single"
+	TransferLimitDaily       TransferLimitKind = "daily"
+	TransferLimitMonthly     TransferLimitKind = "monthly"
+	TransferLimitHourlyCount TransferLimitKind = "hourly_count"
+)
+
+const DefaultTransferTier = "standard"
+
+// TransferLimits caps a user's outgoing transfers in one currency. A nil field is unlimited. Days
+// and months are UTC calendar periods; the hourly count is over the last 60 minutes and counts
+// transfers in every currency.
+type TransferLimits struct {
+	Currency       Currency
+	MaxSingleCents *int64
+	DailyCents     *int64
+	MonthlyCents   *int64
+	HourlyCount    *int
+}
+
+// TransferUsage is what a user has sent to others, counted from the ledger. The amounts are in one
+// currency; HourCount spans all currencies. OldestInHour is the time of the oldest of the
+// HourCount transfers, if any.
+type TransferUsage struct {
+	DayCents     int64
+	MonthCents   int64
+	HourCount    int
+	OldestInHour *time.Time
+}
+
+// UserTransferLimits are a user's tier, the overrides set for the user, and the limits that apply
+// once the overrides are laid over the tier, one per enabled currency.
+type UserTransferLimits struct {
+	UserID    uuid.UUID
+	Tier      string
+	Overrides []*TransferLimits
+	Effective []*TransferLimits
+}
@@ -7,10 +7,79 @@ const (
 	CurrencyEUR Currency = "EUR"
 )
 
+// Role is the access level of a user. System users own the bank's internal accounts and cannot log in.
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleSupport  Role = "support"
+	RoleAdmin    Role = "admin"
+	RoleAuditor  Role = "auditor"
+	RoleSystem   Role = "system"
+)
+
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+type StatementFormat string
+
+const (
+	StatementFormatCSV     StatementFormat = "csv"
+	StatementFormatText    StatementFormat = "txt"
+	StatementFormatCamt053 StatementFormat = "camt053"
+)
+
+// TransactionDirection is seen from the user listing transactions.
+type TransactionDirection string
+
+const (
+	TransactionDirectionIncoming TransactionDirection = "incoming"
+	TransactionDirectionOutgoing TransactionDirection = "outgoing"
+)
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferActive    ScheduledTransferStatus = "active"
+	ScheduledTransferCompleted ScheduledTransferStatus = "completed"
+	ScheduledTransferCancelled ScheduledTransferStatus = "cancelled"
+	ScheduledTransferFailed    ScheduledTransferStatus = "failed"
+)
+
+type ScheduledTransferRunStatus string
+
+const (
+	ScheduledTransferRunSucceeded ScheduledTransferRunStatus = "succeeded"
+	ScheduledTransferRunFailed    ScheduledTransferRunStatus = "failed"
+)
+
 type TransactionType string
 
 const (
 	TransactionTypeTransfer TransactionType = "transfer"
 	TransactionTypeExchange TransactionType = "exchange"
+	TransactionTypeReversal TransactionType = "reversal"
 )
 
+// TransactionStatus tells whether a transaction has moved money. Only authorized transfers are ever
+// pending: they hold funds on the sender's account without ledger entries until they are captured
+// (posted) or released or expired (voided).
+type TransactionStatus string
+
+const (
+	TransactionStatusPending TransactionStatus = "pending"
+	TransactionStatusPosted  TransactionStatus = "posted"
+	TransactionStatusVoided  TransactionStatus = "voided"
+)
+
+const (
+	IdempotencyScopeTransfer = "transfer"
+	IdempotencyScopeExchange = "exchange"
+	IdempotencyScopeQuote    = "exchange_quote"
+	IdempotencyScopeReversal = "reversal"
+)
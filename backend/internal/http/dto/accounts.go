@@ -1,17 +1,38 @@
 package dto
 
 import (
+	"time"
+
 	"banking-platform/internal/domain"
 	"github.com/google/uuid"
 )
 
 type AccountResponse struct {
-	ID           uuid.UUID       `json:"id"`
-	Currency     domain.Currency `json:"currency"`
-	BalanceCents int64           `json:"balance_cents"`
+	ID           uuid.UUID            `json:"id"`
+	Currency     domain.Currency      `json:"currency"`
+	Name         string               `json:"name"`
+	Status       domain.AccountStatus `json:"status"`
+	IsDefault    bool                 `json:"is_default"`
+	BalanceCents int64                `json:"balance_cents"`
+	// LedgerBalanceCents equals BalanceCents; AvailableBalanceCents excludes pending authorizations.
+	LedgerBalanceCents    int64      `json:"ledger_balance_cents"`
+	AvailableBalanceCents int64      `json:"available_balance_cents"`
+	HeldCents             int64      `json:"held_cents"`
+	CreatedAt             time.Time  `json:"created_at"`
+	ClosedAt              *time.Time `json:"closed_at,omitempty"`
+}
+
+type OpenAccountRequest struct {
+	Currency domain.Currency `json:"currency" binding:"required,iso4217"`
+	Name     string          `json:"name" binding:"required,max=100"`
 }
 
 type BalanceResponse struct {
 	BalanceCents int64 `json:"balance_cents"`
 }
 
+type CurrencyResponse struct {
+	Code       domain.Currency `json:"code"`
+	Name       string          `json:"name"`
+	MinorUnits int             `json:"minor_units"`
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"time"
+
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+// AdminAccountResponse is an account as seen by back-office staff, including its owner.
+type AdminAccountResponse struct {
+	*AccountResponse
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// AccountStatusChangeRequest is optional; the reason is kept in the audit log.
+type AccountStatusChangeRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500"`
+}
+
+type BalanceMismatchResponse struct {
+	AccountID      uuid.UUID       `json:"account_id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	Currency       domain.Currency `json:"currency"`
+	BalanceCents   int64           `json:"balance_cents"`
+	LedgerSumCents int64           `json:"ledger_sum_cents"`
+	DiffCents      int64           `json:"diff_cents"`
+}
+
+type ConsistencyReportResponse struct {
+	OK                       bool                       `json:"ok"`
+	UnbalancedTransactionIDs []uuid.UUID                `json:"unbalanced_transaction_ids"`
+	BalanceMismatches        []*BalanceMismatchResponse `json:"balance_mismatches"`
+	CheckedAt                time.Time                  `json:"checked_at"`
+}
+
+// LoginLockoutResponse is the failed-login state of one email address. UserID is omitted when no
+// user has the address.
+type LoginLockoutResponse struct {
+	Email        string     `json:"email"`
+	UserID       *uuid.UUID `json:"user_id,omitempty"`
+	Failures     int        `json:"failures"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+}
+
+// TransferLimitsResponse is a set of transfer limits in one currency. A null limit is unlimited.
+type TransferLimitsResponse struct {
+	Currency       domain.Currency `json:"currency"`
+	MaxSingleCents *int64          `json:"max_single_cents"`
+	DailyCents     *int64          `json:"daily_cents"`
+	MonthlyCents   *int64          `json:"monthly_cents"`
+	HourlyCount    *int            `json:"hourly_count"`
+}
+
+// UserTransferLimitsResponse shows the user's tier, the overrides set for the user and the limits
+// that result in each enabled currency.
+type UserTransferLimitsResponse struct {
+	UserID    uuid.UUID                 `json:"user_id"`
+	Tier      string                    `json:"tier"`
+	Overrides []*TransferLimitsResponse `json:"overrides"`
+	Limits    []*TransferLimitsResponse `json:"limits"`
+}
+
+// TransferLimitOverrideRequest replaces the tier's limits in one currency. An omitted limit falls
+// back to the tier.
+type TransferLimitOverrideRequest struct {
+	Currency       domain.Currency `json:"currency" binding:"required,iso4217"`
+	MaxSingleCents *int64          `json:"max_single_cents,omitempty" binding:"omitempty,gte=0"`
+	DailyCents     *int64          `json:"daily_cents,omitempty" binding:"omitempty,gte=0"`
+	MonthlyCents   *int64          `json:"monthly_cents,omitempty" binding:"omitempty,gte=0"`
+	HourlyCount    *int            `json:"hourly_count,omitempty" binding:"omitempty,gte=0"`
+}
+
+// SetTransferLimitsRequest puts the user on a tier and replaces all of the user's overrides.
+type SetTransferLimitsRequest struct {
+	Tier      string                          `json:"tier" binding:"required,max=32"`
+	Overrides []*TransferLimitOverrideRequest `json:"overrides" binding:"max=20,dive"`
+}
+
+// RiskReviewResponse is a transfer or exchange held by a risk rule. ToUserID is set for transfers
+// and ToCurrency for exchanges.
+type RiskReviewResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	UserID        uuid.UUID              `json:"user_id"`
+	Type          domain.TransactionType `json:"type"`
+	Currency      domain.Currency        `json:"currency"`
+	ToCurrency    domain.Currency        `json:"to_currency,omitempty"`
+	AmountCents   int64                  `json:"amount_cents"`
+	ToUserID      *uuid.UUID             `json:"to_user_id,omitempty"`
+	QuoteID       *uuid.UUID             `json:"quote_id,omitempty"`
+	Rule          string                 `json:"rule"`
+	Reason        string                 `json:"reason"`
+	Status        string                 `json:"status"`
+	ReviewerID    *uuid.UUID             `json:"reviewer_id,omitempty"`
+	ReviewNote    string                 `json:"review_note,omitempty"`
+	TransactionID *uuid.UUID             `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	ReviewedAt    *time.Time             `json:"reviewed_at,omitempty"`
+}
+
+// RiskReviewDecisionRequest is optional; the note is kept with the review and in the audit log.
+type RiskReviewDecisionRequest struct {
+	Note string `json:"note,omitempty" binding:"max=500"`
+}
+
+// RiskReviewApprovalResponse is the approved review and the transaction booked for it.
+type RiskReviewApprovalResponse struct {
+	Review      *RiskReviewResponse  `json:"review"`
+	Transaction *TransactionResponse `json:"transaction"`
+}
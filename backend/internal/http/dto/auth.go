@@ -3,19 +3,39 @@ package dto
 import (
 	"time"
 
+	"banking-platform/internal/domain"
+	"banking-platform/internal/jwt"
 	"github.com/google/uuid"
 )
 
 type RegisterRequest struct {
-	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=6"`
-	FirstName string `json:"first_name" binding:"required"`
-	LastName  string `json:"last_name" binding:"required"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required,max=256"`
+	FirstName   string `json:"first_name" binding:"required"`
+	LastName    string `json:"last_name" binding:"required"`
+	DeviceLabel string `json:"device_label" binding:"omitempty,max=100"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required"`
+	DeviceLabel string `json:"device_label" binding:"omitempty,max=100"`
+}
+
+// LoginMFARequest completes a login that returned an MFA challenge, with either a TOTP code or a
+// recovery code.
+type LoginMFARequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"omitempty,len=6,numeric"`
+	RecoveryCode   string `json:"recovery_code" binding:"omitempty,max=20"`
+	DeviceLabel    string `json:"device_label" binding:"omitempty,max=100"`
+}
+
+// MFAChallengeResponse is returned by login instead of tokens when the user has MFA enabled.
+type MFAChallengeResponse struct {
+	MFARequired    bool      `json:"mfa_required"`
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
 }
 
 type RefreshTokenRequest struct {
@@ -29,12 +49,14 @@ type LogoutRequest struct {
 
 // UserResponse is a client-facing user DTO (no password fields).
 type UserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            uuid.UUID   `json:"id"`
+	Email         string      `json:"email"`
+	FirstName     string      `json:"first_name"`
+	LastName      string      `json:"last_name"`
+	Role          domain.Role `json:"role"`
+	EmailVerified bool        `json:"email_verified"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
 }
 
 type AuthResponse struct {
@@ -48,3 +70,20 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// SessionResponse is a signed-in device. Current marks the session of the calling access token.
+type SessionResponse struct {
+	ID          uuid.UUID `json:"id"`
+	DeviceLabel string    `json:"device_label"`
+	UserAgent   string    `json:"user_agent"`
+	IP          string    `json:"ip"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Current     bool      `json:"current"`
+}
+
+// JWKSResponse is the JSON Web Key Set served at /.well-known/jwks.json.
+type JWKSResponse struct {
+	Keys []jwt.JWK `json:"keys"`
+}
+
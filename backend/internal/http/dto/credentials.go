@@ -0,0 +1,19 @@
+package dto
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required,max=100"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required,max=100"`
+	NewPassword string `json:"new_password" binding:"required,max=256"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,max=256"`
+}
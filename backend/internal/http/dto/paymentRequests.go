@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+type CreatePaymentRequestRequest struct {
+	PayerEmail  string          `json:"payer_email" binding:"required,email"`
+	Currency    domain.Currency `json:"currency" binding:"required,iso4217"`
+	AmountCents int64           `json:"amount_cents" binding:"required,gt=0"`
+	Memo        string          `json:"memo,omitempty" binding:"max=140"`
+}
+
+type AcceptPaymentRequestRequest struct {
+	FromAccountID *uuid.UUID `json:"from_account_id,omitempty"`
+	// MFACode is required for amounts above the step-up threshold.
+	MFACode string `json:"mfa_code,omitempty" binding:"omitempty,len=6,numeric"`
+}
+
+type PaymentRequestFilter struct {
+	Direction domain.TransactionDirection `form:"direction" binding:"omitempty,oneof=incoming outgoing"`
+	Status    domain.PaymentRequestStatus `form:"status" binding:"omitempty,oneof=pending processing paid declined cancelled expired"`
+}
+
+type PaymentRequestResponse struct {
+	ID             uuid.UUID                   `json:"id"`
+	RequesterID    uuid.UUID                   `json:"requester_id"`
+	RequesterEmail string                      `json:"requester_email"`
+	PayerID        uuid.UUID                   `json:"payer_id"`
+	PayerEmail     string                      `json:"payer_email"`
+	Currency       domain.Currency             `json:"currency"`
+	AmountCents    int64                       `json:"amount_cents"`
+	Memo           string                      `json:"memo"`
+	Status         domain.PaymentRequestStatus `json:"status"`
+	TransactionID  *uuid.UUID                  `json:"transaction_id,omitempty"`
+	ReviewID       *uuid.UUID                  `json:"review_id,omitempty"`
+	ExpiresAt      time.Time                   `json:"expires_at"`
+	CreatedAt      time.Time                   `json:"created_at"`
+	UpdatedAt      time.Time                   `json:"updated_at"`
+}
+
+// PaymentRequestAcceptResponse is the paid request and the transfer booked for it.
+type PaymentRequestAcceptResponse struct {
+	PaymentRequest *PaymentRequestResponse `json:"payment_request"`
+	Transaction    *TransactionResponse    `json:"transaction"`
+}
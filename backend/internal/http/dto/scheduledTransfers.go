@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+type CreateScheduledTransferRequest struct {
+	FromAccountID *uuid.UUID          `json:"from_account_id,omitempty"`
+	ToUserID      *uuid.UUID          `json:"to_user_id,omitempty"`
+	ToUserEmail   *string             `json:"to_user_email,omitempty"`
+	ToAccountID   *uuid.UUID          `json:"to_account_id,omitempty"`
+	Currency      domain.Currency     `json:"currency" binding:"required,iso4217"`
+	AmountCents   int64               `json:"amount_cents" binding:"required,gt=0"`
+	Description   string              `json:"description,omitempty" binding:"max=140"`
+	Kind          domain.ScheduleKind `json:"kind" binding:"required,oneof=once weekly monthly"`
+	StartAt       time.Time           `json:"start_at" binding:"required"`
+	DayOfMonth    int                 `json:"day_of_month,omitempty" binding:"omitempty,min=1,max=31"`
+	EndAt         *time.Time          `json:"end_at,omitempty"`
+	// MFACode is required for amounts above the step-up threshold.
+	MFACode string `json:"mfa_code,omitempty" binding:"omitempty,len=6,numeric"`
+}
+
+type ScheduledTransferResponse struct {
+	ID                uuid.UUID                      `json:"id"`
+	FromAccountID     *uuid.UUID                     `json:"from_account_id,omitempty"`
+	ToUserID          *uuid.UUID                     `json:"to_user_id,omitempty"`
+	ToAccountID       *uuid.UUID                     `json:"to_account_id,omitempty"`
+	Currency          domain.Currency                `json:"currency"`
+	AmountCents       int64                          `json:"amount_cents"`
+	Description       string                         `json:"description"`
+	Kind              domain.ScheduleKind            `json:"kind"`
+	StartAt           time.Time                      `json:"start_at"`
+	DayOfMonth        int                            `json:"day_of_month,omitempty"`
+	EndAt             *time.Time                     `json:"end_at,omitempty"`
+	Status            domain.ScheduledTransferStatus `json:"status"`
+	NextRunAt         *time.Time                     `json:"next_run_at,omitempty"`
+	Attempts          int                            `json:"attempts"`
+	LastError         *string                        `json:"last_error,omitempty"`
+	LastTransactionID *uuid.UUID                     `json:"last_transaction_id,omitempty"`
+	CreatedAt         time.Time                      `json:"created_at"`
+	UpdatedAt         time.Time                      `json:"updated_at"`
+}
@@ -8,35 +8,104 @@ import (
 )
 
 type TransferRequest struct {
-	ToUserID    *uuid.UUID      `json:"to_user_id,omitempty"`
-	ToUserEmail *string         `json:"to_user_email,omitempty"`
-	Currency    domain.Currency `json:"currency" binding:"required,oneof=USD EUR"`
-	AmountCents int64           `json:"amount_cents" binding:"required,gt=0"`
+	ToUserID      *uuid.UUID      `json:"to_user_id,omitempty"`
+	ToUserEmail   *string         `json:"to_user_email,omitempty"`
+	ToAccountID   *uuid.UUID      `json:"to_account_id,omitempty"`
+	FromAccountID *uuid.UUID      `json:"from_account_id,omitempty"`
+	Currency      domain.Currency `json:"currency" binding:"required,iso4217"`
+	AmountCents   int64           `json:"amount_cents" binding:"required,gt=0"`
+	// MFACode is required for amounts above the step-up threshold.
+	MFACode string `json:"mfa_code,omitempty" binding:"omitempty,len=6,numeric"`
+	// Authorize only holds the amount on the sender's account until the recipient captures it.
+	Authorize bool `json:"authorize,omitempty"`
 }
 
 type ExchangeRequest struct {
-	FromCurrency domain.Currency `json:"from_currency" binding:"required,oneof=USD EUR"`
-	ToCurrency   domain.Currency `json:"to_currency" binding:"required,oneof=USD EUR"`
+	FromCurrency domain.Currency `json:"from_currency" binding:"required,iso4217"`
+	ToCurrency   domain.Currency `json:"to_currency" binding:"required,iso4217"`
 	AmountCents  int64           `json:"amount_cents" binding:"required,gt=0"`
 }
 
+// ReverseRequest is optional; an empty body reverses the whole remaining amount.
+type ReverseRequest struct {
+	AmountCents *int64 `json:"amount_cents,omitempty" binding:"omitempty,gt=0"`
+	Reason      string `json:"reason,omitempty" binding:"max=500"`
+}
+
+type CaptureRequest struct {
+	AmountCents *int64 `json:"amount_cents,omitempty" binding:"omitempty,gt=0"`
+}
+
+type ExchangeQuoteResponse struct {
+	ID                   uuid.UUID       `json:"id"`
+	FromCurrency         domain.Currency `json:"from_currency"`
+	ToCurrency           domain.Currency `json:"to_currency"`
+	AmountCents          int64           `json:"amount_cents"`
+	ConvertedAmountCents int64           `json:"converted_amount_cents"`
+	Rate                 float64         `json:"rate"`
+	SpreadBps            int64           `json:"spread_bps"`
+	RateSource           string          `json:"rate_source"`
+	RateVersion          string          `json:"rate_version"`
+	ExpiresAt            time.Time       `json:"expires_at"`
+}
+
 type TransactionResponse struct {
-	ID                   uuid.UUID              `json:"id"`
-	Type                 domain.TransactionType `json:"type"`
-	FromAccountID        *uuid.UUID             `json:"from_account_id,omitempty"`
-	ToAccountID          uuid.UUID              `json:"to_account_id"`
-	AmountCents          int64                  `json:"amount_cents"`
-	Currency             domain.Currency        `json:"currency"`
-	ExchangeRate         *float64               `json:"exchange_rate,omitempty"`
-	ConvertedAmountCents *int64                 `json:"converted_amount_cents,omitempty"`
-	Description          string                 `json:"description"`
-	CreatedAt            time.Time              `json:"created_at"`
-	FromUserEmail        *string                `json:"from_user_email,omitempty"`
-	ToUserEmail          *string                `json:"to_user_email,omitempty"`
+	ID                    uuid.UUID                `json:"id"`
+	Type                  domain.TransactionType   `json:"type"`
+	FromAccountID         *uuid.UUID               `json:"from_account_id,omitempty"`
+	ToAccountID           uuid.UUID                `json:"to_account_id"`
+	AmountCents           int64                    `json:"amount_cents"`
+	Currency              domain.Currency          `json:"currency"`
+	ExchangeRate          *float64                 `json:"exchange_rate,omitempty"`
+	ConvertedAmountCents  *int64                   `json:"converted_amount_cents,omitempty"`
+	ToCurrency            domain.Currency          `json:"to_currency,omitempty"`
+	RateSource            *string                  `json:"rate_source,omitempty"`
+	RateVersion           *string                  `json:"rate_version,omitempty"`
+	QuoteID               *uuid.UUID               `json:"quote_id,omitempty"`
+	ReversesTransactionID *uuid.UUID               `json:"reverses_transaction_id,omitempty"`
+	Description           string                   `json:"description"`
+	Status                domain.TransactionStatus `json:"status"`
+	ExpiresAt             *time.Time               `json:"expires_at,omitempty"`
+	CapturedAmountCents   *int64                   `json:"captured_amount_cents,omitempty"`
+	CreatedAt             time.Time                `json:"created_at"`
+	FromUserEmail         *string                  `json:"from_user_email,omitempty"`
+	ToUserEmail           *string                  `json:"to_user_email,omitempty"`
+}
+
+type TransactionDetailResponse struct {
+	*TransactionResponse
+	FromUserName *string            `json:"from_user_name,omitempty"`
+	ToUserName   *string            `json:"to_user_name,omitempty"`
+	Postings     []*PostingResponse `json:"postings"`
+}
+
+type PostingResponse struct {
+	ID                uuid.UUID       `json:"id"`
+	AccountID         uuid.UUID       `json:"account_id"`
+	AccountName       string          `json:"account_name"`
+	OwnerName         string          `json:"owner_name"`
+	Currency          domain.Currency `json:"currency"`
+	AmountCents       int64           `json:"amount_cents"`
+	BalanceAfterCents *int64          `json:"balance_after_cents,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
 }
 
 type TransactionFilter struct {
-	Type  domain.TransactionType `form:"type"`
-	Page  int                    `form:"page"`
-	Limit int                    `form:"limit"`
+	Type           domain.TransactionType      `form:"type" binding:"omitempty,oneof=transfer exchange reversal"`
+	Currency       domain.Currency             `form:"currency" binding:"omitempty,iso4217"`
+	From           string                      `form:"from"`
+	To             string                      `form:"to"`
+	MinAmountCents *int64                      `form:"min_amount_cents" binding:"omitempty,gte=0"`
+	MaxAmountCents *int64                      `form:"max_amount_cents" binding:"omitempty,gte=0"`
+	Counterparty   string                      `form:"counterparty" binding:"omitempty,email"`
+	Direction      domain.TransactionDirection `form:"direction" binding:"omitempty,oneof=incoming outgoing"`
+	Status         domain.TransactionStatus    `form:"status" binding:"omitempty,oneof=pending posted voided"`
+	Cursor         string                      `form:"cursor"`
+	Limit          int                         `form:"limit" binding:"omitempty,gt=0"`
+}
+
+type TransactionListResponse struct {
+	Items      []*TransactionResponse `json:"items"`
+	NextCursor *string                `json:"next_cursor"`
+	TotalCount int64                  `json:"total_count"`
 }
@@ -1,9 +1,14 @@
 package handler
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
+	"time"
 
+	"banking-platform/internal/domain"
 	"banking-platform/internal/http/dto"
+	"banking-platform/internal/statement"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -40,11 +45,7 @@ func (h *AccountHandler) GetAccounts(c *gin.Context) {
 
 	out := make([]*dto.AccountResponse, len(accounts))
 	for i, a := range accounts {
-		out[i] = &dto.AccountResponse{
-			ID:           a.ID,
-			Currency:     a.Currency,
-			BalanceCents: a.BalanceCents,
-		}
+		out[i] = accountResponse(a)
 	}
 	respondWithJSON(c, http.StatusOK, out)
 }
@@ -76,6 +77,186 @@ func (h *AccountHandler) GetBalance(c *gin.Context) {
 		return
 	}
 
-	respondWithJSON(c, http.StatusOK, gin.H{"balance_cents": balance})
+	respondWithJSON(c, http.StatusOK, gin.H{
+		"balance_cents":           balance.LedgerCents,
+		"ledger_balance_cents":    balance.LedgerCents,
+		"available_balance_cents": balance.AvailableCents,
+		"held_cents":              balance.HeldCents,
+	})
+}
+
+func (h *AccountHandler) ListCurrencies(c *gin.Context) {
+	ctx := c.Request.Context()
+	currencies, err := h.accountService.ListCurrencies(ctx)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	out := make([]*dto.CurrencyResponse, len(currencies))
+	for i, cur := range currencies {
+		out[i] = &dto.CurrencyResponse{
+			Code:       cur.Code,
+			Name:       cur.Name,
+			MinorUnits: cur.MinorUnits,
+		}
+	}
+	respondWithJSON(c, http.StatusOK, out)
 }
 
+func (h *AccountHandler) OpenAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.OpenAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	account, err := h.accountService.OpenAccount(ctx, userUUID, &domain.OpenAccountInput{
+		Currency: req.Currency,
+		Name:     req.Name,
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusCreated, accountResponse(account))
+}
+
+func (h *AccountHandler) CloseAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid account ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	account, err := h.accountService.CloseAccount(ctx, userUUID, accountID)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, accountResponse(account))
+}
+
+// GetStatement exports the account statement for ?from=&to= (dates or RFC3339, to is exclusive for
+// timestamps and inclusive for dates) as csv, txt or camt053. The period defaults to the current month.
+func (h *AccountHandler) GetStatement(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid account ID", http.StatusBadRequest)
+		return
+	}
+
+	format := domain.StatementFormat(c.DefaultQuery("format", string(domain.StatementFormatCSV)))
+	if !statement.IsSupported(format) {
+		respondWithError(c, "format must be one of csv, txt, camt053", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+	if v := c.Query("from"); v != "" {
+		if from, err = parseTimeParam(v, false); err != nil {
+			respondWithError(c, "invalid from: use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = parseTimeParam(v, true); err != nil {
+			respondWithError(c, "invalid to: use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	st, err := h.accountService.GetStatement(ctx, userUUID, &domain.StatementQuery{
+		AccountID: accountID,
+		From:      from,
+		To:        to,
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, format, st); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", statement.FileName(format, st)))
+	c.Data(http.StatusOK, statement.ContentType(format), buf.Bytes())
+}
+
+// parseTimeParam accepts a date or an RFC3339 timestamp. A date used as the end of a
+// period includes that whole day.
+func parseTimeParam(v string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	d, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d, nil
+}
+
+func accountResponse(a *domain.Account) *dto.AccountResponse {
+	return &dto.AccountResponse{
+		ID:                    a.ID,
+		Currency:              a.Currency,
+		Name:                  a.Name,
+		Status:                a.Status,
+		IsDefault:             a.IsDefault,
+		BalanceCents:          a.BalanceCents,
+		LedgerBalanceCents:    a.BalanceCents,
+		AvailableBalanceCents: a.AvailableCents(),
+		HeldCents:             a.HeldCents,
+		CreatedAt:             a.CreatedAt,
+		ClosedAt:              a.ClosedAt,
+	}
+}
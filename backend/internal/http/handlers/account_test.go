@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"banking-platform/internal/domain"
+)
+
+func TestParseTimeParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		end     bool
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "date_start", in: "2024-01-01", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "date_end_includes_day", in: "2024-01-31", end: true, want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "rfc3339_end_exact", in: "2024-01-31T12:00:00Z", end: true, want: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{name: "invalid", in: "31/01/2024", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimeParam(tt.in, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("got=%s want=%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccountResponseBalances(t *testing.T) {
+	got := accountResponse(&domain.Account{BalanceCents: 100_00, HeldCents: 25_00})
+	if got.BalanceCents != 100_00 || got.LedgerBalanceCents != 100_00 {
+		t.Fatalf("balance=%d ledger=%d", got.BalanceCents, got.LedgerBalanceCents)
+	}
+	if got.AvailableBalanceCents != 75_00 || got.HeldCents != 25_00 {
+		t.Fatalf("available=%d held=%d", got.AvailableBalanceCents, got.HeldCents)
+	}
+}
@@ -0,0 +1,451 @@
+package handler
+
+import (
+	"net/http"
+
+	"banking-platform/internal/domain"
+	"banking-platform/internal/http/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AdminHandler struct {
+	adminService AdminService
+}
+
+func NewAdminHandler(adminService AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	users, err := h.adminService.ListUsers(ctx, actor)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	out := make([]*dto.UserResponse, len(users))
+	for i, u := range users {
+		out[i] = &dto.UserResponse{
+			ID:            u.ID,
+			Email:         u.Email,
+			FirstName:     u.FirstName,
+			LastName:      u.LastName,
+			Role:          u.Role,
+			EmailVerified: u.EmailVerified,
+			CreatedAt:     u.CreatedAt,
+			UpdatedAt:     u.UpdatedAt,
+		}
+	}
+	respondWithJSON(c, http.StatusOK, out)
+}
+
+func (h *AdminHandler) ListLockouts(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	lockouts, err := h.adminService.ListLockouts(ctx, actor)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	out := make([]*dto.LoginLockoutResponse, len(lockouts))
+	for i, l := range lockouts {
+		out[i] = &dto.LoginLockoutResponse{
+			Email:        l.Email,
+			UserID:       l.UserID,
+			Failures:     l.Failures,
+			LastFailedAt: l.LastFailedAt,
+			LockedUntil:  l.LockedUntil,
+		}
+	}
+	respondWithJSON(c, http.StatusOK, out)
+}
+
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.adminService.UnlockUser(ctx, actor, userID); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) GetAccount(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid account ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	account, err := h.adminService.GetAccount(ctx, actor, accountID)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, adminAccountResponse(account))
+}
+
+func (h *AdminHandler) FreezeAccount(c *gin.Context) {
+	h.changeAccountStatus(c, true)
+}
+
+func (h *AdminHandler) UnfreezeAccount(c *gin.Context) {
+	h.changeAccountStatus(c, false)
+}
+
+func (h *AdminHandler) changeAccountStatus(c *gin.Context, freeze bool) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	accountID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid account ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.AccountStatusChangeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondWithBindError(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	var account *domain.Account
+	if freeze {
+		account, err = h.adminService.FreezeAccount(ctx, actor, accountID, req.Reason)
+	} else {
+		account, err = h.adminService.UnfreezeAccount(ctx, actor, accountID, req.Reason)
+	}
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, adminAccountResponse(account))
+}
+
+func (h *AdminHandler) RunConsistencyChecks(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	report, err := h.adminService.RunConsistencyChecks(ctx, actor)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	out := &dto.ConsistencyReportResponse{
+		OK:                       len(report.UnbalancedTransactionIDs) == 0 && len(report.BalanceMismatches) == 0,
+		UnbalancedTransactionIDs: report.UnbalancedTransactionIDs,
+		BalanceMismatches:        make([]*dto.BalanceMismatchResponse, len(report.BalanceMismatches)),
+		CheckedAt:                report.CheckedAt,
+	}
+	if out.UnbalancedTransactionIDs == nil {
+		out.UnbalancedTransactionIDs = []uuid.UUID{}
+	}
+	for i, m := range report.BalanceMismatches {
+		out.BalanceMismatches[i] = &dto.BalanceMismatchResponse{
+			AccountID:      m.AccountID,
+			UserID:         m.UserID,
+			Currency:       m.Currency,
+			BalanceCents:   m.BalanceCents,
+			LedgerSumCents: m.LedgerSumCents,
+			DiffCents:      m.DiffCents,
+		}
+	}
+	respondWithJSON(c, http.StatusOK, out)
+}
+
+// ReverseTransaction reverses any transfer, regardless of who received it.
+func (h *AdminHandler) ReverseTransaction(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ReverseRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondWithBindError(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	transaction, err := h.adminService.ReverseTransaction(ctx, actor, &domain.ReverseInput{
+		TransactionID: transactionID,
+		AmountCents:   req.AmountCents,
+		Reason:        req.Reason,
+
+		IdempotencyKey: idempotencyKey(c),
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusCreated, transactionResponse(transaction))
+}
+
+// principalFromContext reads the caller set by AuthMiddleware and writes the error response if it is missing.
+func principalFromContext(c *gin.Context) (*domain.Principal, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	roleValue, _ := c.Get("role")
+	role, ok := roleValue.(domain.Role)
+	if !ok {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	principal := &domain.Principal{UserID: userUUID, Role: role}
+	if sessionID, ok := c.Get("session_id"); ok {
+		principal.SessionID, _ = sessionID.(uuid.UUID)
+	}
+	return principal, true
+}
+
+func adminAccountResponse(a *domain.Account) *dto.AdminAccountResponse {
+	return &dto.AdminAccountResponse{
+		AccountResponse: accountResponse(a),
+		UserID:          a.UserID,
+	}
+}
+
+func (h *AdminHandler) GetTransferLimits(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	limits, err := h.adminService.GetTransferLimits(ctx, actor, userID)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+	respondWithJSON(c, http.StatusOK, toUserTransferLimitsResponse(limits))
+}
+
+func (h *AdminHandler) SetTransferLimits(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.SetTransferLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	in := &domain.SetTransferLimitsInput{Tier: req.Tier, Overrides: make([]*domain.TransferLimits, len(req.Overrides))}
+	for i, o := range req.Overrides {
+		in.Overrides[i] = &domain.TransferLimits{
+			Currency:       o.Currency,
+			MaxSingleCents: o.MaxSingleCents,
+			DailyCents:     o.DailyCents,
+			MonthlyCents:   o.MonthlyCents,
+			HourlyCount:    o.HourlyCount,
+		}
+	}
+
+	ctx := c.Request.Context()
+	limits, err := h.adminService.SetTransferLimits(ctx, actor, userID, in)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+	respondWithJSON(c, http.StatusOK, toUserTransferLimitsResponse(limits))
+}
+
+func toUserTransferLimitsResponse(l *domain.UserTransferLimits) *dto.UserTransferLimitsResponse {
+	return &dto.UserTransferLimitsResponse{
+		UserID:    l.UserID,
+		Tier:      l.Tier,
+		Overrides: toTransferLimitsResponses(l.Overrides),
+		Limits:    toTransferLimitsResponses(l.Effective),
+	}
+}
+
+func toTransferLimitsResponses(limits []*domain.TransferLimits) []*dto.TransferLimitsResponse {
+	out := make([]*dto.TransferLimitsResponse, len(limits))
+	for i, l := range limits {
+		out[i] = &dto.TransferLimitsResponse{
+			Currency:       l.Currency,
+			MaxSingleCents: l.MaxSingleCents,
+			DailyCents:     l.DailyCents,
+			MonthlyCents:   l.MonthlyCents,
+			HourlyCount:    l.HourlyCount,
+		}
+	}
+	return out
+}
+
+// ListRiskReviews lists held movements, optionally filtered by ?status=.
+func (h *AdminHandler) ListRiskReviews(c *gin.Context) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	reviews, err := h.adminService.ListRiskReviews(ctx, actor, domain.RiskReviewStatus(c.Query("status")))
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	out := make([]*dto.RiskReviewResponse, len(reviews))
+	for i, r := range reviews {
+		out[i] = riskReviewResponse(r)
+	}
+	respondWithJSON(c, http.StatusOK, out)
+}
+
+// ApproveRiskReview books a held movement and returns the review with its transaction.
+func (h *AdminHandler) ApproveRiskReview(c *gin.Context) {
+	actor, id, req, ok := riskReviewDecision(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	review, transaction, err := h.adminService.ApproveRiskReview(ctx, actor, id, req.Note)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+	respondWithJSON(c, http.StatusOK, &dto.RiskReviewApprovalResponse{
+		Review:      riskReviewResponse(review),
+		Transaction: transactionResponse(transaction),
+	})
+}
+
+func (h *AdminHandler) RejectRiskReview(c *gin.Context) {
+	actor, id, req, ok := riskReviewDecision(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	review, err := h.adminService.RejectRiskReview(ctx, actor, id, req.Note)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+	respondWithJSON(c, http.StatusOK, riskReviewResponse(review))
+}
+
+// riskReviewDecision reads the caller, review ID and optional body, writing the error response on failure.
+func riskReviewDecision(c *gin.Context) (*domain.Principal, uuid.UUID, *dto.RiskReviewDecisionRequest, bool) {
+	actor, ok := principalFromContext(c)
+	if !ok {
+		return nil, uuid.Nil, nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid review ID", http.StatusBadRequest)
+		return nil, uuid.Nil, nil, false
+	}
+
+	req := &dto.RiskReviewDecisionRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(req); err != nil {
+			respondWithBindError(c, err)
+			return nil, uuid.Nil, nil, false
+		}
+	}
+	return actor, id, req, true
+}
+
+func riskReviewResponse(r *domain.RiskReview) *dto.RiskReviewResponse {
+	out := &dto.RiskReviewResponse{
+		ID:            r.ID,
+		UserID:        r.UserID,
+		Type:          r.Type,
+		Currency:      r.Currency,
+		AmountCents:   r.AmountCents,
+		ToUserID:      r.ToUserID,
+		Rule:          r.Rule,
+		Reason:        r.Reason,
+		Status:        string(r.Status),
+		ReviewerID:    r.ReviewerID,
+		ReviewNote:    r.ReviewNote,
+		TransactionID: r.TransactionID,
+		CreatedAt:     r.CreatedAt,
+		ReviewedAt:    r.ReviewedAt,
+	}
+	if r.Exchange != nil {
+		out.ToCurrency = r.Exchange.ToCurrency
+		out.QuoteID = r.Exchange.QuoteID
+	}
+	return out
+}
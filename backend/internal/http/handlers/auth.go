@@ -32,6 +32,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Password:  req.Password,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
+		Client:    clientInfo(c, req.DeviceLabel),
 	})
 	if err != nil {
 		respondWithServiceError(c, err)
@@ -42,12 +43,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		AccessToken:  out.Tokens.AccessToken,
 		RefreshToken: out.Tokens.RefreshToken,
 		User: &dto.UserResponse{
-			ID:        out.User.ID,
-			Email:     out.User.Email,
-			FirstName: out.User.FirstName,
-			LastName:  out.User.LastName,
-			CreatedAt: out.User.CreatedAt,
-			UpdatedAt: out.User.UpdatedAt,
+			ID:            out.User.ID,
+			Email:         out.User.Email,
+			FirstName:     out.User.FirstName,
+			LastName:      out.User.LastName,
+			Role:          out.User.Role,
+			EmailVerified: out.User.EmailVerified,
+			CreatedAt:     out.User.CreatedAt,
+			UpdatedAt:     out.User.UpdatedAt,
 		},
 	})
 }
@@ -63,22 +66,69 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	out, err := h.authService.Login(ctx, &domain.LoginInput{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   clientInfo(c, req.DeviceLabel),
 	})
 	if err != nil {
 		respondWithServiceError(c, err)
 		return
 	}
+	if out.MFAChallenge != nil {
+		respondWithJSON(c, http.StatusOK, &dto.MFAChallengeResponse{
+			MFARequired:    true,
+			ChallengeToken: out.MFAChallenge.Token,
+			ExpiresAt:      out.MFAChallenge.ExpiresAt,
+		})
+		return
+	}
 
 	respondWithJSON(c, http.StatusOK, &dto.AuthResponse{
 		AccessToken:  out.Tokens.AccessToken,
 		RefreshToken: out.Tokens.RefreshToken,
 		User: &dto.UserResponse{
-			ID:        out.User.ID,
-			Email:     out.User.Email,
-			FirstName: out.User.FirstName,
-			LastName:  out.User.LastName,
-			CreatedAt: out.User.CreatedAt,
-			UpdatedAt: out.User.UpdatedAt,
+			ID:            out.User.ID,
+			Email:         out.User.Email,
+			FirstName:     out.User.FirstName,
+			LastName:      out.User.LastName,
+			Role:          out.User.Role,
+			EmailVerified: out.User.EmailVerified,
+			CreatedAt:     out.User.CreatedAt,
+			UpdatedAt:     out.User.UpdatedAt,
+		},
+	})
+}
+
+// LoginMFA exchanges the challenge returned by Login and a second factor for tokens.
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req dto.LoginMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	out, err := h.authService.LoginMFA(ctx, &domain.LoginMFAInput{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		RecoveryCode:   req.RecoveryCode,
+		Client:         clientInfo(c, req.DeviceLabel),
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, &dto.AuthResponse{
+		AccessToken:  out.Tokens.AccessToken,
+		RefreshToken: out.Tokens.RefreshToken,
+		User: &dto.UserResponse{
+			ID:            out.User.ID,
+			Email:         out.User.Email,
+			FirstName:     out.User.FirstName,
+			LastName:      out.User.LastName,
+			Role:          out.User.Role,
+			EmailVerified: out.User.EmailVerified,
+			CreatedAt:     out.User.CreatedAt,
+			UpdatedAt:     out.User.UpdatedAt,
 		},
 	})
 }
@@ -104,12 +154,14 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	}
 
 	respondWithJSON(c, http.StatusOK, &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	})
 }
 
@@ -121,7 +173,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	tokenPair, err := h.authService.RefreshToken(ctx, req.RefreshToken)
+	tokenPair, err := h.authService.RefreshToken(ctx, req.RefreshToken, clientInfo(c, ""))
 	if err != nil {
 		respondWithServiceError(c, err)
 		return
@@ -152,3 +204,75 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), principal)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	resp := make([]*dto.SessionResponse, len(sessions))
+	for i, s := range sessions {
+		resp[i] = &dto.SessionResponse{
+			ID:          s.ID,
+			DeviceLabel: s.DeviceLabel,
+			UserAgent:   s.UserAgent,
+			IP:          s.IP,
+			CreatedAt:   s.CreatedAt,
+			LastUsedAt:  s.LastUsedAt,
+			ExpiresAt:   s.ExpiresAt,
+			Current:     s.Current,
+		}
+	}
+	respondWithJSON(c, http.StatusOK, resp)
+}
+
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), principal, id); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll ends every session of the caller, including the one making the request.
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.LogoutAll(c.Request.Context(), principal); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// clientInfo captures the device a session is opened or refreshed from.
+func clientInfo(c *gin.Context, deviceLabel string) domain.ClientInfo {
+	return domain.ClientInfo{
+		UserAgent:   c.Request.UserAgent(),
+		IP:          c.ClientIP(),
+		DeviceLabel: deviceLabel,
+	}
+}
+
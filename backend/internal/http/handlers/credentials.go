@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+
+	"banking-platform/internal/domain"
+	"banking-platform/internal/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type CredentialHandler struct {
+	credentialService CredentialService
+}
+
+func NewCredentialHandler(credentialService CredentialService) *CredentialHandler {
+	return &CredentialHandler{
+		credentialService: credentialService,
+	}
+}
+
+func (h *CredentialHandler) RequestEmailVerification(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := h.credentialService.RequestEmailVerification(c.Request.Context(), principal); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+func (h *CredentialHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	if err := h.credentialService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword answers 202 whether or not the email is registered.
+func (h *CredentialHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	if err := h.credentialService.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+func (h *CredentialHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	if err := h.credentialService.ResetPassword(c.Request.Context(), &domain.ResetPasswordInput{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	}); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CredentialHandler) ChangePassword(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	if err := h.credentialService.ChangePassword(c.Request.Context(), principal, &domain.ChangePasswordInput{
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"banking-platform/internal/domain"
+	"banking-platform/internal/jwt"
 	"github.com/google/uuid"
 )
 
@@ -11,21 +12,91 @@ import (
 type AuthService interface {
 	Register(ctx context.Context, in *domain.RegisterInput) (*domain.AuthResult, error)
 	Login(ctx context.Context, in *domain.LoginInput) (*domain.AuthResult, error)
-	ValidateToken(ctx context.Context, tokenString string) (uuid.UUID, error)
+	ValidateToken(ctx context.Context, tokenString string) (*domain.Principal, error)
 	GetUserByID(ctx context.Context, userID uuid.UUID) (*domain.UserInfo, error)
-	RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenPair, error)
+	RefreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.TokenPair, error)
 	Logout(ctx context.Context, in *domain.LogoutInput) error
+	LogoutAll(ctx context.Context, principal *domain.Principal) error
+	ListSessions(ctx context.Context, principal *domain.Principal) ([]*domain.Session, error)
+	RevokeSession(ctx context.Context, principal *domain.Principal, sessionID uuid.UUID) error
+	LoginMFA(ctx context.Context, in *domain.LoginMFAInput) (*domain.AuthResult, error)
+}
+
+// MFAService defines two-factor authentication operations used by HTTP handlers.
+type MFAService interface {
+	Status(ctx context.Context, principal *domain.Principal) (*domain.MFAStatus, error)
+	Enroll(ctx context.Context, principal *domain.Principal) (*domain.MFAEnrollment, error)
+	Confirm(ctx context.Context, principal *domain.Principal, code string) ([]string, error)
+	Disable(ctx context.Context, principal *domain.Principal, code string, recoveryCode string) error
+	RegenerateRecoveryCodes(ctx context.Context, principal *domain.Principal, code string) ([]string, error)
+}
+
+// CredentialService defines email verification and password operations used by HTTP handlers.
+type CredentialService interface {
+	RequestEmailVerification(ctx context.Context, principal *domain.Principal) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, in *domain.ResetPasswordInput) error
+	ChangePassword(ctx context.Context, principal *domain.Principal, in *domain.ChangePasswordInput) error
+}
+
+// KeyPublisher exposes the public keys access tokens can be verified with.
+type KeyPublisher interface {
+	PublicJWKs() []jwt.JWK
 }
 
 // AccountService defines account operations used by HTTP handlers.
 type AccountService interface {
 	GetUserAccounts(ctx context.Context, userID uuid.UUID) ([]*domain.Account, error)
-	GetAccountBalance(ctx context.Context, accountID uuid.UUID, userID uuid.UUID) (int64, error)
+	GetAccountBalance(ctx context.Context, accountID uuid.UUID, userID uuid.UUID) (*domain.AccountBalance, error)
+	ListCurrencies(ctx context.Context) ([]domain.CurrencyInfo, error)
+	OpenAccount(ctx context.Context, userID uuid.UUID, in *domain.OpenAccountInput) (*domain.Account, error)
+	CloseAccount(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) (*domain.Account, error)
+	GetStatement(ctx context.Context, userID uuid.UUID, q *domain.StatementQuery) (*domain.Statement, error)
 }
 
 // TransactionService defines transaction operations used by HTTP handlers.
 type TransactionService interface {
 	Transfer(ctx context.Context, fromUserID uuid.UUID, in *domain.TransferInput) (*domain.TransactionInfo, error)
 	Exchange(ctx context.Context, userID uuid.UUID, in *domain.ExchangeInput) (*domain.TransactionInfo, error)
-	GetUserTransactions(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter) ([]*domain.TransactionInfo, error)
+	QuoteExchange(ctx context.Context, userID uuid.UUID, in *domain.ExchangeInput) (*domain.ExchangeQuote, error)
+	ExecuteQuote(ctx context.Context, userID uuid.UUID, in *domain.ExecuteQuoteInput) (*domain.TransactionInfo, error)
+	GetUserTransactions(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter) (*domain.TransactionPage, error)
+	GetTransactionDetail(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.TransactionDetail, error)
+	Reverse(ctx context.Context, actorID uuid.UUID, in *domain.ReverseInput) (*domain.TransactionInfo, error)
+	Capture(ctx context.Context, actorID uuid.UUID, in *domain.CaptureInput) (*domain.TransactionInfo, error)
+	Release(ctx context.Context, actorID uuid.UUID, id uuid.UUID) (*domain.TransactionInfo, error)
+}
+
+// ScheduledTransferService defines scheduled transfer operations used by HTTP handlers.
+type ScheduledTransferService interface {
+	Create(ctx context.Context, userID uuid.UUID, in *domain.CreateScheduledTransferInput) (*domain.ScheduledTransfer, error)
+	List(ctx context.Context, userID uuid.UUID) ([]*domain.ScheduledTransfer, error)
+	Cancel(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.ScheduledTransfer, error)
+}
+
+// PaymentRequestService defines payment request operations used by HTTP handlers.
+type PaymentRequestService interface {
+	Create(ctx context.Context, requesterID uuid.UUID, in *domain.CreatePaymentRequestInput) (*domain.PaymentRequest, error)
+	List(ctx context.Context, userID uuid.UUID, filter *domain.PaymentRequestFilter) ([]*domain.PaymentRequest, error)
+	Accept(ctx context.Context, payerID uuid.UUID, in *domain.AcceptPaymentRequestInput) (*domain.PaymentRequest, *domain.TransactionInfo, error)
+	Decline(ctx context.Context, payerID uuid.UUID, id uuid.UUID) (*domain.PaymentRequest, error)
+	Cancel(ctx context.Context, requesterID uuid.UUID, id uuid.UUID) (*domain.PaymentRequest, error)
+}
+
+// AdminService defines back-office operations used by HTTP handlers.
+type AdminService interface {
+	ListUsers(ctx context.Context, actor *domain.Principal) ([]*domain.UserInfo, error)
+	GetAccount(ctx context.Context, actor *domain.Principal, accountID uuid.UUID) (*domain.Account, error)
+	FreezeAccount(ctx context.Context, actor *domain.Principal, accountID uuid.UUID, reason string) (*domain.Account, error)
+	UnfreezeAccount(ctx context.Context, actor *domain.Principal, accountID uuid.UUID, reason string) (*domain.Account, error)
+	RunConsistencyChecks(ctx context.Context, actor *domain.Principal) (*domain.ConsistencyReport, error)
+	ReverseTransaction(ctx context.Context, actor *domain.Principal, in *domain.ReverseInput) (*domain.TransactionInfo, error)
+	ListLockouts(ctx context.Context, actor *domain.Principal) ([]*domain.LoginLockout, error)
+	UnlockUser(ctx context.Context, actor *domain.Principal, userID uuid.UUID) error
+	GetTransferLimits(ctx context.Context, actor *domain.Principal, userID uuid.UUID) (*domain.UserTransferLimits, error)
+	SetTransferLimits(ctx context.Context, actor *domain.Principal, userID uuid.UUID, in *domain.SetTransferLimitsInput) (*domain.UserTransferLimits, error)
+	ListRiskReviews(ctx context.Context, actor *domain.Principal, status domain.RiskReviewStatus) ([]*domain.RiskReview, error)
+	ApproveRiskReview(ctx context.Context, actor *domain.Principal, id uuid.UUID, note string) (*domain.RiskReview, *domain.TransactionInfo, error)
+	RejectRiskReview(ctx context.Context, actor *domain.Principal, id uuid.UUID, note string) (*domain.RiskReview, error)
 }
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+
+	"banking-platform/internal/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// jwksMaxAge is how long verifiers may cache the key set. A retired key must stay published for at
+// least this long after its last token expired.
+const jwksMaxAge = "public, max-age=300"
+
+type JWKSHandler struct {
+	keys KeyPublisher
+}
+
+func NewJWKSHandler(keys KeyPublisher) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+	}
+}
+
+// Get serves the public keys access tokens are signed with. With HS256 the set is empty.
+func (h *JWKSHandler) Get(c *gin.Context) {
+	c.Header("Cache-Control", jwksMaxAge)
+	respondWithJSON(c, http.StatusOK, &dto.JWKSResponse{Keys: h.keys.PublicJWKs()})
+}
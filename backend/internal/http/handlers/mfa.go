@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+
+	"banking-platform/internal/http/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaService MFAService
+}
+
+func NewMFAHandler(mfaService MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+func (h *MFAHandler) Status(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.mfaService.Status(c.Request.Context(), principal)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, &dto.MFAStatusResponse{
+		Enabled:                status.Enabled,
+		EnabledAt:              status.EnabledAt,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	})
+}
+
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.mfaService.Enroll(c.Request.Context(), principal)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, &dto.MFAEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+	})
+}
+
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	codes, err := h.mfaService.Confirm(c.Request.Context(), principal, req.Code)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, &dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) Disable(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MFADisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	if err := h.mfaService.Disable(c.Request.Context(), principal, req.Code, req.RecoveryCode); err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	principal, ok := principalFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(c.Request.Context(), principal, req.Code)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, &dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
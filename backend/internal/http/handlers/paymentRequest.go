@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"banking-platform/internal/domain"
+	"banking-platform/internal/http/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PaymentRequestHandler struct {
+	paymentRequestService PaymentRequestService
+}
+
+func NewPaymentRequestHandler(paymentRequestService PaymentRequestService) *PaymentRequestHandler {
+	return &PaymentRequestHandler{
+		paymentRequestService: paymentRequestService,
+	}
+}
+
+func (h *PaymentRequestHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.CreatePaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	pr, err := h.paymentRequestService.Create(ctx, userUUID, &domain.CreatePaymentRequestInput{
+		PayerEmail:  req.PayerEmail,
+		Currency:    req.Currency,
+		AmountCents: req.AmountCents,
+		Memo:        req.Memo,
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusCreated, paymentRequestResponse(pr))
+}
+
+func (h *PaymentRequestHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var filter dto.PaymentRequestFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	items, err := h.paymentRequestService.List(ctx, userUUID, &domain.PaymentRequestFilter{
+		Direction: filter.Direction,
+		Status:    filter.Status,
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	out := make([]*dto.PaymentRequestResponse, len(items))
+	for i, pr := range items {
+		out[i] = paymentRequestResponse(pr)
+	}
+	respondWithJSON(c, http.StatusOK, out)
+}
+
+// Accept pays the request and returns it with the transfer booked for it.
+func (h *PaymentRequestHandler) Accept(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid payment request ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.AcceptPaymentRequestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondWithBindError(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	pr, transaction, err := h.paymentRequestService.Accept(ctx, userUUID, &domain.AcceptPaymentRequestInput{
+		RequestID:     id,
+		FromAccountID: req.FromAccountID,
+		MFACode:       req.MFACode,
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusCreated, &dto.PaymentRequestAcceptResponse{
+		PaymentRequest: paymentRequestResponse(pr),
+		Transaction:    transactionResponse(transaction),
+	})
+}
+
+func (h *PaymentRequestHandler) Decline(c *gin.Context) {
+	h.close(c, h.paymentRequestService.Decline)
+}
+
+func (h *PaymentRequestHandler) Cancel(c *gin.Context) {
+	h.close(c, h.paymentRequestService.Cancel)
+}
+
+// close runs a decline or cancel for the caller and the request in the path.
+func (h *PaymentRequestHandler) close(c *gin.Context, fn func(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.PaymentRequest, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid payment request ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	pr, err := fn(ctx, userUUID, id)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, paymentRequestResponse(pr))
+}
+
+func paymentRequestResponse(pr *domain.PaymentRequest) *dto.PaymentRequestResponse {
+	return &dto.PaymentRequestResponse{
+		ID:             pr.ID,
+		RequesterID:    pr.RequesterID,
+		RequesterEmail: pr.RequesterEmail,
+		PayerID:        pr.PayerID,
+		PayerEmail:     pr.PayerEmail,
+		Currency:       pr.Currency,
+		AmountCents:    pr.AmountCents,
+		Memo:           pr.Memo,
+		Status:         pr.Status,
+		TransactionID:  pr.TransactionID,
+		ReviewID:       pr.ReviewID,
+		ExpiresAt:      pr.ExpiresAt,
+		CreatedAt:      pr.CreatedAt,
+		UpdatedAt:      pr.UpdatedAt,
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"banking-platform/internal/apperr"
 	"github.com/gin-gonic/gin"
@@ -35,6 +38,41 @@ func respondWithServiceError(c *gin.Context, err error) {
 		return
 	}
 
+	var limited *apperr.TransferLimitError
+	if errors.As(err, &limited) && limited != nil {
+		slog.Default().Warn(
+			"request failed",
+			"method", c.Request.Method,
+			"path", c.FullPath(),
+			"error", err,
+		)
+		c.JSON(http.StatusUnprocessableEntity, transferLimitErrorResponse{
+			Error:    apperr.ErrTransferLimitExceeded.Error(),
+			Limit:    limited.Limit,
+			Currency: limited.Currency,
+			Max:      limited.Max,
+			ResetsAt: limited.ResetsAt,
+		})
+		return
+	}
+
+	var held *apperr.HeldForReviewError
+	if errors.As(err, &held) && held != nil {
+		slog.Default().Info(
+			"request held for review",
+			"method", c.Request.Method,
+			"path", c.FullPath(),
+			"review_id", held.ReviewID,
+		)
+		c.JSON(http.StatusAccepted, gin.H{"status": "held_for_review", "review_id": held.ReviewID})
+		return
+	}
+
+	var throttled *apperr.ThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	}
+
 	cause := apperr.RootCause(err)
 
 	isClientError :=
@@ -50,7 +88,41 @@ func respondWithServiceError(c *gin.Context, err error) {
 			errors.Is(cause, apperr.ErrInvalidCurrency) ||
 			errors.Is(cause, apperr.ErrCurrenciesMustDiffer) ||
 			errors.Is(cause, apperr.ErrCannotTransferToSelf) ||
-			errors.Is(cause, apperr.ErrLiquidityUnavailable)
+			errors.Is(cause, apperr.ErrLiquidityUnavailable) ||
+			errors.Is(cause, apperr.ErrRateUnavailable) ||
+			errors.Is(cause, apperr.ErrIdempotencyKeyConflict) ||
+			errors.Is(cause, apperr.ErrQuoteNotFound) ||
+			errors.Is(cause, apperr.ErrQuoteExpired) ||
+			errors.Is(cause, apperr.ErrQuoteAlreadyUsed) ||
+			errors.Is(cause, apperr.ErrAccountFrozen) ||
+			errors.Is(cause, apperr.ErrAccountClosed) ||
+			errors.Is(cause, apperr.ErrAccountBalanceNotZero) ||
+			errors.Is(cause, apperr.ErrTransactionNotReversible) ||
+			errors.Is(cause, apperr.ErrTransactionAlreadyReversed) ||
+			errors.Is(cause, apperr.ErrReversalExceedsRemaining) ||
+			errors.Is(cause, apperr.ErrBankTransferNotReversible) ||
+			errors.Is(cause, apperr.ErrScheduledTransferNotFound) ||
+			errors.Is(cause, apperr.ErrScheduledTransferNotCancellable) ||
+			errors.Is(cause, apperr.ErrSessionNotFound) ||
+			errors.Is(cause, apperr.ErrMFAAlreadyEnabled) ||
+			errors.Is(cause, apperr.ErrMFANotEnabled) ||
+			errors.Is(cause, apperr.ErrInvalidMFACode) ||
+			errors.Is(cause, apperr.ErrInvalidMFAChallenge) ||
+			errors.Is(cause, apperr.ErrMFACodeRequired) ||
+			errors.Is(cause, apperr.ErrMFAEnrollmentRequired) ||
+			errors.Is(cause, apperr.ErrInvalidAccountToken) ||
+			errors.Is(cause, apperr.ErrEmailAlreadyVerified) ||
+			errors.Is(cause, apperr.ErrIncorrectPassword) ||
+			errors.Is(cause, apperr.ErrPasswordUnchanged) ||
+			errors.Is(cause, apperr.ErrLoginThrottled) ||
+			errors.Is(cause, apperr.ErrWeakPassword) ||
+			errors.Is(cause, apperr.ErrTransferLimitExceeded) ||
+			errors.Is(cause, apperr.ErrTransactionDeclined) ||
+			errors.Is(cause, apperr.ErrRiskReviewNotFound) ||
+			errors.Is(cause, apperr.ErrRiskReviewNotPending) ||
+			errors.Is(cause, apperr.ErrAuthorizationNotPending) ||
+			errors.Is(cause, apperr.ErrPaymentRequestNotFound) ||
+			errors.Is(cause, apperr.ErrPaymentRequestNotPending)
 
 	if isClientError {
 		slog.Default().Warn(
@@ -84,7 +156,7 @@ func respondWithServiceError(c *gin.Context, err error) {
 	case errors.Is(cause, apperr.ErrTransactionNotFound):
 		respondWithError(c, apperr.ErrTransactionNotFound.Error(), http.StatusNotFound)
 	case errors.Is(cause, apperr.ErrUserNotFound):
-		if c.FullPath() == "/transactions/transfer" {
+		if c.FullPath() == "/transactions/transfer" || c.FullPath() == "/scheduled-transfers" {
 			respondWithError(c, "recipient not found", http.StatusBadRequest)
 			return
 		}
@@ -101,11 +173,88 @@ func respondWithServiceError(c *gin.Context, err error) {
 		respondWithError(c, apperr.ErrCannotTransferToSelf.Error(), http.StatusBadRequest)
 	case errors.Is(cause, apperr.ErrLiquidityUnavailable):
 		respondWithError(c, apperr.ErrLiquidityUnavailable.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrRateUnavailable):
+		respondWithError(c, apperr.ErrRateUnavailable.Error(), http.StatusServiceUnavailable)
+	case errors.Is(cause, apperr.ErrIdempotencyKeyConflict):
+		respondWithError(c, apperr.ErrIdempotencyKeyConflict.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrQuoteNotFound):
+		respondWithError(c, apperr.ErrQuoteNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, apperr.ErrQuoteExpired):
+		respondWithError(c, apperr.ErrQuoteExpired.Error(), http.StatusGone)
+	case errors.Is(cause, apperr.ErrQuoteAlreadyUsed):
+		respondWithError(c, apperr.ErrQuoteAlreadyUsed.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrAccountFrozen):
+		respondWithError(c, apperr.ErrAccountFrozen.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrAccountClosed):
+		respondWithError(c, apperr.ErrAccountClosed.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrAccountBalanceNotZero):
+		respondWithError(c, apperr.ErrAccountBalanceNotZero.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrTransactionNotReversible):
+		respondWithError(c, apperr.ErrTransactionNotReversible.Error(), http.StatusBadRequest)
+	case errors.Is(cause, apperr.ErrTransactionAlreadyReversed):
+		respondWithError(c, apperr.ErrTransactionAlreadyReversed.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrReversalExceedsRemaining):
+		respondWithError(c, apperr.ErrReversalExceedsRemaining.Error(), http.StatusBadRequest)
+	case errors.Is(cause, apperr.ErrBankTransferNotReversible):
+		respondWithError(c, apperr.ErrBankTransferNotReversible.Error(), http.StatusForbidden)
+	case errors.Is(cause, apperr.ErrScheduledTransferNotFound):
+		respondWithError(c, apperr.ErrScheduledTransferNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, apperr.ErrScheduledTransferNotCancellable):
+		respondWithError(c, apperr.ErrScheduledTransferNotCancellable.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrSessionNotFound):
+		respondWithError(c, apperr.ErrSessionNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, apperr.ErrMFAAlreadyEnabled):
+		respondWithError(c, apperr.ErrMFAAlreadyEnabled.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrMFANotEnabled):
+		respondWithError(c, apperr.ErrMFANotEnabled.Error(), http.StatusBadRequest)
+	case errors.Is(cause, apperr.ErrInvalidMFACode):
+		respondWithError(c, apperr.ErrInvalidMFACode.Error(), http.StatusUnauthorized)
+	case errors.Is(cause, apperr.ErrInvalidMFAChallenge):
+		respondWithError(c, apperr.ErrInvalidMFAChallenge.Error(), http.StatusUnauthorized)
+	case errors.Is(cause, apperr.ErrMFACodeRequired):
+		respondWithError(c, apperr.ErrMFACodeRequired.Error(), http.StatusForbidden)
+	case errors.Is(cause, apperr.ErrMFAEnrollmentRequired):
+		respondWithError(c, apperr.ErrMFAEnrollmentRequired.Error(), http.StatusForbidden)
+	case errors.Is(cause, apperr.ErrInvalidAccountToken):
+		respondWithError(c, apperr.ErrInvalidAccountToken.Error(), http.StatusBadRequest)
+	case errors.Is(cause, apperr.ErrEmailAlreadyVerified):
+		respondWithError(c, apperr.ErrEmailAlreadyVerified.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrIncorrectPassword):
+		respondWithError(c, apperr.ErrIncorrectPassword.Error(), http.StatusForbidden)
+	case errors.Is(cause, apperr.ErrPasswordUnchanged):
+		respondWithError(c, apperr.ErrPasswordUnchanged.Error(), http.StatusBadRequest)
+	case errors.Is(cause, apperr.ErrLoginThrottled):
+		respondWithError(c, apperr.ErrLoginThrottled.Error(), http.StatusTooManyRequests)
+	case errors.Is(cause, apperr.ErrWeakPassword):
+		respondWithError(c, apperr.ErrWeakPassword.Error(), http.StatusBadRequest)
+	case errors.Is(cause, apperr.ErrTransferLimitExceeded):
+		respondWithError(c, apperr.ErrTransferLimitExceeded.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(cause, apperr.ErrTransactionDeclined):
+		respondWithError(c, apperr.ErrTransactionDeclined.Error(), http.StatusForbidden)
+	case errors.Is(cause, apperr.ErrRiskReviewNotFound):
+		respondWithError(c, apperr.ErrRiskReviewNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, apperr.ErrRiskReviewNotPending):
+		respondWithError(c, apperr.ErrRiskReviewNotPending.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrAuthorizationNotPending):
+		respondWithError(c, apperr.ErrAuthorizationNotPending.Error(), http.StatusConflict)
+	case errors.Is(cause, apperr.ErrPaymentRequestNotFound):
+		respondWithError(c, apperr.ErrPaymentRequestNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, apperr.ErrPaymentRequestNotPending):
+		respondWithError(c, apperr.ErrPaymentRequestNotPending.Error(), http.StatusConflict)
 	default:
 		respondWithError(c, "internal_error", http.StatusInternalServerError)
 	}
 }
 
+// transferLimitErrorResponse tells the client which limit a transfer hit and when it resets.
+type transferLimitErrorResponse struct {
+	Error    string     `json:"error"`
+	Limit    string     `json:"limit"`
+	Currency string     `json:"currency"`
+	Max      int64      `json:"max"`
+	ResetsAt *time.Time `json:"resets_at,omitempty"`
+}
+
 type validationFieldError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
@@ -191,4 +340,3 @@ func toSnakeCase(s string) string {
 	}
 	return b.String()
 }
-
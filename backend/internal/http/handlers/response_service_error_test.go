@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"banking-platform/internal/apperr"
 	"github.com/gin-gonic/gin"
@@ -40,6 +41,7 @@ func TestRespondWithServiceError(t *testing.T) {
 
 		{name: "user_not_found_normal", fullPath: "/users/me", err: apperr.ErrUserNotFound, wantCode: http.StatusNotFound, wantError: apperr.ErrUserNotFound.Error()},
 		{name: "user_not_found_transfer_is_400", fullPath: "/transactions/transfer", err: apperr.ErrUserNotFound, wantCode: http.StatusBadRequest, wantError: "recipient not found"},
+		{name: "user_not_found_scheduled_transfer_is_400", fullPath: "/scheduled-transfers", err: apperr.ErrUserNotFound, wantCode: http.StatusBadRequest, wantError: "recipient not found"},
 
 		{name: "insufficient_funds", fullPath: "/x", err: apperr.ErrInsufficientFunds, wantCode: http.StatusBadRequest, wantError: apperr.ErrInsufficientFunds.Error()},
 		{name: "invalid_amount", fullPath: "/x", err: apperr.ErrInvalidAmount, wantCode: http.StatusBadRequest, wantError: apperr.ErrInvalidAmount.Error()},
@@ -47,6 +49,40 @@ func TestRespondWithServiceError(t *testing.T) {
 		{name: "currencies_must_differ", fullPath: "/x", err: apperr.ErrCurrenciesMustDiffer, wantCode: http.StatusBadRequest, wantError: apperr.ErrCurrenciesMustDiffer.Error()},
 		{name: "cannot_transfer_to_self", fullPath: "/x", err: apperr.ErrCannotTransferToSelf, wantCode: http.StatusBadRequest, wantError: apperr.ErrCannotTransferToSelf.Error()},
 		{name: "liquidity_unavailable_conflict", fullPath: "/x", err: apperr.ErrLiquidityUnavailable, wantCode: http.StatusConflict, wantError: apperr.ErrLiquidityUnavailable.Error()},
+		{name: "rate_unavailable", fullPath: "/x", err: apperr.ErrRateUnavailable, wantCode: http.StatusServiceUnavailable, wantError: apperr.ErrRateUnavailable.Error()},
+		{name: "idempotency_key_conflict", fullPath: "/x", err: apperr.ErrIdempotencyKeyConflict, wantCode: http.StatusConflict, wantError: apperr.ErrIdempotencyKeyConflict.Error()},
+		{name: "quote_not_found", fullPath: "/x", err: apperr.ErrQuoteNotFound, wantCode: http.StatusNotFound, wantError: apperr.ErrQuoteNotFound.Error()},
+		{name: "quote_expired_gone", fullPath: "/x", err: apperr.ErrQuoteExpired, wantCode: http.StatusGone, wantError: apperr.ErrQuoteExpired.Error()},
+		{name: "quote_already_used_conflict", fullPath: "/x", err: apperr.ErrQuoteAlreadyUsed, wantCode: http.StatusConflict, wantError: apperr.ErrQuoteAlreadyUsed.Error()},
+		{name: "account_frozen_conflict", fullPath: "/x", err: apperr.ErrAccountFrozen, wantCode: http.StatusConflict, wantError: apperr.ErrAccountFrozen.Error()},
+		{name: "account_closed_conflict", fullPath: "/x", err: apperr.ErrAccountClosed, wantCode: http.StatusConflict, wantError: apperr.ErrAccountClosed.Error()},
+		{name: "account_balance_not_zero_conflict", fullPath: "/x", err: apperr.ErrAccountBalanceNotZero, wantCode: http.StatusConflict, wantError: apperr.ErrAccountBalanceNotZero.Error()},
+		{name: "transaction_not_reversible", fullPath: "/x", err: apperr.ErrTransactionNotReversible, wantCode: http.StatusBadRequest, wantError: apperr.ErrTransactionNotReversible.Error()},
+		{name: "transaction_already_reversed_conflict", fullPath: "/x", err: apperr.ErrTransactionAlreadyReversed, wantCode: http.StatusConflict, wantError: apperr.ErrTransactionAlreadyReversed.Error()},
+		{name: "bank_transfer_not_reversible_forbidden", fullPath: "/x", err: apperr.ErrBankTransferNotReversible, wantCode: http.StatusForbidden, wantError: apperr.ErrBankTransferNotReversible.Error()},
+		{name: "reversal_exceeds_remaining", fullPath: "/x", err: apperr.ErrReversalExceedsRemaining, wantCode: http.StatusBadRequest, wantError: apperr.ErrReversalExceedsRemaining.Error()},
+		{name: "scheduled_transfer_not_found", fullPath: "/x", err: apperr.ErrScheduledTransferNotFound, wantCode: http.StatusNotFound, wantError: apperr.ErrScheduledTransferNotFound.Error()},
+		{name: "scheduled_transfer_not_cancellable_conflict", fullPath: "/x", err: apperr.ErrScheduledTransferNotCancellable, wantCode: http.StatusConflict, wantError: apperr.ErrScheduledTransferNotCancellable.Error()},
+		{name: "session_not_found", fullPath: "/x", err: apperr.ErrSessionNotFound, wantCode: http.StatusNotFound, wantError: apperr.ErrSessionNotFound.Error()},
+		{name: "mfa_already_enabled", fullPath: "/x", err: apperr.ErrMFAAlreadyEnabled, wantCode: http.StatusConflict, wantError: apperr.ErrMFAAlreadyEnabled.Error()},
+		{name: "mfa_not_enabled", fullPath: "/x", err: apperr.ErrMFANotEnabled, wantCode: http.StatusBadRequest, wantError: apperr.ErrMFANotEnabled.Error()},
+		{name: "invalid_mfa_code", fullPath: "/x", err: apperr.ErrInvalidMFACode, wantCode: http.StatusUnauthorized, wantError: apperr.ErrInvalidMFACode.Error()},
+		{name: "invalid_mfa_challenge", fullPath: "/x", err: apperr.ErrInvalidMFAChallenge, wantCode: http.StatusUnauthorized, wantError: apperr.ErrInvalidMFAChallenge.Error()},
+		{name: "mfa_code_required", fullPath: "/x", err: apperr.ErrMFACodeRequired, wantCode: http.StatusForbidden, wantError: apperr.ErrMFACodeRequired.Error()},
+		{name: "mfa_enrollment_required", fullPath: "/x", err: apperr.ErrMFAEnrollmentRequired, wantCode: http.StatusForbidden, wantError: apperr.ErrMFAEnrollmentRequired.Error()},
+		{name: "invalid_account_token", fullPath: "/x", err: apperr.ErrInvalidAccountToken, wantCode: http.StatusBadRequest, wantError: apperr.ErrInvalidAccountToken.Error()},
+		{name: "email_already_verified", fullPath: "/x", err: apperr.ErrEmailAlreadyVerified, wantCode: http.StatusConflict, wantError: apperr.ErrEmailAlreadyVerified.Error()},
+		{name: "incorrect_password", fullPath: "/x", err: apperr.ErrIncorrectPassword, wantCode: http.StatusForbidden, wantError: apperr.ErrIncorrectPassword.Error()},
+		{name: "password_unchanged", fullPath: "/x", err: apperr.ErrPasswordUnchanged, wantCode: http.StatusBadRequest, wantError: apperr.ErrPasswordUnchanged.Error()},
+		{name: "login_throttled", fullPath: "/x", err: apperr.ErrLoginThrottled, wantCode: http.StatusTooManyRequests, wantError: apperr.ErrLoginThrottled.Error()},
+		{name: "weak_password", fullPath: "/x", err: apperr.ErrWeakPassword, wantCode: http.StatusBadRequest, wantError: apperr.ErrWeakPassword.Error()},
+		{name: "transfer_limit_exceeded", fullPath: "/x", err: apperr.ErrTransferLimitExceeded, wantCode: http.StatusUnprocessableEntity, wantError: apperr.ErrTransferLimitExceeded.Error()},
+		{name: "transaction_declined", fullPath: "/x", err: apperr.ErrTransactionDeclined, wantCode: http.StatusForbidden, wantError: apperr.ErrTransactionDeclined.Error()},
+		{name: "risk_review_not_found", fullPath: "/x", err: apperr.ErrRiskReviewNotFound, wantCode: http.StatusNotFound, wantError: apperr.ErrRiskReviewNotFound.Error()},
+		{name: "risk_review_not_pending", fullPath: "/x", err: apperr.ErrRiskReviewNotPending, wantCode: http.StatusConflict, wantError: apperr.ErrRiskReviewNotPending.Error()},
+		{name: "authorization_not_pending", fullPath: "/x", err: apperr.ErrAuthorizationNotPending, wantCode: http.StatusConflict, wantError: apperr.ErrAuthorizationNotPending.Error()},
+		{name: "payment_request_not_found", fullPath: "/x", err: apperr.ErrPaymentRequestNotFound, wantCode: http.StatusNotFound, wantError: apperr.ErrPaymentRequestNotFound.Error()},
+		{name: "payment_request_not_pending", fullPath: "/x", err: apperr.ErrPaymentRequestNotPending, wantCode: http.StatusConflict, wantError: apperr.ErrPaymentRequestNotPending.Error()},
 
 		{name: "unknown_internal", fullPath: "/x", err: errors.New("boom"), wantCode: http.StatusInternalServerError, wantError: "internal_error"},
 		{name: "wrapped_unknown_internal", fullPath: "/x", err: fmt.Errorf("op: %w", errors.New("boom")), wantCode: http.StatusInternalServerError, wantError: "internal_error"},
@@ -78,3 +114,76 @@ func TestRespondWithServiceError(t *testing.T) {
 	}
 }
 
+func TestRespondWithServiceErrorRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+
+	router := gin.New()
+	router.GET("/x", func(c *gin.Context) {
+		respondWithServiceError(c, fmt.Errorf("op: %w", &apperr.ThrottledError{RetryAfter: 1500 * time.Millisecond}))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status=%d want=%d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("Retry-After=%q want=%q", got, "2")
+	}
+}
+
+func TestRespondWithServiceErrorTransferLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+
+	resetsAt := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	router := gin.New()
+	router.GET("/x", func(c *gin.Context) {
+		respondWithServiceError(c, fmt.Errorf("op: %w", &apperr.TransferLimitError{
+			Limit: "daily", Currency: "USD", Max: 2_500_000, ResetsAt: &resetsAt,
+		}))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status=%d want=%d", w.Code, http.StatusUnprocessableEntity)
+	}
+	var body transferLimitErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Error != apperr.ErrTransferLimitExceeded.Error() || body.Limit != "daily" || body.Currency != "USD" || body.Max != 2_500_000 {
+		t.Fatalf("body=%+v", body)
+	}
+	if body.ResetsAt == nil || !body.ResetsAt.Equal(resetsAt) {
+		t.Fatalf("resets_at=%v want=%v", body.ResetsAt, resetsAt)
+	}
+}
+
+func TestRespondWithServiceErrorHeldForReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{})))
+
+	router := gin.New()
+	router.POST("/x", func(c *gin.Context) {
+		respondWithServiceError(c, fmt.Errorf("op: %w", &apperr.HeldForReviewError{ReviewID: "r-1"}))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/x", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status=%d want=%d", w.Code, http.StatusAccepted)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["status"] != "held_for_review" || body["review_id"] != "r-1" {
+		t.Fatalf("body=%v", body)
+	}
+}
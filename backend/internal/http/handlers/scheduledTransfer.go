@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"banking-platform/internal/domain"
+	"banking-platform/internal/http/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ScheduledTransferHandler struct {
+	scheduledTransferService ScheduledTransferService
+}
+
+func NewScheduledTransferHandler(scheduledTransferService ScheduledTransferService) *ScheduledTransferHandler {
+	return &ScheduledTransferHandler{
+		scheduledTransferService: scheduledTransferService,
+	}
+}
+
+func (h *ScheduledTransferHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.CreateScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+	if countSet(req.ToUserID != nil, req.ToUserEmail != nil, req.ToAccountID != nil) != 1 {
+		respondWithError(c, "provide exactly one of to_user_id, to_user_email or to_account_id", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	st, err := h.scheduledTransferService.Create(ctx, userUUID, &domain.CreateScheduledTransferInput{
+		FromAccountID: req.FromAccountID,
+		ToUserID:      req.ToUserID,
+		ToUserEmail:   req.ToUserEmail,
+		ToAccountID:   req.ToAccountID,
+		Currency:      req.Currency,
+		AmountCents:   req.AmountCents,
+		Description:   req.Description,
+		Schedule: domain.Schedule{
+			Kind:       req.Kind,
+			StartAt:    req.StartAt.UTC(),
+			DayOfMonth: req.DayOfMonth,
+			EndAt:      utcPtr(req.EndAt),
+		},
+		MFACode: req.MFACode,
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusCreated, scheduledTransferResponse(st))
+}
+
+func (h *ScheduledTransferHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	items, err := h.scheduledTransferService.List(ctx, userUUID)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	out := make([]*dto.ScheduledTransferResponse, len(items))
+	for i, st := range items {
+		out[i] = scheduledTransferResponse(st)
+	}
+	respondWithJSON(c, http.StatusOK, out)
+}
+
+func (h *ScheduledTransferHandler) Cancel(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid scheduled transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	st, err := h.scheduledTransferService.Cancel(ctx, userUUID, id)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, scheduledTransferResponse(st))
+}
+
+func scheduledTransferResponse(st *domain.ScheduledTransfer) *dto.ScheduledTransferResponse {
+	resp := &dto.ScheduledTransferResponse{
+		ID:                st.ID,
+		FromAccountID:     st.FromAccountID,
+		ToUserID:          st.ToUserID,
+		ToAccountID:       st.ToAccountID,
+		Currency:          st.Currency,
+		AmountCents:       st.AmountCents,
+		Description:       st.Description,
+		Kind:              st.Schedule.Kind,
+		StartAt:           st.Schedule.StartAt,
+		DayOfMonth:        st.Schedule.DayOfMonth,
+		EndAt:             st.Schedule.EndAt,
+		Status:            st.Status,
+		Attempts:          st.Attempts,
+		LastError:         st.LastError,
+		LastTransactionID: st.LastTransactionID,
+		CreatedAt:         st.CreatedAt,
+		UpdatedAt:         st.UpdatedAt,
+	}
+	// Only active orders have an upcoming run; a pending retry is reported as its attempt time.
+	if st.Status == domain.ScheduledTransferActive {
+		next := st.NextAttemptAt
+		resp.NextRunAt = &next
+	}
+	return resp
+}
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC()
+	return &v
+}
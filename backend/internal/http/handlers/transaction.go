@@ -1,13 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"banking-platform/internal/domain"
 	"banking-platform/internal/http/dto"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
@@ -39,8 +40,9 @@ func (h *TransactionHandler) Transfer(c *gin.Context) {
 		respondWithBindError(c, err)
 		return
 	}
-	if (req.ToUserID == nil) == (req.ToUserEmail == nil) {
-		respondWithJSON(c, http.StatusBadRequest, gin.H{"error": "validation_error", "fields": []validationFieldError{{Field: "to_user_id", Message: "provide either to_user_id or to_user_email"}, {Field: "to_user_email", Message: "provide either to_user_id or to_user_email"}}})
+	if countSet(req.ToUserID != nil, req.ToUserEmail != nil, req.ToAccountID != nil) != 1 {
+		const msg = "provide exactly one of to_user_id, to_user_email or to_account_id"
+		respondWithJSON(c, http.StatusBadRequest, gin.H{"error": "validation_error", "fields": []validationFieldError{{Field: "to_user_id", Message: msg}, {Field: "to_user_email", Message: msg}, {Field: "to_account_id", Message: msg}}})
 		return
 	}
 	if req.ToUserEmail != nil && strings.TrimSpace(*req.ToUserEmail) == "" {
@@ -50,30 +52,23 @@ func (h *TransactionHandler) Transfer(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	transaction, err := h.transactionService.Transfer(ctx, userUUID, &domain.TransferInput{
-		ToUserID:    req.ToUserID,
-		ToUserEmail: req.ToUserEmail,
-		Currency:    req.Currency,
-		AmountCents: req.AmountCents,
+		ToUserID:      req.ToUserID,
+		ToUserEmail:   req.ToUserEmail,
+		ToAccountID:   req.ToAccountID,
+		FromAccountID: req.FromAccountID,
+		Currency:      req.Currency,
+		AmountCents:   req.AmountCents,
+
+		IdempotencyKey: idempotencyKey(c),
+		MFACode:        req.MFACode,
+		Authorize:      req.Authorize,
 	})
 	if err != nil {
 		respondWithServiceError(c, err)
 		return
 	}
 
-	respondWithJSON(c, http.StatusCreated, &dto.TransactionResponse{
-		ID:                   transaction.ID,
-		Type:                 transaction.Type,
-		FromAccountID:        transaction.FromAccountID,
-		ToAccountID:          transaction.ToAccountID,
-		AmountCents:          transaction.AmountCents,
-		Currency:             transaction.Currency,
-		ExchangeRate:         transaction.ExchangeRate,
-		ConvertedAmountCents: transaction.ConvertedAmountCents,
-		Description:          transaction.Description,
-		CreatedAt:            transaction.CreatedAt,
-		FromUserEmail:        transaction.FromUserEmail,
-		ToUserEmail:          transaction.ToUserEmail,
-	})
+	respondWithJSON(c, http.StatusCreated, transactionResponse(transaction))
 }
 
 func (h *TransactionHandler) Exchange(c *gin.Context) {
@@ -100,26 +95,91 @@ func (h *TransactionHandler) Exchange(c *gin.Context) {
 		FromCurrency: req.FromCurrency,
 		ToCurrency:   req.ToCurrency,
 		AmountCents:  req.AmountCents,
+
+		IdempotencyKey: idempotencyKey(c),
 	})
 	if err != nil {
 		respondWithServiceError(c, err)
 		return
 	}
 
-	respondWithJSON(c, http.StatusCreated, &dto.TransactionResponse{
-		ID:                   transaction.ID,
-		Type:                 transaction.Type,
-		FromAccountID:        transaction.FromAccountID,
-		ToAccountID:          transaction.ToAccountID,
-		AmountCents:          transaction.AmountCents,
-		Currency:             transaction.Currency,
-		ExchangeRate:         transaction.ExchangeRate,
-		ConvertedAmountCents: transaction.ConvertedAmountCents,
-		Description:          transaction.Description,
-		CreatedAt:            transaction.CreatedAt,
-		FromUserEmail:        transaction.FromUserEmail,
-		ToUserEmail:          transaction.ToUserEmail,
+	respondWithJSON(c, http.StatusCreated, transactionResponse(transaction))
+}
+
+func (h *TransactionHandler) QuoteExchange(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.ExchangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	quote, err := h.transactionService.QuoteExchange(ctx, userUUID, &domain.ExchangeInput{
+		FromCurrency: req.FromCurrency,
+		ToCurrency:   req.ToCurrency,
+		AmountCents:  req.AmountCents,
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusCreated, &dto.ExchangeQuoteResponse{
+		ID:                   quote.ID,
+		FromCurrency:         quote.FromCurrency,
+		ToCurrency:           quote.ToCurrency,
+		AmountCents:          quote.AmountCents,
+		ConvertedAmountCents: quote.ConvertedAmountCents,
+		Rate:                 float64(quote.RateNum) / float64(quote.RateDen),
+		SpreadBps:            quote.SpreadBps,
+		RateSource:           quote.RateSource,
+		RateVersion:          quote.RateVersion,
+		ExpiresAt:            quote.ExpiresAt,
+	})
+}
+
+func (h *TransactionHandler) ExecuteQuote(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	quoteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid quote ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	transaction, err := h.transactionService.ExecuteQuote(ctx, userUUID, &domain.ExecuteQuoteInput{
+		QuoteID:        quoteID,
+		IdempotencyKey: idempotencyKey(c),
 	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusCreated, transactionResponse(transaction))
 }
 
 func (h *TransactionHandler) GetTransactions(c *gin.Context) {
@@ -135,49 +195,260 @@ func (h *TransactionHandler) GetTransactions(c *gin.Context) {
 		return
 	}
 
-	filter := &dto.TransactionFilter{
-		Type: domain.TransactionType(c.Query("type")),
+	var filter dto.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			respondWithBindError(c, err)
+			return
+		}
+		respondWithError(c, "invalid query parameters", http.StatusBadRequest)
+		return
 	}
 
-	pageStr := c.DefaultQuery("page", "1")
-	limitStr := c.DefaultQuery("limit", "50")
+	df := &domain.TransactionFilter{
+		Type:         filter.Type,
+		Currency:     filter.Currency,
+		MinAmount:    filter.MinAmountCents,
+		MaxAmount:    filter.MaxAmountCents,
+		Counterparty: filter.Counterparty,
+		Direction:    filter.Direction,
+		Status:       filter.Status,
+		Cursor:       filter.Cursor,
+		Limit:        filter.Limit,
+	}
+	if filter.From != "" {
+		from, err := parseTimeParam(filter.From, false)
+		if err != nil {
+			respondWithError(c, "invalid from: use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+			return
+		}
+		df.From = &from
+	}
+	if filter.To != "" {
+		to, err := parseTimeParam(filter.To, true)
+		if err != nil {
+			respondWithError(c, "invalid to: use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+			return
+		}
+		df.To = &to
+	}
 
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
+	ctx := c.Request.Context()
+	page, err := h.transactionService.GetUserTransactions(ctx, userUUID, df)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	out := make([]*dto.TransactionResponse, 0, len(page.Items))
+	for _, t := range page.Items {
+		out = append(out, transactionResponse(t))
 	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 {
-		limit = 50
+	resp := &dto.TransactionListResponse{Items: out, TotalCount: page.TotalCount}
+	if page.NextCursor != "" {
+		resp.NextCursor = &page.NextCursor
 	}
+	respondWithJSON(c, http.StatusOK, resp)
+}
 
-	df := &domain.TransactionFilter{Type: filter.Type, Page: page, Limit: limit}
+// idempotencyKey returns the optional Idempotency-Key header of a money-moving request.
+func idempotencyKey(c *gin.Context) string {
+	return strings.TrimSpace(c.GetHeader("Idempotency-Key"))
+}
+
+func countSet(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid transaction ID", http.StatusBadRequest)
+		return
+	}
 
 	ctx := c.Request.Context()
-	transactions, err := h.transactionService.GetUserTransactions(ctx, userUUID, df)
+	detail, err := h.transactionService.GetTransactionDetail(ctx, userUUID, transactionID)
 	if err != nil {
 		respondWithServiceError(c, err)
 		return
 	}
 
-	out := make([]*dto.TransactionResponse, 0, len(transactions))
-	for _, t := range transactions {
-		out = append(out, &dto.TransactionResponse{
-			ID:                   t.ID,
-			Type:                 t.Type,
-			FromAccountID:        t.FromAccountID,
-			ToAccountID:          t.ToAccountID,
-			AmountCents:          t.AmountCents,
-			Currency:             t.Currency,
-			ExchangeRate:         t.ExchangeRate,
-			ConvertedAmountCents: t.ConvertedAmountCents,
-			Description:          t.Description,
-			CreatedAt:            t.CreatedAt,
-			FromUserEmail:        t.FromUserEmail,
-			ToUserEmail:          t.ToUserEmail,
+	postings := make([]*dto.PostingResponse, 0, len(detail.Postings))
+	for _, p := range detail.Postings {
+		postings = append(postings, &dto.PostingResponse{
+			ID:                p.EntryID,
+			AccountID:         p.AccountID,
+			AccountName:       p.AccountName,
+			OwnerName:         p.OwnerName,
+			Currency:          p.Currency,
+			AmountCents:       p.AmountCents,
+			BalanceAfterCents: p.BalanceAfterCents,
+			CreatedAt:         p.CreatedAt,
 		})
 	}
-	respondWithJSON(c, http.StatusOK, out)
+	respondWithJSON(c, http.StatusOK, &dto.TransactionDetailResponse{
+		TransactionResponse: transactionResponse(&detail.Transaction),
+		FromUserName:        detail.FromUserName,
+		ToUserName:          detail.ToUserName,
+		Postings:            postings,
+	})
 }
 
+// Reverse lets the recipient of a transfer refund it, fully or partially.
+func (h *TransactionHandler) Reverse(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.ReverseRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondWithBindError(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	transaction, err := h.transactionService.Reverse(ctx, userUUID, &domain.ReverseInput{
+		TransactionID: transactionID,
+		AmountCents:   req.AmountCents,
+		Reason:        req.Reason,
+
+		IdempotencyKey: idempotencyKey(c),
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusCreated, transactionResponse(transaction))
+}
+
+func (h *TransactionHandler) Capture(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req dto.CaptureRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondWithBindError(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	transaction, err := h.transactionService.Capture(ctx, userUUID, &domain.CaptureInput{
+		TransactionID: transactionID,
+		AmountCents:   req.AmountCents,
+	})
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, transactionResponse(transaction))
+}
+
+func (h *TransactionHandler) Release(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		respondWithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondWithError(c, "invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := c.Request.Context()
+	transaction, err := h.transactionService.Release(ctx, userUUID, transactionID)
+	if err != nil {
+		respondWithServiceError(c, err)
+		return
+	}
+
+	respondWithJSON(c, http.StatusOK, transactionResponse(transaction))
+}
+
+func transactionResponse(t *domain.TransactionInfo) *dto.TransactionResponse {
+	return &dto.TransactionResponse{
+		ID:                    t.ID,
+		Type:                  t.Type,
+		FromAccountID:         t.FromAccountID,
+		ToAccountID:           t.ToAccountID,
+		AmountCents:           t.AmountCents,
+		Currency:              t.Currency,
+		ExchangeRate:          t.ExchangeRate,
+		ConvertedAmountCents:  t.ConvertedAmountCents,
+		ToCurrency:            t.ToCurrency,
+		RateSource:            t.RateSource,
+		RateVersion:           t.RateVersion,
+		QuoteID:               t.QuoteID,
+		ReversesTransactionID: t.ReversesTransactionID,
+		Description:           t.Description,
+		Status:                t.Status,
+		ExpiresAt:             t.ExpiresAt,
+		CapturedAmountCents:   t.CapturedAmountCents,
+		CreatedAt:             t.CreatedAt,
+		FromUserEmail:         t.FromUserEmail,
+		ToUserEmail:           t.ToUserEmail,
+	}
+}
@@ -1,14 +1,12 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
 
+	"banking-platform/internal/domain"
 	"github.com/gin-gonic/gin"
 )
 
-type userIDContextKey struct{}
-
 func AuthMiddleware(authService AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -26,15 +24,40 @@ func AuthMiddleware(authService AuthService) gin.HandlerFunc {
 		}
 
 		ctx := c.Request.Context()
-		userID, err := authService.ValidateToken(ctx, token)
+		principal, err := authService.ValidateToken(ctx, token)
 		if err != nil {
 			respondWithError(c, "invalid or expired token", http.StatusUnauthorized)
 			c.Abort()
 			return
 		}
 
-		c.Set("user_id", userID)
-		c.Request = c.Request.WithContext(context.WithValue(ctx, userIDContextKey{}, userID))
+		c.Set("user_id", principal.UserID)
+		c.Set("role", principal.Role)
+		c.Set("session_id", principal.SessionID)
+		c.Request = c.Request.WithContext(domain.WithPrincipal(ctx, principal))
+		c.Next()
+	}
+}
+
+// RequireRole rejects callers whose role is not listed. It must run after AuthMiddleware.
+func RequireRole(roles ...domain.Role) gin.HandlerFunc {
+	allowed := make(map[domain.Role]struct{}, len(roles))
+	for _, r := range roles {
+		allowed[r] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		v, exists := c.Get("role")
+		role, ok := v.(domain.Role)
+		if !exists || !ok {
+			respondWithError(c, "user not authenticated", http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+		if _, ok := allowed[role]; !ok {
+			respondWithError(c, "forbidden", http.StatusForbidden)
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
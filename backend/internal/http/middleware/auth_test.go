@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type stubAuthService struct {
+	principal *domain.Principal
+}
+
+func (s stubAuthService) ValidateToken(ctx context.Context, tokenString string) (*domain.Principal, error) {
+	if s.principal == nil {
+		return nil, apperr.ErrInvalidToken
+	}
+	return s.principal, nil
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		header    string
+		wantCode  int
+	}{
+		{name: "admin_allowed", principal: &domain.Principal{UserID: uuid.New(), Role: domain.RoleAdmin}, header: "Bearer t", wantCode: http.StatusOK},
+		{name: "auditor_allowed", principal: &domain.Principal{UserID: uuid.New(), Role: domain.RoleAuditor}, header: "Bearer t", wantCode: http.StatusOK},
+		{name: "customer_forbidden", principal: &domain.Principal{UserID: uuid.New(), Role: domain.RoleCustomer}, header: "Bearer t", wantCode: http.StatusForbidden},
+		{name: "support_forbidden", principal: &domain.Principal{UserID: uuid.New(), Role: domain.RoleSupport}, header: "Bearer t", wantCode: http.StatusForbidden},
+		{name: "invalid_token", principal: nil, header: "Bearer t", wantCode: http.StatusUnauthorized},
+		{name: "missing_header", principal: &domain.Principal{UserID: uuid.New(), Role: domain.RoleAdmin}, header: "", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/admin", AuthMiddleware(stubAuthService{principal: tt.principal}), RequireRole(domain.RoleAdmin, domain.RoleAuditor), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got=%d want=%d", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRequireRoleWithoutAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/admin", RequireRole(domain.RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("got=%d want=%d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -3,10 +3,15 @@ package middleware
 import (
 	"context"
 
-	"github.com/google/uuid"
+	"banking-platform/internal/domain"
 )
 
 type AuthService interface {
-	ValidateToken(ctx context.Context, tokenString string) (uuid.UUID, error)
+	ValidateToken(ctx context.Context, tokenString string) (*domain.Principal, error)
+}
+
+// RateLimiter counts a request against key. service.RateLimiter implements it.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitDecision, error)
 }
 
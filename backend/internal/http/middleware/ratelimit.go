@@ -1,109 +1,91 @@
 package middleware
 
 import (
+	"log/slog"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
+	"banking-platform/internal/domain"
 	"github.com/gin-gonic/gin"
 )
 
-type tokenBucket struct {
-	tokens   float64
-	last     time.Time
-	lastSeen time.Time
-}
-
-type ipRateLimiter struct {
-	rps   float64
-	burst float64
-
-	mu      sync.Mutex
-	buckets map[string]*tokenBucket
-}
+// RateLimitKey is what a policy counts requests by.
+type RateLimitKey string
 
-func newIPRateLimiter(limitPerSecond int, burst int) *ipRateLimiter {
-	if limitPerSecond <= 0 {
-		limitPerSecond = 1
-	}
-	if burst <= 0 {
-		burst = 1
-	}
-	rl := &ipRateLimiter{
-		rps:     float64(limitPerSecond),
-		burst:   float64(burst),
-		buckets: make(map[string]*tokenBucket),
-	}
-	go rl.cleanupLoop(10*time.Minute, 30*time.Minute)
-	return rl
-}
+const (
+	RateLimitByIP RateLimitKey = "ip"
+	// RateLimitByUser counts per authenticated user and falls back to the IP for anonymous
+	// requests. It must run after AuthMiddleware.
+	RateLimitByUser RateLimitKey = "user"
+)
 
-func (r *ipRateLimiter) cleanupLoop(every time.Duration, maxAge time.Duration) {
-	t := time.NewTicker(every)
-	defer t.Stop()
-	for now := range t.C {
-		cutoff := now.Add(-maxAge)
-		r.mu.Lock()
-		for k, b := range r.buckets {
-			if b.lastSeen.Before(cutoff) {
-				delete(r.buckets, k)
-			}
-		}
-		r.mu.Unlock()
-	}
+// RateLimitPolicy is one named limit. Policies with different names have separate budgets, so a
+// route can be under a general policy and a stricter one for its group.
+type RateLimitPolicy struct {
+	Name  string
+	By    RateLimitKey
+	Limit domain.RateLimit
 }
 
-func (r *ipRateLimiter) allow(key string) bool {
-	now := time.Now()
-
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	b, ok := r.buckets[key]
-	if !ok {
-		b = &tokenBucket{
-			tokens:   r.burst,
-			last:     now,
-			lastSeen: now,
-		}
-		r.buckets[key] = b
-	}
-
-	elapsed := now.Sub(b.last).Seconds()
-	if elapsed < 0 {
-		elapsed = 0
-	}
-	b.tokens += elapsed * r.rps
-	if b.tokens > r.burst {
-		b.tokens = r.burst
-	}
-	b.last = now
-	b.lastSeen = now
-
-	if b.tokens < 1 {
-		return false
-	}
-	b.tokens -= 1
-	return true
+// Enabled reports whether the policy limits anything; zero requests or burst disables it.
+func (p RateLimitPolicy) Enabled() bool {
+	return p.Limit.Requests > 0 && p.Limit.Period > 0 && p.Limit.Burst > 0
 }
 
-func RateLimitMiddleware(limitPerSecond int, burst int) gin.HandlerFunc {
-	if limitPerSecond <= 0 || burst <= 0 {
+// RateLimitMiddleware refuses requests over the policy with 429 and Retry-After. Every response
+// carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset for the most exhausted policy
+// that applied. If the limiter fails, the request is let through.
+func RateLimitMiddleware(limiter RateLimiter, policy RateLimitPolicy) gin.HandlerFunc {
+	if limiter == nil || !policy.Enabled() {
 		return func(c *gin.Context) { c.Next() }
 	}
 
-	rl := newIPRateLimiter(limitPerSecond, burst)
 	return func(c *gin.Context) {
-		key := c.ClientIP()
-		if key == "" {
-			key = "unknown"
+		key := policy.Name + ":" + rateLimitSubject(c, policy.By)
+		decision, err := limiter.Allow(c.Request.Context(), key, policy.Limit)
+		if err != nil {
+			slog.Default().Warn("Rate limiter unavailable; allowing request", "policy", policy.Name, "error", err)
+			c.Next()
+			return
 		}
-		if !rl.allow(key) {
+
+		if !decision.Allowed {
+			setRateLimitHeaders(c, policy, decision)
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
 			c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate_limited"})
 			c.Abort()
 			return
 		}
+
+		if current, err := strconv.Atoi(c.Writer.Header().Get("RateLimit-Remaining")); err != nil || decision.Remaining < current {
+			setRateLimitHeaders(c, policy, decision)
+		}
 		c.Next()
 	}
 }
 
+func rateLimitSubject(c *gin.Context, by RateLimitKey) string {
+	if by == RateLimitByUser {
+		if principal := domain.PrincipalFrom(c.Request.Context()); principal != nil {
+			return "user:" + principal.UserID.String()
+		}
+	}
+	ip := c.ClientIP()
+	if ip == "" {
+		ip = "unknown"
+	}
+	return "ip:" + ip
+}
+
+func setRateLimitHeaders(c *gin.Context, policy RateLimitPolicy, d *domain.RateLimitDecision) {
+	c.Header("RateLimit-Limit", strconv.Itoa(d.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+	c.Header("RateLimit-Policy", strconv.Itoa(policy.Limit.Burst)+";w="+strconv.Itoa(ceilSeconds(policy.Limit.Period))+`;name="`+policy.Name+`"`)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"banking-platform/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type stubRateLimiter struct {
+	decision *domain.RateLimitDecision
+	err      error
+	keys     []string
+}
+
+func (l *stubRateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitDecision, error) {
+	l.keys = append(l.keys, key)
+	return l.decision, l.err
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	policy := RateLimitPolicy{Name: "money", By: RateLimitByUser, Limit: domain.RateLimit{Requests: 20, Period: time.Minute, Burst: 5}}
+
+	tests := []struct {
+		name       string
+		limiter    *stubRateLimiter
+		principal  *domain.Principal
+		wantCode   int
+		wantKey    string
+		wantHeader map[string]string
+	}{
+		{
+			name:       "allowed_user",
+			limiter:    &stubRateLimiter{decision: &domain.RateLimitDecision{Allowed: true, Limit: 5, Remaining: 3, Reset: 4500 * time.Millisecond}},
+			principal:  &domain.Principal{UserID: userID},
+			wantCode:   http.StatusOK,
+			wantKey:    "money:user:" + userID.String(),
+			wantHeader: map[string]string{"RateLimit-Limit": "5", "RateLimit-Remaining": "3", "RateLimit-Reset": "5", "RateLimit-Policy": `5;w=60;name="money"`, "Retry-After": ""},
+		},
+		{
+			name:       "refused_anonymous_falls_back_to_ip",
+			limiter:    &stubRateLimiter{decision: &domain.RateLimitDecision{Allowed: false, Limit: 5, Reset: 15 * time.Second, RetryAfter: 2100 * time.Millisecond}},
+			wantCode:   http.StatusTooManyRequests,
+			wantKey:    "money:ip:192.0.2.1",
+			wantHeader: map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "15", "Retry-After": "3"},
+		},
+		{
+			name:       "limiter_error_fails_open",
+			limiter:    &stubRateLimiter{err: errors.New("db down")},
+			wantCode:   http.StatusOK,
+			wantKey:    "money:ip:192.0.2.1",
+			wantHeader: map[string]string{"RateLimit-Limit": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/x", func(c *gin.Context) {
+				if tt.principal != nil {
+					c.Request = c.Request.WithContext(domain.WithPrincipal(c.Request.Context(), tt.principal))
+				}
+			}, RateLimitMiddleware(tt.limiter, policy), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/x", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status=%d want=%d", w.Code, tt.wantCode)
+			}
+			if len(tt.limiter.keys) != 1 || tt.limiter.keys[0] != tt.wantKey {
+				t.Fatalf("keys=%v want [%s]", tt.limiter.keys, tt.wantKey)
+			}
+			for h, want := range tt.wantHeader {
+				if got := w.Header().Get(h); got != want {
+					t.Fatalf("%s=%q want=%q", h, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddlewareReportsTightestPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	loose := &stubRateLimiter{decision: &domain.RateLimitDecision{Allowed: true, Limit: 100, Remaining: 90}}
+	tight := &stubRateLimiter{decision: &domain.RateLimitDecision{Allowed: true, Limit: 5, Remaining: 1}}
+	limit := domain.RateLimit{Requests: 1, Period: time.Second, Burst: 1}
+
+	r := gin.New()
+	r.GET("/x",
+		RateLimitMiddleware(tight, RateLimitPolicy{Name: "money", By: RateLimitByIP, Limit: limit}),
+		RateLimitMiddleware(loose, RateLimitPolicy{Name: "user", By: RateLimitByIP, Limit: limit}),
+		func(c *gin.Context) { c.Status(http.StatusOK) },
+	)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	if got := w.Header().Get("RateLimit-Remaining"); got != "1" {
+		t.Fatalf("RateLimit-Remaining=%q want=%q", got, "1")
+	}
+}
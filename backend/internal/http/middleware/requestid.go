@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"banking-platform/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID tags every request with an ID, reusing a well-formed X-Request-ID from the caller, and
+// stores it with the client IP and user agent in the request context for audit events.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set("request_id", id)
+		c.Writer.Header().Set(RequestIDHeader, id)
+		ctx := domain.WithRequestMeta(c.Request.Context(), domain.RequestMeta{
+			RequestID: id,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validRequestID accepts printable ASCII without spaces so IDs are safe to log and store.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"banking-platform/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		header    string
+		wantReuse bool
+	}{
+		{name: "reuses_caller_id", header: "abc-123", wantReuse: true},
+		{name: "generates_when_missing", header: ""},
+		{name: "rejects_too_long", header: strings.Repeat("a", maxRequestIDLength+1)},
+		{name: "rejects_control_chars", header: "abc\tdef"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var meta domain.RequestMeta
+			r := gin.New()
+			r.Use(RequestID())
+			r.GET("/", func(c *gin.Context) {
+				meta = domain.RequestMetaFrom(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			req.Header.Set("User-Agent", "test-agent")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			got := w.Header().Get(RequestIDHeader)
+			if got == "" || got != meta.RequestID {
+				t.Fatalf("got=%q want=%q", got, meta.RequestID)
+			}
+			if (got == tt.header) != tt.wantReuse {
+				t.Fatalf("got=%q reuse=%v", got, tt.wantReuse)
+			}
+			if meta.UserAgent != "test-agent" || meta.IP == "" {
+				t.Fatalf("got=%+v", meta)
+			}
+		})
+	}
+}
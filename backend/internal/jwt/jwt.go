@@ -14,36 +14,55 @@ import (
 const refreshTokenBytes = 64
 
 type TokenManager struct {
-	accessTokenSecret string
-	accessTokenTTL    time.Duration
-	refreshTokenTTL   time.Duration
+	keys            *KeySet
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	revocations     RevocationChecker
+}
+
+// RevocationChecker reports whether an access token with a valid signature has been revoked.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, claims *Claims) (bool, error)
 }
 
 type Claims struct {
 	UserID uuid.UUID `json:"user_id"`
+	Role   string    `json:"role,omitempty"`
+	// SessionID is the refresh token family the access token was issued for.
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func NewTokenManager(accessSecret string, accessTTL, refreshTTL time.Duration) *TokenManager {
+// NewTokenManager creates a token manager that signs access tokens with the signing key of keys.
+// revocations may be nil, in which case access tokens are valid until they expire.
+func NewTokenManager(keys *KeySet, accessTTL, refreshTTL time.Duration, revocations RevocationChecker) *TokenManager {
 	return &TokenManager{
-		accessTokenSecret: accessSecret,
-		accessTokenTTL:    accessTTL,
-		refreshTokenTTL:   refreshTTL,
+		keys:            keys,
+		accessTokenTTL:  accessTTL,
+		refreshTokenTTL: refreshTTL,
+		revocations:     revocations,
 	}
 }
 
-func (tm *TokenManager) GenerateAccessToken(ctx context.Context, userID uuid.UUID) (string, error) {
+func (tm *TokenManager) GenerateAccessToken(ctx context.Context, userID uuid.UUID, role string, sessionID uuid.UUID) (string, error) {
 	claims := Claims{
-		UserID: userID,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(tm.accessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(tm.accessTokenSecret))
+	key := tm.keys.SigningKey()
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.sign)
 }
 
 func (tm *TokenManager) GenerateRefreshToken(ctx context.Context, userID uuid.UUID) (string, error) {
@@ -58,12 +77,7 @@ func (tm *TokenManager) GenerateRefreshToken(ctx context.Context, userID uuid.UU
 
 func (tm *TokenManager) ValidateAccessToken(ctx context.Context, tokenString string) (*Claims, error) {
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(tm.accessTokenSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, tm.keys.keyFunc, jwt.WithValidMethods(tm.keys.validMethods()))
 
 	if err != nil {
 		return nil, err
@@ -72,6 +86,15 @@ func (tm *TokenManager) ValidateAccessToken(ctx context.Context, tokenString str
 	if !token.Valid {
 		return nil, fmt.Errorf("invalid token")
 	}
+	if tm.revocations != nil {
+		revoked, err := tm.revocations.IsRevoked(ctx, claims)
+		if err != nil {
+			return nil, fmt.Errorf("check revocation: %w", err)
+		}
+		if revoked {
+			return nil, fmt.Errorf("token revoked")
+		}
+	}
 
 	return claims, nil
 }
@@ -90,6 +113,10 @@ func (tm *TokenManager) ParseAccessToken(ctx context.Context, tokenString string
 	return claims, exp.Time, nil
 }
 
+func (tm *TokenManager) GetAccessTokenTTL() time.Duration {
+	return tm.accessTokenTTL
+}
+
 func (tm *TokenManager) GetRefreshTokenTTL() time.Duration {
 	return tm.refreshTokenTTL
 }
@@ -0,0 +1,276 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is one signing or verification key. Asymmetric keys are identified by their RFC 7638
+// thumbprint, which is sent as the kid header; the HMAC key has an empty ID.
+type Key struct {
+	ID     string
+	method jwt.SigningMethod
+	// sign is nil for verification-only keys.
+	sign   any
+	verify any
+	public crypto.PublicKey
+}
+
+// Algorithm returns the JWS alg of the key: HS256, RS256 or EdDSA.
+func (k *Key) Algorithm() string {
+	return k.method.Alg()
+}
+
+// NewHMACKey returns the shared-secret HS256 key. It is never published in the JWKS.
+func NewHMACKey(secret string) *Key {
+	return &Key{method: jwt.SigningMethodHS256, sign: []byte(secret), verify: []byte(secret)}
+}
+
+// NewHMACVerifyKey returns the shared-secret HS256 key without signing, so HS256 tokens issued before
+// a switch to a key pair keep validating until they expire.
+func NewHMACVerifyKey(secret string) *Key {
+	return &Key{method: jwt.SigningMethodHS256, verify: []byte(secret)}
+}
+
+// LoadPEMKey reads an RSA or Ed25519 key. A private key (PKCS#8, or PKCS#1 for RSA) can sign; a
+// public key (PKIX) only verifies.
+func LoadPEMKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", path, err)
+	}
+	key, err := ParsePEMKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+func ParsePEMKey(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return newRSAKey(k, &k.PublicKey)
+	case *rsa.PublicKey:
+		return newRSAKey(nil, k)
+	case ed25519.PrivateKey:
+		return newEd25519Key(k, k.Public().(ed25519.PublicKey))
+	case ed25519.PublicKey:
+		return newEd25519Key(nil, k)
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+func newRSAKey(private *rsa.PrivateKey, public *rsa.PublicKey) (*Key, error) {
+	if public.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("RSA key must be at least 2048 bits, got %d", public.N.BitLen())
+	}
+	key := &Key{method: jwt.SigningMethodRS256, verify: public, public: public}
+	if private != nil {
+		key.sign = private
+	}
+	key.ID = thumbprint(key.jwk())
+	return key, nil
+}
+
+func newEd25519Key(private ed25519.PrivateKey, public ed25519.PublicKey) (*Key, error) {
+	key := &Key{method: jwt.SigningMethodEdDSA, verify: public, public: public}
+	if private != nil {
+		key.sign = private
+	}
+	key.ID = thumbprint(key.jwk())
+	return key, nil
+}
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// jwk returns the required members only, as used for the thumbprint.
+func (k *Key) jwk() JWK {
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			KeyType: "RSA",
+			N:       base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return JWK{KeyType: "OKP", Curve: "Ed25519", X: base64.RawURLEncoding.EncodeToString(pub)}
+	default:
+		return JWK{}
+	}
+}
+
+// thumbprint computes the RFC 7638 SHA-256 thumbprint: the required members in lexicographic order,
+// without whitespace.
+func thumbprint(j JWK) string {
+	var members string
+	switch j.KeyType {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, j.E, j.N)
+	case "OKP":
+		members = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, j.Curve, j.X)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// KeySet holds the key new tokens are signed with and every key tokens are still accepted from.
+// Rotation overlaps: the previous key stays in the set until the tokens it signed have expired, and
+// the next key can be published before it starts signing.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeySet returns a set that signs with signing and also verifies with others. The HMAC key may
+// only be among others as a verification-only key; it matches tokens without a kid header.
+func NewKeySet(signing *Key, others ...*Key) (*KeySet, error) {
+	if signing == nil || signing.sign == nil {
+		return nil, errors.New("signing key must include the private key")
+	}
+	ks := &KeySet{signing: signing, keys: map[string]*Key{signing.ID: signing}}
+	for _, k := range others {
+		if _, dup := ks.keys[k.ID]; dup {
+			continue
+		}
+		if k.ID == "" && k.sign != nil {
+			return nil, errors.New("an additional HMAC key must be verification-only")
+		}
+		ks.keys[k.ID] = k
+	}
+	return ks, nil
+}
+
+// NewHMACKeySet returns a set that signs and verifies with the shared secret only.
+func NewHMACKeySet(secret string) *KeySet {
+	key := NewHMACKey(secret)
+	return &KeySet{signing: key, keys: map[string]*Key{key.ID: key}}
+}
+
+// LoadKeySet builds a set from PEM files: signingPath must hold a private key, verifyPaths may hold
+// public or private keys. extra keys, such as NewHMACVerifyKey, are also accepted.
+func LoadKeySet(signingPath string, verifyPaths []string, extra ...*Key) (*KeySet, error) {
+	signing, err := LoadPEMKey(signingPath)
+	if err != nil {
+		return nil, err
+	}
+	others := make([]*Key, 0, len(verifyPaths)+len(extra))
+	for _, p := range verifyPaths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		k, err := LoadPEMKey(p)
+		if err != nil {
+			return nil, err
+		}
+		others = append(others, k)
+	}
+	return NewKeySet(signing, append(others, extra...)...)
+}
+
+// SigningKey returns the key new tokens are signed with.
+func (ks *KeySet) SigningKey() *Key {
+	return ks.signing
+}
+
+// validMethods lists the algorithms of the set, so a token cannot pick another one.
+func (ks *KeySet) validMethods() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, k := range ks.keys {
+		if alg := k.Algorithm(); !seen[alg] {
+			seen[alg] = true
+			out = append(out, alg)
+		}
+	}
+	return out
+}
+
+// keyFunc resolves the verification key from the kid header and rejects a token whose alg does not
+// match that key.
+func (ks *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verify, nil
+}
+
+// PublicJWKs returns the asymmetric keys of the set in JWKS form. The HMAC key is never published.
+func (ks *KeySet) PublicJWKs() []JWK {
+	out := make([]JWK, 0, len(ks.keys))
+	add := func(k *Key) {
+		if k.public == nil {
+			return
+		}
+		j := k.jwk()
+		j.KeyID = k.ID
+		j.Use = "sig"
+		j.Algorithm = k.Algorithm()
+		out = append(out, j)
+	}
+	// The signing key first, then the rest in a stable order.
+	add(ks.signing)
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		if id != ks.signing.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		add(ks.keys[id])
+	}
+	return out
+}
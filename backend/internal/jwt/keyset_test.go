@@ -0,0 +1,234 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return path
+}
+
+func ed25519KeyFile(t *testing.T) (string, ed25519.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	return writePEM(t, "PRIVATE KEY", der), pub
+}
+
+func rsaKeyFile(t *testing.T) (string, *rsa.PublicKey) {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(priv)), &priv.PublicKey
+}
+
+func TestAsymmetricSignAndVerify(t *testing.T) {
+	edPath, _ := ed25519KeyFile(t)
+	rsaPath, _ := rsaKeyFile(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		wantAlg string
+	}{
+		{name: "ed25519", path: edPath, wantAlg: "EdDSA"},
+		{name: "rsa", path: rsaPath, wantAlg: "RS256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := LoadKeySet(tt.path, nil)
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			tm := NewTokenManager(keys, time.Minute, time.Hour, nil)
+			userID := uuid.New()
+			token, err := tm.GenerateAccessToken(context.Background(), userID, "customer", uuid.New())
+			if err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatalf("parse header: %v", err)
+			}
+			if parsed.Header["alg"] != tt.wantAlg || parsed.Header["kid"] != keys.SigningKey().ID {
+				t.Fatalf("header=%v want alg=%s kid=%s", parsed.Header, tt.wantAlg, keys.SigningKey().ID)
+			}
+
+			claims, err := tm.ValidateAccessToken(context.Background(), token)
+			if err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+			if claims.UserID != userID {
+				t.Fatalf("user id=%s want=%s", claims.UserID, userID)
+			}
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldPath, _ := ed25519KeyFile(t)
+	newPath, _ := ed25519KeyFile(t)
+	ctx := context.Background()
+
+	oldKeys, err := LoadKeySet(oldPath, nil)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	token, err := NewTokenManager(oldKeys, time.Minute, time.Hour, nil).GenerateAccessToken(ctx, uuid.New(), "customer", uuid.New())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	// During the overlap the new key signs and the old one still verifies.
+	rotated, err := LoadKeySet(newPath, []string{oldPath})
+	if err != nil {
+		t.Fatalf("load rotated: %v", err)
+	}
+	if _, err := NewTokenManager(rotated, time.Minute, time.Hour, nil).ValidateAccessToken(ctx, token); err != nil {
+		t.Fatalf("old token during overlap: %v", err)
+	}
+	if jwks := rotated.PublicJWKs(); len(jwks) != 2 || jwks[0].KeyID != rotated.SigningKey().ID {
+		t.Fatalf("jwks=%+v want signing key first of 2", jwks)
+	}
+
+	retired, err := LoadKeySet(newPath, nil)
+	if err != nil {
+		t.Fatalf("load retired: %v", err)
+	}
+	if _, err := NewTokenManager(retired, time.Minute, time.Hour, nil).ValidateAccessToken(ctx, token); err == nil {
+		t.Fatalf("token of a retired key accepted")
+	}
+}
+
+func TestHMACToRSACutover(t *testing.T) {
+	rsaPath, pub := rsaKeyFile(t)
+	ctx := context.Background()
+
+	token, err := NewTokenManager(NewHMACKeySet("secret"), time.Minute, time.Hour, nil).GenerateAccessToken(ctx, uuid.New(), "customer", uuid.New())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	// During the cutover the RSA key signs and the HMAC secret only verifies.
+	cutover, err := LoadKeySet(rsaPath, nil, NewHMACVerifyKey("secret"))
+	if err != nil {
+		t.Fatalf("load cutover: %v", err)
+	}
+	tm := NewTokenManager(cutover, time.Minute, time.Hour, nil)
+	if _, err := tm.ValidateAccessToken(ctx, token); err != nil {
+		t.Fatalf("HS256 token during cutover: %v", err)
+	}
+	if cutover.SigningKey().Algorithm() != "RS256" {
+		t.Fatalf("signing alg=%s want RS256", cutover.SigningKey().Algorithm())
+	}
+	if jwks := cutover.PublicJWKs(); len(jwks) != 1 || jwks[0].Algorithm != "RS256" {
+		t.Fatalf("jwks=%+v want only the RSA key", jwks)
+	}
+
+	// kid/alg pinning still holds: an HS256 token naming the RSA kid, keyed with its public key, fails.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: uuid.New()})
+	forged.Header["kid"] = cutover.SigningKey().ID
+	signed, err := forged.SignedString(x509.MarshalPKCS1PublicKey(pub))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := tm.ValidateAccessToken(ctx, signed); err == nil {
+		t.Fatalf("HS256 token with the RSA kid accepted")
+	}
+	// And an RS256 token without a kid does not fall back to the HMAC key.
+	rsaToken, err := tm.GenerateAccessToken(ctx, uuid.New(), "customer", uuid.New())
+	if err != nil {
+		t.Fatalf("generate rsa: %v", err)
+	}
+	unkeyed, _, err := jwt.NewParser().ParseUnverified(rsaToken, &Claims{})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	delete(unkeyed.Header, "kid")
+	stripped, err := unkeyed.SignedString(cutover.SigningKey().sign)
+	if err != nil {
+		t.Fatalf("resign: %v", err)
+	}
+	if _, err := tm.ValidateAccessToken(ctx, stripped); err == nil {
+		t.Fatalf("RS256 token without kid accepted")
+	}
+
+	// Once the HMAC key is dropped its tokens fail, and a signing HMAC key is refused as a verifier.
+	retired, err := LoadKeySet(rsaPath, nil)
+	if err != nil {
+		t.Fatalf("load retired: %v", err)
+	}
+	if _, err := NewTokenManager(retired, time.Minute, time.Hour, nil).ValidateAccessToken(ctx, token); err == nil {
+		t.Fatalf("HS256 token accepted after cutover")
+	}
+	if _, err := LoadKeySet(rsaPath, nil, NewHMACKey("secret")); err == nil {
+		t.Fatalf("signing HMAC key accepted as an additional key")
+	}
+}
+
+func TestRejectsAlgorithmConfusion(t *testing.T) {
+	path, pub := ed25519KeyFile(t)
+	keys, err := LoadKeySet(path, nil)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	tm := NewTokenManager(keys, time.Minute, time.Hour, nil)
+
+	// An HS256 token keyed with the published public key must not verify.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: uuid.New()})
+	forged.Header["kid"] = keys.SigningKey().ID
+	signed, err := forged.SignedString([]byte(pub))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := tm.ValidateAccessToken(context.Background(), signed); err == nil {
+		t.Fatalf("HS256 token accepted by an EdDSA key set")
+	}
+}
+
+func TestHMACKeyNotPublished(t *testing.T) {
+	if jwks := NewHMACKeySet("secret").PublicJWKs(); len(jwks) != 0 {
+		t.Fatalf("jwks=%+v want empty", jwks)
+	}
+}
+
+func TestThumbprintRFC7638(t *testing.T) {
+	// The example key of RFC 7638 section 3.1.
+	j := JWK{
+		KeyType: "RSA",
+		E:       "AQAB",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMs" +
+			"tn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91" +
+			"CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+	}
+	if got, want := thumbprint(j), "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Fatalf("thumbprint=%s want=%s", got, want)
+	}
+}
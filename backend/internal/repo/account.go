@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"banking-platform/internal/apperr"
 	"banking-platform/internal/domain"
@@ -12,33 +13,41 @@ import (
 )
 
 type AccountRepository struct {
-	db *DB
+	db         *DB
+	currencies *domain.CurrencyRegistry
 }
 
-func NewAccountRepository(db *DB) *AccountRepository {
-	return &AccountRepository{db: db}
+func NewAccountRepository(db *DB, currencies *domain.CurrencyRegistry) *AccountRepository {
+	return &AccountRepository{db: db, currencies: currencies}
 }
 
-// Create inserts a new account. Balance is stored as DECIMAL(15,2) in DB.
+// accountColumns selects from accounts; held is the sum of the account's unexpired pending authorizations.
+const accountColumns = `id, user_id, currency, name, status, is_default, balance,
+	(SELECT COALESCE(SUM(h.amount), 0) FROM transactions h
+	 WHERE h.from_account_id = accounts.id AND h.status = 'pending' AND h.expires_at > NOW())::text AS held,
+	created_at, updated_at, closed_at`
+
+// Create inserts a new account. Balance is stored in major units as DECIMAL(15,2) in DB.
 func (r *AccountRepository) Create(ctx context.Context, account *domain.Account) error {
 	query := `
-		INSERT INTO accounts (id, user_id, currency, balance, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO accounts (id, user_id, currency, name, status, is_default, balance, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 	_, err := r.db.GetDB().ExecContext(
 		ctx,
 		query,
-		account.ID, account.UserID, account.Currency, domain.CentsToDecimalString(account.BalanceCents),
+		account.ID, account.UserID, account.Currency, account.Name, account.Status, account.IsDefault,
+		r.currencies.Format(account.Currency, account.BalanceCents),
 		account.CreatedAt, account.UpdatedAt,
 	)
 	return err
 }
 
-// GetByUserID loads all accounts for a user.
+// GetByUserID loads all accounts for a user, default account first within each currency.
 func (r *AccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Account, error) {
 	query := `
-		SELECT id, user_id, currency, balance, created_at, updated_at
-		FROM accounts WHERE user_id = $1 ORDER BY currency
+		SELECT ` + accountColumns + `
+		FROM accounts WHERE user_id = $1 ORDER BY currency, is_default DESC, created_at
 	`
 
 	rows, err := r.db.GetDB().QueryContext(ctx, query, userID)
@@ -49,19 +58,10 @@ func (r *AccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 
 	var accounts []*domain.Account
 	for rows.Next() {
-		account := &domain.Account{}
-		var balanceStr string
-		if err := rows.Scan(
-			&account.ID, &account.UserID, &account.Currency, &balanceStr,
-			&account.CreatedAt, &account.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		bc, err := domain.DecimalStringToCents(balanceStr)
+		account, err := r.scanAccount(rows)
 		if err != nil {
-			return nil, fmt.Errorf("invalid balance in db for account %s: %w", account.ID.String(), err)
+			return nil, err
 		}
-		account.BalanceCents = bc
 		accounts = append(accounts, account)
 	}
 	return accounts, rows.Err()
@@ -69,60 +69,39 @@ func (r *AccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 
 // GetByID loads a single account by id.
 func (r *AccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Account, error) {
-	account := &domain.Account{}
-	query := `
-		SELECT id, user_id, currency, balance, created_at, updated_at
-		FROM accounts WHERE id = $1
-	`
-	var balanceStr string
-	err := r.db.GetDB().QueryRowContext(ctx, query, id).Scan(
-		&account.ID, &account.UserID, &account.Currency, &balanceStr,
-		&account.CreatedAt, &account.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, apperr.ErrAccountNotFound
-	}
-	if err != nil {
-		return nil, err
-	}
-	bc, err := domain.DecimalStringToCents(balanceStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid balance in db for account %s: %w", account.ID.String(), err)
-	}
-	account.BalanceCents = bc
-	return account, nil
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE id = $1`
+	return r.getOne(r.db.GetDB().QueryRowContext(ctx, query, id))
 }
 
-// GetByUserIDAndCurrency loads a single account for a given user and currency.
+// GetByUserIDAndCurrency loads the default account for a given user and currency.
 func (r *AccountRepository) GetByUserIDAndCurrency(ctx context.Context, userID uuid.UUID, currency domain.Currency) (*domain.Account, error) {
-	account := &domain.Account{}
-	query := `
-		SELECT id, user_id, currency, balance, created_at, updated_at
-		FROM accounts WHERE user_id = $1 AND currency = $2
-	`
-	var balanceStr string
-	err := r.db.GetDB().QueryRowContext(ctx, query, userID, currency).Scan(
-		&account.ID, &account.UserID, &account.Currency, &balanceStr,
-		&account.CreatedAt, &account.UpdatedAt,
-	)
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE user_id = $1 AND currency = $2 AND is_default`
+	return r.getOne(r.db.GetDB().QueryRowContext(ctx, query, userID, currency))
+}
+
+// FindAccountIDTx finds the default account id for (user, currency) within an existing transaction.
+func (r *AccountRepository) FindAccountIDTx(ctx context.Context, tx service.Tx, userID uuid.UUID, currency domain.Currency) (uuid.UUID, error) {
+	var id uuid.UUID
+	query := `SELECT id FROM accounts WHERE user_id = $1 AND currency = $2 AND is_default`
+	err := tx.QueryRowContext(ctx, query, userID, currency).Scan(&id)
 	if err == sql.ErrNoRows {
-		return nil, apperr.ErrAccountNotFound
-	}
-	if err != nil {
-		return nil, err
+		return uuid.Nil, apperr.ErrAccountNotFound
 	}
-	bc, err := domain.DecimalStringToCents(balanceStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid balance in db for account %s: %w", account.ID.String(), err)
+		return uuid.Nil, err
 	}
-	account.BalanceCents = bc
-	return account, nil
+	return id, nil
 }
 
-// FindAccountIDTx finds account id within an existing transaction (used by services).
-func (r *AccountRepository) FindAccountIDTx(ctx context.Context, tx service.Tx, userID uuid.UUID, currency domain.Currency) (uuid.UUID, error) {
+// FindSuccessorTx returns the oldest active non-default account for (user, currency), if any.
+func (r *AccountRepository) FindSuccessorTx(ctx context.Context, tx service.Tx, userID uuid.UUID, currency domain.Currency) (uuid.UUID, error) {
 	var id uuid.UUID
-	query := `SELECT id FROM accounts WHERE user_id = $1 AND currency = $2`
+	query := `
+		SELECT id FROM accounts
+		WHERE user_id = $1 AND currency = $2 AND status = 'active' AND NOT is_default
+		ORDER BY created_at, id
+		LIMIT 1
+	`
 	err := tx.QueryRowContext(ctx, query, userID, currency).Scan(&id)
 	if err == sql.ErrNoRows {
 		return uuid.Nil, apperr.ErrAccountNotFound
@@ -133,60 +112,73 @@ func (r *AccountRepository) FindAccountIDTx(ctx context.Context, tx service.Tx,
 	return id, nil
 }
 
-// UpdateBalanceString updates balance using a decimal string (e.g. "10.25").
+// UpdateBalanceString updates balance using a decimal string in major units (e.g. "10.25").
 func (r *AccountRepository) UpdateBalanceString(ctx context.Context, tx service.Tx, accountID uuid.UUID, newBalance string) error {
 	query := `UPDATE accounts SET balance = $1, updated_at = NOW() WHERE id = $2`
 	_, err := tx.ExecContext(ctx, query, newBalance, accountID)
 	return err
 }
 
-// LockAccountForUpdate locks the account row FOR UPDATE and returns the current state.
-func (r *AccountRepository) LockAccountForUpdate(ctx context.Context, tx service.Tx, accountID uuid.UUID) (*domain.Account, error) {
-	account := &domain.Account{}
+// UpdateStatusTx changes the account status; closing also stamps closed_at.
+func (r *AccountRepository) UpdateStatusTx(ctx context.Context, tx service.Tx, accountID uuid.UUID, status domain.AccountStatus, at time.Time) error {
 	query := `
-		SELECT id, user_id, currency, balance, created_at, updated_at
-		FROM accounts WHERE id = $1 FOR UPDATE
+		UPDATE accounts
+		SET status = $1,
+			closed_at = CASE WHEN $1 = 'closed' THEN $2::timestamp ELSE NULL END,
+			updated_at = $2
+		WHERE id = $3
 	`
-	var balanceStr string
-	err := tx.QueryRowContext(ctx, query, accountID).Scan(
-		&account.ID, &account.UserID, &account.Currency, &balanceStr,
-		&account.CreatedAt, &account.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, apperr.ErrAccountNotFound
-	}
-	if err != nil {
-		return nil, err
-	}
-	bc, err := domain.DecimalStringToCents(balanceStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid balance in db for account %s: %w", account.ID.String(), err)
-	}
-	account.BalanceCents = bc
-	return account, nil
+	_, err := tx.ExecContext(ctx, query, status, at, accountID)
+	return err
+}
+
+// SetDefaultTx sets or clears the default flag of an account.
+func (r *AccountRepository) SetDefaultTx(ctx context.Context, tx service.Tx, accountID uuid.UUID, isDefault bool) error {
+	query := `UPDATE accounts SET is_default = $1, updated_at = NOW() WHERE id = $2`
+	_, err := tx.ExecContext(ctx, query, isDefault, accountID)
+	return err
+}
+
+// LockAccountForUpdate locks the account row FOR UPDATE and returns the current state.
+func (r *AccountRepository) LockAccountForUpdate(ctx context.Context, tx service.Tx, accountID uuid.UUID) (*domain.Account, error) {
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE id = $1 FOR UPDATE`
+	return r.getOne(tx.QueryRowContext(ctx, query, accountID))
 }
 
 func (r *AccountRepository) LockAccount(ctx context.Context, tx service.Tx, userID uuid.UUID, currency domain.Currency) (*domain.Account, error) {
-	account := &domain.Account{}
-	query := `
-		SELECT id, user_id, currency, balance, created_at, updated_at
-		FROM accounts WHERE user_id = $1 AND currency = $2 FOR UPDATE
-	`
-	var balanceStr string
-	err := tx.QueryRowContext(ctx, query, userID, currency).Scan(
-		&account.ID, &account.UserID, &account.Currency, &balanceStr,
-		&account.CreatedAt, &account.UpdatedAt,
-	)
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE user_id = $1 AND currency = $2 AND is_default FOR UPDATE`
+	return r.getOne(tx.QueryRowContext(ctx, query, userID, currency))
+}
+
+func (r *AccountRepository) getOne(row rowScanner) (*domain.Account, error) {
+	account, err := r.scanAccount(row)
 	if err == sql.ErrNoRows {
 		return nil, apperr.ErrAccountNotFound
 	}
-	if err != nil {
+	return account, err
+}
+
+func (r *AccountRepository) scanAccount(row rowScanner) (*domain.Account, error) {
+	account := &domain.Account{}
+	var balanceStr, heldStr string
+	var closedAt sql.NullTime
+	if err := row.Scan(
+		&account.ID, &account.UserID, &account.Currency, &account.Name, &account.Status, &account.IsDefault,
+		&balanceStr, &heldStr, &account.CreatedAt, &account.UpdatedAt, &closedAt,
+	); err != nil {
 		return nil, err
 	}
-	bc, err := domain.DecimalStringToCents(balanceStr)
+	bc, err := r.currencies.Parse(account.Currency, balanceStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid balance in db for account %s: %w", account.ID.String(), err)
 	}
 	account.BalanceCents = bc
+	if account.HeldCents, err = r.currencies.Parse(account.Currency, heldStr); err != nil {
+		return nil, fmt.Errorf("invalid held amount in db for account %s: %w", account.ID.String(), err)
+	}
+	if closedAt.Valid {
+		v := closedAt.Time
+		account.ClosedAt = &v
+	}
 	return account, nil
 }
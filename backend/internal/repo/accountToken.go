@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+)
+
+type AccountTokenRepository struct {
+	db *DB
+}
+
+func NewAccountTokenRepository(db *DB) *AccountTokenRepository {
+	return &AccountTokenRepository{db: db}
+}
+
+func (r *AccountTokenRepository) CreateTx(ctx context.Context, tx service.Tx, token *service.AccountToken) error {
+	query := `
+		INSERT INTO account_tokens (id, user_id, purpose, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+// LockByTokenHashTx returns the token locked FOR UPDATE, used and expired ones included; the caller
+// decides whether it is still valid.
+func (r *AccountTokenRepository) LockByTokenHashTx(ctx context.Context, tx service.Tx, purpose service.AccountTokenPurpose, tokenHash string) (*service.AccountToken, error) {
+	query := `
+		SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at
+		FROM account_tokens
+		WHERE token_hash = $1 AND purpose = $2
+		FOR UPDATE
+	`
+	t := &service.AccountToken{}
+	var usedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, query, tokenHash, purpose).Scan(
+		&t.ID, &t.UserID, &t.Purpose, &t.TokenHash, &t.ExpiresAt, &usedAt, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrInvalidAccountToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		t.UsedAt = &usedAt.Time
+	}
+	return t, nil
+}
+
+func (r *AccountTokenRepository) InvalidateTx(ctx context.Context, tx service.Tx, userID uuid.UUID, purpose service.AccountTokenPurpose, at time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE account_tokens SET used_at = $3 WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`,
+		userID, purpose, at)
+	return err
+}
+
+func (r *AccountTokenRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	_, err := r.db.GetDB().ExecContext(ctx, `DELETE FROM account_tokens WHERE expires_at < $1`, before)
+	return err
+}
@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"banking-platform/internal/domain"
+	"banking-platform/internal/service"
+)
+
+// auditChainLockKey serializes appends so every event links to the one committed right before it.
+const auditChainLockKey = 7_300_100_001
+
+type AuditEventRepository struct {
+	db *DB
+}
+
+func NewAuditEventRepository(db *DB) *AuditEventRepository {
+	return &AuditEventRepository{db: db}
+}
+
+const auditEventColumns = `
+	seq, id, actor_id, actor_role, action, target_type, target_id, request_id, ip,
+	before_state, after_state, metadata, created_at, prev_hash, hash`
+
+// AppendTx seals the event onto the tail of the chain and inserts it within tx. The advisory lock is
+// held until tx ends, so concurrent appends queue up instead of forking the chain.
+func (r *AuditEventRepository) AppendTx(ctx context.Context, tx service.Tx, event *domain.AuditEvent) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockKey); err != nil {
+		return fmt.Errorf("lock audit chain: %w", err)
+	}
+
+	var prevHash string
+	err := tx.QueryRowContext(ctx, `SELECT hash FROM audit_events ORDER BY seq DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("load audit chain tail: %w", err)
+	}
+	event.Seal(prevHash)
+
+	query := `
+		INSERT INTO audit_events (
+			id, actor_id, actor_role, action, target_type, target_id, request_id, ip,
+			before_state, after_state, metadata, created_at, prev_hash, hash
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING seq
+	`
+	return tx.QueryRowContext(
+		ctx,
+		query,
+		event.ID, event.ActorID, event.ActorRole, event.Action, event.TargetType, event.TargetID,
+		event.RequestID, event.IP,
+		jsonArg(event.Before), jsonArg(event.After), jsonArg(event.Metadata),
+		event.CreatedAt, event.PrevHash, event.Hash,
+	).Scan(&event.Seq)
+}
+
+// ListAfter returns up to limit events with seq greater than afterSeq, in chain order.
+func (r *AuditEventRepository) ListAfter(ctx context.Context, afterSeq int64, limit int) ([]*domain.AuditEvent, error) {
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events WHERE seq > $1 ORDER BY seq LIMIT $2`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.AuditEvent
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func scanAuditEvent(row rowScanner) (*domain.AuditEvent, error) {
+	e := &domain.AuditEvent{}
+	var before, after, metadata []byte
+	if err := row.Scan(
+		&e.Seq, &e.ID, &e.ActorID, &e.ActorRole, &e.Action, &e.TargetType, &e.TargetID, &e.RequestID, &e.IP,
+		&before, &after, &metadata, &e.CreatedAt, &e.PrevHash, &e.Hash,
+	); err != nil {
+		return nil, err
+	}
+	e.Before = json.RawMessage(before)
+	e.After = json.RawMessage(after)
+	e.Metadata = json.RawMessage(metadata)
+	return e, nil
+}
+
+// jsonArg passes raw JSON as text (lib/pq would send []byte as bytea) and empty values as NULL.
+func jsonArg(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
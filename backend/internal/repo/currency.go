@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"context"
+
+	"banking-platform/internal/domain"
+)
+
+type CurrencyRepository struct {
+	db *DB
+}
+
+func NewCurrencyRepository(db *DB) *CurrencyRepository {
+	return &CurrencyRepository{db: db}
+}
+
+// List loads every registered currency, enabled or not.
+func (r *CurrencyRepository) List(ctx context.Context) ([]domain.CurrencyInfo, error) {
+	query := `SELECT code, name, minor_units, enabled FROM currencies ORDER BY code`
+
+	rows, err := r.db.GetDB().QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.CurrencyInfo
+	for rows.Next() {
+		var c domain.CurrencyInfo
+		if err := rows.Scan(&c.Code, &c.Name, &c.MinorUnits, &c.Enabled); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
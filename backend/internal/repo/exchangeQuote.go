@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+)
+
+type ExchangeQuoteRepository struct {
+	db         *DB
+	currencies *domain.CurrencyRegistry
+}
+
+func NewExchangeQuoteRepository(db *DB, currencies *domain.CurrencyRegistry) *ExchangeQuoteRepository {
+	return &ExchangeQuoteRepository{db: db, currencies: currencies}
+}
+
+// Create stores an issued quote. Amounts are stored in major units as DECIMAL(15,2) in DB.
+func (r *ExchangeQuoteRepository) Create(ctx context.Context, quote *domain.ExchangeQuote) error {
+	query := `
+		INSERT INTO exchange_quotes (
+			id, user_id, from_currency, to_currency, amount, converted_amount,
+			rate_num, rate_den, spread_bps, rate_source, rate_version, expires_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err := r.db.GetDB().ExecContext(
+		ctx,
+		query,
+		quote.ID, quote.UserID, quote.FromCurrency, quote.ToCurrency,
+		r.currencies.Format(quote.FromCurrency, quote.AmountCents), r.currencies.Format(quote.ToCurrency, quote.ConvertedAmountCents),
+		quote.RateNum, quote.RateDen, quote.SpreadBps, quote.RateSource, quote.RateVersion,
+		quote.ExpiresAt, quote.CreatedAt,
+	)
+	return err
+}
+
+const exchangeQuoteColumns = `
+	id, user_id, from_currency, to_currency, amount, converted_amount,
+	rate_num, rate_den, spread_bps, rate_source, rate_version, expires_at, used_at, transaction_id, created_at`
+
+// GetByID reads the quote without locking it.
+func (r *ExchangeQuoteRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ExchangeQuote, error) {
+	query := `SELECT ` + exchangeQuoteColumns + ` FROM exchange_quotes WHERE id = $1`
+	return r.scan(r.db.GetDB().QueryRowContext(ctx, query, id))
+}
+
+// LockByIDTx locks the quote row FOR UPDATE so it can be executed at most once.
+func (r *ExchangeQuoteRepository) LockByIDTx(ctx context.Context, tx service.Tx, id uuid.UUID) (*domain.ExchangeQuote, error) {
+	query := `SELECT ` + exchangeQuoteColumns + ` FROM exchange_quotes WHERE id = $1 FOR UPDATE`
+	return r.scan(tx.QueryRowContext(ctx, query, id))
+}
+
+func (r *ExchangeQuoteRepository) scan(row rowScanner) (*domain.ExchangeQuote, error) {
+	quote := &domain.ExchangeQuote{}
+	var amountStr, convertedStr string
+	var usedAt sql.NullTime
+	var transactionID uuid.NullUUID
+	err := row.Scan(
+		&quote.ID, &quote.UserID, &quote.FromCurrency, &quote.ToCurrency, &amountStr, &convertedStr,
+		&quote.RateNum, &quote.RateDen, &quote.SpreadBps, &quote.RateSource, &quote.RateVersion,
+		&quote.ExpiresAt, &usedAt, &transactionID, &quote.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrQuoteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quote.AmountCents, err = r.currencies.Parse(quote.FromCurrency, amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote amount in db for %s: %w", quote.ID.String(), err)
+	}
+	quote.ConvertedAmountCents, err = r.currencies.Parse(quote.ToCurrency, convertedStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote converted_amount in db for %s: %w", quote.ID.String(), err)
+	}
+	if usedAt.Valid {
+		v := usedAt.Time
+		quote.UsedAt = &v
+	}
+	if transactionID.Valid {
+		quote.TransactionID = &transactionID.UUID
+	}
+	return quote, nil
+}
+
+// MarkUsedTx records that the quote was executed by the given transaction.
+func (r *ExchangeQuoteRepository) MarkUsedTx(ctx context.Context, tx service.Tx, id uuid.UUID, transactionID uuid.UUID, usedAt time.Time) error {
+	query := `UPDATE exchange_quotes SET used_at = $1, transaction_id = $2 WHERE id = $3 AND used_at IS NULL`
+	res, err := tx.ExecContext(ctx, query, usedAt, transactionID, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return apperr.ErrQuoteAlreadyUsed
+	}
+	return nil
+}
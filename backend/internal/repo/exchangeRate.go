@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+)
+
+type ExchangeRateRepository struct {
+	db *DB
+}
+
+func NewExchangeRateRepository(db *DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// GetActive returns the most recent base->quote rate valid at the given time.
+func (r *ExchangeRateRepository) GetActive(ctx context.Context, base domain.Currency, quote domain.Currency, at time.Time) (*domain.ExchangeRate, error) {
+	query := `
+		SELECT id, base_currency, quote_currency, rate::text, valid_from, valid_to
+		FROM exchange_rates
+		WHERE base_currency = $1 AND quote_currency = $2
+		  AND valid_from <= $3 AND (valid_to IS NULL OR valid_to > $3)
+		ORDER BY valid_from DESC
+		LIMIT 1
+	`
+	var id uuid.UUID
+	var rateStr string
+	var validTo sql.NullTime
+	rate := &domain.ExchangeRate{Source: service.RateSourceDB}
+	err := r.db.GetDB().QueryRowContext(ctx, query, base, quote, at).Scan(
+		&id, &rate.Base, &rate.Quote, &rateStr, &rate.ValidFrom, &validTo,
+	)
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrRateUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	num, den, err := domain.ParseRateFraction(rateStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate in db for %s: %w", id.String(), err)
+	}
+	rate.Num = num
+	rate.Den = den
+	rate.Version = id.String()
+	if validTo.Valid {
+		v := validTo.Time
+		rate.ValidTo = &v
+	}
+	return rate, nil
+}
@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"banking-platform/internal/domain"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+)
+
+type IdempotencyRepository struct {
+	db *DB
+}
+
+func NewIdempotencyRepository(db *DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// ReserveTx inserts the key within tx. A concurrent request with the same key blocks on the
+// primary key until the first transaction finishes; the committed record is then returned.
+func (r *IdempotencyRepository) ReserveTx(ctx context.Context, tx service.Tx, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	insert := `
+		INSERT INTO idempotency_keys (user_id, scope, idempotency_key, request_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, scope, idempotency_key) DO NOTHING
+	`
+	res, err := tx.ExecContext(ctx, insert, key.UserID, key.Scope, key.Key, key.RequestHash, key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 1 {
+		return nil, nil
+	}
+
+	existing, err := scanIdempotencyKey(tx.QueryRowContext(ctx, idempotencyKeyQuery, key.UserID, key.Scope, key.Key))
+	if err != nil {
+		return nil, fmt.Errorf("load existing idempotency key: %w", err)
+	}
+	return existing, nil
+}
+
+// Get returns the stored key, or nil if it was never reserved or its reservation is not committed.
+func (r *IdempotencyRepository) Get(ctx context.Context, userID uuid.UUID, scope string, key string) (*domain.IdempotencyKey, error) {
+	existing, err := scanIdempotencyKey(r.db.GetDB().QueryRowContext(ctx, idempotencyKeyQuery, userID, scope, key))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return existing, err
+}
+
+// CompleteTx attaches the created transaction to a reserved key.
+func (r *IdempotencyRepository) CompleteTx(ctx context.Context, tx service.Tx, userID uuid.UUID, scope string, key string, transactionID uuid.UUID) error {
+	query := `
+		UPDATE idempotency_keys SET transaction_id = $1
+		WHERE user_id = $2 AND scope = $3 AND idempotency_key = $4
+	`
+	_, err := tx.ExecContext(ctx, query, transactionID, userID, scope, key)
+	return err
+}
+
+const idempotencyKeyQuery = `
+	SELECT user_id, scope, idempotency_key, request_hash, transaction_id, created_at
+	FROM idempotency_keys
+	WHERE user_id = $1 AND scope = $2 AND idempotency_key = $3
+`
+
+func scanIdempotencyKey(row rowScanner) (*domain.IdempotencyKey, error) {
+	existing := &domain.IdempotencyKey{}
+	var transactionID sql.NullString
+	if err := row.Scan(
+		&existing.UserID, &existing.Scope, &existing.Key, &existing.RequestHash, &transactionID, &existing.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if transactionID.Valid {
+		parsed, err := uuid.Parse(transactionID.String)
+		if err != nil {
+			return nil, err
+		}
+		existing.TransactionID = &parsed
+	}
+	return existing, nil
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"banking-platform/internal/domain"
 	"banking-platform/internal/service"
@@ -10,14 +11,15 @@ import (
 )
 
 type LedgerRepository struct {
-	db *DB
+	db         *DB
+	currencies *domain.CurrencyRegistry
 }
 
-func NewLedgerRepository(db *DB) *LedgerRepository {
-	return &LedgerRepository{db: db}
+func NewLedgerRepository(db *DB, currencies *domain.CurrencyRegistry) *LedgerRepository {
+	return &LedgerRepository{db: db, currencies: currencies}
 }
 
-// CreateEntry inserts a ledger entry. Amount is stored in DB as DECIMAL(15,2).
+// CreateEntry inserts a ledger entry. Amount is stored in major units of entry.Currency as DECIMAL(15,2).
 func (r *LedgerRepository) CreateEntry(ctx context.Context, tx service.Tx, entry *domain.LedgerEntry) error {
 	query := `
 		INSERT INTO ledger (id, transaction_id, account_id, amount, created_at)
@@ -26,7 +28,7 @@ func (r *LedgerRepository) CreateEntry(ctx context.Context, tx service.Tx, entry
 	_, err := tx.ExecContext(
 		ctx,
 		query,
-		entry.ID, entry.TransactionID, entry.AccountID, domain.CentsToDecimalString(entry.AmountCents), entry.CreatedAt,
+		entry.ID, entry.TransactionID, entry.AccountID, r.currencies.Format(entry.Currency, entry.AmountCents), entry.CreatedAt,
 	)
 	return err
 }
@@ -34,8 +36,10 @@ func (r *LedgerRepository) CreateEntry(ctx context.Context, tx service.Tx, entry
 // GetByTransactionID loads all ledger entries for a transaction (ordered by creation time).
 func (r *LedgerRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.LedgerEntry, error) {
 	query := `
-		SELECT id, transaction_id, account_id, amount, created_at
-		FROM ledger WHERE transaction_id = $1 ORDER BY created_at
+		SELECT l.id, l.transaction_id, l.account_id, a.currency, l.amount, l.created_at
+		FROM ledger l
+		JOIN accounts a ON a.id = l.account_id
+		WHERE l.transaction_id = $1 ORDER BY l.created_at
 	`
 
 	rows, err := r.db.GetDB().QueryContext(ctx, query, transactionID)
@@ -49,11 +53,11 @@ func (r *LedgerRepository) GetByTransactionID(ctx context.Context, transactionID
 		entry := &domain.LedgerEntry{}
 		var amountStr string
 		if err := rows.Scan(
-			&entry.ID, &entry.TransactionID, &entry.AccountID, &amountStr, &entry.CreatedAt,
+			&entry.ID, &entry.TransactionID, &entry.AccountID, &entry.Currency, &amountStr, &entry.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
-		ac, err := domain.DecimalStringToCents(amountStr)
+		ac, err := r.currencies.Parse(entry.Currency, amountStr)
 		if err != nil {
 			return nil, fmt.Errorf("invalid ledger amount in db for entry %s: %w", entry.ID.String(), err)
 		}
@@ -63,6 +67,51 @@ func (r *LedgerRepository) GetByTransactionID(ctx context.Context, transactionID
 	return entries, rows.Err()
 }
 
+// GetPostingsByTransactionID loads the entries of a transaction with account and owner details.
+// The balance after an entry is the sum of all entries of the account up to and including it.
+func (r *LedgerRepository) GetPostingsByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.TransactionPosting, error) {
+	query := `
+		SELECT l.id, l.account_id, a.name, a.user_id, TRIM(u.first_name || ' ' || u.last_name), a.currency, l.amount::text,
+			(
+				SELECT SUM(prev.amount)::text FROM ledger prev
+				WHERE prev.account_id = l.account_id AND (prev.created_at, prev.id) <= (l.created_at, l.id)
+			),
+			l.created_at
+		FROM ledger l
+		JOIN accounts a ON a.id = l.account_id
+		JOIN users u ON u.id = a.user_id
+		WHERE l.transaction_id = $1
+		ORDER BY l.created_at, l.id
+	`
+
+	rows, err := r.db.GetDB().QueryContext(ctx, query, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var postings []*domain.TransactionPosting
+	for rows.Next() {
+		p := &domain.TransactionPosting{}
+		var amountStr, balanceStr string
+		if err := rows.Scan(
+			&p.EntryID, &p.AccountID, &p.AccountName, &p.OwnerID, &p.OwnerName, &p.Currency, &amountStr, &balanceStr, &p.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if p.AmountCents, err = r.currencies.Parse(p.Currency, amountStr); err != nil {
+			return nil, fmt.Errorf("invalid ledger amount in db for entry %s: %w", p.EntryID.String(), err)
+		}
+		balance, err := r.currencies.Parse(p.Currency, balanceStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance after entry %s: %w", p.EntryID.String(), err)
+		}
+		p.BalanceAfterCents = &balance
+		postings = append(postings, p)
+	}
+	return postings, rows.Err()
+}
+
 // VerifyTransactionBalanceTx ensures the ledger is balanced for a given transaction.
 func (r *LedgerRepository) VerifyTransactionBalanceTx(ctx context.Context, tx service.Tx, transactionID uuid.UUID) error {
 	query := `SELECT COALESCE(SUM((amount * 100)::bigint), 0) FROM ledger WHERE transaction_id = $1`
@@ -107,7 +156,7 @@ func (r *LedgerRepository) FindUnbalancedTransactionIDs(ctx context.Context, lim
 	return ids, rows.Err()
 }
 
-// FindAccountBalanceMismatches compares account balance vs ledger sum (both in cents).
+// FindAccountBalanceMismatches compares account balance vs ledger sum (both in minor units of the account currency).
 func (r *LedgerRepository) FindAccountBalanceMismatches(ctx context.Context, limit int) ([]*domain.AccountBalanceMismatch, error) {
 	if limit <= 0 {
 		limit = 100
@@ -117,14 +166,15 @@ func (r *LedgerRepository) FindAccountBalanceMismatches(ctx context.Context, lim
 			a.id,
 			a.user_id,
 			a.currency,
-			(a.balance * 100)::bigint AS balance_cents,
-			COALESCE(SUM((l.amount * 100)::bigint), 0) AS ledger_sum_cents,
-			((a.balance * 100)::bigint - COALESCE(SUM((l.amount * 100)::bigint), 0)) AS diff_cents
+			(a.balance * power(10::numeric, c.minor_units))::bigint AS balance_minor,
+			COALESCE(SUM((l.amount * power(10::numeric, c.minor_units))::bigint), 0) AS ledger_sum_minor,
+			((a.balance * power(10::numeric, c.minor_units))::bigint - COALESCE(SUM((l.amount * power(10::numeric, c.minor_units))::bigint), 0)) AS diff_minor
 		FROM accounts a
+		JOIN currencies c ON c.code = a.currency
 		LEFT JOIN ledger l ON l.account_id = a.id
-		GROUP BY a.id, a.user_id, a.currency, a.balance
-		HAVING ((a.balance * 100)::bigint - COALESCE(SUM((l.amount * 100)::bigint), 0)) <> 0
-		ORDER BY ABS(((a.balance * 100)::bigint - COALESCE(SUM((l.amount * 100)::bigint), 0))) DESC
+		GROUP BY a.id, a.user_id, a.currency, a.balance, c.minor_units
+		HAVING (a.balance * power(10::numeric, c.minor_units))::bigint <> COALESCE(SUM((l.amount * power(10::numeric, c.minor_units))::bigint), 0)
+		ORDER BY ABS((a.balance * power(10::numeric, c.minor_units))::bigint - COALESCE(SUM((l.amount * power(10::numeric, c.minor_units))::bigint), 0)) DESC
 		LIMIT $1
 	`
 	rows, err := r.db.GetDB().QueryContext(ctx, query, limit)
@@ -143,3 +193,60 @@ func (r *LedgerRepository) FindAccountBalanceMismatches(ctx context.Context, lim
 	}
 	return out, rows.Err()
 }
+
+// SumBefore returns the ledger balance of an account over entries booked before the given time.
+func (r *LedgerRepository) SumBefore(ctx context.Context, accountID uuid.UUID, currency domain.Currency, before time.Time) (int64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0)::text FROM ledger WHERE account_id = $1 AND created_at < $2`
+	var sumStr string
+	if err := r.db.GetDB().QueryRowContext(ctx, query, accountID, before).Scan(&sumStr); err != nil {
+		return 0, err
+	}
+	sum, err := r.currencies.Parse(currency, sumStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ledger sum in db for account %s: %w", accountID.String(), err)
+	}
+	return sum, nil
+}
+
+// ListAccountEntries loads ledger entries of an account booked in [from, to), oldest first.
+// The running balance is a window sum over every entry of the account, not the cached accounts.balance.
+func (r *LedgerRepository) ListAccountEntries(ctx context.Context, accountID uuid.UUID, currency domain.Currency, from time.Time, to time.Time) ([]*domain.StatementLine, error) {
+	query := `
+		WITH entries AS (
+			SELECT l.id, l.transaction_id, t.type, t.description, l.amount, l.created_at,
+				SUM(l.amount) OVER (ORDER BY l.created_at, l.id) AS running
+			FROM ledger l
+			JOIN transactions t ON t.id = l.transaction_id
+			WHERE l.account_id = $1 AND l.created_at < $3
+		)
+		SELECT id, transaction_id, type, description, amount::text, running::text, created_at
+		FROM entries
+		WHERE created_at >= $2
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.GetDB().QueryContext(ctx, query, accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []*domain.StatementLine
+	for rows.Next() {
+		line := &domain.StatementLine{}
+		var amountStr, runningStr string
+		if err := rows.Scan(
+			&line.EntryID, &line.TransactionID, &line.Type, &line.Description, &amountStr, &runningStr, &line.BookedAt,
+		); err != nil {
+			return nil, err
+		}
+		if line.AmountCents, err = r.currencies.Parse(currency, amountStr); err != nil {
+			return nil, fmt.Errorf("invalid ledger amount in db for entry %s: %w", line.EntryID.String(), err)
+		}
+		if line.BalanceCents, err = r.currencies.Parse(currency, runningStr); err != nil {
+			return nil, fmt.Errorf("invalid running balance for entry %s: %w", line.EntryID.String(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
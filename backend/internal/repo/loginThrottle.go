@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"banking-platform/internal/domain"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+)
+
+type LoginThrottleRepository struct {
+	db *DB
+}
+
+func NewLoginThrottleRepository(db *DB) *LoginThrottleRepository {
+	return &LoginThrottleRepository{db: db}
+}
+
+const loginThrottleColumns = `scope, key, failures, first_failed_at, last_failed_at, locked_until`
+
+func (r *LoginThrottleRepository) Get(ctx context.Context, scope service.LoginThrottleScope, key string) (*service.LoginThrottleState, error) {
+	query := `SELECT ` + loginThrottleColumns + ` FROM login_throttles WHERE scope = $1 AND key = $2`
+	state, err := scanLoginThrottle(r.db.GetDB().QueryRowContext(ctx, query, scope, key))
+	if err == sql.ErrNoRows {
+		return &service.LoginThrottleState{Scope: scope, Key: key}, nil
+	}
+	return state, err
+}
+
+// LockTx inserts an empty row first so that concurrent first failures of a key serialize on it.
+func (r *LoginThrottleRepository) LockTx(ctx context.Context, tx service.Tx, scope service.LoginThrottleScope, key string) (*service.LoginThrottleState, error) {
+	insert := `
+		INSERT INTO login_throttles (scope, key, failures, first_failed_at, last_failed_at)
+		VALUES ($1, $2, 0, NOW(), NOW())
+		ON CONFLICT (scope, key) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, insert, scope, key); err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + loginThrottleColumns + ` FROM login_throttles WHERE scope = $1 AND key = $2 FOR UPDATE`
+	return scanLoginThrottle(tx.QueryRowContext(ctx, query, scope, key))
+}
+
+func (r *LoginThrottleRepository) SaveTx(ctx context.Context, tx service.Tx, state *service.LoginThrottleState) error {
+	query := `
+		UPDATE login_throttles
+		SET failures = $3, first_failed_at = $4, last_failed_at = $5, locked_until = $6
+		WHERE scope = $1 AND key = $2
+	`
+	_, err := tx.ExecContext(ctx, query,
+		state.Scope, state.Key, state.Failures, state.FirstFailedAt, state.LastFailedAt, state.LockedUntil)
+	return err
+}
+
+func (r *LoginThrottleRepository) Delete(ctx context.Context, scope service.LoginThrottleScope, key string) error {
+	_, err := r.db.GetDB().ExecContext(ctx, `DELETE FROM login_throttles WHERE scope = $1 AND key = $2`, scope, key)
+	return err
+}
+
+func (r *LoginThrottleRepository) ListEmails(ctx context.Context, since time.Time, limit int) ([]*domain.LoginLockout, error) {
+	query := `
+		SELECT t.key, u.id, t.failures, t.last_failed_at, t.locked_until
+		FROM login_throttles t
+		LEFT JOIN users u ON lower(u.email) = t.key
+		WHERE t.scope = 'email' AND t.failures > 0 AND t.last_failed_at >= $1
+		ORDER BY t.last_failed_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*domain.LoginLockout
+	for rows.Next() {
+		l := &domain.LoginLockout{}
+		var userID uuid.NullUUID
+		var lockedUntil sql.NullTime
+		if err := rows.Scan(&l.Email, &userID, &l.Failures, &l.LastFailedAt, &lockedUntil); err != nil {
+			return nil, err
+		}
+		if userID.Valid {
+			l.UserID = &userID.UUID
+		}
+		if lockedUntil.Valid {
+			l.LockedUntil = &lockedUntil.Time
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+func (r *LoginThrottleRepository) DeleteStale(ctx context.Context, before time.Time) error {
+	_, err := r.db.GetDB().ExecContext(ctx,
+		`DELETE FROM login_throttles WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until < $1)`, before)
+	return err
+}
+
+func scanLoginThrottle(row rowScanner) (*service.LoginThrottleState, error) {
+	s := &service.LoginThrottleState{}
+	var lockedUntil sql.NullTime
+	if err := row.Scan(&s.Scope, &s.Key, &s.Failures, &s.FirstFailedAt, &s.LastFailedAt, &lockedUntil); err != nil {
+		return nil, err
+	}
+	if lockedUntil.Valid {
+		s.LockedUntil = &lockedUntil.Time
+	}
+	return s, nil
+}
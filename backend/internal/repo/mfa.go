@@ -0,0 +1,125 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+)
+
+type MFARepository struct {
+	db *DB
+}
+
+func NewMFARepository(db *DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+const userMFAColumns = `user_id, secret, enabled_at, last_used_step, created_at, updated_at`
+
+func (r *MFARepository) Get(ctx context.Context, userID uuid.UUID) (*service.UserMFA, error) {
+	query := `SELECT ` + userMFAColumns + ` FROM user_mfa WHERE user_id = $1`
+	return scanUserMFA(r.db.GetDB().QueryRowContext(ctx, query, userID))
+}
+
+// LockTx returns the user's MFA row locked FOR UPDATE, so concurrent logins cannot accept the same code.
+func (r *MFARepository) LockTx(ctx context.Context, tx service.Tx, userID uuid.UUID) (*service.UserMFA, error) {
+	query := `SELECT ` + userMFAColumns + ` FROM user_mfa WHERE user_id = $1 FOR UPDATE`
+	return scanUserMFA(tx.QueryRowContext(ctx, query, userID))
+}
+
+// SavePending stores an unconfirmed secret. An enabled row is left untouched.
+func (r *MFARepository) SavePending(ctx context.Context, mfa *service.UserMFA) error {
+	query := `
+		INSERT INTO user_mfa (user_id, secret, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, updated_at = EXCLUDED.updated_at
+		WHERE user_mfa.enabled_at IS NULL
+	`
+	res, err := r.db.GetDB().ExecContext(ctx, query, mfa.UserID, mfa.Secret, mfa.CreatedAt)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperr.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *MFARepository) EnableTx(ctx context.Context, tx service.Tx, userID uuid.UUID, at time.Time, step int64) error {
+	query := `UPDATE user_mfa SET enabled_at = $2, last_used_step = $3, updated_at = $2 WHERE user_id = $1`
+	_, err := tx.ExecContext(ctx, query, userID, at, step)
+	return err
+}
+
+func (r *MFARepository) SetLastUsedStepTx(ctx context.Context, tx service.Tx, userID uuid.UUID, step int64) error {
+	query := `UPDATE user_mfa SET last_used_step = $2, updated_at = NOW() WHERE user_id = $1`
+	_, err := tx.ExecContext(ctx, query, userID, step)
+	return err
+}
+
+func (r *MFARepository) DeleteTx(ctx context.Context, tx service.Tx, userID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID)
+	return err
+}
+
+// ReplaceRecoveryCodesTx discards every existing code, used or not, and stores the new hashes.
+func (r *MFARepository) ReplaceRecoveryCodesTx(ctx context.Context, tx service.Tx, userID uuid.UUID, codeHashes []string, at time.Time) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	query := `INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at) VALUES ($1, $2, $3, $4)`
+	for _, h := range codeHashes {
+		if _, err := tx.ExecContext(ctx, query, uuid.New(), userID, h, at); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MFARepository) UseRecoveryCodeTx(ctx context.Context, tx service.Tx, userID uuid.UUID, codeHash string, at time.Time) (bool, error) {
+	query := `UPDATE mfa_recovery_codes SET used_at = $3 WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	res, err := tx.ExecContext(ctx, query, userID, codeHash, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MFARepository) CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+	err := r.db.GetDB().QueryRowContext(ctx, query, userID).Scan(&n)
+	return n, err
+}
+
+func scanUserMFA(row rowScanner) (*service.UserMFA, error) {
+	mfa := &service.UserMFA{}
+	var enabledAt sql.NullTime
+	err := row.Scan(&mfa.UserID, &mfa.Secret, &enabledAt, &mfa.LastUsedStep, &mfa.CreatedAt, &mfa.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrMFANotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if enabledAt.Valid {
+		mfa.EnabledAt = &enabledAt.Time
+	}
+	return mfa, nil
+}
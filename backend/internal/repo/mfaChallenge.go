@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+)
+
+type MFAChallengeRepository struct {
+	db *DB
+}
+
+func NewMFAChallengeRepository(db *DB) *MFAChallengeRepository {
+	return &MFAChallengeRepository{db: db}
+}
+
+func (r *MFAChallengeRepository) Create(ctx context.Context, challenge *service.MFAChallengeToken) error {
+	query := `
+		INSERT INTO mfa_challenges (id, user_id, token_hash, attempts, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query,
+		challenge.ID, challenge.UserID, challenge.TokenHash, challenge.Attempts, challenge.ExpiresAt, challenge.CreatedAt)
+	return err
+}
+
+// LockByTokenHashTx returns the challenge locked FOR UPDATE, expired and consumed ones included, so
+// the caller can count attempts.
+func (r *MFAChallengeRepository) LockByTokenHashTx(ctx context.Context, tx service.Tx, tokenHash string) (*service.MFAChallengeToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, attempts, expires_at, consumed_at, created_at
+		FROM mfa_challenges
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+	c := &service.MFAChallengeToken{}
+	var consumedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, query, tokenHash).Scan(
+		&c.ID, &c.UserID, &c.TokenHash, &c.Attempts, &c.ExpiresAt, &consumedAt, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrInvalidMFAChallenge
+	}
+	if err != nil {
+		return nil, err
+	}
+	if consumedAt.Valid {
+		c.ConsumedAt = &consumedAt.Time
+	}
+	return c, nil
+}
+
+func (r *MFAChallengeRepository) RecordAttemptTx(ctx context.Context, tx service.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `UPDATE mfa_challenges SET attempts = attempts + 1 WHERE id = $1`, id)
+	return err
+}
+
+func (r *MFAChallengeRepository) ConsumeTx(ctx context.Context, tx service.Tx, id uuid.UUID, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE mfa_challenges SET consumed_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+func (r *MFAChallengeRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	_, err := r.db.GetDB().ExecContext(ctx, `DELETE FROM mfa_challenges WHERE expires_at < $1`, before)
+	return err
+}
@@ -0,0 +1,188 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type PaymentRequestRepository struct {
+	db         *DB
+	currencies *domain.CurrencyRegistry
+}
+
+func NewPaymentRequestRepository(db *DB, currencies *domain.CurrencyRegistry) *PaymentRequestRepository {
+	return &PaymentRequestRepository{db: db, currencies: currencies}
+}
+
+const paymentRequestColumns = `
+	p.id, p.requester_id, requester.email, p.payer_id, payer.email, p.currency, p.amount, p.memo,
+	p.status, p.transaction_id, p.review_id, p.expires_at, p.created_at, p.updated_at`
+
+const paymentRequestFrom = `
+	FROM payment_requests p
+	JOIN users requester ON requester.id = p.requester_id
+	JOIN users payer ON payer.id = p.payer_id`
+
+func (r *PaymentRequestRepository) Create(ctx context.Context, pr *domain.PaymentRequest) error {
+	query := `
+		INSERT INTO payment_requests (id, requester_id, payer_id, currency, amount, memo, status, transaction_id, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query,
+		pr.ID, pr.RequesterID, pr.PayerID, pr.Currency, r.currencies.Format(pr.Currency, pr.AmountCents), pr.Memo,
+		pr.Status, pr.TransactionID, pr.ExpiresAt, pr.CreatedAt, pr.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PaymentRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.PaymentRequest, error) {
+	query := `SELECT ` + paymentRequestColumns + paymentRequestFrom + ` WHERE p.id = $1`
+	pr, err := r.scan(r.db.GetDB().QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrPaymentRequestNotFound
+	}
+	return pr, err
+}
+
+func (r *PaymentRequestRepository) GetByReviewID(ctx context.Context, reviewID uuid.UUID) (*domain.PaymentRequest, error) {
+	query := `SELECT ` + paymentRequestColumns + paymentRequestFrom + ` WHERE p.review_id = $1`
+	pr, err := r.scan(r.db.GetDB().QueryRowContext(ctx, query, reviewID))
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrPaymentRequestNotFound
+	}
+	return pr, err
+}
+
+// ListByUserID returns the user's requests, newest first. Pending requests past their expiry match
+// the expired status rather than the pending one.
+func (r *PaymentRequestRepository) ListByUserID(ctx context.Context, userID uuid.UUID, filter *domain.PaymentRequestFilter, now time.Time, limit int) ([]*domain.PaymentRequest, error) {
+	query := `
+		SELECT ` + paymentRequestColumns + paymentRequestFrom + `
+		WHERE CASE $2::text
+				WHEN 'incoming' THEN p.payer_id = $1
+				WHEN 'outgoing' THEN p.requester_id = $1
+				ELSE p.payer_id = $1 OR p.requester_id = $1
+			END
+		  AND CASE $3::text
+				WHEN '' THEN TRUE
+				WHEN 'pending' THEN p.status = 'pending' AND p.expires_at > $4
+				WHEN 'expired' THEN p.status = 'expired' OR (p.status = 'pending' AND p.expires_at <= $4)
+				ELSE p.status = $3::text
+			END
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $5
+	`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, userID, filter.Direction, filter.Status, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []*domain.PaymentRequest{}
+	for rows.Next() {
+		pr, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, pr)
+	}
+	return out, rows.Err()
+}
+
+// Transition moves a request from one of the from statuses to status, clearing its review when it goes
+// back to pending. It returns apperr.ErrPaymentRequestNotPending if the request is in any other status.
+func (r *PaymentRequestRepository) Transition(ctx context.Context, id uuid.UUID, from []domain.PaymentRequestStatus, status domain.PaymentRequestStatus, transactionID *uuid.UUID, at time.Time) error {
+	statuses := make([]string, len(from))
+	for i, s := range from {
+		statuses[i] = string(s)
+	}
+	query := `
+		UPDATE payment_requests
+		SET status = $3, transaction_id = COALESCE($4, transaction_id),
+			review_id = CASE WHEN $3 = 'pending' THEN NULL ELSE review_id END, updated_at = $5
+		WHERE id = $1 AND status = ANY($2)
+	`
+	res, err := r.db.GetDB().ExecContext(ctx, query, id, pq.Array(statuses), status, transactionID, at)
+	if err != nil {
+		return err
+	}
+	return r.checkUpdated(ctx, id, res)
+}
+
+// HoldForReview records the review a processing request's transfer is held in. It returns
+// apperr.ErrPaymentRequestNotPending if the request is not processing.
+func (r *PaymentRequestRepository) HoldForReview(ctx context.Context, id uuid.UUID, reviewID uuid.UUID, at time.Time) error {
+	query := `UPDATE payment_requests SET review_id = $2, updated_at = $3 WHERE id = $1 AND status = 'processing'`
+	res, err := r.db.GetDB().ExecContext(ctx, query, id, reviewID, at)
+	if err != nil {
+		return err
+	}
+	return r.checkUpdated(ctx, id, res)
+}
+
+// ClaimForAccept moves a pending request, or a processing one without a review, to processing. It
+// returns apperr.ErrPaymentRequestNotPending otherwise.
+func (r *PaymentRequestRepository) ClaimForAccept(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE payment_requests
+		SET status = 'processing', updated_at = $2
+		WHERE id = $1 AND (status = 'pending' OR (status = 'processing' AND review_id IS NULL))
+	`
+	res, err := r.db.GetDB().ExecContext(ctx, query, id, at)
+	if err != nil {
+		return err
+	}
+	return r.checkUpdated(ctx, id, res)
+}
+
+// checkUpdated tells a missing request from one whose status did not allow the update.
+func (r *PaymentRequestRepository) checkUpdated(ctx context.Context, id uuid.UUID, res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.GetDB().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM payment_requests WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return apperr.ErrPaymentRequestNotFound
+	}
+	return apperr.ErrPaymentRequestNotPending
+}
+
+func (r *PaymentRequestRepository) scan(row rowScanner) (*domain.PaymentRequest, error) {
+	pr := &domain.PaymentRequest{}
+	var amountStr string
+	var transactionID, reviewID uuid.NullUUID
+	if err := row.Scan(
+		&pr.ID, &pr.RequesterID, &pr.RequesterEmail, &pr.PayerID, &pr.PayerEmail, &pr.Currency, &amountStr, &pr.Memo,
+		&pr.Status, &transactionID, &reviewID, &pr.ExpiresAt, &pr.CreatedAt, &pr.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	amount, err := r.currencies.Parse(pr.Currency, amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment request amount in db for %s: %w", pr.ID.String(), err)
+	}
+	pr.AmountCents = amount
+	if transactionID.Valid {
+		pr.TransactionID = &transactionID.UUID
+	}
+	if reviewID.Valid {
+		pr.ReviewID = &reviewID.UUID
+	}
+	return pr, nil
+}
@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type RateLimitRepository struct {
+	db *DB
+}
+
+func NewRateLimitRepository(db *DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// Take runs the GCRA check and update in one statement; the row lock taken by the upsert serializes
+// concurrent requests for the same key across replicas. The WHERE clause skips the update when the
+// request does not fit, which returns no row.
+func (r *RateLimitRepository) Take(ctx context.Context, key string, now time.Time, interval time.Duration, tolerance time.Duration) (time.Time, bool, error) {
+	query := `
+		INSERT INTO rate_limits (key, tat)
+		VALUES ($1, $2::timestamp + $3::bigint * INTERVAL '1 microsecond')
+		ON CONFLICT (key) DO UPDATE
+		SET tat = GREATEST(rate_limits.tat, $2::timestamp) + $3::bigint * INTERVAL '1 microsecond'
+		WHERE GREATEST(rate_limits.tat, $2::timestamp) + ($3::bigint - $4::bigint) * INTERVAL '1 microsecond' <= $2::timestamp
+		RETURNING tat
+	`
+	var tat time.Time
+	err := r.db.GetDB().QueryRowContext(ctx, query, key, now, interval.Microseconds(), tolerance.Microseconds()).Scan(&tat)
+	if err == nil {
+		return tat, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return time.Time{}, false, err
+	}
+
+	if err := r.db.GetDB().QueryRowContext(ctx, `SELECT tat FROM rate_limits WHERE key = $1`, key).Scan(&tat); err != nil {
+		return time.Time{}, false, err
+	}
+	return tat, false, nil
+}
+
+func (r *RateLimitRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	_, err := r.db.GetDB().ExecContext(ctx, `DELETE FROM rate_limits WHERE tat < $1`, before)
+	return err
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"banking-platform/internal/apperr"
 	"banking-platform/internal/service"
@@ -17,36 +18,88 @@ func NewRefreshTokenRepository(db *DB) *RefreshTokenRepository {
 	return &RefreshTokenRepository{db: db}
 }
 
+const refreshTokenColumns = `
+	id, user_id, family_id, parent_id, token_hash, expires_at, created_at, rotated_at, revoked_at,
+	user_agent, ip, device_label, session_created_at`
+
+const insertRefreshToken = `
+	INSERT INTO refresh_tokens (
+		id, user_id, family_id, parent_id, token_hash, expires_at, created_at,
+		user_agent, ip, device_label, session_created_at
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+`
+
+func insertRefreshTokenArgs(token *service.RefreshToken) []any {
+	return []any{
+		token.ID, token.UserID, token.FamilyID, token.ParentID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+		token.UserAgent, token.IP, token.DeviceLabel, token.SessionCreatedAt,
+	}
+}
+
 // Create inserts a refresh token record.
 func (r *RefreshTokenRepository) Create(ctx context.Context, token *service.RefreshToken) error {
-	query := `
-		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, created_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-	_, err := r.db.GetDB().ExecContext(ctx, query,
-		token.ID, token.UserID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	_, err := r.db.GetDB().ExecContext(ctx, insertRefreshToken, insertRefreshTokenArgs(token)...)
+	return err
+}
+
+// CreateTx inserts a refresh token record within tx.
+func (r *RefreshTokenRepository) CreateTx(ctx context.Context, tx service.Tx, token *service.RefreshToken) error {
+	_, err := tx.ExecContext(ctx, insertRefreshToken, insertRefreshTokenArgs(token)...)
 	return err
 }
 
 // GetByTokenHash returns a non-expired refresh token record by hash.
 func (r *RefreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*service.RefreshToken, error) {
-	token := &service.RefreshToken{}
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1 AND expires_at > NOW()`
+	return scanRefreshToken(r.db.GetDB().QueryRowContext(ctx, query, tokenHash))
+}
+
+// LockByTokenHashTx returns a non-expired refresh token record by hash, locked FOR UPDATE so
+// concurrent refreshes of the same token are serialized.
+func (r *RefreshTokenRepository) LockByTokenHashTx(ctx context.Context, tx service.Tx, tokenHash string) (*service.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1 AND expires_at > NOW() FOR UPDATE`
+	return scanRefreshToken(tx.QueryRowContext(ctx, query, tokenHash))
+}
+
+// MarkRotatedTx records that the token was exchanged for a child token.
+func (r *RefreshTokenRepository) MarkRotatedTx(ctx context.Context, tx service.Tx, id uuid.UUID, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE refresh_tokens SET rotated_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+// RevokeFamilyTx revokes all tokens of the family that are not revoked yet.
+func (r *RefreshTokenRepository) RevokeFamilyTx(ctx context.Context, tx service.Tx, familyID uuid.UUID, at time.Time) (int64, error) {
+	res, err := tx.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL`, familyID, at)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ListActiveByUserID returns the live token of each of the user's sessions, most recently used first.
+func (r *RefreshTokenRepository) ListActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*service.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token_hash, expires_at, created_at
+		SELECT ` + refreshTokenColumns + `
 		FROM refresh_tokens
-		WHERE token_hash = $1 AND expires_at > NOW()
+		WHERE user_id = $1 AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC, id DESC
 	`
-
-	err := r.db.GetDB().QueryRowContext(ctx, query, tokenHash).Scan(
-		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, apperr.ErrInvalidToken
-	}
+	rows, err := r.db.GetDB().QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
-	return token, nil
+	defer rows.Close()
+
+	var out []*service.RefreshToken
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, token)
+	}
+	return out, rows.Err()
 }
 
 // Delete removes a refresh token record by hash.
@@ -62,3 +115,30 @@ func (r *RefreshTokenRepository) DeleteByUserID(ctx context.Context, userID uuid
 	_, err := r.db.GetDB().ExecContext(ctx, query, userID)
 	return err
 }
+
+func scanRefreshToken(row rowScanner) (*service.RefreshToken, error) {
+	token := &service.RefreshToken{}
+	var parentID uuid.NullUUID
+	var rotatedAt, revokedAt sql.NullTime
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &parentID, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt,
+		&rotatedAt, &revokedAt,
+		&token.UserAgent, &token.IP, &token.DeviceLabel, &token.SessionCreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		token.ParentID = &parentID.UUID
+	}
+	if rotatedAt.Valid {
+		token.RotatedAt = &rotatedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
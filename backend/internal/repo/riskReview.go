@@ -0,0 +1,189 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type RiskReviewRepository struct {
+	db         *DB
+	currencies *domain.CurrencyRegistry
+}
+
+func NewRiskReviewRepository(db *DB, currencies *domain.CurrencyRegistry) *RiskReviewRepository {
+	return &RiskReviewRepository{db: db, currencies: currencies}
+}
+
+const riskReviewColumns = `
+	id, user_id, type, currency, amount, to_user_id, rule, reason, request, idempotency_key,
+	status, reviewed_by, review_note, transaction_id, created_at, reviewed_at`
+
+// Create stores the original request as JSON: the TransferInput for transfers and the
+// ExchangeInput for exchanges.
+func (r *RiskReviewRepository) Create(ctx context.Context, review *domain.RiskReview) (*domain.RiskReview, error) {
+	var request any = review.Transfer
+	if review.Type == domain.TransactionTypeExchange {
+		request = review.Exchange
+	}
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal risk review request: %w", err)
+	}
+
+	query := `
+		INSERT INTO risk_reviews (` + riskReviewColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (user_id, type, idempotency_key) WHERE idempotency_key <> '' DO NOTHING
+	`
+	res, err := r.db.GetDB().ExecContext(ctx, query,
+		review.ID, review.UserID, review.Type, review.Currency, r.currencies.Format(review.Currency, review.AmountCents),
+		review.ToUserID, review.Rule, review.Reason, raw, review.IdempotencyKey,
+		review.Status, review.ReviewerID, review.ReviewNote, review.TransactionID, review.CreatedAt, review.ReviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 1 {
+		return review, nil
+	}
+	return r.GetByIdempotencyKey(ctx, review.UserID, review.Type, review.IdempotencyKey)
+}
+
+func (r *RiskReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RiskReview, error) {
+	query := `SELECT ` + riskReviewColumns + ` FROM risk_reviews WHERE id = $1`
+	review, err := r.scan(r.db.GetDB().QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrRiskReviewNotFound
+	}
+	return review, err
+}
+
+func (r *RiskReviewRepository) GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, typ domain.TransactionType, key string) (*domain.RiskReview, error) {
+	query := `
+		SELECT ` + riskReviewColumns + `
+		FROM risk_reviews
+		WHERE user_id = $1 AND type = $2 AND idempotency_key = $3 AND idempotency_key <> ''
+	`
+	review, err := r.scan(r.db.GetDB().QueryRowContext(ctx, query, userID, typ, key))
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrRiskReviewNotFound
+	}
+	return review, err
+}
+
+func (r *RiskReviewRepository) List(ctx context.Context, status domain.RiskReviewStatus, limit int) ([]*domain.RiskReview, error) {
+	query := `
+		SELECT ` + riskReviewColumns + `
+		FROM risk_reviews
+		WHERE $1::text = '' OR status = $1::text
+		ORDER BY created_at, id
+		LIMIT $2
+	`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []*domain.RiskReview{}
+	for rows.Next() {
+		review, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, review)
+	}
+	return out, rows.Err()
+}
+
+func (r *RiskReviewRepository) Transition(ctx context.Context, id uuid.UUID, from []domain.RiskReviewStatus, status domain.RiskReviewStatus, reviewerID *uuid.UUID, note string, transactionID *uuid.UUID, at *time.Time) error {
+	statuses := make([]string, len(from))
+	for i, s := range from {
+		statuses[i] = string(s)
+	}
+	query := `
+		UPDATE risk_reviews
+		SET status = $3, reviewed_by = $4, review_note = $5, transaction_id = $6, reviewed_at = $7
+		WHERE id = $1 AND status = ANY($2)
+	`
+	res, err := r.db.GetDB().ExecContext(ctx, query, id, pq.Array(statuses), status, reviewerID, note, transactionID, at)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.GetDB().QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM risk_reviews WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return apperr.ErrRiskReviewNotFound
+	}
+	return apperr.ErrRiskReviewNotPending
+}
+
+func (r *RiskReviewRepository) scan(row rowScanner) (*domain.RiskReview, error) {
+	review := &domain.RiskReview{}
+	var toUserID, reviewerID, transactionID uuid.NullUUID
+	var amountStr string
+	var request []byte
+	var reviewedAt sql.NullTime
+	if err := row.Scan(
+		&review.ID, &review.UserID, &review.Type, &review.Currency, &amountStr, &toUserID, &review.Rule, &review.Reason,
+		&request, &review.IdempotencyKey,
+		&review.Status, &reviewerID, &review.ReviewNote, &transactionID, &review.CreatedAt, &reviewedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	amount, err := r.currencies.Parse(review.Currency, amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid risk review amount in db for %s: %w", review.ID, err)
+	}
+	review.AmountCents = amount
+
+	var target any
+	if review.Type == domain.TransactionTypeExchange {
+		review.Exchange = &domain.ExchangeInput{}
+		target = review.Exchange
+	} else {
+		review.Transfer = &domain.TransferInput{}
+		target = review.Transfer
+	}
+	if err := json.Unmarshal(request, target); err != nil {
+		return nil, fmt.Errorf("invalid risk review request in db for %s: %w", review.ID, err)
+	}
+
+	if toUserID.Valid {
+		review.ToUserID = &toUserID.UUID
+	}
+	if reviewerID.Valid {
+		review.ReviewerID = &reviewerID.UUID
+	}
+	if transactionID.Valid {
+		review.TransactionID = &transactionID.UUID
+	}
+	if reviewedAt.Valid {
+		v := reviewedAt.Time
+		review.ReviewedAt = &v
+	}
+	return review, nil
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RiskSignalRepository struct {
+	db *DB
+}
+
+func NewRiskSignalRepository(db *DB) *RiskSignalRepository {
+	return &RiskSignalRepository{db: db}
+}
+
+// HasSentTo counts any posted transfer, including ones later reversed.
+func (r *RiskSignalRepository) HasSentTo(ctx context.Context, userID uuid.UUID, toUserID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM transactions t
+			JOIN accounts src ON src.id = t.from_account_id
+			JOIN accounts dst ON dst.id = t.to_account_id
+			WHERE t.type = 'transfer' AND t.status = 'posted' AND src.user_id = $1 AND dst.user_id = $2
+		)
+	`
+	var sent bool
+	err := r.db.GetDB().QueryRowContext(ctx, query, userID, toUserID).Scan(&sent)
+	return sent, err
+}
+
+// RecipientsSince counts pending authorizations as well as posted transfers.
+func (r *RiskSignalRepository) RecipientsSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT dst.user_id
+		FROM transactions t
+		JOIN accounts src ON src.id = t.from_account_id
+		JOIN accounts dst ON dst.id = t.to_account_id
+		WHERE t.type = 'transfer'
+		  AND t.status <> 'voided'
+		  AND src.user_id = $1
+		  AND dst.user_id <> $1
+		  AND t.created_at >= $2
+	`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
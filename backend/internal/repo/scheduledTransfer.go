@@ -0,0 +1,197 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ScheduledTransferRepository struct {
+	db         *DB
+	currencies *domain.CurrencyRegistry
+}
+
+func NewScheduledTransferRepository(db *DB, currencies *domain.CurrencyRegistry) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{db: db, currencies: currencies}
+}
+
+const scheduledTransferColumns = `
+	id, user_id, from_account_id, to_user_id, to_account_id, currency, amount, description,
+	schedule_kind, start_at, day_of_month, end_at,
+	status, next_run_at, next_attempt_at, attempts, last_error, last_transaction_id, locked_until,
+	created_at, updated_at`
+
+// Create stores a new scheduled transfer. The amount is stored in major units as DECIMAL(15,2).
+func (r *ScheduledTransferRepository) Create(ctx context.Context, st *domain.ScheduledTransfer) error {
+	query := `
+		INSERT INTO scheduled_transfers (` + scheduledTransferColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`
+	var dayOfMonth sql.NullInt64
+	if st.Schedule.DayOfMonth != 0 {
+		dayOfMonth = sql.NullInt64{Int64: int64(st.Schedule.DayOfMonth), Valid: true}
+	}
+	_, err := r.db.GetDB().ExecContext(
+		ctx,
+		query,
+		st.ID, st.UserID, st.FromAccountID, st.ToUserID, st.ToAccountID, st.Currency,
+		r.currencies.Format(st.Currency, st.AmountCents), st.Description,
+		st.Schedule.Kind, st.Schedule.StartAt, dayOfMonth, st.Schedule.EndAt,
+		st.Status, st.NextRunAt, st.NextAttemptAt, st.Attempts, st.LastError, st.LastTransactionID, st.LockedUntil,
+		st.CreatedAt, st.UpdatedAt,
+	)
+	return err
+}
+
+// ListByUserID returns the user's scheduled transfers, newest first.
+func (r *ScheduledTransferRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.ScheduledTransfer, error) {
+	query := `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return r.scanAll(rows)
+}
+
+// LockByIDTx locks a scheduled transfer row FOR UPDATE.
+func (r *ScheduledTransferRepository) LockByIDTx(ctx context.Context, tx service.Tx, id uuid.UUID) (*domain.ScheduledTransfer, error) {
+	query := `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers WHERE id = $1 FOR UPDATE`
+	st, err := r.scan(tx.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrScheduledTransferNotFound
+	}
+	return st, err
+}
+
+// ClaimDueTx leases up to limit due transfers until leaseUntil. Rows locked by another instance
+// are skipped, and leased rows stay invisible to other workers until the lease expires.
+func (r *ScheduledTransferRepository) ClaimDueTx(ctx context.Context, tx service.Tx, now time.Time, leaseUntil time.Time, limit int) ([]*domain.ScheduledTransfer, error) {
+	query := `
+		SELECT ` + scheduledTransferColumns + `
+		FROM scheduled_transfers
+		WHERE status = 'active'
+		  AND next_attempt_at <= $1
+		  AND (locked_until IS NULL OR locked_until <= $1)
+		ORDER BY next_attempt_at, id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := r.scanAll(rows)
+	rows.Close()
+	if err != nil || len(claimed) == 0 {
+		return claimed, err
+	}
+
+	ids := make([]string, len(claimed))
+	for i, st := range claimed {
+		ids[i] = st.ID.String()
+		lease := leaseUntil
+		st.LockedUntil = &lease
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE scheduled_transfers SET locked_until = $1 WHERE id = ANY($2::uuid[])`, leaseUntil, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("lease scheduled transfers: %w", err)
+	}
+	return claimed, nil
+}
+
+// UpdateStateTx persists the execution state of a scheduled transfer.
+func (r *ScheduledTransferRepository) UpdateStateTx(ctx context.Context, tx service.Tx, st *domain.ScheduledTransfer) error {
+	query := `
+		UPDATE scheduled_transfers
+		SET status = $1, next_run_at = $2, next_attempt_at = $3, attempts = $4, last_error = $5,
+			last_transaction_id = $6, locked_until = $7, updated_at = $8
+		WHERE id = $9
+	`
+	_, err := tx.ExecContext(ctx, query,
+		st.Status, st.NextRunAt, st.NextAttemptAt, st.Attempts, st.LastError,
+		st.LastTransactionID, st.LockedUntil, st.UpdatedAt, st.ID,
+	)
+	return err
+}
+
+// CreateRunTx records an execution attempt.
+func (r *ScheduledTransferRepository) CreateRunTx(ctx context.Context, tx service.Tx, run *domain.ScheduledTransferRun) error {
+	query := `
+		INSERT INTO scheduled_transfer_runs (id, scheduled_transfer_id, occurrence_at, attempt, status, transaction_id, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		run.ID, run.ScheduledTransferID, run.OccurrenceAt, run.Attempt, run.Status, run.TransactionID, run.Error, run.CreatedAt,
+	)
+	return err
+}
+
+func (r *ScheduledTransferRepository) scanAll(rows *sql.Rows) ([]*domain.ScheduledTransfer, error) {
+	var out []*domain.ScheduledTransfer
+	for rows.Next() {
+		st, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+func (r *ScheduledTransferRepository) scan(row rowScanner) (*domain.ScheduledTransfer, error) {
+	st := &domain.ScheduledTransfer{}
+	var fromAccountID, toUserID, toAccountID, lastTransactionID uuid.NullUUID
+	var amountStr string
+	var dayOfMonth sql.NullInt64
+	var endAt, lockedUntil sql.NullTime
+	var lastError sql.NullString
+	if err := row.Scan(
+		&st.ID, &st.UserID, &fromAccountID, &toUserID, &toAccountID, &st.Currency, &amountStr, &st.Description,
+		&st.Schedule.Kind, &st.Schedule.StartAt, &dayOfMonth, &endAt,
+		&st.Status, &st.NextRunAt, &st.NextAttemptAt, &st.Attempts, &lastError, &lastTransactionID, &lockedUntil,
+		&st.CreatedAt, &st.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	amount, err := r.currencies.Parse(st.Currency, amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduled transfer amount in db for %s: %w", st.ID.String(), err)
+	}
+	st.AmountCents = amount
+
+	if fromAccountID.Valid {
+		st.FromAccountID = &fromAccountID.UUID
+	}
+	if toUserID.Valid {
+		st.ToUserID = &toUserID.UUID
+	}
+	if toAccountID.Valid {
+		st.ToAccountID = &toAccountID.UUID
+	}
+	if lastTransactionID.Valid {
+		st.LastTransactionID = &lastTransactionID.UUID
+	}
+	if dayOfMonth.Valid {
+		st.Schedule.DayOfMonth = int(dayOfMonth.Int64)
+	}
+	if endAt.Valid {
+		v := endAt.Time
+		st.Schedule.EndAt = &v
+	}
+	if lockedUntil.Valid {
+		v := lockedUntil.Time
+		st.LockedUntil = &v
+	}
+	if lastError.Valid {
+		st.LastError = &lastError.String
+	}
+	return st, nil
+}
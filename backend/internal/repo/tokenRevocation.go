@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"banking-platform/internal/service"
+)
+
+type TokenRevocationRepository struct {
+	db *DB
+}
+
+func NewTokenRevocationRepository(db *DB) *TokenRevocationRepository {
+	return &TokenRevocationRepository{db: db}
+}
+
+// RevokeToken records a revoked jti. Revoking the same token twice is a no-op.
+func (r *TokenRevocationRepository) RevokeToken(ctx context.Context, token *service.RevokedAccessToken) error {
+	query := `
+		INSERT INTO revoked_access_tokens (jti, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, token.JTI, token.UserID, token.ExpiresAt, token.RevokedAt)
+	return err
+}
+
+// SetCutoff upserts the user's cutoff, keeping the later of the stored and the new value.
+func (r *TokenRevocationRepository) SetCutoff(ctx context.Context, cutoff *service.AccessTokenCutoff) error {
+	query := `
+		INSERT INTO access_token_cutoffs (user_id, revoked_before, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET revoked_before = GREATEST(access_token_cutoffs.revoked_before, EXCLUDED.revoked_before),
+		    updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, cutoff.UserID, cutoff.RevokedBefore, cutoff.UpdatedAt)
+	return err
+}
+
+func (r *TokenRevocationRepository) ListRevokedSince(ctx context.Context, since time.Time) ([]*service.RevokedAccessToken, error) {
+	query := `
+		SELECT jti, user_id, expires_at, revoked_at
+		FROM revoked_access_tokens
+		WHERE revoked_at >= $1 AND expires_at > NOW()
+	`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*service.RevokedAccessToken
+	for rows.Next() {
+		t := &service.RevokedAccessToken{}
+		if err := rows.Scan(&t.JTI, &t.UserID, &t.ExpiresAt, &t.RevokedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *TokenRevocationRepository) ListCutoffsSince(ctx context.Context, since time.Time) ([]*service.AccessTokenCutoff, error) {
+	query := `
+		SELECT user_id, revoked_before, updated_at
+		FROM access_token_cutoffs
+		WHERE updated_at >= $1
+	`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*service.AccessTokenCutoff
+	for rows.Next() {
+		c := &service.AccessTokenCutoff{}
+		if err := rows.Scan(&c.UserID, &c.RevokedBefore, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// RevokeSession records a revoked session. Revoking the same session twice is a no-op.
+func (r *TokenRevocationRepository) RevokeSession(ctx context.Context, session *service.RevokedSession) error {
+	query := `
+		INSERT INTO revoked_sessions (session_id, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (session_id) DO NOTHING
+	`
+	_, err := r.db.GetDB().ExecContext(ctx, query, session.SessionID, session.UserID, session.ExpiresAt, session.RevokedAt)
+	return err
+}
+
+func (r *TokenRevocationRepository) ListRevokedSessionsSince(ctx context.Context, since time.Time) ([]*service.RevokedSession, error) {
+	query := `
+		SELECT session_id, user_id, expires_at, revoked_at
+		FROM revoked_sessions
+		WHERE revoked_at >= $1 AND expires_at > NOW()
+	`
+	rows, err := r.db.GetDB().QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*service.RevokedSession
+	for rows.Next() {
+		s := &service.RevokedSession{}
+		if err := rows.Scan(&s.SessionID, &s.UserID, &s.ExpiresAt, &s.RevokedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *TokenRevocationRepository) DeleteExpired(ctx context.Context, now time.Time, cutoffsBefore time.Time) error {
+	if _, err := r.db.GetDB().ExecContext(ctx, `DELETE FROM revoked_access_tokens WHERE expires_at <= $1`, now); err != nil {
+		return err
+	}
+	if _, err := r.db.GetDB().ExecContext(ctx, `DELETE FROM revoked_sessions WHERE expires_at <= $1`, now); err != nil {
+		return err
+	}
+	_, err := r.db.GetDB().ExecContext(ctx, `DELETE FROM access_token_cutoffs WHERE revoked_before < $1`, cutoffsBefore)
+	return err
+}
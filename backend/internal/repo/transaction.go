@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"banking-platform/internal/apperr"
 	"banking-platform/internal/domain"
@@ -12,70 +13,132 @@ import (
 )
 
 type TransactionRepository struct {
-	db *DB
+	db         *DB
+	currencies *domain.CurrencyRegistry
 }
 
-func NewTransactionRepository(db *DB) *TransactionRepository {
-	return &TransactionRepository{db: db}
+func NewTransactionRepository(db *DB, currencies *domain.CurrencyRegistry) *TransactionRepository {
+	return &TransactionRepository{db: db, currencies: currencies}
 }
 
-// Create inserts a transaction row. Amounts are stored as DECIMAL(15,2) in DB.
+// Create inserts a transaction row. Amounts are stored in major units as DECIMAL(15,2) in DB;
+// the converted amount is in the currency of the destination account.
 func (r *TransactionRepository) Create(ctx context.Context, tx service.Tx, transaction *domain.Transaction) error {
 	query := `
-		INSERT INTO transactions (id, type, from_account_id, to_account_id, amount, currency, exchange_rate, converted_amount, description, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO transactions (id, type, from_account_id, to_account_id, amount, currency, exchange_rate, converted_amount, rate_source, rate_version, quote_id, reverses_transaction_id, description, status, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
+	status := transaction.Status
+	if status == "" {
+		status = domain.TransactionStatusPosted
+	}
 	var converted any = nil
 	if transaction.ConvertedAmountCents != nil {
-		converted = domain.CentsToDecimalString(*transaction.ConvertedAmountCents)
+		converted = r.currencies.Format(transaction.ToCurrency, *transaction.ConvertedAmountCents)
 	}
 	_, err := tx.ExecContext(
 		ctx,
 		query,
 		transaction.ID, transaction.Type, transaction.FromAccountID, transaction.ToAccountID,
-		domain.CentsToDecimalString(transaction.AmountCents), transaction.Currency, transaction.ExchangeRate,
-		converted, transaction.Description, transaction.CreatedAt,
+		r.currencies.Format(transaction.Currency, transaction.AmountCents), transaction.Currency, transaction.ExchangeRate,
+		converted, transaction.RateSource, transaction.RateVersion, transaction.QuoteID, transaction.ReversesTransactionID, transaction.Description,
+		status, transaction.ExpiresAt, transaction.CreatedAt,
 	)
 	return err
 }
 
-// GetByUserID returns a filtered/paginated list of transactions visible to a user.
-func (r *TransactionRepository) GetByUserID(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter) ([]*domain.TransactionWithEmails, error) {
-	query := `
-		SELECT 
-			t.id, t.type, t.from_account_id, t.to_account_id, t.amount, t.currency,
-			t.exchange_rate, t.converted_amount, t.description, t.created_at,
-			from_user.email as from_user_email,
-			to_user.email as to_user_email
+// SettleTx writes the status and captured amount of a transaction, for capturing or voiding an authorization.
+func (r *TransactionRepository) SettleTx(ctx context.Context, tx service.Tx, transaction *domain.Transaction) error {
+	var captured any = nil
+	if transaction.CapturedAmountCents != nil {
+		captured = r.currencies.Format(transaction.Currency, *transaction.CapturedAmountCents)
+	}
+	query := `UPDATE transactions SET status = $1, captured_amount = $2 WHERE id = $3`
+	_, err := tx.ExecContext(ctx, query, transaction.Status, captured, transaction.ID)
+	return err
+}
+
+// VoidExpired voids pending authorizations that expired at or before now and returns how many.
+func (r *TransactionRepository) VoidExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := `UPDATE transactions SET status = 'voided' WHERE status = 'pending' AND expires_at <= $1`
+	res, err := r.db.GetDB().ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const transactionWithEmailsFrom = `
 		FROM transactions t
 		LEFT JOIN accounts from_acc ON t.from_account_id = from_acc.id
 		LEFT JOIN users from_user ON from_acc.user_id = from_user.id
 		JOIN accounts to_acc ON t.to_account_id = to_acc.id
 		JOIN users to_user ON to_acc.user_id = to_user.id
-		WHERE (from_acc.user_id = $1 OR to_acc.user_id = $1)
-	`
+		JOIN currencies cur ON cur.code = t.currency
+`
 
+// transactionFilterWhere builds the WHERE clause shared by the list and count queries.
+// Amount bounds are in minor units of the transaction currency and match the posted amount.
+func transactionFilterWhere(userID uuid.UUID, filter *domain.TransactionFilter) (string, []interface{}) {
+	where := ` WHERE (from_acc.user_id = $1 OR to_acc.user_id = $1)`
 	args := []interface{}{userID}
-	argIndex := 2
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where += " AND " + fmt.Sprintf(cond, len(args))
+	}
 
 	if filter.Type != "" {
-		query += fmt.Sprintf(" AND t.type = $%d", argIndex)
-		args = append(args, filter.Type)
-		argIndex++
+		add("t.type = $%d", filter.Type)
 	}
+	if filter.Currency != "" {
+		add("t.currency = $%d", filter.Currency)
+	}
+	if filter.Status != "" {
+		add("t.status = $%d", filter.Status)
+	}
+	if filter.From != nil {
+		add("t.created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("t.created_at < $%d", *filter.To)
+	}
+	if filter.MinAmount != nil {
+		add("COALESCE(t.captured_amount, t.amount) * power(10::numeric, cur.minor_units) >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		add("COALESCE(t.captured_amount, t.amount) * power(10::numeric, cur.minor_units) <= $%d", *filter.MaxAmount)
+	}
+	switch filter.Direction {
+	case domain.TransactionDirectionOutgoing:
+		where += " AND from_acc.user_id = $1"
+	case domain.TransactionDirectionIncoming:
+		where += " AND to_acc.user_id = $1"
+	}
+	if filter.Counterparty != "" {
+		// The counterparty is the other side as seen from the user.
+		add("(CASE WHEN from_acc.user_id = $1 THEN to_user.email ELSE from_user.email END) = $%d", filter.Counterparty)
+	}
+	return where, args
+}
 
-	query += " ORDER BY t.created_at DESC"
-
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argIndex)
-		args = append(args, filter.Limit)
-		argIndex++
-		if filter.Page > 0 {
-			offset := (filter.Page - 1) * filter.Limit
-			query += fmt.Sprintf(" OFFSET $%d", argIndex)
-			args = append(args, offset)
-		}
+// GetByUserID returns up to limit transactions visible to a user, newest first, strictly after
+// the keyset position when one is given.
+func (r *TransactionRepository) GetByUserID(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter, after *domain.TransactionCursor, limit int) ([]*domain.TransactionWithEmails, error) {
+	where, args := transactionFilterWhere(userID, filter)
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		where += fmt.Sprintf(" AND (t.created_at, t.id) < ($%d, $%d)", len(args)-1, len(args))
 	}
+	args = append(args, limit)
+
+	query := `
+		SELECT
+			t.id, t.type, t.from_account_id, t.to_account_id, t.amount, t.currency,
+			t.exchange_rate, t.converted_amount, to_acc.currency, t.rate_source, t.rate_version, t.quote_id, t.reverses_transaction_id, t.description,
+			t.status, t.expires_at, t.captured_amount, t.created_at,
+			from_user.email as from_user_email,
+			to_user.email as to_user_email
+	` + transactionWithEmailsFrom + where + fmt.Sprintf(" ORDER BY t.created_at DESC, t.id DESC LIMIT $%d", len(args))
 
 	rows, err := r.db.GetDB().QueryContext(ctx, query, args...)
 	if err != nil {
@@ -85,76 +148,176 @@ func (r *TransactionRepository) GetByUserID(ctx context.Context, userID uuid.UUI
 
 	var transactions []*domain.TransactionWithEmails
 	for rows.Next() {
-		out := &domain.TransactionWithEmails{}
-		t := &out.Transaction
-		var fromAccountID sql.NullString
-		var fromUserEmail, toUserEmail sql.NullString
-		var amountStr string
-		var convertedStr sql.NullString
-		var exchangeRate sql.NullFloat64
-
-		if err := rows.Scan(
-			&t.ID, &t.Type, &fromAccountID, &t.ToAccountID, &amountStr, &t.Currency,
-			&exchangeRate, &convertedStr, &t.Description, &t.CreatedAt,
-			&fromUserEmail, &toUserEmail,
-		); err != nil {
+		out, err := scanTransactionWithEmails(rows, r.currencies)
+		if err != nil {
 			return nil, err
 		}
+		transactions = append(transactions, out)
+	}
+	return transactions, rows.Err()
+}
 
-		if fromAccountID.Valid {
-			parsed, err := uuid.Parse(fromAccountID.String)
-			if err != nil {
-				return nil, err
-			}
-			t.FromAccountID = &parsed
-		}
+// CountByUserID counts the transactions matching the filter, ignoring pagination.
+func (r *TransactionRepository) CountByUserID(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter) (int64, error) {
+	where, args := transactionFilterWhere(userID, filter)
+	query := `SELECT COUNT(*)` + transactionWithEmailsFrom + where
 
-		ac, err := domain.DecimalStringToCents(amountStr)
+	var count int64
+	if err := r.db.GetDB().QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetWithEmailsByID loads a transaction by id together with participant emails.
+func (r *TransactionRepository) GetWithEmailsByID(ctx context.Context, id uuid.UUID) (*domain.TransactionWithEmails, error) {
+	query := `
+		SELECT
+			t.id, t.type, t.from_account_id, t.to_account_id, t.amount, t.currency,
+			t.exchange_rate, t.converted_amount, to_acc.currency, t.rate_source, t.rate_version, t.quote_id, t.reverses_transaction_id, t.description,
+			t.status, t.expires_at, t.captured_amount, t.created_at,
+			from_user.email as from_user_email,
+			to_user.email as to_user_email
+		FROM transactions t
+		LEFT JOIN accounts from_acc ON t.from_account_id = from_acc.id
+		LEFT JOIN users from_user ON from_acc.user_id = from_user.id
+		JOIN accounts to_acc ON t.to_account_id = to_acc.id
+		JOIN users to_user ON to_acc.user_id = to_user.id
+		WHERE t.id = $1
+	`
+	out, err := scanTransactionWithEmails(r.db.GetDB().QueryRowContext(ctx, query, id), r.currencies)
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTransactionWithEmails(row rowScanner, currencies *domain.CurrencyRegistry) (*domain.TransactionWithEmails, error) {
+	out := &domain.TransactionWithEmails{}
+	t := &out.Transaction
+	var fromAccountID sql.NullString
+	var fromUserEmail, toUserEmail sql.NullString
+	var amountStr string
+	var convertedStr sql.NullString
+	var exchangeRate sql.NullFloat64
+	var quoteID, reversesID uuid.NullUUID
+	var expiresAt sql.NullTime
+	var capturedStr sql.NullString
+
+	if err := row.Scan(
+		&t.ID, &t.Type, &fromAccountID, &t.ToAccountID, &amountStr, &t.Currency,
+		&exchangeRate, &convertedStr, &t.ToCurrency, &t.RateSource, &t.RateVersion, &quoteID, &reversesID, &t.Description,
+		&t.Status, &expiresAt, &capturedStr, &t.CreatedAt,
+		&fromUserEmail, &toUserEmail,
+	); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		v := expiresAt.Time
+		t.ExpiresAt = &v
+	}
+
+	if fromAccountID.Valid {
+		parsed, err := uuid.Parse(fromAccountID.String)
 		if err != nil {
-			return nil, fmt.Errorf("invalid transaction amount in db for %s: %w", t.ID.String(), err)
+			return nil, err
 		}
-		t.AmountCents = ac
+		t.FromAccountID = &parsed
+	}
 
-		if exchangeRate.Valid {
-			v := exchangeRate.Float64
-			t.ExchangeRate = &v
-		}
-		if convertedStr.Valid {
-			cc, err := domain.DecimalStringToCents(convertedStr.String)
-			if err != nil {
-				return nil, fmt.Errorf("invalid converted_amount in db for %s: %w", t.ID.String(), err)
-			}
-			t.ConvertedAmountCents = &cc
+	ac, err := currencies.Parse(t.Currency, amountStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction amount in db for %s: %w", t.ID.String(), err)
+	}
+	t.AmountCents = ac
+	if capturedStr.Valid {
+		cc, err := currencies.Parse(t.Currency, capturedStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid captured_amount in db for %s: %w", t.ID.String(), err)
 		}
+		t.CapturedAmountCents = &cc
+	}
 
-		if fromUserEmail.Valid {
-			out.FromUserEmail = &fromUserEmail.String
-		}
-		if toUserEmail.Valid {
-			out.ToUserEmail = &toUserEmail.String
+	if exchangeRate.Valid {
+		v := exchangeRate.Float64
+		t.ExchangeRate = &v
+	}
+	if convertedStr.Valid {
+		cc, err := currencies.Parse(t.ToCurrency, convertedStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid converted_amount in db for %s: %w", t.ID.String(), err)
 		}
+		t.ConvertedAmountCents = &cc
+	}
 
-		transactions = append(transactions, out)
+	if quoteID.Valid {
+		t.QuoteID = &quoteID.UUID
 	}
-	return transactions, rows.Err()
+	if reversesID.Valid {
+		t.ReversesTransactionID = &reversesID.UUID
+	}
+
+	if fromUserEmail.Valid {
+		out.FromUserEmail = &fromUserEmail.String
+	}
+	if toUserEmail.Valid {
+		out.ToUserEmail = &toUserEmail.String
+	}
+	return out, nil
 }
 
 // GetByID loads a transaction by id and decodes DECIMAL amounts into cents.
 func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
-	transaction := &domain.Transaction{}
-	query := `
-		SELECT id, type, from_account_id, to_account_id, amount, currency, exchange_rate, converted_amount, description, created_at
-		FROM transactions WHERE id = $1
-	`
+	query := `SELECT ` + transactionColumns + ` FROM transactions t JOIN accounts to_acc ON t.to_account_id = to_acc.id WHERE t.id = $1`
+	return r.getOne(r.db.GetDB().QueryRowContext(ctx, query, id))
+}
+
+// LockByIDTx locks the transaction row FOR UPDATE so concurrent reversals of it serialize.
+func (r *TransactionRepository) LockByIDTx(ctx context.Context, tx service.Tx, id uuid.UUID) (*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions t JOIN accounts to_acc ON t.to_account_id = to_acc.id WHERE t.id = $1 FOR UPDATE OF t`
+	return r.getOne(tx.QueryRowContext(ctx, query, id))
+}
 
+// SumReversalsTx returns how much of a transaction has already been reversed, in its currency.
+func (r *TransactionRepository) SumReversalsTx(ctx context.Context, tx service.Tx, id uuid.UUID, currency domain.Currency) (int64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0)::text FROM transactions WHERE reverses_transaction_id = $1`
+	var sumStr string
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&sumStr); err != nil {
+		return 0, err
+	}
+	sum, err := r.currencies.Parse(currency, sumStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid reversal sum in db for %s: %w", id.String(), err)
+	}
+	return sum, nil
+}
+
+const transactionColumns = `
+	t.id, t.type, t.from_account_id, t.to_account_id, t.amount, t.currency,
+	t.exchange_rate, t.converted_amount, to_acc.currency, t.rate_source, t.rate_version, t.quote_id, t.reverses_transaction_id, t.description,
+	t.status, t.expires_at, t.captured_amount, t.created_at`
+
+func (r *TransactionRepository) getOne(row rowScanner) (*domain.Transaction, error) {
+	transaction := &domain.Transaction{}
 	var fromAccountID sql.NullString
 	var amountStr string
 	var convertedStr sql.NullString
 	var exchangeRate sql.NullFloat64
-	err := r.db.GetDB().QueryRowContext(ctx, query, id).Scan(
+	var quoteID, reversesID uuid.NullUUID
+	var expiresAt sql.NullTime
+	var capturedStr sql.NullString
+	err := row.Scan(
 		&transaction.ID, &transaction.Type, &fromAccountID, &transaction.ToAccountID,
 		&amountStr, &transaction.Currency, &exchangeRate,
-		&convertedStr, &transaction.Description, &transaction.CreatedAt,
+		&convertedStr, &transaction.ToCurrency, &transaction.RateSource, &transaction.RateVersion, &quoteID, &reversesID, &transaction.Description,
+		&transaction.Status, &expiresAt, &capturedStr, &transaction.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, apperr.ErrTransactionNotFound
@@ -168,23 +331,40 @@ func (r *TransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 		transaction.FromAccountID = &parsedID
 	}
 
-	ac, err := domain.DecimalStringToCents(amountStr)
+	ac, err := r.currencies.Parse(transaction.Currency, amountStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid transaction amount in db for %s: %w", transaction.ID.String(), err)
 	}
 	transaction.AmountCents = ac
+	if capturedStr.Valid {
+		cc, err := r.currencies.Parse(transaction.Currency, capturedStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid captured_amount in db for %s: %w", transaction.ID.String(), err)
+		}
+		transaction.CapturedAmountCents = &cc
+	}
 
 	if exchangeRate.Valid {
 		v := exchangeRate.Float64
 		transaction.ExchangeRate = &v
 	}
 	if convertedStr.Valid {
-		cc, err := domain.DecimalStringToCents(convertedStr.String)
+		cc, err := r.currencies.Parse(transaction.ToCurrency, convertedStr.String)
 		if err != nil {
 			return nil, fmt.Errorf("invalid converted_amount in db for %s: %w", transaction.ID.String(), err)
 		}
 		transaction.ConvertedAmountCents = &cc
 	}
+	if quoteID.Valid {
+		transaction.QuoteID = &quoteID.UUID
+	}
+	if reversesID.Valid {
+		transaction.ReversesTransactionID = &reversesID.UUID
+	}
+	if expiresAt.Valid {
+		v := expiresAt.Time
+		transaction.ExpiresAt = &v
+	}
 
 	return transaction, nil
 }
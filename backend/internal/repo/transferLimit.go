@@ -0,0 +1,219 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/internal/service"
+	"github.com/google/uuid"
+)
+
+type TransferLimitRepository struct {
+	db         *DB
+	currencies *domain.CurrencyRegistry
+}
+
+func NewTransferLimitRepository(db *DB, currencies *domain.CurrencyRegistry) *TransferLimitRepository {
+	return &TransferLimitRepository{db: db, currencies: currencies}
+}
+
+// effectiveLimitsFrom joins a user row u with its tier and override rows for the currency c.code.
+const effectiveLimitsFrom = `
+	LEFT JOIN transfer_limit_tiers t ON t.tier = u.transfer_tier AND t.currency = c.code
+	LEFT JOIN user_transfer_limits o ON o.user_id = u.id AND o.currency = c.code
+`
+
+const effectiveLimitsColumns = `c.code,
+	COALESCE(o.max_single_amount, t.max_single_amount)::text,
+	COALESCE(o.daily_amount, t.daily_amount)::text,
+	COALESCE(o.monthly_amount, t.monthly_amount)::text,
+	COALESCE(o.hourly_count, t.hourly_count)`
+
+func (r *TransferLimitRepository) LockUsageTx(ctx context.Context, tx service.Tx, userID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`,
+		"transfer_limit:"+userID.String())
+	return err
+}
+
+func (r *TransferLimitRepository) GetEffectiveTx(ctx context.Context, tx service.Tx, userID uuid.UUID, currency domain.Currency) (*domain.TransferLimits, error) {
+	query := `
+		SELECT ` + effectiveLimitsColumns + `
+		FROM users u
+		JOIN currencies c ON c.code = $2
+		` + effectiveLimitsFrom + `
+		WHERE u.id = $1
+	`
+	limits, err := r.scanLimits(tx.QueryRowContext(ctx, query, userID, currency))
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrUserNotFound
+	}
+	return limits, err
+}
+
+// GetUsageTx sums the user's debits of transfers whose receiving account belongs to someone else.
+// Amounts are in currency only; the hourly count spans every currency. Reversals are separate
+// transactions, so a reversed transfer still counts.
+func (r *TransferLimitRepository) GetUsageTx(ctx context.Context, tx service.Tx, userID uuid.UUID, currency domain.Currency, dayStart time.Time, monthStart time.Time, hourStart time.Time) (*domain.TransferUsage, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(-l.amount) FILTER (WHERE a.currency = $2 AND l.created_at >= $3), 0)::text,
+			COALESCE(SUM(-l.amount) FILTER (WHERE a.currency = $2 AND l.created_at >= $4), 0)::text,
+			COUNT(*) FILTER (WHERE l.created_at >= $5),
+			MIN(l.created_at) FILTER (WHERE l.created_at >= $5)
+		FROM ledger l
+		JOIN accounts a ON a.id = l.account_id
+		JOIN transactions t ON t.id = l.transaction_id
+		JOIN accounts dest ON dest.id = t.to_account_id
+		WHERE a.user_id = $1
+		  AND l.amount < 0
+		  AND t.type = 'transfer'
+		  AND dest.user_id <> $1
+		  AND l.created_at >= LEAST($4::timestamp, $5::timestamp)
+	`
+	var dayStr, monthStr string
+	var oldest sql.NullTime
+	usage := &domain.TransferUsage{}
+	if err := tx.QueryRowContext(ctx, query, userID, currency, dayStart, monthStart, hourStart).
+		Scan(&dayStr, &monthStr, &usage.HourCount, &oldest); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if usage.DayCents, err = r.currencies.Parse(currency, dayStr); err != nil {
+		return nil, fmt.Errorf("invalid daily transfer sum in db for user %s: %w", userID, err)
+	}
+	if usage.MonthCents, err = r.currencies.Parse(currency, monthStr); err != nil {
+		return nil, fmt.Errorf("invalid monthly transfer sum in db for user %s: %w", userID, err)
+	}
+	if oldest.Valid {
+		usage.OldestInHour = &oldest.Time
+	}
+	return usage, nil
+}
+
+func (r *TransferLimitRepository) GetTier(ctx context.Context, userID uuid.UUID) (string, error) {
+	var tier string
+	err := r.db.GetDB().QueryRowContext(ctx, `SELECT transfer_tier FROM users WHERE id = $1`, userID).Scan(&tier)
+	if err == sql.ErrNoRows {
+		return "", apperr.ErrUserNotFound
+	}
+	return tier, err
+}
+
+func (r *TransferLimitRepository) ListOverrides(ctx context.Context, userID uuid.UUID) ([]*domain.TransferLimits, error) {
+	query := `
+		SELECT currency, max_single_amount::text, daily_amount::text, monthly_amount::text, hourly_count
+		FROM user_transfer_limits
+		WHERE user_id = $1
+		ORDER BY currency
+	`
+	return r.queryLimits(ctx, query, userID)
+}
+
+func (r *TransferLimitRepository) ListEffective(ctx context.Context, userID uuid.UUID) ([]*domain.TransferLimits, error) {
+	query := `
+		SELECT ` + effectiveLimitsColumns + `
+		FROM users u
+		CROSS JOIN currencies c
+		` + effectiveLimitsFrom + `
+		WHERE u.id = $1 AND c.enabled
+		ORDER BY c.code
+	`
+	return r.queryLimits(ctx, query, userID)
+}
+
+func (r *TransferLimitRepository) TierExistsTx(ctx context.Context, tx service.Tx, tier string) (bool, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM transfer_tiers WHERE name = $1)`, tier).Scan(&exists)
+	return exists, err
+}
+
+func (r *TransferLimitRepository) SetTierTx(ctx context.Context, tx service.Tx, userID uuid.UUID, tier string) error {
+	res, err := tx.ExecContext(ctx, `UPDATE users SET transfer_tier = $2, updated_at = NOW() WHERE id = $1`, userID, tier)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return apperr.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *TransferLimitRepository) ReplaceOverridesTx(ctx context.Context, tx service.Tx, userID uuid.UUID, overrides []*domain.TransferLimits) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_transfer_limits WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	insert := `
+		INSERT INTO user_transfer_limits (user_id, currency, max_single_amount, daily_amount, monthly_amount, hourly_count, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+	`
+	for _, o := range overrides {
+		if _, err := tx.ExecContext(ctx, insert, userID, o.Currency,
+			r.formatLimit(o.Currency, o.MaxSingleCents),
+			r.formatLimit(o.Currency, o.DailyCents),
+			r.formatLimit(o.Currency, o.MonthlyCents),
+			o.HourlyCount,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TransferLimitRepository) formatLimit(currency domain.Currency, cents *int64) *string {
+	if cents == nil {
+		return nil
+	}
+	s := r.currencies.Format(currency, *cents)
+	return &s
+}
+
+func (r *TransferLimitRepository) queryLimits(ctx context.Context, query string, args ...any) ([]*domain.TransferLimits, error) {
+	rows, err := r.db.GetDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []*domain.TransferLimits{}
+	for rows.Next() {
+		limits, err := r.scanLimits(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, limits)
+	}
+	return out, rows.Err()
+}
+
+func (r *TransferLimitRepository) scanLimits(row rowScanner) (*domain.TransferLimits, error) {
+	l := &domain.TransferLimits{}
+	var maxSingle, daily, monthly sql.NullString
+	var hourly sql.NullInt64
+	if err := row.Scan(&l.Currency, &maxSingle, &daily, &monthly, &hourly); err != nil {
+		return nil, err
+	}
+
+	for _, f := range []struct {
+		raw sql.NullString
+		dst **int64
+	}{{maxSingle, &l.MaxSingleCents}, {daily, &l.DailyCents}, {monthly, &l.MonthlyCents}} {
+		if !f.raw.Valid {
+			continue
+		}
+		cents, err := r.currencies.Parse(l.Currency, f.raw.String)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer limit in db for currency %s: %w", l.Currency, err)
+		}
+		*f.dst = &cents
+	}
+	if hourly.Valid {
+		n := int(hourly.Int64)
+		l.HourlyCount = &n
+	}
+	return l, nil
+}
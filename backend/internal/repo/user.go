@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"strings"
+	"time"
 
 	"banking-platform/internal/apperr"
 	"banking-platform/internal/domain"
+	"banking-platform/internal/service"
 	"github.com/google/uuid"
 )
 
@@ -22,60 +24,38 @@ func NewUserRepository(db *DB) *UserRepository {
 func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
 	query := `
-		INSERT INTO users (id, email, password, first_name, last_name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, email, password, first_name, last_name, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
+	if user.Role == "" {
+		user.Role = domain.RoleCustomer
+	}
 	_, err := r.db.GetDB().ExecContext(
 		ctx,
 		query,
-		user.ID, user.Email, user.PasswordHash, user.FirstName, user.LastName,
+		user.ID, user.Email, user.PasswordHash, user.FirstName, user.LastName, user.Role,
 		user.CreatedAt, user.UpdatedAt,
 	)
 	return err
 }
 
+const userColumns = `id, email, password, first_name, last_name, role, email_verified_at, created_at, updated_at`
+
 // GetByEmail returns a user by email.
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	user := &domain.User{}
 	email = strings.ToLower(strings.TrimSpace(email))
-	query := `SELECT id, email, password, first_name, last_name, created_at, updated_at
-			  FROM users WHERE lower(email) = lower($1)`
-
-	err := r.db.GetDB().QueryRowContext(ctx, query, email).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.FirstName,
-		&user.LastName, &user.CreatedAt, &user.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, apperr.ErrUserNotFound
-	}
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	query := `SELECT ` + userColumns + ` FROM users WHERE lower(email) = lower($1)`
+	return scanUser(r.db.GetDB().QueryRowContext(ctx, query, email))
 }
 
 // GetByID returns a user by UUID.
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	user := &domain.User{}
-	query := `SELECT id, email, password, first_name, last_name, created_at, updated_at
-			  FROM users WHERE id = $1`
-
-	err := r.db.GetDB().QueryRowContext(ctx, query, id).Scan(
-		&user.ID, &user.Email, &user.PasswordHash, &user.FirstName,
-		&user.LastName, &user.CreatedAt, &user.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, apperr.ErrUserNotFound
-	}
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return scanUser(r.db.GetDB().QueryRowContext(ctx, query, id))
 }
 
 func (r *UserRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
-	query := `SELECT id, email, password, first_name, last_name, created_at, updated_at
-			  FROM users ORDER BY created_at`
+	query := `SELECT ` + userColumns + ` FROM users ORDER BY created_at`
 
 	rows, err := r.db.GetDB().QueryContext(ctx, query)
 	if err != nil {
@@ -85,14 +65,56 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
 
 	var users []*domain.User
 	for rows.Next() {
-		user := &domain.User{}
-		if err := rows.Scan(
-			&user.ID, &user.Email, &user.PasswordHash, &user.FirstName,
-			&user.LastName, &user.CreatedAt, &user.UpdatedAt,
-		); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
 		users = append(users, user)
 	}
 	return users, rows.Err()
 }
+
+// UpdatePasswordTx replaces the password hash.
+func (r *UserRepository) UpdatePasswordTx(ctx context.Context, tx service.Tx, id uuid.UUID, passwordHash string, at time.Time) error {
+	res, err := tx.ExecContext(ctx, `UPDATE users SET password = $2, updated_at = $3 WHERE id = $1`, id, passwordHash, at)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return apperr.ErrUserNotFound
+	}
+	return nil
+}
+
+// ReplacePasswordHash upgrades the stored hash without touching updated_at; the password is the same.
+func (r *UserRepository) ReplacePasswordHash(ctx context.Context, id uuid.UUID, oldHash string, newHash string) error {
+	_, err := r.db.GetDB().ExecContext(ctx,
+		`UPDATE users SET password = $3 WHERE id = $1 AND password = $2`, id, oldHash, newHash)
+	return err
+}
+
+// MarkEmailVerifiedTx records the first successful verification; later calls keep the original time.
+func (r *UserRepository) MarkEmailVerifiedTx(ctx context.Context, tx service.Tx, id uuid.UUID, at time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE users SET email_verified_at = COALESCE(email_verified_at, $2), updated_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	user := &domain.User{}
+	var verifiedAt sql.NullTime
+	err := row.Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.FirstName,
+		&user.LastName, &user.Role, &verifiedAt, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, apperr.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if verifiedAt.Valid {
+		user.EmailVerifiedAt = &verifiedAt.Time
+	}
+	return user, nil
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"banking-platform/config"
+	"banking-platform/internal/domain"
 	handler "banking-platform/internal/http/handlers"
 	"banking-platform/internal/http/middleware"
 	"banking-platform/internal/repo"
@@ -23,20 +25,27 @@ type Server struct {
 func NewServer(
 	cfg *config.Config,
 	db *repo.DB,
+	keys handler.KeyPublisher,
+	limiter middleware.RateLimiter,
 	authService handler.AuthService,
+	mfaService handler.MFAService,
+	credentialService handler.CredentialService,
 	accountService handler.AccountService,
 	transactionService handler.TransactionService,
+	scheduledTransferService handler.ScheduledTransferService,
+	paymentRequestService handler.PaymentRequestService,
+	adminService handler.AdminService,
 ) *Server {
 	router := gin.New()
-	router.Use(gin.Logger(), gin.Recovery())
-	if cfg != nil && cfg.RateLimitEnabled {
-		router.Use(middleware.RateLimitMiddleware(cfg.RateLimitRPS, cfg.RateLimitBurst))
-	}
+	router.Use(middleware.RequestID(), gin.Logger(), gin.Recovery())
 
+	// CORS comes before rate limiting: preflight requests are answered without spending the budget,
+	// and a 429 still carries the CORS headers the browser needs to read it.
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key, X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -47,33 +56,109 @@ func NewServer(
 		c.Next()
 	})
 
+	policies := rateLimitPolicies(cfg)
+	router.Use(middleware.RateLimitMiddleware(limiter, policies.global))
+	// Routes that take a password or a token from an anonymous caller share a stricter per-IP budget.
+	authLimit := middleware.RateLimitMiddleware(limiter, policies.auth)
+	userLimit := middleware.RateLimitMiddleware(limiter, policies.user)
+	moneyLimit := middleware.RateLimitMiddleware(limiter, policies.money)
+	requireAuth := middleware.AuthMiddleware(authService)
+
 	authHandler := handler.NewAuthHandler(authService)
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	credentialHandler := handler.NewCredentialHandler(credentialService)
+	jwksHandler := handler.NewJWKSHandler(keys)
 	accountHandler := handler.NewAccountHandler(accountService)
 	transactionHandler := handler.NewTransactionHandler(transactionService)
+	scheduledTransferHandler := handler.NewScheduledTransferHandler(scheduledTransferService)
+	paymentRequestHandler := handler.NewPaymentRequestHandler(paymentRequestService)
+	adminHandler := handler.NewAdminHandler(adminService)
 
 	auth := router.Group("/auth")
 	{
-		auth.POST("/register", authHandler.Register)
-		auth.POST("/login", authHandler.Login)
-		auth.POST("/refresh", authHandler.RefreshToken)
-		auth.POST("/logout", authHandler.Logout)
-		auth.GET("/me", middleware.AuthMiddleware(authService), authHandler.GetMe)
+		auth.POST("/register", authLimit, authHandler.Register)
+		auth.POST("/login", authLimit, authHandler.Login)
+		auth.POST("/login/mfa", authLimit, authHandler.LoginMFA)
+		auth.POST("/refresh", authLimit, authHandler.RefreshToken)
+		auth.POST("/logout", authLimit, authHandler.Logout)
+		auth.POST("/logout-all", requireAuth, userLimit, authHandler.LogoutAll)
+		auth.GET("/sessions", requireAuth, userLimit, authHandler.ListSessions)
+		auth.DELETE("/sessions/:id", requireAuth, userLimit, authHandler.RevokeSession)
+		auth.GET("/me", requireAuth, userLimit, authHandler.GetMe)
+
+		auth.POST("/email/verification", requireAuth, userLimit, credentialHandler.RequestEmailVerification)
+		auth.POST("/email/verify", authLimit, credentialHandler.VerifyEmail)
+		auth.POST("/password/forgot", authLimit, credentialHandler.ForgotPassword)
+		auth.POST("/password/reset", authLimit, credentialHandler.ResetPassword)
+		auth.POST("/password/change", requireAuth, userLimit, authLimit, credentialHandler.ChangePassword)
+
+		mfa := auth.Group("/mfa", requireAuth, userLimit)
+		{
+			mfa.GET("", mfaHandler.Status)
+			mfa.POST("/enroll", mfaHandler.Enroll)
+			mfa.POST("/confirm", mfaHandler.Confirm)
+			mfa.POST("/disable", mfaHandler.Disable)
+			mfa.POST("/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+		}
 	}
 
 	protected := router.Group("")
-	protected.Use(middleware.AuthMiddleware(authService))
+	protected.Use(requireAuth, userLimit)
 	{
 		protected.GET("/accounts", accountHandler.GetAccounts)
+		protected.POST("/accounts", accountHandler.OpenAccount)
 		protected.GET("/accounts/:id/balance", accountHandler.GetBalance)
-
-		protected.POST("/transactions/transfer", transactionHandler.Transfer)
-		protected.POST("/transactions/exchange", transactionHandler.Exchange)
+		protected.POST("/accounts/:id/close", accountHandler.CloseAccount)
+		protected.GET("/accounts/:id/statement", accountHandler.GetStatement)
+		protected.GET("/currencies", accountHandler.ListCurrencies)
+
+		protected.POST("/transactions/transfer", moneyLimit, transactionHandler.Transfer)
+		protected.POST("/transactions/exchange", moneyLimit, transactionHandler.Exchange)
+		protected.POST("/transactions/exchange/quote", transactionHandler.QuoteExchange)
+		protected.POST("/transactions/exchange/quote/:id/execute", moneyLimit, transactionHandler.ExecuteQuote)
 		protected.GET("/transactions", transactionHandler.GetTransactions)
+		protected.GET("/transactions/:id", transactionHandler.GetTransaction)
+		protected.POST("/transactions/:id/reverse", moneyLimit, transactionHandler.Reverse)
+		protected.POST("/transactions/:id/capture", moneyLimit, transactionHandler.Capture)
+		protected.POST("/transactions/:id/release", transactionHandler.Release)
+
+		protected.POST("/scheduled-transfers", moneyLimit, scheduledTransferHandler.Create)
+		protected.GET("/scheduled-transfers", scheduledTransferHandler.List)
+		protected.POST("/scheduled-transfers/:id/cancel", scheduledTransferHandler.Cancel)
+
+		protected.POST("/payment-requests", paymentRequestHandler.Create)
+		protected.GET("/payment-requests", paymentRequestHandler.List)
+		protected.POST("/payment-requests/:id/accept", moneyLimit, paymentRequestHandler.Accept)
+		protected.POST("/payment-requests/:id/decline", paymentRequestHandler.Decline)
+		protected.POST("/payment-requests/:id/cancel", paymentRequestHandler.Cancel)
+	}
+
+	// Support and auditors can look; only admins can change state.
+	admin := router.Group("/admin")
+	admin.Use(requireAuth, userLimit)
+	{
+		staff := middleware.RequireRole(domain.RoleAdmin, domain.RoleSupport, domain.RoleAuditor)
+		adminOnly := middleware.RequireRole(domain.RoleAdmin)
+
+		admin.GET("/users", staff, adminHandler.ListUsers)
+		admin.POST("/users/:id/unlock", adminOnly, adminHandler.UnlockUser)
+		admin.GET("/users/:id/transfer-limits", staff, adminHandler.GetTransferLimits)
+		admin.PUT("/users/:id/transfer-limits", adminOnly, adminHandler.SetTransferLimits)
+		admin.GET("/risk-reviews", staff, adminHandler.ListRiskReviews)
+		admin.POST("/risk-reviews/:id/approve", adminOnly, adminHandler.ApproveRiskReview)
+		admin.POST("/risk-reviews/:id/reject", adminOnly, adminHandler.RejectRiskReview)
+		admin.GET("/lockouts", staff, adminHandler.ListLockouts)
+		admin.GET("/accounts/:id", staff, adminHandler.GetAccount)
+		admin.POST("/accounts/:id/freeze", adminOnly, adminHandler.FreezeAccount)
+		admin.POST("/accounts/:id/unfreeze", adminOnly, adminHandler.UnfreezeAccount)
+		admin.POST("/consistency-checks", middleware.RequireRole(domain.RoleAdmin, domain.RoleAuditor), adminHandler.RunConsistencyChecks)
+		admin.POST("/transactions/:id/reverse", adminOnly, adminHandler.ReverseTransaction)
 	}
 
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
+	router.GET("/.well-known/jwks.json", jwksHandler.Get)
 
 	return &Server{
 		router: router,
@@ -81,6 +166,29 @@ func NewServer(
 	}
 }
 
+type serverRateLimits struct {
+	global, auth, user, money middleware.RateLimitPolicy
+}
+
+// rateLimitPolicies turns the configured limits into policies; a zero policy is disabled.
+func rateLimitPolicies(cfg *config.Config) serverRateLimits {
+	var p serverRateLimits
+	if cfg == nil {
+		return p
+	}
+	if cfg.RateLimitEnabled {
+		p.global = middleware.RateLimitPolicy{Name: "global", By: middleware.RateLimitByIP,
+			Limit: domain.RateLimit{Requests: cfg.RateLimitRPS, Period: time.Second, Burst: cfg.RateLimitBurst}}
+	}
+	p.auth = middleware.RateLimitPolicy{Name: "auth", By: middleware.RateLimitByIP,
+		Limit: domain.RateLimit{Requests: cfg.AuthRateLimitPerMinute, Period: time.Minute, Burst: cfg.AuthRateLimitBurst}}
+	p.user = middleware.RateLimitPolicy{Name: "user", By: middleware.RateLimitByUser,
+		Limit: domain.RateLimit{Requests: cfg.UserRateLimitPerMinute, Period: time.Minute, Burst: cfg.UserRateLimitBurst}}
+	p.money = middleware.RateLimitPolicy{Name: "money", By: middleware.RateLimitByUser,
+		Limit: domain.RateLimit{Requests: cfg.MoneyRateLimitPerMinute, Period: time.Minute, Burst: cfg.MoneyRateLimitBurst}}
+	return p
+}
+
 func (s *Server) Start(port string) error {
 	addr := fmt.Sprintf(":%s", port)
 	s.httpServer = &http.Server{
@@ -5,20 +5,31 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"banking-platform/internal/apperr"
 	"banking-platform/internal/domain"
 	"github.com/google/uuid"
 )
 
+// maxStatementPeriod bounds a single statement request.
+const maxStatementPeriod = 366 * 24 * time.Hour
+
 type AccountService struct {
 	accountRepo AccountRepo
+	ledgerRepo  LedgerRepo
+	txRunner    TxRunner
+	currencies  *domain.CurrencyRegistry
 	logger      *slog.Logger
 }
 
-func NewAccountService(accountRepo AccountRepo, logger *slog.Logger) *AccountService {
+func NewAccountService(accountRepo AccountRepo, ledgerRepo LedgerRepo, txRunner TxRunner, currencies *domain.CurrencyRegistry, logger *slog.Logger) *AccountService {
 	return &AccountService{
 		accountRepo: accountRepo,
+		ledgerRepo:  ledgerRepo,
+		txRunner:    txRunner,
+		currencies:  currencies,
 		logger:      logger,
 	}
 }
@@ -37,23 +48,207 @@ func (s *AccountService) GetUserAccounts(ctx context.Context, userID uuid.UUID)
 	return accounts, nil
 }
 
-func (s *AccountService) GetAccountBalance(ctx context.Context, accountID uuid.UUID, userID uuid.UUID) (int64, error) {
+// GetAccountBalance returns the ledger balance of the user's account and how much of it is available,
+// that is not held by pending authorizations.
+func (s *AccountService) GetAccountBalance(ctx context.Context, accountID uuid.UUID, userID uuid.UUID) (*domain.AccountBalance, error) {
 	s.logger.Info("Getting account balance", "account_id", accountID, "user_id", userID)
 
 	account, err := s.accountRepo.GetByID(ctx, accountID)
 	if err != nil {
 		if errors.Is(err, apperr.ErrAccountNotFound) {
 			s.logger.Warn("Account not found", "account_id", accountID)
-			return 0, apperr.ErrAccountNotFound
+			return nil, apperr.ErrAccountNotFound
 		}
-		return 0, fmt.Errorf("account.get_balance: get account %s: %w", accountID.String(), err)
+		return nil, fmt.Errorf("account.get_balance: get account %s: %w", accountID.String(), err)
 	}
 
 	if account.UserID != userID {
 		s.logger.Warn("Unauthorized access to account", "account_id", accountID, "user_id", userID)
-		return 0, apperr.ErrUnauthorized
+		return nil, apperr.ErrUnauthorized
+	}
+
+	s.logger.Info("Retrieved account balance", "account_id", accountID, "balance_cents", account.BalanceCents, "held_cents", account.HeldCents)
+	return &domain.AccountBalance{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		LedgerCents:    account.BalanceCents,
+		HeldCents:      account.HeldCents,
+		AvailableCents: account.AvailableCents(),
+	}, nil
+}
+
+// ListCurrencies returns the currencies accounts can be held in.
+func (s *AccountService) ListCurrencies(ctx context.Context) ([]domain.CurrencyInfo, error) {
+	return s.currencies.Enabled(), nil
+}
+
+// OpenAccount opens an additional named account. The first open account in a currency becomes its default.
+func (s *AccountService) OpenAccount(ctx context.Context, userID uuid.UUID, in *domain.OpenAccountInput) (*domain.Account, error) {
+	s.logger.Info("Opening account", "user_id", userID, "currency", in.Currency)
+
+	if !s.currencies.IsEnabled(in.Currency) {
+		return nil, apperr.ErrInvalidCurrency
+	}
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return nil, apperr.BadRequest("account name is required")
+	}
+
+	now := time.Now()
+	account := &domain.Account{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Currency:  in.Currency,
+		Name:      name,
+		Status:    domain.AccountStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	current, err := s.accountRepo.GetByUserIDAndCurrency(ctx, userID, in.Currency)
+	switch {
+	case errors.Is(err, apperr.ErrAccountNotFound):
+		account.IsDefault = true
+	case err != nil:
+		return nil, fmt.Errorf("account.open: get default account: %w", err)
+	case current.Status == domain.AccountStatusClosed:
+		// A closed default without successors: the new account takes over.
+		if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+			return s.accountRepo.SetDefaultTx(ctx, tx, current.ID, false)
+		}); err != nil {
+			return nil, fmt.Errorf("account.open: clear closed default: %w", err)
+		}
+		account.IsDefault = true
+	}
+
+	if err := s.accountRepo.Create(ctx, account); err != nil {
+		s.logger.Error("Failed to open account", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("account.open: create account: %w", err)
 	}
 
-	s.logger.Info("Retrieved account balance", "account_id", accountID, "balance_cents", account.BalanceCents)
-	return account.BalanceCents, nil
+	s.logger.Info("Account opened", "account_id", account.ID, "user_id", userID, "currency", account.Currency, "is_default", account.IsDefault)
+	return account, nil
+}
+
+// CloseAccount closes an active, zero-balance account owned by the user with no pending authorizations
+// from it. If it was the default account for its currency, the oldest remaining active account in
+// that currency becomes the default.
+func (s *AccountService) CloseAccount(ctx context.Context, userID uuid.UUID, accountID uuid.UUID) (*domain.Account, error) {
+	s.logger.Info("Closing account", "account_id", accountID, "user_id", userID)
+
+	var closed *domain.Account
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		account, err := s.accountRepo.LockAccountForUpdate(ctx, tx, accountID)
+		if err != nil {
+			return fmt.Errorf("account.close: lock account: %w", err)
+		}
+		if account.UserID != userID {
+			s.logger.Warn("Unauthorized access to account", "account_id", accountID, "user_id", userID)
+			return apperr.ErrUnauthorized
+		}
+		if err := ensureAccountActive(account); err != nil {
+			return err
+		}
+		// Pending authorizations from the account must be captured or released first.
+		if account.BalanceCents != 0 || account.HeldCents != 0 {
+			return apperr.ErrAccountBalanceNotZero
+		}
+
+		now := time.Now()
+		if err := s.accountRepo.UpdateStatusTx(ctx, tx, account.ID, domain.AccountStatusClosed, now); err != nil {
+			return fmt.Errorf("account.close: update status: %w", err)
+		}
+		account.Status = domain.AccountStatusClosed
+		account.ClosedAt = &now
+		account.UpdatedAt = now
+
+		if account.IsDefault {
+			successorID, err := s.accountRepo.FindSuccessorTx(ctx, tx, userID, account.Currency)
+			if err != nil && !errors.Is(err, apperr.ErrAccountNotFound) {
+				return fmt.Errorf("account.close: find successor: %w", err)
+			}
+			if err == nil {
+				if err := s.accountRepo.SetDefaultTx(ctx, tx, account.ID, false); err != nil {
+					return fmt.Errorf("account.close: clear default: %w", err)
+				}
+				if err := s.accountRepo.SetDefaultTx(ctx, tx, successorID, true); err != nil {
+					return fmt.Errorf("account.close: promote successor: %w", err)
+				}
+				account.IsDefault = false
+			}
+		}
+
+		closed = account
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Account closed", "account_id", accountID, "user_id", userID)
+	return closed, nil
+}
+
+// GetStatement builds an account statement for [q.From, q.To) from the ledger.
+func (s *AccountService) GetStatement(ctx context.Context, userID uuid.UUID, q *domain.StatementQuery) (*domain.Statement, error) {
+	s.logger.Info("Building account statement", "account_id", q.AccountID, "user_id", userID, "from", q.From, "to", q.To)
+
+	if !q.From.Before(q.To) {
+		return nil, apperr.BadRequest("from must be before to")
+	}
+	if q.To.Sub(q.From) > maxStatementPeriod {
+		return nil, apperr.BadRequest("statement period must not exceed 366 days")
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, q.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("account.statement: get account: %w", err)
+	}
+	if account.UserID != userID {
+		s.logger.Warn("Unauthorized access to account", "account_id", q.AccountID, "user_id", userID)
+		return nil, apperr.ErrUnauthorized
+	}
+
+	lines, err := s.ledgerRepo.ListAccountEntries(ctx, account.ID, account.Currency, q.From, q.To)
+	if err != nil {
+		return nil, fmt.Errorf("account.statement: list ledger entries: %w", err)
+	}
+
+	// Derive the opening balance from the first line so opening, lines and closing come from one snapshot.
+	var opening int64
+	if len(lines) > 0 {
+		opening = lines[0].BalanceCents - lines[0].AmountCents
+	} else {
+		opening, err = s.ledgerRepo.SumBefore(ctx, account.ID, account.Currency, q.From)
+		if err != nil {
+			return nil, fmt.Errorf("account.statement: opening balance: %w", err)
+		}
+	}
+	closing := opening
+	if len(lines) > 0 {
+		closing = lines[len(lines)-1].BalanceCents
+	}
+
+	return &domain.Statement{
+		AccountID:           account.ID,
+		AccountName:         account.Name,
+		Currency:            account.Currency,
+		MinorUnits:          s.currencies.MinorUnits(account.Currency),
+		From:                q.From,
+		To:                  q.To,
+		OpeningBalanceCents: opening,
+		ClosingBalanceCents: closing,
+		Lines:               lines,
+		GeneratedAt:         time.Now(),
+	}, nil
+}
+
+// ensureAccountActive rejects frozen and closed accounts.
+func ensureAccountActive(account *domain.Account) error {
+	switch account.Status {
+	case domain.AccountStatusFrozen:
+		return apperr.ErrAccountFrozen
+	case domain.AccountStatusClosed:
+		return apperr.ErrAccountClosed
+	}
+	return nil
 }
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+)
+
+func TestEnsureAccountActive(t *testing.T) {
+	tests := []struct {
+		name   string
+		status domain.AccountStatus
+		want   error
+	}{
+		{name: "active", status: domain.AccountStatusActive, want: nil},
+		{name: "frozen", status: domain.AccountStatusFrozen, want: apperr.ErrAccountFrozen},
+		{name: "closed", status: domain.AccountStatusClosed, want: apperr.ErrAccountClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ensureAccountActive(&domain.Account{Status: tt.status})
+			if !errors.Is(got, tt.want) {
+				t.Fatalf("got=%v want=%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	maxFreezeReasonLength = 500
+
+	// adminConsistencyCheckLimit caps the findings returned by an on-demand check.
+	adminConsistencyCheckLimit = 100
+)
+
+// Reverser books reversals. TransactionService implements it.
+type Reverser interface {
+	Reverse(ctx context.Context, actorID uuid.UUID, in *domain.ReverseInput) (*domain.TransactionInfo, error)
+}
+
+// ConsistencyReporter runs ledger consistency checks. LedgerConsistencyService implements it.
+type ConsistencyReporter interface {
+	Report(ctx context.Context, limit int) (*domain.ConsistencyReport, error)
+}
+
+// LockoutManager shows and lifts failed-login lockouts. LoginThrottle implements it.
+type LockoutManager interface {
+	ListLockouts(ctx context.Context) ([]*domain.LoginLockout, error)
+	Unlock(ctx context.Context, email string) error
+}
+
+// TransferLimitManager shows and changes a user's transfer tier and limit overrides.
+// TransferLimiter implements it.
+type TransferLimitManager interface {
+	UserLimits(ctx context.Context, userID uuid.UUID) (*domain.UserTransferLimits, error)
+	SetUserLimits(ctx context.Context, userID uuid.UUID, in *domain.SetTransferLimitsInput) (*domain.UserTransferLimits, error)
+}
+
+// RiskReviewManager lists and decides movements held by risk rules. RiskReviewQueue implements it.
+type RiskReviewManager interface {
+	List(ctx context.Context, status domain.RiskReviewStatus) ([]*domain.RiskReview, error)
+	Approve(ctx context.Context, reviewerID uuid.UUID, id uuid.UUID, note string) (*domain.RiskReview, *domain.TransactionInfo, error)
+	Reject(ctx context.Context, reviewerID uuid.UUID, id uuid.UUID, note string) (*domain.RiskReview, error)
+}
+
+// AdminService implements back-office operations. Role checks happen at the route; every
+// operation here is recorded in the audit log, and reads fail if the event cannot be recorded.
+type AdminService struct {
+	userRepo    UserRepo
+	accountRepo AccountRepo
+	txRunner    TxRunner
+	reverser    Reverser
+	consistency ConsistencyReporter
+	lockouts    LockoutManager
+	limits      TransferLimitManager
+	reviews     RiskReviewManager
+	audit       AuditRecorder
+	logger      *slog.Logger
+}
+
+func NewAdminService(
+	userRepo UserRepo,
+	accountRepo AccountRepo,
+	txRunner TxRunner,
+	reverser Reverser,
+	consistency ConsistencyReporter,
+	lockouts LockoutManager,
+	limits TransferLimitManager,
+	reviews RiskReviewManager,
+	audit AuditRecorder,
+	logger *slog.Logger,
+) *AdminService {
+	return &AdminService{
+		userRepo:    userRepo,
+		accountRepo: accountRepo,
+		txRunner:    txRunner,
+		reverser:    reverser,
+		consistency: consistency,
+		lockouts:    lockouts,
+		limits:      limits,
+		reviews:     reviews,
+		audit:       audit,
+		logger:      logger,
+	}
+}
+
+// ListUsers returns every user, including system users, without password hashes.
+func (s *AdminService) ListUsers(ctx context.Context, actor *domain.Principal) ([]*domain.UserInfo, error) {
+	users, err := s.userRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("admin.list_users: %w", err)
+	}
+	if err := s.record(ctx, actor, AuditActionAdminListUsers, "user", "", map[string]any{"count": len(users)}); err != nil {
+		return nil, fmt.Errorf("admin.list_users: %w", err)
+	}
+
+	out := make([]*domain.UserInfo, len(users))
+	for i, u := range users {
+		out[i] = &domain.UserInfo{
+			ID:            u.ID,
+			Email:         u.Email,
+			FirstName:     u.FirstName,
+			LastName:      u.LastName,
+			Role:          u.Role,
+			EmailVerified: u.EmailVerifiedAt != nil,
+			CreatedAt:     u.CreatedAt,
+			UpdatedAt:     u.UpdatedAt,
+		}
+	}
+	return out, nil
+}
+
+// GetAccount returns any account regardless of owner.
+func (s *AdminService) GetAccount(ctx context.Context, actor *domain.Principal, accountID uuid.UUID) (*domain.Account, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("admin.get_account: %w", err)
+	}
+	if err := s.record(ctx, actor, AuditActionAdminViewAccount, "account", accountID.String(), map[string]any{"owner_id": account.UserID}); err != nil {
+		return nil, fmt.Errorf("admin.get_account: %w", err)
+	}
+	return account, nil
+}
+
+// FreezeAccount blocks all money movement on an active account.
+func (s *AdminService) FreezeAccount(ctx context.Context, actor *domain.Principal, accountID uuid.UUID, reason string) (*domain.Account, error) {
+	return s.setAccountStatus(ctx, actor, accountID, domain.AccountStatusFrozen, reason)
+}
+
+// UnfreezeAccount makes a frozen account active again.
+func (s *AdminService) UnfreezeAccount(ctx context.Context, actor *domain.Principal, accountID uuid.UUID, reason string) (*domain.Account, error) {
+	return s.setAccountStatus(ctx, actor, accountID, domain.AccountStatusActive, reason)
+}
+
+func (s *AdminService) setAccountStatus(ctx context.Context, actor *domain.Principal, accountID uuid.UUID, status domain.AccountStatus, reason string) (*domain.Account, error) {
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxFreezeReasonLength {
+		return nil, apperr.BadRequest("reason must be at most 500 characters")
+	}
+	action := AuditActionAdminFreezeAccount
+	if status == domain.AccountStatusActive {
+		action = AuditActionAdminUnfreezeAccount
+	}
+
+	var account *domain.Account
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		var err error
+		account, err = s.accountRepo.LockAccountForUpdate(ctx, tx, accountID)
+		if err != nil {
+			return err
+		}
+
+		owner, err := s.userRepo.GetByID(ctx, account.UserID)
+		if err != nil {
+			return fmt.Errorf("get owner: %w", err)
+		}
+		// Freezing a bank liquidity account would halt exchanges and funding for everyone.
+		if owner.Role == domain.RoleSystem {
+			return apperr.BadRequest("system accounts cannot be frozen")
+		}
+		if account.Status == domain.AccountStatusClosed {
+			return apperr.ErrAccountClosed
+		}
+
+		before := account.Status
+		if before != status {
+			now := time.Now().UTC()
+			if err := s.accountRepo.UpdateStatusTx(ctx, tx, accountID, status, now); err != nil {
+				return fmt.Errorf("update status: %w", err)
+			}
+			account.Status = status
+			account.UpdatedAt = now
+		}
+
+		// Recorded inside the transaction so the status change and its audit trail stand or fall together.
+		event := s.event(actor, action, "account", accountID.String(), map[string]any{
+			"owner_id": account.UserID,
+			"reason":   reason,
+		})
+		event.Before = auditJSON(map[string]any{"status": before})
+		event.After = auditJSON(map[string]any{"status": status})
+		return s.audit.RecordTx(ctx, tx, event)
+	}); err != nil {
+		return nil, fmt.Errorf("admin.set_account_status: %w", err)
+	}
+
+	s.logger.Info("Account status changed by operator", "account_id", accountID, "status", status, "actor_id", actor.UserID)
+	return account, nil
+}
+
+// RunConsistencyChecks runs the ledger checks the background cron performs and returns the findings.
+func (s *AdminService) RunConsistencyChecks(ctx context.Context, actor *domain.Principal) (*domain.ConsistencyReport, error) {
+	report, err := s.consistency.Report(ctx, adminConsistencyCheckLimit)
+	if err != nil {
+		return nil, fmt.Errorf("admin.consistency_checks: %w", err)
+	}
+	if err := s.record(ctx, actor, AuditActionAdminConsistencyCheck, "ledger", "", map[string]any{
+		"unbalanced_transactions": len(report.UnbalancedTransactionIDs),
+		"balance_mismatches":      len(report.BalanceMismatches),
+	}); err != nil {
+		return nil, fmt.Errorf("admin.consistency_checks: %w", err)
+	}
+	return report, nil
+}
+
+// ReverseTransaction reverses any transfer on behalf of the operator.
+func (s *AdminService) ReverseTransaction(ctx context.Context, actor *domain.Principal, in *domain.ReverseInput) (*domain.TransactionInfo, error) {
+	in.AsOperator = true
+	info, err := s.reverser.Reverse(ctx, actor.UserID, in)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reversal is already booked, so a failed audit write is logged rather than reported as a failure.
+	event := s.event(actor, AuditActionAdminReverse, "transaction", in.TransactionID.String(), map[string]any{
+		"reason": in.Reason,
+	})
+	event.After = auditTransaction(info)
+	recordAudit(ctx, s.audit, s.logger, event)
+	return info, nil
+}
+
+// ListLockouts returns the email addresses with recent failed logins, locked or not.
+func (s *AdminService) ListLockouts(ctx context.Context, actor *domain.Principal) ([]*domain.LoginLockout, error) {
+	lockouts, err := s.lockouts.ListLockouts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("admin.list_lockouts: %w", err)
+	}
+	if err := s.record(ctx, actor, AuditActionAdminListLockouts, "user", "", map[string]any{"count": len(lockouts)}); err != nil {
+		return nil, fmt.Errorf("admin.list_lockouts: %w", err)
+	}
+	return lockouts, nil
+}
+
+// UnlockUser clears the failed logins of the user's email, so they can sign in again at once.
+func (s *AdminService) UnlockUser(ctx context.Context, actor *domain.Principal, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("admin.unlock_user: %w", err)
+	}
+	if err := s.lockouts.Unlock(ctx, user.Email); err != nil {
+		return fmt.Errorf("admin.unlock_user: %w", err)
+	}
+
+	s.logger.Info("Login lockout cleared by operator", "user_id", userID, "actor_id", actor.UserID)
+	recordAudit(ctx, s.audit, s.logger, s.event(actor, AuditActionAdminUnlockUser, "user", userID.String(), nil))
+	return nil
+}
+
+// GetTransferLimits returns the user's transfer tier, overrides and effective limits.
+func (s *AdminService) GetTransferLimits(ctx context.Context, actor *domain.Principal, userID uuid.UUID) (*domain.UserTransferLimits, error) {
+	limits, err := s.limits.UserLimits(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("admin.get_transfer_limits: %w", err)
+	}
+	if err := s.record(ctx, actor, AuditActionAdminViewTransferLimits, "user", userID.String(), nil); err != nil {
+		return nil, fmt.Errorf("admin.get_transfer_limits: %w", err)
+	}
+	return limits, nil
+}
+
+// SetTransferLimits moves the user to a tier and replaces the user's limit overrides.
+func (s *AdminService) SetTransferLimits(ctx context.Context, actor *domain.Principal, userID uuid.UUID, in *domain.SetTransferLimitsInput) (*domain.UserTransferLimits, error) {
+	before, err := s.limits.UserLimits(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("admin.set_transfer_limits: %w", err)
+	}
+	after, err := s.limits.SetUserLimits(ctx, userID, in)
+	if err != nil {
+		return nil, fmt.Errorf("admin.set_transfer_limits: %w", err)
+	}
+
+	s.logger.Info("Transfer limits changed by operator", "user_id", userID, "tier", after.Tier, "actor_id", actor.UserID)
+	event := s.event(actor, AuditActionAdminSetTransferLimits, "user", userID.String(), nil)
+	event.Before = auditTransferLimits(before)
+	event.After = auditTransferLimits(after)
+	recordAudit(ctx, s.audit, s.logger, event)
+	return after, nil
+}
+
+func auditTransferLimits(l *domain.UserTransferLimits) json.RawMessage {
+	overrides := make([]map[string]any, len(l.Overrides))
+	for i, o := range l.Overrides {
+		overrides[i] = map[string]any{
+			"currency":         o.Currency,
+			"max_single_cents": o.MaxSingleCents,
+			"daily_cents":      o.DailyCents,
+			"monthly_cents":    o.MonthlyCents,
+			"hourly_count":     o.HourlyCount,
+		}
+	}
+	return auditJSON(map[string]any{"tier": l.Tier, "overrides": overrides})
+}
+
+// ListRiskReviews returns held movements with the status, oldest first; an empty status lists all.
+func (s *AdminService) ListRiskReviews(ctx context.Context, actor *domain.Principal, status domain.RiskReviewStatus) ([]*domain.RiskReview, error) {
+	reviews, err := s.reviews.List(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("admin.list_risk_reviews: %w", err)
+	}
+	if err := s.record(ctx, actor, AuditActionAdminListRiskReviews, "risk_review", "", map[string]any{"status": status, "count": len(reviews)}); err != nil {
+		return nil, fmt.Errorf("admin.list_risk_reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// ApproveRiskReview books a held movement on behalf of its owner.
+func (s *AdminService) ApproveRiskReview(ctx context.Context, actor *domain.Principal, id uuid.UUID, note string) (*domain.RiskReview, *domain.TransactionInfo, error) {
+	review, info, err := s.reviews.Approve(ctx, actor.UserID, id, note)
+	if err != nil {
+		return nil, nil, fmt.Errorf("admin.approve_risk_review: %w", err)
+	}
+
+	event := s.event(actor, AuditActionAdminApproveRiskReview, "risk_review", id.String(), map[string]any{
+		"rule": review.Rule,
+		"note": review.ReviewNote,
+	})
+	event.After = auditTransaction(info)
+	recordAudit(ctx, s.audit, s.logger, event)
+	return review, info, nil
+}
+
+// RejectRiskReview closes a held movement without booking it.
+func (s *AdminService) RejectRiskReview(ctx context.Context, actor *domain.Principal, id uuid.UUID, note string) (*domain.RiskReview, error) {
+	review, err := s.reviews.Reject(ctx, actor.UserID, id, note)
+	if err != nil {
+		return nil, fmt.Errorf("admin.reject_risk_review: %w", err)
+	}
+
+	recordAudit(ctx, s.audit, s.logger, s.event(actor, AuditActionAdminRejectRiskReview, "risk_review", id.String(), map[string]any{
+		"rule": review.Rule,
+		"note": review.ReviewNote,
+	}))
+	return review, nil
+}
+
+func (s *AdminService) record(ctx context.Context, actor *domain.Principal, action string, targetType string, targetID string, metadata map[string]any) error {
+	if err := s.audit.Record(ctx, s.event(actor, action, targetType, targetID, metadata)); err != nil {
+		return fmt.Errorf("record audit event: %w", err)
+	}
+	return nil
+}
+
+func (s *AdminService) event(actor *domain.Principal, action string, targetType string, targetID string, metadata map[string]any) *domain.AuditEvent {
+	actorID := actor.UserID
+	return &domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    &actorID,
+		ActorRole:  actor.Role,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Metadata:   auditJSON(metadata),
+		CreatedAt:  time.Now().UTC(),
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Audited actions. Auth and money events are written after the business transaction commits;
+// admin status changes are written inside it.
+const (
+	AuditActionRegister       = "auth.register"
+	AuditActionLoginSucceeded = "auth.login.succeeded"
+	AuditActionLoginFailed    = "auth.login.failed"
+	AuditActionLoginLocked    = "auth.login.locked"
+	AuditActionTokenRefreshed = "auth.token.refreshed"
+	// AuditActionRefreshTokenReuse is a security event: a rotated refresh token was presented again.
+	AuditActionRefreshTokenReuse = "auth.refresh_token.reused"
+	AuditActionLogout            = "auth.logout"
+	AuditActionLogoutAll         = "auth.logout_all"
+	AuditActionSessionRevoked    = "auth.session.revoked"
+
+	AuditActionMFAEnabled                  = "auth.mfa.enabled"
+	AuditActionMFADisabled                 = "auth.mfa.disabled"
+	AuditActionMFARecoveryCodesRegenerated = "auth.mfa.recovery_codes.regenerated"
+	AuditActionMFARecoveryCodeUsed         = "auth.mfa.recovery_code.used"
+	AuditActionMFAStepUpFailed             = "auth.mfa.step_up.failed"
+
+	AuditActionEmailVerificationSent = "auth.email.verification_sent"
+	AuditActionEmailVerified         = "auth.email.verified"
+	AuditActionPasswordResetRequest  = "auth.password.reset_requested"
+	AuditActionPasswordReset         = "auth.password.reset"
+	AuditActionPasswordChanged       = "auth.password.changed"
+
+	AuditActionTransfer  = "transaction.transfer"
+	AuditActionExchange  = "transaction.exchange"
+	AuditActionReversal  = "transaction.reversal"
+	AuditActionAuthorize = "transaction.authorize"
+	AuditActionCapture   = "transaction.capture"
+	AuditActionRelease   = "transaction.release"
+
+	AuditActionRiskDeclined = "risk.declined"
+	AuditActionRiskHeld     = "risk.held"
+
+	AuditActionAdminListUsers          = "admin.users.list"
+	AuditActionAdminViewAccount        = "admin.account.view"
+	AuditActionAdminFreezeAccount      = "admin.account.freeze"
+	AuditActionAdminUnfreezeAccount    = "admin.account.unfreeze"
+	AuditActionAdminConsistencyCheck   = "admin.consistency.check"
+	AuditActionAdminReverse            = "admin.transaction.reverse"
+	AuditActionAdminListLockouts       = "admin.lockouts.list"
+	AuditActionAdminUnlockUser         = "admin.user.unlock"
+	AuditActionAdminViewTransferLimits = "admin.transfer_limits.view"
+	AuditActionAdminSetTransferLimits  = "admin.transfer_limits.update"
+	AuditActionAdminListRiskReviews    = "admin.risk_reviews.list"
+	AuditActionAdminApproveRiskReview  = "admin.risk_review.approve"
+	AuditActionAdminRejectRiskReview   = "admin.risk_review.reject"
+)
+
+// auditVerifyBatchSize bounds how many events Verify holds in memory at once.
+const auditVerifyBatchSize = 1000
+
+// AuditLog appends events to the hash-chained audit_events table and verifies the chain.
+type AuditLog struct {
+	repo     AuditEventRepo
+	txRunner TxRunner
+	logger   *slog.Logger
+}
+
+func NewAuditLog(repo AuditEventRepo, txRunner TxRunner, logger *slog.Logger) *AuditLog {
+	return &AuditLog{repo: repo, txRunner: txRunner, logger: logger}
+}
+
+// Record appends the event in its own transaction.
+func (l *AuditLog) Record(ctx context.Context, event *domain.AuditEvent) error {
+	return l.txRunner.WithTx(ctx, func(tx Tx) error {
+		return l.RecordTx(ctx, tx, event)
+	})
+}
+
+// RecordTx appends the event within tx, so it commits or rolls back with the caller's changes.
+// Request ID and client IP are taken from ctx when the event does not set them.
+func (l *AuditLog) RecordTx(ctx context.Context, tx Tx, event *domain.AuditEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	meta := domain.RequestMetaFrom(ctx)
+	if event.RequestID == "" {
+		event.RequestID = meta.RequestID
+	}
+	if event.IP == "" {
+		event.IP = meta.IP
+	}
+	if err := l.repo.AppendTx(ctx, tx, event); err != nil {
+		return fmt.Errorf("append audit event: %w", err)
+	}
+	return nil
+}
+
+// Verify recomputes the whole chain and reports the first event that was altered, removed or
+// inserted out of band.
+func (l *AuditLog) Verify(ctx context.Context) (*domain.AuditVerification, error) {
+	result := &domain.AuditVerification{}
+	prevHash := ""
+	for {
+		events, err := l.repo.ListAfter(ctx, result.LastSeq, auditVerifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("audit.verify: %w", err)
+		}
+		if len(events) == 0 {
+			return result, nil
+		}
+
+		var brk *domain.AuditChainBreak
+		prevHash, brk = domain.VerifyAuditChain(prevHash, events)
+		if brk != nil {
+			result.Break = brk
+			result.Checked += countBefore(events, brk.Seq)
+			return result, nil
+		}
+		result.Checked += int64(len(events))
+		result.LastSeq = events[len(events)-1].Seq
+	}
+}
+
+func countBefore(events []*domain.AuditEvent, seq int64) int64 {
+	var n int64
+	for _, e := range events {
+		if e.Seq >= seq {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// recordAudit writes an event whose business change has already committed. The change cannot be
+// undone at this point, so a failed write is logged instead of failing the request.
+func recordAudit(ctx context.Context, audit AuditRecorder, logger *slog.Logger, event *domain.AuditEvent) {
+	if err := audit.Record(ctx, event); err != nil {
+		logger.ErrorContext(ctx, "Failed to record audit event", "action", event.Action, "target_id", event.TargetID, "error", err)
+	}
+}
+
+// auditJSON encodes audit state or metadata; nil stays nil so the column is NULL.
+func auditJSON(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage(fmt.Sprintf(`{"marshal_error":%q}`, err.Error()))
+	}
+	return raw
+}
+
+// auditTransaction is the after-state recorded for money movements.
+func auditTransaction(info *domain.TransactionInfo) json.RawMessage {
+	if info == nil {
+		return nil
+	}
+	return auditJSON(map[string]any{
+		"id":                      info.ID,
+		"type":                    info.Type,
+		"from_account_id":         info.FromAccountID,
+		"to_account_id":           info.ToAccountID,
+		"amount_cents":            info.AmountCents,
+		"currency":                info.Currency,
+		"exchange_rate":           info.ExchangeRate,
+		"converted_amount_cents":  info.ConvertedAmountCents,
+		"to_currency":             info.ToCurrency,
+		"quote_id":                info.QuoteID,
+		"reverses_transaction_id": info.ReversesTransactionID,
+		"status":                  info.Status,
+		"captured_amount_cents":   info.CapturedAmountCents,
+	})
+}
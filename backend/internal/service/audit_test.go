@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryAuditRepo is an in-memory chain with the same sealing behaviour as the Postgres repository.
+type memoryAuditRepo struct {
+	events []*domain.AuditEvent
+}
+
+func (r *memoryAuditRepo) AppendTx(ctx context.Context, tx Tx, event *domain.AuditEvent) error {
+	prev := ""
+	if n := len(r.events); n > 0 {
+		prev = r.events[n-1].Hash
+	}
+	event.Seal(prev)
+	event.Seq = int64(len(r.events) + 1)
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *memoryAuditRepo) ListAfter(ctx context.Context, afterSeq int64, limit int) ([]*domain.AuditEvent, error) {
+	var out []*domain.AuditEvent
+	for _, e := range r.events {
+		if e.Seq > afterSeq && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+type inlineTxRunner struct{}
+
+func (inlineTxRunner) WithTx(ctx context.Context, fn func(tx Tx) error) error {
+	return fn(nil)
+}
+
+func TestAuditLogVerify(t *testing.T) {
+	total := auditVerifyBatchSize + 5
+
+	testCases := []struct {
+		name        string
+		tamper      func(r *memoryAuditRepo)
+		wantBreak   int64
+		wantChecked int64
+	}{
+		{name: "intact_across_batches", tamper: func(r *memoryAuditRepo) {}, wantChecked: int64(total)},
+		{name: "edited_in_second_batch", tamper: func(r *memoryAuditRepo) {
+			r.events[auditVerifyBatchSize+2].Metadata = json.RawMessage(`{"reason":"edited"}`)
+		}, wantBreak: int64(auditVerifyBatchSize + 3), wantChecked: int64(auditVerifyBatchSize + 2)},
+		{name: "deleted_at_batch_boundary", tamper: func(r *memoryAuditRepo) {
+			r.events = append(r.events[:auditVerifyBatchSize], r.events[auditVerifyBatchSize+1:]...)
+		}, wantBreak: int64(auditVerifyBatchSize + 2), wantChecked: int64(auditVerifyBatchSize)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &memoryAuditRepo{}
+			audit := NewAuditLog(repo, inlineTxRunner{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			for i := 0; i < total; i++ {
+				if err := audit.Record(context.Background(), &domain.AuditEvent{Action: AuditActionLoginSucceeded}); err != nil {
+					t.Fatalf("record: %v", err)
+				}
+			}
+			tc.tamper(repo)
+
+			result, err := audit.Verify(context.Background())
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			var gotBreak int64
+			if result.Break != nil {
+				gotBreak = result.Break.Seq
+			}
+			if gotBreak != tc.wantBreak || result.Checked != tc.wantChecked {
+				t.Fatalf("got break=%d checked=%d want break=%d checked=%d", gotBreak, result.Checked, tc.wantBreak, tc.wantChecked)
+			}
+		})
+	}
+}
+
+func TestAuditLogRecordUsesRequestMeta(t *testing.T) {
+	repo := &memoryAuditRepo{}
+	audit := NewAuditLog(repo, inlineTxRunner{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := domain.WithRequestMeta(context.Background(), domain.RequestMeta{RequestID: "req-42", IP: "192.0.2.7"})
+
+	if err := audit.Record(ctx, &domain.AuditEvent{Action: AuditActionLogout}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	got := repo.events[0]
+	if got.RequestID != "req-42" || got.IP != "192.0.2.7" {
+		t.Fatalf("got=%q/%q want=req-42/192.0.2.7", got.RequestID, got.IP)
+	}
+	if got.ID == uuid.Nil || got.CreatedAt.IsZero() || got.Hash == "" {
+		t.Fatalf("event not filled in: %+v", got)
+	}
+}
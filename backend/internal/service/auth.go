@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 
 	"banking-platform/internal/apperr"
 	"banking-platform/internal/domain"
@@ -25,8 +24,16 @@ type AuthService struct {
 	transactionRepo  TransactionRepo
 	ledgerRepo       LedgerRepo
 	refreshTokenRepo RefreshTokenRepo
+	revoker          TokenRevoker
+	mfa              MFAGate
+	verifier         VerificationSender
+	guard            LoginGuard
 	tokenManager     *jwt.TokenManager
 	hasher           *hash.Hasher
+	passwords        *hash.PasswordHasher
+	policy           *PasswordPolicy
+	currencies       *domain.CurrencyRegistry
+	audit            AuditRecorder
 	logger           *slog.Logger
 }
 
@@ -37,8 +44,16 @@ func NewAuthService(
 	transactionRepo TransactionRepo,
 	ledgerRepo LedgerRepo,
 	refreshTokenRepo RefreshTokenRepo,
+	revoker TokenRevoker,
+	mfa MFAGate,
+	verifier VerificationSender,
+	guard LoginGuard,
 	tokenManager *jwt.TokenManager,
 	hasher *hash.Hasher,
+	passwords *hash.PasswordHasher,
+	policy *PasswordPolicy,
+	currencies *domain.CurrencyRegistry,
+	audit AuditRecorder,
 	logger *slog.Logger,
 ) *AuthService {
 	return &AuthService{
@@ -48,8 +63,16 @@ func NewAuthService(
 		transactionRepo:  transactionRepo,
 		ledgerRepo:       ledgerRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		revoker:          revoker,
+		mfa:              mfa,
+		verifier:         verifier,
+		guard:            guard,
 		tokenManager:     tokenManager,
 		hasher:           hasher,
+		passwords:        passwords,
+		policy:           policy,
+		currencies:       currencies,
+		audit:            audit,
 		logger:           logger,
 	}
 }
@@ -59,6 +82,10 @@ func (s *AuthService) Register(ctx context.Context, in *domain.RegisterInput) (*
 	in.Email = strings.ToLower(strings.TrimSpace(in.Email))
 	s.logger.Info("Registering new user", "email", in.Email)
 
+	if err := s.policy.Validate(in.Password, in.Email); err != nil {
+		return nil, err
+	}
+
 	_, err := s.userRepo.GetByEmail(ctx, in.Email)
 	if err == nil {
 		s.logger.Warn("User already exists", "email", in.Email)
@@ -68,7 +95,7 @@ func (s *AuthService) Register(ctx context.Context, in *domain.RegisterInput) (*
 		return nil, fmt.Errorf("auth.register: get user by email: %w", err)
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(in.Password), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwords.Hash(in.Password)
 	if err != nil {
 		s.logger.Error("Failed to hash password", "error", err)
 		return nil, fmt.Errorf("failed to hash password: %w", err)
@@ -78,9 +105,10 @@ func (s *AuthService) Register(ctx context.Context, in *domain.RegisterInput) (*
 	user := &domain.User{
 		ID:           uuid.New(),
 		Email:        in.Email,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		FirstName:    in.FirstName,
 		LastName:     in.LastName,
+		Role:         domain.RoleCustomer,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -90,36 +118,34 @@ func (s *AuthService) Register(ctx context.Context, in *domain.RegisterInput) (*
 		return nil, fmt.Errorf("auth.register: create user: %w", err)
 	}
 
-	usdAccount, eurAccount, err := s.createDefaultAccounts(ctx, user.ID)
+	accounts, err := s.createDefaultAccounts(ctx, user.ID)
 	if err != nil {
 		s.logger.Error("Failed to create default accounts", "error", err, "user_id", user.ID)
 		return nil, fmt.Errorf("auth.register: create default accounts: %w", err)
 	}
 
-	if err := s.fundInitialBalancesViaLedger(ctx, user.ID, usdAccount.ID, eurAccount.ID); err != nil {
+	if err := s.fundInitialBalancesViaLedger(ctx, user.ID, accounts); err != nil {
 		s.logger.Error("Failed to fund initial balances via ledger", "error", err, "user_id", user.ID)
 		return nil, fmt.Errorf("auth.register: fund initial balances: %w", err)
 	}
 
-	tokenPair, err := s.generateTokenPair(ctx, user.ID)
+	tokenPair, err := s.generateTokenPair(ctx, user, in.Client)
 	if err != nil {
 		s.logger.Error("Failed to generate tokens", "error", err, "user_id", user.ID)
 		return nil, fmt.Errorf("auth.register: generate tokens: %w", err)
 	}
 
 	s.logger.Info("User registered successfully", "user_id", user.ID, "email", in.Email)
+	s.recordAuth(ctx, AuditActionRegister, &user.ID, user.Role, "user", user.ID.String(), map[string]any{"email": user.Email})
 
-	return &domain.AuthResult{
-		Tokens: *tokenPair,
-		User: domain.UserInfo{
-			ID:        user.ID,
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		},
-	}, nil
+	// The account is usable right away; a failed email can be resent from the profile.
+	if s.verifier != nil {
+		if err := s.verifier.SendVerification(ctx, user); err != nil {
+			s.logger.Error("Failed to send verification email", "error", err, "user_id", user.ID)
+		}
+	}
+
+	return authResult(user, tokenPair), nil
 }
 
 // Login validates credentials and returns a token pair.
@@ -127,93 +153,167 @@ func (s *AuthService) Login(ctx context.Context, in *domain.LoginInput) (*domain
 	in.Email = strings.ToLower(strings.TrimSpace(in.Email))
 	s.logger.Info("User login attempt", "email", in.Email)
 
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, in.Email, in.Client.IP); err != nil {
+			s.logger.Warn("Login attempt throttled", "email", in.Email, "ip", in.Client.IP)
+			return nil, err
+		}
+	}
+
 	user, err := s.userRepo.GetByEmail(ctx, in.Email)
 	if err != nil {
 		if errors.Is(err, apperr.ErrUserNotFound) {
 			s.logger.Warn("User not found", "email", in.Email)
+			s.recordAuth(ctx, AuditActionLoginFailed, nil, "", "email", in.Email, map[string]any{"reason": "unknown_email"})
+			s.recordLoginFailure(ctx, in.Email, in.Client.IP)
 			return nil, apperr.ErrInvalidCredentials
 		}
 		return nil, fmt.Errorf("auth.login: get user by email: %w", err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(in.Password)); err != nil {
+	if err := s.passwords.Verify(user.PasswordHash, in.Password); err != nil {
 		s.logger.Warn("Invalid password", "email", in.Email)
+		s.recordAuth(ctx, AuditActionLoginFailed, nil, "", "user", user.ID.String(), map[string]any{"reason": "invalid_password"})
+		s.recordLoginFailure(ctx, in.Email, in.Client.IP)
+		return nil, apperr.ErrInvalidCredentials
+	}
+	if user.Role == domain.RoleSystem {
+		s.logger.Warn("Login attempt for system user", "user_id", user.ID)
+		s.recordAuth(ctx, AuditActionLoginFailed, nil, "", "user", user.ID.String(), map[string]any{"reason": "system_user"})
+		s.recordLoginFailure(ctx, in.Email, in.Client.IP)
 		return nil, apperr.ErrInvalidCredentials
 	}
+	s.upgradePasswordHash(ctx, user, in.Password)
+
+	if s.mfa != nil {
+		enabled, err := s.mfa.Enabled(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("auth.login: %w", err)
+		}
+		if enabled {
+			challenge, err := s.mfa.StartChallenge(ctx, user.ID)
+			if err != nil {
+				return nil, fmt.Errorf("auth.login: %w", err)
+			}
+			s.logger.Info("Password accepted, second factor required", "user_id", user.ID)
+			return &domain.AuthResult{MFAChallenge: challenge}, nil
+		}
+	}
 
-	tokenPair, err := s.generateTokenPair(ctx, user.ID)
+	tokenPair, err := s.generateTokenPair(ctx, user, in.Client)
 	if err != nil {
 		s.logger.Error("Failed to generate tokens", "error", err, "user_id", user.ID)
 		return nil, fmt.Errorf("auth.login: generate tokens: %w", err)
 	}
 
 	s.logger.Info("User logged in successfully", "user_id", user.ID, "email", in.Email)
+	s.recordAuth(ctx, AuditActionLoginSucceeded, &user.ID, user.Role, "user", user.ID.String(), nil)
+	s.recordLoginSuccess(ctx, user.Email)
 
-	return &domain.AuthResult{
-		Tokens: *tokenPair,
-		User: domain.UserInfo{
-			ID:        user.ID,
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		},
-	}, nil
+	return authResult(user, tokenPair), nil
 }
 
-func (s *AuthService) createDefaultAccounts(ctx context.Context, userID uuid.UUID) (*domain.Account, *domain.Account, error) {
-	now := time.Now()
-
-	usdAccount := &domain.Account{
-		ID:           uuid.New(),
-		UserID:       userID,
-		Currency:     domain.CurrencyUSD,
-		BalanceCents: 0,
-		CreatedAt:    now,
-		UpdatedAt:    now,
+// LoginMFA completes a login that returned an MFA challenge.
+func (s *AuthService) LoginMFA(ctx context.Context, in *domain.LoginMFAInput) (*domain.AuthResult, error) {
+	if s.mfa == nil {
+		return nil, apperr.ErrInvalidMFAChallenge
 	}
-	if err := s.accountRepo.Create(ctx, usdAccount); err != nil {
-		s.logger.Error("Failed to create USD account", "error", err, "user_id", userID)
-		return nil, nil, err
+	userID, err := s.mfa.CompleteChallenge(ctx, in)
+	if err != nil {
+		if errors.Is(err, apperr.ErrInvalidMFACode) {
+			s.logger.Warn("Invalid second factor", "user_id", userID)
+			s.recordAuth(ctx, AuditActionLoginFailed, nil, "", "user", userID.String(), map[string]any{"reason": "invalid_mfa_code"})
+			// Count wrong codes like wrong passwords, so fresh challenges cannot be used to guess codes.
+			if user, err := s.userRepo.GetByID(ctx, userID); err == nil {
+				s.recordLoginFailure(ctx, user.Email, in.Client.IP)
+			}
+		}
+		return nil, err
 	}
 
-	eurAccount := &domain.Account{
-		ID:           uuid.New(),
-		UserID:       userID,
-		Currency:     domain.CurrencyEUR,
-		BalanceCents: 0,
-		CreatedAt:    now,
-		UpdatedAt:    now,
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("auth.login_mfa: get user: %w", err)
 	}
-	if err := s.accountRepo.Create(ctx, eurAccount); err != nil {
-		s.logger.Error("Failed to create EUR account", "error", err, "user_id", userID)
-		return nil, nil, err
+	tokenPair, err := s.generateTokenPair(ctx, user, in.Client)
+	if err != nil {
+		s.logger.Error("Failed to generate tokens", "error", err, "user_id", user.ID)
+		return nil, fmt.Errorf("auth.login_mfa: generate tokens: %w", err)
 	}
 
-	s.logger.Info("Default accounts created", "user_id", userID)
-	return usdAccount, eurAccount, nil
+	s.logger.Info("User logged in successfully", "user_id", user.ID, "mfa", true)
+	s.recordAuth(ctx, AuditActionLoginSucceeded, &user.ID, user.Role, "user", user.ID.String(), map[string]any{"mfa": true})
+	s.recordLoginSuccess(ctx, user.Email)
+
+	return authResult(user, tokenPair), nil
 }
 
-func (s *AuthService) fundInitialBalancesViaLedger(ctx context.Context, userID uuid.UUID, userUSDAccountID uuid.UUID, userEURAccountID uuid.UUID) error {
-	const initialUSDCents int64 = 1000_00
-	const initialEURCents int64 = 500_00
+func authResult(user *domain.User, tokens *domain.TokenPair) *domain.AuthResult {
+	return &domain.AuthResult{
+		Tokens: *tokens,
+		User: domain.UserInfo{
+			ID:            user.ID,
+			Email:         user.Email,
+			FirstName:     user.FirstName,
+			LastName:      user.LastName,
+			Role:          user.Role,
+			EmailVerified: user.EmailVerifiedAt != nil,
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+		},
+	}
+}
 
-	return s.txRunner.WithTx(ctx, func(tx Tx) error {
-		bankUSDAccountID, err := s.accountRepo.FindAccountIDTx(ctx, tx, systemBankUserID, domain.CurrencyUSD)
-		if err != nil {
-			return fmt.Errorf("find bank USD account: %w", err)
+// initialFundingMinor is the demo balance granted on registration, per currency in minor units.
+var initialFundingMinor = map[domain.Currency]int64{
+	domain.CurrencyUSD: 1000_00,
+	domain.CurrencyEUR: 500_00,
+}
+
+// createDefaultAccounts opens one account per enabled currency.
+func (s *AuthService) createDefaultAccounts(ctx context.Context, userID uuid.UUID) ([]*domain.Account, error) {
+	now := time.Now()
+
+	enabled := s.currencies.Enabled()
+	accounts := make([]*domain.Account, 0, len(enabled))
+	for _, c := range enabled {
+		account := &domain.Account{
+			ID:           uuid.New(),
+			UserID:       userID,
+			Currency:     c.Code,
+			Name:         "Main " + string(c.Code),
+			Status:       domain.AccountStatusActive,
+			IsDefault:    true,
+			BalanceCents: 0,
+			CreatedAt:    now,
+			UpdatedAt:    now,
 		}
-		bankEURAccountID, err := s.accountRepo.FindAccountIDTx(ctx, tx, systemBankUserID, domain.CurrencyEUR)
-		if err != nil {
-			return fmt.Errorf("find bank EUR account: %w", err)
+		if err := s.accountRepo.Create(ctx, account); err != nil {
+			s.logger.Error("Failed to create account", "error", err, "user_id", userID, "currency", c.Code)
+			return nil, err
 		}
+		accounts = append(accounts, account)
+	}
 
-		if err := s.createFundingTransferTx(ctx, tx, bankUSDAccountID, userUSDAccountID, domain.CurrencyUSD, initialUSDCents, "Initial USD funding"); err != nil {
-			return fmt.Errorf("initial USD funding: %w", err)
-		}
-		if err := s.createFundingTransferTx(ctx, tx, bankEURAccountID, userEURAccountID, domain.CurrencyEUR, initialEURCents, "Initial EUR funding"); err != nil {
-			return fmt.Errorf("initial EUR funding: %w", err)
+	s.logger.Info("Default accounts created", "user_id", userID, "count", len(accounts))
+	return accounts, nil
+}
+
+func (s *AuthService) fundInitialBalancesViaLedger(ctx context.Context, userID uuid.UUID, accounts []*domain.Account) error {
+	return s.txRunner.WithTx(ctx, func(tx Tx) error {
+		for _, account := range accounts {
+			amount := initialFundingMinor[account.Currency]
+			if amount == 0 {
+				continue
+			}
+			bankAccountID, err := s.accountRepo.FindAccountIDTx(ctx, tx, systemBankUserID, account.Currency)
+			if err != nil {
+				return fmt.Errorf("find bank %s account: %w", account.Currency, err)
+			}
+			purpose := fmt.Sprintf("Initial %s funding", account.Currency)
+			if err := s.createFundingTransferTx(ctx, tx, bankAccountID, account.ID, account.Currency, amount, purpose); err != nil {
+				return fmt.Errorf("initial %s funding: %w", account.Currency, err)
+			}
 		}
 		return nil
 	})
@@ -251,7 +351,7 @@ func (s *AuthService) createFundingTransferTx(ctx context.Context, tx Tx, fromAc
 
 	transactionID := uuid.New()
 	createdAt := time.Now()
-	amountStr := domain.CentsToDecimalString(amountCents)
+	amountStr := s.currencies.Format(currency, amountCents)
 	created := &domain.Transaction{
 		ID:            transactionID,
 		Type:          domain.TransactionTypeTransfer,
@@ -259,7 +359,9 @@ func (s *AuthService) createFundingTransferTx(ctx context.Context, tx Tx, fromAc
 		ToAccountID:   toAccountID,
 		AmountCents:   amountCents,
 		Currency:      currency,
+		ToCurrency:    currency,
 		Description:   fmt.Sprintf("%s: %s %s", purpose, currency, amountStr),
+		Status:        domain.TransactionStatusPosted,
 		CreatedAt:     createdAt,
 	}
 
@@ -271,6 +373,7 @@ func (s *AuthService) createFundingTransferTx(ctx context.Context, tx Tx, fromAc
 		ID:            uuid.New(),
 		TransactionID: transactionID,
 		AccountID:     fromAccountID,
+		Currency:      currency,
 		AmountCents:   -amountCents,
 		CreatedAt:     createdAt,
 	}
@@ -281,6 +384,7 @@ func (s *AuthService) createFundingTransferTx(ctx context.Context, tx Tx, fromAc
 		ID:            uuid.New(),
 		TransactionID: transactionID,
 		AccountID:     toAccountID,
+		Currency:      currency,
 		AmountCents:   amountCents,
 		CreatedAt:     createdAt,
 	}
@@ -295,52 +399,86 @@ func (s *AuthService) createFundingTransferTx(ctx context.Context, tx Tx, fromAc
 	newFrom := fromAccount.BalanceCents - amountCents
 	newTo := toAccount.BalanceCents + amountCents
 
-	if err := s.accountRepo.UpdateBalanceString(ctx, tx, fromAccountID, domain.CentsToDecimalString(newFrom)); err != nil {
+	if err := s.accountRepo.UpdateBalanceString(ctx, tx, fromAccountID, s.currencies.Format(currency, newFrom)); err != nil {
 		return fmt.Errorf("update from balance: %w", err)
 	}
-	if err := s.accountRepo.UpdateBalanceString(ctx, tx, toAccountID, domain.CentsToDecimalString(newTo)); err != nil {
+	if err := s.accountRepo.UpdateBalanceString(ctx, tx, toAccountID, s.currencies.Format(currency, newTo)); err != nil {
 		return fmt.Errorf("update to balance: %w", err)
 	}
 
 	return nil
 }
 
-func (s *AuthService) generateTokenPair(ctx context.Context, userID uuid.UUID) (*domain.TokenPair, error) {
-	accessToken, err := s.tokenManager.GenerateAccessToken(ctx, userID)
+// generateTokenPair issues tokens for a new login, starting a new refresh token family (session).
+func (s *AuthService) generateTokenPair(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.TokenPair, error) {
+	pair, record, err := s.newTokenPair(ctx, user, nil, client)
 	if err != nil {
-		return nil, fmt.Errorf("auth.generate_token_pair: generate access token: %w", err)
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.Create(ctx, record); err != nil {
+		return nil, fmt.Errorf("auth.generate_token_pair: store refresh token: %w", err)
 	}
+	return pair, nil
+}
 
-	refreshToken, err := s.tokenManager.GenerateRefreshToken(ctx, userID)
+// newTokenPair signs an access token and creates a refresh token record. With a parent the record
+// joins the parent's family and keeps its device label; otherwise it starts a new one.
+func (s *AuthService) newTokenPair(ctx context.Context, user *domain.User, parent *RefreshToken, client domain.ClientInfo) (*domain.TokenPair, *RefreshToken, error) {
+	userID := user.ID
+	client = client.Normalize()
+	now := time.Now()
+	record := &RefreshToken{
+		ID:               uuid.New(),
+		UserID:           userID,
+		ExpiresAt:        now.Add(s.tokenManager.GetRefreshTokenTTL()),
+		CreatedAt:        now,
+		UserAgent:        client.UserAgent,
+		IP:               client.IP,
+		DeviceLabel:      client.DeviceLabel,
+		SessionCreatedAt: now,
+	}
+	record.FamilyID = record.ID
+	if parent != nil {
+		parentID := parent.ID
+		record.FamilyID = parent.FamilyID
+		record.ParentID = &parentID
+		record.DeviceLabel = parent.DeviceLabel
+		record.SessionCreatedAt = parent.SessionCreatedAt
+	}
+
+	accessToken, err := s.tokenManager.GenerateAccessToken(ctx, userID, string(user.Role), record.FamilyID)
 	if err != nil {
-		return nil, fmt.Errorf("auth.generate_token_pair: generate refresh token: %w", err)
+		return nil, nil, fmt.Errorf("auth.generate_token_pair: generate access token: %w", err)
 	}
 
-	tokenHash := s.hasher.SHA256Hex(refreshToken)
-
-	refreshTokenRecord := &RefreshToken{
-		ID:        uuid.New(),
-		UserID:    userID,
-		TokenHash: tokenHash,
-		ExpiresAt: time.Now().Add(s.tokenManager.GetRefreshTokenTTL()),
-		CreatedAt: time.Now(),
+	refreshToken, err := s.tokenManager.GenerateRefreshToken(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("auth.generate_token_pair: generate refresh token: %w", err)
 	}
 
-	if err := s.refreshTokenRepo.Create(ctx, refreshTokenRecord); err != nil {
-		return nil, fmt.Errorf("auth.generate_token_pair: store refresh token: %w", err)
-	}
+	record.TokenHash = s.hasher.SHA256Hex(refreshToken)
 
-	return &domain.TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+	return &domain.TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, record, nil
 }
 
-func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (uuid.UUID, error) {
+// ValidateToken verifies an access token and returns the caller. Tokens issued before roles existed
+// carry no role claim and are treated as customer tokens.
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*domain.Principal, error) {
 	claims, err := s.tokenManager.ValidateAccessToken(ctx, tokenString)
 	if err != nil {
 		s.logger.Warn("Invalid token", "error", err)
-		return uuid.Nil, apperr.ErrInvalidToken
+		return nil, apperr.ErrInvalidToken
 	}
 
-	return claims.UserID, nil
+	role := domain.Role(claims.Role)
+	if role == "" {
+		role = domain.RoleCustomer
+	}
+	principal := &domain.Principal{UserID: claims.UserID, Role: role}
+	if sessionID, err := uuid.Parse(claims.SessionID); err == nil {
+		principal.SessionID = sessionID
+	}
+	return principal, nil
 }
 
 // GetUserByID returns a client-facing user DTO.
@@ -354,54 +492,121 @@ func (s *AuthService) GetUserByID(ctx context.Context, userID uuid.UUID) (*domai
 		return nil, fmt.Errorf("auth.get_user_by_id: %w", err)
 	}
 	return &domain.UserInfo{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}, nil
 }
 
-// RefreshToken rotates refresh token and issues a new token pair.
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+// RefreshToken rotates refresh token and issues a new token pair. The presented token is kept as
+// rotated; presenting it again means it was copied, so the whole family is revoked.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.TokenPair, error) {
 	s.logger.Info("Refreshing token")
 
 	tokenHash := s.hasher.SHA256Hex(refreshToken)
 
-	tokenRecord, err := s.refreshTokenRepo.GetByTokenHash(ctx, tokenHash)
-	if err != nil {
+	var (
+		tokenRecord *RefreshToken
+		user        *domain.User
+		tokenPair   *domain.TokenPair
+		revoked     int64
+	)
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		var err error
+		tokenRecord, err = s.refreshTokenRepo.LockByTokenHashTx(ctx, tx, tokenHash)
+		if err != nil {
+			return err
+		}
+		if tokenRecord.RevokedAt != nil {
+			return apperr.ErrInvalidToken
+		}
+
+		now := time.Now()
+		if tokenRecord.RotatedAt != nil {
+			// Commit the revocation; the caller still gets ErrInvalidToken below.
+			revoked, err = s.refreshTokenRepo.RevokeFamilyTx(ctx, tx, tokenRecord.FamilyID, now)
+			if err != nil {
+				return fmt.Errorf("revoke token family: %w", err)
+			}
+			return nil
+		}
+
+		// Reload the user so role changes apply from the next refresh on.
+		user, err = s.userRepo.GetByID(ctx, tokenRecord.UserID)
+		if err != nil {
+			return fmt.Errorf("get user: %w", err)
+		}
+
+		var next *RefreshToken
+		tokenPair, next, err = s.newTokenPair(ctx, user, tokenRecord, client)
+		if err != nil {
+			return err
+		}
+		if err := s.refreshTokenRepo.MarkRotatedTx(ctx, tx, tokenRecord.ID, now); err != nil {
+			return fmt.Errorf("mark token rotated: %w", err)
+		}
+		if err := s.refreshTokenRepo.CreateTx(ctx, tx, next); err != nil {
+			return fmt.Errorf("store refresh token: %w", err)
+		}
+		return nil
+	}); err != nil {
 		if errors.Is(err, apperr.ErrInvalidToken) {
-			s.logger.Warn("Refresh token not found or expired", "error", err)
+			s.logger.Warn("Refresh token not found, expired or revoked")
 			return nil, apperr.ErrInvalidToken
 		}
-		return nil, fmt.Errorf("auth.refresh_token: get refresh token: %w", err)
+		s.logger.Error("Failed to refresh tokens", "error", err)
+		return nil, fmt.Errorf("auth.refresh_token: %w", err)
 	}
 
-	if err := s.refreshTokenRepo.Delete(ctx, tokenHash); err != nil {
-		s.logger.Warn("Failed to delete old refresh token", "error", err)
-	}
-
-	tokenPair, err := s.generateTokenPair(ctx, tokenRecord.UserID)
-	if err != nil {
-		s.logger.Error("Failed to generate new tokens", "error", err, "user_id", tokenRecord.UserID)
-		return nil, fmt.Errorf("auth.refresh_token: generate tokens: %w", err)
+	if tokenPair == nil {
+		s.logger.Warn("Refresh token reuse detected, token family revoked",
+			"user_id", tokenRecord.UserID, "family_id", tokenRecord.FamilyID, "revoked", revoked)
+		// Access tokens already issued to the family are what a thief would be holding.
+		if err := s.revoker.RevokeSession(ctx, tokenRecord.FamilyID, tokenRecord.UserID); err != nil {
+			s.logger.Error("Failed to revoke access tokens of reused token family", "user_id", tokenRecord.UserID, "family_id", tokenRecord.FamilyID, "error", err)
+		}
+		s.recordAuth(ctx, AuditActionRefreshTokenReuse, nil, "", "user", tokenRecord.UserID.String(), map[string]any{
+			"family_id":      tokenRecord.FamilyID,
+			"token_id":       tokenRecord.ID,
+			"rotated_at":     tokenRecord.RotatedAt,
+			"revoked_tokens": revoked,
+		})
+		return nil, apperr.ErrInvalidToken
 	}
 
 	s.logger.Info("Token refreshed successfully", "user_id", tokenRecord.UserID)
+	s.recordAuth(ctx, AuditActionTokenRefreshed, &user.ID, user.Role, "user", user.ID.String(), nil)
 
 	return tokenPair, nil
 }
 
-// Logout revokes the provided refresh token.
+// Logout revokes the provided refresh token and, if it is still valid, the access token.
 func (s *AuthService) Logout(ctx context.Context, in *domain.LogoutInput) error {
 	s.logger.Info("User logout")
 
+	var actorID *uuid.UUID
+	var role domain.Role
 	claims, err := s.tokenManager.ValidateAccessToken(ctx, in.AccessToken)
 	if err != nil {
 		s.logger.Warn("Invalid access token during logout", "error", err)
 	} else {
 		s.logger.Info("Logging out user", "user_id", claims.UserID)
+		actorID = &claims.UserID
+		role = domain.Role(claims.Role)
+		if role == "" {
+			role = domain.RoleCustomer
+		}
+		if claims.ID != "" && claims.ExpiresAt != nil {
+			if err := s.revoker.RevokeToken(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+				s.logger.Error("Failed to revoke access token", "error", err, "user_id", claims.UserID)
+				return fmt.Errorf("auth.logout: %w", err)
+			}
+		}
 	}
 
 	tokenHash := s.hasher.SHA256Hex(in.RefreshToken)
@@ -410,5 +615,146 @@ func (s *AuthService) Logout(ctx context.Context, in *domain.LogoutInput) error
 	}
 
 	s.logger.Info("User logged out successfully")
+	targetID := ""
+	if actorID != nil {
+		targetID = actorID.String()
+	}
+	s.recordAuth(ctx, AuditActionLogout, actorID, role, "user", targetID, nil)
 	return nil
 }
+
+// LogoutAll ends every session of the user: all refresh tokens are deleted and every access token
+// issued so far, including the caller's, stops being accepted.
+func (s *AuthService) LogoutAll(ctx context.Context, principal *domain.Principal) error {
+	if err := s.revoker.RevokeAllForUser(ctx, principal.UserID, time.Now()); err != nil {
+		s.logger.Error("Failed to revoke access tokens", "error", err, "user_id", principal.UserID)
+		return fmt.Errorf("auth.logout_all: %w", err)
+	}
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, principal.UserID); err != nil {
+		s.logger.Error("Failed to delete refresh tokens", "error", err, "user_id", principal.UserID)
+		return fmt.Errorf("auth.logout_all: delete refresh tokens: %w", err)
+	}
+
+	s.logger.Info("User logged out everywhere", "user_id", principal.UserID)
+	s.recordAuth(ctx, AuditActionLogoutAll, &principal.UserID, principal.Role, "user", principal.UserID.String(), nil)
+	return nil
+}
+
+// ListSessions returns the caller's signed-in devices, marking the one making the request.
+func (s *AuthService) ListSessions(ctx context.Context, principal *domain.Principal) ([]*domain.Session, error) {
+	tokens, err := s.refreshTokenRepo.ListActiveByUserID(ctx, principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("auth.list_sessions: %w", err)
+	}
+
+	sessions := make([]*domain.Session, len(tokens))
+	for i, t := range tokens {
+		sessions[i] = &domain.Session{
+			ID:          t.FamilyID,
+			DeviceLabel: t.DeviceLabel,
+			UserAgent:   t.UserAgent,
+			IP:          t.IP,
+			CreatedAt:   t.SessionCreatedAt,
+			LastUsedAt:  t.CreatedAt,
+			ExpiresAt:   t.ExpiresAt,
+			Current:     t.FamilyID == principal.SessionID,
+		}
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the caller's devices out: its refresh tokens are revoked and access
+// tokens issued for it stop being accepted.
+func (s *AuthService) RevokeSession(ctx context.Context, principal *domain.Principal, sessionID uuid.UUID) error {
+	tokens, err := s.refreshTokenRepo.ListActiveByUserID(ctx, principal.UserID)
+	if err != nil {
+		return fmt.Errorf("auth.revoke_session: %w", err)
+	}
+	found := false
+	for _, t := range tokens {
+		if t.FamilyID == sessionID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return apperr.ErrSessionNotFound
+	}
+
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		_, err := s.refreshTokenRepo.RevokeFamilyTx(ctx, tx, sessionID, time.Now())
+		return err
+	}); err != nil {
+		return fmt.Errorf("auth.revoke_session: revoke refresh tokens: %w", err)
+	}
+	if err := s.revoker.RevokeSession(ctx, sessionID, principal.UserID); err != nil {
+		return fmt.Errorf("auth.revoke_session: %w", err)
+	}
+
+	s.logger.Info("Session revoked", "user_id", principal.UserID, "session_id", sessionID)
+	s.recordAuth(ctx, AuditActionSessionRevoked, &principal.UserID, principal.Role, "session", sessionID.String(), nil)
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt towards the email's and the client's throttles. A failure
+// to count is logged; the login still fails with the original error.
+func (s *AuthService) recordLoginFailure(ctx context.Context, email string, ip string) {
+	if s.guard == nil {
+		return
+	}
+	locked, err := s.guard.RecordFailure(ctx, email, ip)
+	if err != nil {
+		s.logger.Error("Failed to record failed login", "error", err, "email", email)
+		return
+	}
+	if locked {
+		s.logger.Warn("Login locked after repeated failures", "email", email, "ip", ip)
+		s.recordAuth(ctx, AuditActionLoginLocked, nil, "", "email", email, map[string]any{"ip": ip})
+	}
+}
+
+// upgradePasswordHash rehashes a correct password whose hash uses an older algorithm or a lower cost
+// than configured. The login does not depend on it, so failures are only logged.
+func (s *AuthService) upgradePasswordHash(ctx context.Context, user *domain.User, password string) {
+	if !s.passwords.NeedsRehash(user.PasswordHash) {
+		return
+	}
+	newHash, err := s.passwords.Hash(password)
+	if err != nil {
+		s.logger.Error("Failed to rehash password", "error", err, "user_id", user.ID)
+		return
+	}
+	if err := s.userRepo.ReplacePasswordHash(ctx, user.ID, user.PasswordHash, newHash); err != nil {
+		s.logger.Error("Failed to store rehashed password", "error", err, "user_id", user.ID)
+		return
+	}
+	user.PasswordHash = newHash
+	s.logger.Info("Password hash upgraded", "user_id", user.ID)
+}
+
+func (s *AuthService) recordLoginSuccess(ctx context.Context, email string) {
+	if s.guard == nil {
+		return
+	}
+	if err := s.guard.RecordSuccess(ctx, email); err != nil {
+		s.logger.Error("Failed to reset failed logins", "error", err, "email", email)
+	}
+}
+
+// recordAuth writes an authentication event. actorID is nil when the caller is not authenticated,
+// as for failed logins.
+func (s *AuthService) recordAuth(ctx context.Context, action string, actorID *uuid.UUID, role domain.Role, targetType string, targetID string, metadata map[string]any) {
+	event := &domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    actorID,
+		ActorRole:  role,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if metadata != nil {
+		event.Metadata = auditJSON(metadata)
+	}
+	recordAudit(ctx, s.audit, s.logger, event)
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/internal/jwt"
+	"banking-platform/pkg/hash"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type memoryUserRepo struct {
+	users map[uuid.UUID]*domain.User
+}
+
+func (r *memoryUserRepo) Create(ctx context.Context, user *domain.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, apperr.ErrUserNotFound
+}
+
+func (r *memoryUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, apperr.ErrUserNotFound
+}
+
+func (r *memoryUserRepo) GetAll(ctx context.Context) ([]*domain.User, error) {
+	var out []*domain.User
+	for _, u := range r.users {
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+func (r *memoryUserRepo) UpdatePasswordTx(ctx context.Context, tx Tx, id uuid.UUID, passwordHash string, at time.Time) error {
+	u, ok := r.users[id]
+	if !ok {
+		return apperr.ErrUserNotFound
+	}
+	u.PasswordHash = passwordHash
+	u.UpdatedAt = at
+	return nil
+}
+
+func (r *memoryUserRepo) ReplacePasswordHash(ctx context.Context, id uuid.UUID, oldHash string, newHash string) error {
+	if u, ok := r.users[id]; ok && u.PasswordHash == oldHash {
+		u.PasswordHash = newHash
+	}
+	return nil
+}
+
+func (r *memoryUserRepo) MarkEmailVerifiedTx(ctx context.Context, tx Tx, id uuid.UUID, at time.Time) error {
+	if u, ok := r.users[id]; ok && u.EmailVerifiedAt == nil {
+		u.EmailVerifiedAt = &at
+	}
+	return nil
+}
+
+type memoryRefreshTokenRepo struct {
+	tokens map[string]*RefreshToken
+}
+
+func (r *memoryRefreshTokenRepo) Create(ctx context.Context, token *RefreshToken) error {
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *memoryRefreshTokenRepo) CreateTx(ctx context.Context, tx Tx, token *RefreshToken) error {
+	return r.Create(ctx, token)
+}
+
+func (r *memoryRefreshTokenRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	t, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, apperr.ErrInvalidToken
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (r *memoryRefreshTokenRepo) LockByTokenHashTx(ctx context.Context, tx Tx, tokenHash string) (*RefreshToken, error) {
+	return r.GetByTokenHash(ctx, tokenHash)
+}
+
+func (r *memoryRefreshTokenRepo) MarkRotatedTx(ctx context.Context, tx Tx, id uuid.UUID, at time.Time) error {
+	for _, t := range r.tokens {
+		if t.ID == id {
+			t.RotatedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryRefreshTokenRepo) RevokeFamilyTx(ctx context.Context, tx Tx, familyID uuid.UUID, at time.Time) (int64, error) {
+	var n int64
+	for _, t := range r.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &at
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryRefreshTokenRepo) ListActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*RefreshToken, error) {
+	var out []*RefreshToken
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RotatedAt == nil && t.RevokedAt == nil {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryRefreshTokenRepo) Delete(ctx context.Context, tokenHash string) error {
+	delete(r.tokens, tokenHash)
+	return nil
+}
+
+func (r *memoryRefreshTokenRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	for h, t := range r.tokens {
+		if t.UserID == userID {
+			delete(r.tokens, h)
+		}
+	}
+	return nil
+}
+
+type authFixture struct {
+	service     *AuthService
+	tokens      *memoryRefreshTokenRepo
+	revocations *TokenRevocationStore
+	audit       *memoryAuditRepo
+	mfa         *MFAService
+	credentials *CredentialService
+	mail        *memoryMailer
+	throttle    *LoginThrottle
+	user        *domain.User
+}
+
+func newAuthFixture() *authFixture {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	user := &domain.User{ID: uuid.New(), Email: "alice@test.com", Role: domain.RoleCustomer}
+	users := &memoryUserRepo{users: map[uuid.UUID]*domain.User{user.ID: user}}
+	tokens := &memoryRefreshTokenRepo{tokens: map[string]*RefreshToken{}}
+	auditRepo := &memoryAuditRepo{}
+	revocations := NewTokenRevocationStore(&memoryTokenRevocationRepo{}, time.Minute, time.Second, logger)
+	auditLog := NewAuditLog(auditRepo, inlineTxRunner{}, logger)
+	mfa := NewMFAService(&memoryMFARepo{}, &memoryMFAChallengeRepo{}, users, inlineTxRunner{}, hash.NewHasher(), "Test Bank", auditLog, logger)
+	mail := &memoryMailer{}
+	passwords, _ := hash.NewPasswordHasher(hash.AlgorithmBcrypt, bcrypt.MinCost, hash.DefaultArgon2Params)
+	policy, _ := NewPasswordPolicy(8, nil, []string{"password1"})
+	credentials := NewCredentialService(
+		users, &memoryAccountTokenRepo{}, tokens, revocations, inlineTxRunner{}, mail, hash.NewHasher(), passwords, policy,
+		"https://bank.test/", auditLog, logger,
+	)
+	throttle := NewLoginThrottle(&memoryLoginThrottleRepo{}, inlineTxRunner{}, testLoginThrottlePolicy, logger)
+	svc := NewAuthService(
+		users, nil, inlineTxRunner{}, nil, nil, tokens, revocations, mfa, credentials, throttle,
+		jwt.NewTokenManager(jwt.NewHMACKeySet("test-secret"), time.Minute, time.Hour, revocations),
+		hash.NewHasher(), passwords, policy, nil,
+		auditLog,
+		logger,
+	)
+	return &authFixture{
+		service: svc, tokens: tokens, revocations: revocations, audit: auditRepo,
+		mfa: mfa, credentials: credentials, mail: mail, throttle: throttle, user: user,
+	}
+}
+
+func TestRefreshTokenRotationKeepsFamily(t *testing.T) {
+	f := newAuthFixture()
+	first, err := f.service.generateTokenPair(context.Background(), f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	second, err := f.service.RefreshToken(context.Background(), first.RefreshToken, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	parent, _ := f.tokens.GetByTokenHash(context.Background(), f.service.hasher.SHA256Hex(first.RefreshToken))
+	child, _ := f.tokens.GetByTokenHash(context.Background(), f.service.hasher.SHA256Hex(second.RefreshToken))
+	if parent.RotatedAt == nil {
+		t.Fatalf("parent not marked rotated")
+	}
+	if child.FamilyID != parent.FamilyID || child.ParentID == nil || *child.ParentID != parent.ID {
+		t.Fatalf("got family=%s parent=%v want family=%s parent=%s", child.FamilyID, child.ParentID, parent.FamilyID, parent.ID)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+	first, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	second, err := f.service.RefreshToken(ctx, first.RefreshToken, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	// An unrelated login must survive the revocation.
+	other, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	if _, err := f.service.RefreshToken(ctx, first.RefreshToken, domain.ClientInfo{}); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("reuse err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.ValidateToken(ctx, second.AccessToken); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("family access token err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.ValidateToken(ctx, other.AccessToken); err != nil {
+		t.Fatalf("other family access token err=%v", err)
+	}
+	if _, err := f.service.RefreshToken(ctx, second.RefreshToken, domain.ClientInfo{}); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("descendant err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.RefreshToken(ctx, other.RefreshToken, domain.ClientInfo{}); err != nil {
+		t.Fatalf("other family err=%v", err)
+	}
+
+	var reuseEvents int
+	for _, e := range f.audit.events {
+		if e.Action == AuditActionRefreshTokenReuse {
+			reuseEvents++
+		}
+	}
+	if reuseEvents != 1 {
+		t.Fatalf("reuse events=%d want=1", reuseEvents)
+	}
+}
+
+func TestLogoutRevokesAccessToken(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+	pair, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	other, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	if err := f.service.Logout(ctx, &domain.LogoutInput{AccessToken: pair.AccessToken, RefreshToken: pair.RefreshToken}); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if _, err := f.service.ValidateToken(ctx, pair.AccessToken); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("logged out token err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.ValidateToken(ctx, other.AccessToken); err != nil {
+		t.Fatalf("other session err=%v", err)
+	}
+}
+
+func TestLogoutAllRevokesEverySession(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+	first, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	second, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	if err := f.service.LogoutAll(ctx, &domain.Principal{UserID: f.user.ID, Role: f.user.Role}); err != nil {
+		t.Fatalf("logout all: %v", err)
+	}
+	for _, pair := range []*domain.TokenPair{first, second} {
+		if _, err := f.service.ValidateToken(ctx, pair.AccessToken); !errors.Is(err, apperr.ErrInvalidToken) {
+			t.Fatalf("access token err=%v want=%v", err, apperr.ErrInvalidToken)
+		}
+		if _, err := f.service.RefreshToken(ctx, pair.RefreshToken, domain.ClientInfo{}); !errors.Is(err, apperr.ErrInvalidToken) {
+			t.Fatalf("refresh token err=%v want=%v", err, apperr.ErrInvalidToken)
+		}
+	}
+}
+
+func TestSessionsListAndRevoke(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+	phone, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)", IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	laptop, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{DeviceLabel: "Work laptop", IP: "10.0.0.2"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	laptop, err = f.service.RefreshToken(ctx, laptop.RefreshToken, domain.ClientInfo{IP: "10.0.0.3"})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	principal, err := f.service.ValidateToken(ctx, laptop.AccessToken)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	sessions, err := f.service.ListSessions(ctx, principal)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("sessions=%d want=2", len(sessions))
+	}
+	var current, other *domain.Session
+	for _, s := range sessions {
+		if s.Current {
+			current = s
+		} else {
+			other = s
+		}
+	}
+	if current == nil || current.ID != principal.SessionID {
+		t.Fatalf("current session not marked")
+	}
+	if current.DeviceLabel != "Work laptop" || current.IP != "10.0.0.3" {
+		t.Fatalf("current label=%q ip=%q want label=%q ip=%q", current.DeviceLabel, current.IP, "Work laptop", "10.0.0.3")
+	}
+
+	if err := f.service.RevokeSession(ctx, principal, other.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := f.service.ValidateToken(ctx, phone.AccessToken); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("revoked access token err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.RefreshToken(ctx, phone.RefreshToken, domain.ClientInfo{}); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("revoked refresh token err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.ValidateToken(ctx, laptop.AccessToken); err != nil {
+		t.Fatalf("current session err=%v", err)
+	}
+	if err := f.service.RevokeSession(ctx, principal, other.ID); !errors.Is(err, apperr.ErrSessionNotFound) {
+		t.Fatalf("second revoke err=%v want=%v", err, apperr.ErrSessionNotFound)
+	}
+}
+
+func TestLoginUpgradesPasswordHash(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	oldHash := f.user.PasswordHash
+
+	if _, err := f.service.Login(ctx, &domain.LoginInput{Email: f.user.Email, Password: "password123"}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if f.user.PasswordHash != oldHash {
+		t.Fatalf("hash at the configured cost was replaced")
+	}
+
+	argon := hash.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	upgraded, err := hash.NewPasswordHasher(hash.AlgorithmArgon2id, bcrypt.MinCost, argon)
+	if err != nil {
+		t.Fatalf("new hasher: %v", err)
+	}
+	f.service.passwords = upgraded
+
+	if _, err := f.service.Login(ctx, &domain.LoginInput{Email: f.user.Email, Password: "wrong-password"}); !errors.Is(err, apperr.ErrInvalidCredentials) {
+		t.Fatalf("wrong password err=%v want=%v", err, apperr.ErrInvalidCredentials)
+	}
+	if f.user.PasswordHash != oldHash {
+		t.Fatalf("hash replaced after a failed login")
+	}
+	if _, err := f.service.Login(ctx, &domain.LoginInput{Email: f.user.Email, Password: "password123"}); err != nil {
+		t.Fatalf("login with old hash: %v", err)
+	}
+	if upgraded.NeedsRehash(f.user.PasswordHash) {
+		t.Fatalf("hash not upgraded: %s", f.user.PasswordHash)
+	}
+	if _, err := f.service.Login(ctx, &domain.LoginInput{Email: f.user.Email, Password: "password123"}); err != nil {
+		t.Fatalf("login with upgraded hash: %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Capture posts a pending authorization. The recipient captures it, for the authorized amount or
+// less; whatever is not captured goes back to the sender's available balance. The authorized amount
+// is kept and the posted one recorded next to it. Transfer limits are checked now, as the money
+// only leaves the sender's account at this point.
+func (s *TransactionService) Capture(ctx context.Context, actorID uuid.UUID, in *domain.CaptureInput) (*domain.TransactionInfo, error) {
+	s.logger.Info("Processing capture", "transaction_id", in.TransactionID, "actor_id", actorID)
+
+	if in.AmountCents != nil && *in.AmountCents <= 0 {
+		return nil, apperr.BadRequest("amount_cents must be greater than 0")
+	}
+
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		// Locking the authorization serializes concurrent captures and releases of it.
+		auth, err := s.transactionRepo.LockByIDTx(ctx, tx, in.TransactionID)
+		if err != nil {
+			return fmt.Errorf("transaction.capture: lock authorization: %w", err)
+		}
+		party, err := s.isParty(ctx, actorID, auth)
+		if err != nil {
+			return fmt.Errorf("transaction.capture: %w", err)
+		}
+		if !party {
+			return apperr.ErrTransactionNotFound
+		}
+		if !isLivePending(auth, time.Now()) {
+			return apperr.ErrAuthorizationNotPending
+		}
+		amountCents := auth.AmountCents
+		if in.AmountCents != nil {
+			if *in.AmountCents > auth.AmountCents {
+				return apperr.BadRequest("amount_cents cannot exceed the authorized amount")
+			}
+			amountCents = *in.AmountCents
+		}
+
+		payerAccountID := *auth.FromAccountID
+		payeeAccountID := auth.ToAccountID
+
+		// Lock deterministically to avoid deadlocks.
+		lockIDs := []uuid.UUID{payerAccountID, payeeAccountID}
+		sort.Slice(lockIDs, func(i, j int) bool { return lockIDs[i].String() < lockIDs[j].String() })
+
+		locked := make(map[uuid.UUID]*domain.Account, 2)
+		for _, id := range lockIDs {
+			acc, err := s.accountRepo.LockAccountForUpdate(ctx, tx, id)
+			if err != nil {
+				return fmt.Errorf("transaction.capture: lock account: %w", err)
+			}
+			locked[id] = acc
+		}
+
+		payer := locked[payerAccountID]
+		payee := locked[payeeAccountID]
+		if payer == nil || payee == nil {
+			return fmt.Errorf("transaction.capture: failed to lock accounts")
+		}
+
+		if payee.UserID != actorID {
+			if payer.UserID == actorID {
+				// The sender can release the hold but not collect it.
+				return apperr.ErrUnauthorized
+			}
+			return apperr.ErrTransactionNotFound
+		}
+		if err := ensureAccountActive(payer); err != nil {
+			return err
+		}
+		if err := ensureAccountActive(payee); err != nil {
+			return err
+		}
+		// The payer's available balance already excludes this hold.
+		if payer.AvailableCents()+auth.AmountCents < amountCents {
+			s.logger.Warn("Insufficient funds for capture", "account_id", payer.ID, "balance_cents", payer.BalanceCents, "held_cents", payer.HeldCents, "amount_cents", amountCents)
+			return apperr.ErrInsufficientFunds
+		}
+		if s.limits != nil && payer.UserID != payee.UserID {
+			if err := s.limits.CheckTx(ctx, tx, payer.UserID, auth.Currency, amountCents); err != nil {
+				s.logger.Warn("Transfer limit exceeded on capture", "user_id", payer.UserID, "amount_cents", amountCents, "currency", auth.Currency, "error", err)
+				return err
+			}
+		}
+
+		postedAt := time.Now()
+		for _, e := range []*domain.LedgerEntry{
+			{ID: uuid.New(), TransactionID: auth.ID, AccountID: payer.ID, Currency: auth.Currency, AmountCents: -amountCents, CreatedAt: postedAt},
+			{ID: uuid.New(), TransactionID: auth.ID, AccountID: payee.ID, Currency: auth.Currency, AmountCents: amountCents, CreatedAt: postedAt},
+		} {
+			if err := s.ledgerRepo.CreateEntry(ctx, tx, e); err != nil {
+				return fmt.Errorf("transaction.capture: create ledger entry: %w", err)
+			}
+		}
+
+		if err := s.ledgerRepo.VerifyTransactionBalanceTx(ctx, tx, auth.ID); err != nil {
+			s.logger.Error("Ledger not balanced (capture)", "error", err, "transaction_id", auth.ID)
+			return err
+		}
+
+		if err := s.accountRepo.UpdateBalanceString(ctx, tx, payer.ID, s.currencies.Format(auth.Currency, payer.BalanceCents-amountCents)); err != nil {
+			return fmt.Errorf("transaction.capture: update payer balance: %w", err)
+		}
+		if err := s.accountRepo.UpdateBalanceString(ctx, tx, payee.ID, s.currencies.Format(auth.Currency, payee.BalanceCents+amountCents)); err != nil {
+			return fmt.Errorf("transaction.capture: update payee balance: %w", err)
+		}
+
+		auth.Status = domain.TransactionStatusPosted
+		auth.CapturedAmountCents = &amountCents
+		if err := s.transactionRepo.SettleTx(ctx, tx, auth); err != nil {
+			return fmt.Errorf("transaction.capture: settle authorization: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	info, err := s.getTransactionInfo(ctx, in.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Authorization captured", "transaction_id", info.ID, "actor_id", actorID, "authorized_cents", info.AmountCents, "captured_cents", *info.CapturedAmountCents)
+	s.recordMovement(ctx, AuditActionCapture, actorID, info)
+	return info, nil
+}
+
+// Release voids a pending authorization without moving money, returning the held amount to the
+// sender's available balance. Either party may release it.
+func (s *TransactionService) Release(ctx context.Context, actorID uuid.UUID, id uuid.UUID) (*domain.TransactionInfo, error) {
+	s.logger.Info("Processing release", "transaction_id", id, "actor_id", actorID)
+
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		auth, err := s.transactionRepo.LockByIDTx(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("transaction.release: lock authorization: %w", err)
+		}
+		party, err := s.isParty(ctx, actorID, auth)
+		if err != nil {
+			return fmt.Errorf("transaction.release: %w", err)
+		}
+		if !party {
+			return apperr.ErrTransactionNotFound
+		}
+		if !isLivePending(auth, time.Now()) {
+			return apperr.ErrAuthorizationNotPending
+		}
+
+		auth.Status = domain.TransactionStatusVoided
+		if err := s.transactionRepo.SettleTx(ctx, tx, auth); err != nil {
+			return fmt.Errorf("transaction.release: void authorization: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	info, err := s.getTransactionInfo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Authorization released", "transaction_id", id, "actor_id", actorID)
+	s.recordMovement(ctx, AuditActionRelease, actorID, info)
+	return info, nil
+}
+
+// ExpireAuthorizations voids pending authorizations whose expiry has passed. Expired holds already
+// stop counting against the available balance; this only settles their status.
+func (s *TransactionService) ExpireAuthorizations(ctx context.Context, now time.Time) (int64, error) {
+	n, err := s.transactionRepo.VoidExpired(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("transaction.expire_authorizations: %w", err)
+	}
+	if n > 0 {
+		s.logger.Info("Expired authorizations voided", "count", n)
+	}
+	return n, nil
+}
+
+// isParty reports whether the user owns either account of a transaction. Authorizations have no
+// ledger postings to tell the parties by until they are captured.
+func (s *TransactionService) isParty(ctx context.Context, userID uuid.UUID, t *domain.Transaction) (bool, error) {
+	ids := []uuid.UUID{t.ToAccountID}
+	if t.FromAccountID != nil {
+		ids = append(ids, *t.FromAccountID)
+	}
+	for _, id := range ids {
+		acc, err := s.accountRepo.GetByID(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("get account: %w", err)
+		}
+		if acc.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isLivePending(t *domain.Transaction, now time.Time) bool {
+	return t.Type == domain.TransactionTypeTransfer && t.FromAccountID != nil &&
+		t.Status == domain.TransactionStatusPending && t.ExpiresAt != nil && t.ExpiresAt.After(now)
+}
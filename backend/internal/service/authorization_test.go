@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestIsLivePending(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+	from := uuid.New()
+	pending := func(mut func(*domain.Transaction)) *domain.Transaction {
+		t := &domain.Transaction{
+			Type:          domain.TransactionTypeTransfer,
+			FromAccountID: &from,
+			Status:        domain.TransactionStatusPending,
+			ExpiresAt:     &later,
+		}
+		if mut != nil {
+			mut(t)
+		}
+		return t
+	}
+
+	tests := []struct {
+		name string
+		tx   *domain.Transaction
+		want bool
+	}{
+		{name: "pending", tx: pending(nil), want: true},
+		{name: "expired", tx: pending(func(t *domain.Transaction) { t.ExpiresAt = &earlier })},
+		{name: "expires_now", tx: pending(func(t *domain.Transaction) { t.ExpiresAt = &now })},
+		{name: "posted", tx: pending(func(t *domain.Transaction) { t.Status = domain.TransactionStatusPosted })},
+		{name: "voided", tx: pending(func(t *domain.Transaction) { t.Status = domain.TransactionStatusVoided })},
+		{name: "no_expiry", tx: pending(func(t *domain.Transaction) { t.ExpiresAt = nil })},
+		{name: "exchange", tx: pending(func(t *domain.Transaction) { t.Type = domain.TransactionTypeExchange })},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLivePending(tt.tx, now); got != tt.want {
+				t.Fatalf("got=%v want=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureHidesAuthorizationsFromNonParties(t *testing.T) {
+	ctx := context.Background()
+	payer, payee := uuid.New(), uuid.New()
+	from := &domain.Account{ID: uuid.New(), UserID: payer, Currency: domain.CurrencyUSD}
+	to := &domain.Account{ID: uuid.New(), UserID: payee, Currency: domain.CurrencyUSD}
+	expired := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name   string
+		status domain.TransactionStatus
+	}{
+		{name: "voided", status: domain.TransactionStatusVoided},
+		{name: "expired", status: domain.TransactionStatusPending},
+		{name: "captured", status: domain.TransactionStatusPosted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &domain.Transaction{
+				ID:            uuid.New(),
+				Type:          domain.TransactionTypeTransfer,
+				FromAccountID: &from.ID,
+				ToAccountID:   to.ID,
+				Currency:      domain.CurrencyUSD,
+				AmountCents:   25_00,
+				Status:        tt.status,
+				ExpiresAt:     &expired,
+			}
+			s := &TransactionService{
+				accountRepo:     &memoryAccountRepo{accounts: map[uuid.UUID]*domain.Account{from.ID: from, to.ID: to}},
+				transactionRepo: &lockedTransactionRepo{transactions: map[uuid.UUID]*domain.Transaction{auth.ID: auth}},
+				txRunner:        inlineTxRunner{},
+				logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+
+			if _, err := s.Capture(ctx, uuid.New(), &domain.CaptureInput{TransactionID: auth.ID}); !errors.Is(err, apperr.ErrTransactionNotFound) {
+				t.Fatalf("stranger err=%v want not found", err)
+			}
+			if _, err := s.Capture(ctx, payee, &domain.CaptureInput{TransactionID: auth.ID}); !errors.Is(err, apperr.ErrAuthorizationNotPending) {
+				t.Fatalf("payee err=%v want not pending", err)
+			}
+		})
+	}
+}
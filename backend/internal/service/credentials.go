@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/pkg/hash"
+)
+
+const (
+	emailVerificationTTL = 24 * time.Hour
+	passwordResetTTL     = time.Hour
+	accountTokenBytes    = 32
+)
+
+// CredentialService verifies email addresses and changes or resets passwords. Links sent by email
+// carry single-use tokens that are stored hashed, like refresh tokens.
+type CredentialService struct {
+	userRepo         UserRepo
+	tokenRepo        AccountTokenRepo
+	refreshTokenRepo RefreshTokenRepo
+	revoker          TokenRevoker
+	txRunner         TxRunner
+	mailer           Mailer
+	hasher           *hash.Hasher
+	passwords        *hash.PasswordHasher
+	policy           *PasswordPolicy
+	appBaseURL       string
+	audit            AuditRecorder
+	logger           *slog.Logger
+}
+
+func NewCredentialService(
+	userRepo UserRepo,
+	tokenRepo AccountTokenRepo,
+	refreshTokenRepo RefreshTokenRepo,
+	revoker TokenRevoker,
+	txRunner TxRunner,
+	mailer Mailer,
+	hasher *hash.Hasher,
+	passwords *hash.PasswordHasher,
+	policy *PasswordPolicy,
+	appBaseURL string,
+	audit AuditRecorder,
+	logger *slog.Logger,
+) *CredentialService {
+	return &CredentialService{
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revoker:          revoker,
+		txRunner:         txRunner,
+		mailer:           mailer,
+		hasher:           hasher,
+		passwords:        passwords,
+		policy:           policy,
+		appBaseURL:       strings.TrimRight(appBaseURL, "/"),
+		audit:            audit,
+		logger:           logger,
+	}
+}
+
+// SendVerification emails the user a link that verifies their address. Earlier links stop working.
+func (s *CredentialService) SendVerification(ctx context.Context, user *domain.User) error {
+	if user.EmailVerifiedAt != nil {
+		return apperr.ErrEmailAlreadyVerified
+	}
+	token, err := s.issue(ctx, user.ID, AccountTokenEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return fmt.Errorf("credentials.send_verification: %w", err)
+	}
+
+	if err := s.mailer.Send(ctx, &MailMessage{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nconfirm your email address by opening this link within %s:\n\n%s\n",
+			user.FirstName, formatTTL(emailVerificationTTL), s.link("/verify-email", token)),
+	}); err != nil {
+		return fmt.Errorf("credentials.send_verification: send email: %w", err)
+	}
+
+	s.logger.Info("Verification email sent", "user_id", user.ID)
+	s.record(ctx, AuditActionEmailVerificationSent, &user.ID, user.Role, user.ID, nil)
+	return nil
+}
+
+// RequestEmailVerification sends the caller a new verification link.
+func (s *CredentialService) RequestEmailVerification(ctx context.Context, principal *domain.Principal) error {
+	user, err := s.userRepo.GetByID(ctx, principal.UserID)
+	if err != nil {
+		return fmt.Errorf("credentials.request_email_verification: get user: %w", err)
+	}
+	return s.SendVerification(ctx, user)
+}
+
+// VerifyEmail marks the address of the token's user as verified.
+func (s *CredentialService) VerifyEmail(ctx context.Context, token string) error {
+	var userID uuid.UUID
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		t, err := s.consumeTx(ctx, tx, AccountTokenEmailVerification, token)
+		if err != nil {
+			return err
+		}
+		userID = t.UserID
+		return s.userRepo.MarkEmailVerifiedTx(ctx, tx, t.UserID, time.Now())
+	}); err != nil {
+		return fmt.Errorf("credentials.verify_email: %w", err)
+	}
+
+	s.logger.Info("Email verified", "user_id", userID)
+	s.record(ctx, AuditActionEmailVerified, &userID, "", userID, nil)
+	return nil
+}
+
+// RequestPasswordReset emails a reset link if the address belongs to a user. It succeeds either way,
+// so the endpoint cannot be used to find out which addresses are registered.
+func (s *CredentialService) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if errors.Is(err, apperr.ErrUserNotFound) {
+		s.logger.Info("Password reset requested for unknown email", "email", email)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("credentials.request_password_reset: get user by email: %w", err)
+	}
+	if user.Role == domain.RoleSystem {
+		s.logger.Warn("Password reset requested for system user", "user_id", user.ID)
+		return nil
+	}
+
+	token, err := s.issue(ctx, user.ID, AccountTokenPasswordReset, passwordResetTTL)
+	if err != nil {
+		return fmt.Errorf("credentials.request_password_reset: %w", err)
+	}
+	if err := s.mailer.Send(ctx, &MailMessage{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nset a new password by opening this link within %s:\n\n%s\n\n"+
+			"If you did not ask for this, ignore this email; your password stays the same.\n",
+			user.FirstName, formatTTL(passwordResetTTL), s.link("/reset-password", token)),
+	}); err != nil {
+		return fmt.Errorf("credentials.request_password_reset: send email: %w", err)
+	}
+
+	s.logger.Info("Password reset email sent", "user_id", user.ID)
+	s.record(ctx, AuditActionPasswordResetRequest, nil, "", user.ID, nil)
+	return nil
+}
+
+// ResetPassword sets a new password with the token of a reset email and signs the user out
+// everywhere. Following the link also proves the address, so it is marked verified.
+func (s *CredentialService) ResetPassword(ctx context.Context, in *domain.ResetPasswordInput) error {
+	now := time.Now()
+	var userID uuid.UUID
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		t, err := s.consumeTx(ctx, tx, AccountTokenPasswordReset, in.Token)
+		if err != nil {
+			return err
+		}
+		userID = t.UserID
+		// The policy needs the address, so it is checked once the token names the user. A rejected
+		// password rolls back, leaving the link usable for another try.
+		user, err := s.userRepo.GetByID(ctx, t.UserID)
+		if err != nil {
+			return fmt.Errorf("get user: %w", err)
+		}
+		if err := s.policy.Validate(in.NewPassword, user.Email); err != nil {
+			return err
+		}
+		passwordHash, err := s.passwords.Hash(in.NewPassword)
+		if err != nil {
+			return fmt.Errorf("hash password: %w", err)
+		}
+		if err := s.userRepo.UpdatePasswordTx(ctx, tx, t.UserID, passwordHash, now); err != nil {
+			return fmt.Errorf("update password: %w", err)
+		}
+		return s.userRepo.MarkEmailVerifiedTx(ctx, tx, t.UserID, now)
+	}); err != nil {
+		return fmt.Errorf("credentials.reset_password: %w", err)
+	}
+
+	if err := s.revoker.RevokeAllForUser(ctx, userID, now); err != nil {
+		return fmt.Errorf("credentials.reset_password: %w", err)
+	}
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("credentials.reset_password: delete refresh tokens: %w", err)
+	}
+
+	s.logger.Info("Password reset", "user_id", userID)
+	s.record(ctx, AuditActionPasswordReset, &userID, "", userID, nil)
+	return nil
+}
+
+// ChangePassword replaces the caller's password after checking the current one. Every other session
+// is signed out; the one making the request stays signed in. Outstanding reset links stop working.
+func (s *CredentialService) ChangePassword(ctx context.Context, principal *domain.Principal, in *domain.ChangePasswordInput) error {
+	user, err := s.userRepo.GetByID(ctx, principal.UserID)
+	if err != nil {
+		return fmt.Errorf("credentials.change_password: get user: %w", err)
+	}
+	if err := s.passwords.Verify(user.PasswordHash, in.CurrentPassword); err != nil {
+		s.logger.Warn("Password change with incorrect current password", "user_id", user.ID)
+		return apperr.ErrIncorrectPassword
+	}
+	if in.NewPassword == in.CurrentPassword {
+		return apperr.ErrPasswordUnchanged
+	}
+	if err := s.policy.Validate(in.NewPassword, user.Email); err != nil {
+		return err
+	}
+	passwordHash, err := s.passwords.Hash(in.NewPassword)
+	if err != nil {
+		return fmt.Errorf("credentials.change_password: hash password: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		if err := s.userRepo.UpdatePasswordTx(ctx, tx, user.ID, passwordHash, now); err != nil {
+			return fmt.Errorf("update password: %w", err)
+		}
+		return s.tokenRepo.InvalidateTx(ctx, tx, user.ID, AccountTokenPasswordReset, now)
+	}); err != nil {
+		return fmt.Errorf("credentials.change_password: %w", err)
+	}
+
+	revoked, err := s.revokeOtherSessions(ctx, principal)
+	if err != nil {
+		return fmt.Errorf("credentials.change_password: %w", err)
+	}
+
+	s.logger.Info("Password changed", "user_id", user.ID, "revoked_sessions", revoked)
+	s.record(ctx, AuditActionPasswordChanged, &user.ID, principal.Role, user.ID, map[string]any{"revoked_sessions": revoked})
+	return nil
+}
+
+// revokeOtherSessions signs out every session of the user except the caller's.
+func (s *CredentialService) revokeOtherSessions(ctx context.Context, principal *domain.Principal) (int, error) {
+	tokens, err := s.refreshTokenRepo.ListActiveByUserID(ctx, principal.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("list sessions: %w", err)
+	}
+	revoked := 0
+	for _, t := range tokens {
+		if t.FamilyID == principal.SessionID {
+			continue
+		}
+		if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+			_, err := s.refreshTokenRepo.RevokeFamilyTx(ctx, tx, t.FamilyID, time.Now())
+			return err
+		}); err != nil {
+			return revoked, fmt.Errorf("revoke refresh tokens: %w", err)
+		}
+		if err := s.revoker.RevokeSession(ctx, t.FamilyID, principal.UserID); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// issue stores a new token of the purpose, invalidating the user's earlier ones, and returns it.
+func (s *CredentialService) issue(ctx context.Context, userID uuid.UUID, purpose AccountTokenPurpose, ttl time.Duration) (string, error) {
+	b := make([]byte, accountTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		if err := s.tokenRepo.InvalidateTx(ctx, tx, userID, purpose, now); err != nil {
+			return err
+		}
+		return s.tokenRepo.CreateTx(ctx, tx, &AccountToken{
+			ID:        uuid.New(),
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: s.hasher.SHA256Hex(token),
+			ExpiresAt: now.Add(ttl),
+			CreatedAt: now,
+		})
+	}); err != nil {
+		return "", fmt.Errorf("store token: %w", err)
+	}
+	if err := s.tokenRepo.DeleteExpired(ctx, now); err != nil {
+		s.logger.Warn("Failed to delete expired account tokens", "error", err)
+	}
+	return token, nil
+}
+
+// consumeTx checks that the token is unused and unexpired, and marks it and every other token of the
+// user with the same purpose as used.
+func (s *CredentialService) consumeTx(ctx context.Context, tx Tx, purpose AccountTokenPurpose, token string) (*AccountToken, error) {
+	t, err := s.tokenRepo.LockByTokenHashTx(ctx, tx, purpose, s.hasher.SHA256Hex(strings.TrimSpace(token)))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if t.UsedAt != nil || !now.Before(t.ExpiresAt) {
+		return nil, apperr.ErrInvalidAccountToken
+	}
+	if err := s.tokenRepo.InvalidateTx(ctx, tx, t.UserID, purpose, now); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *CredentialService) link(path string, token string) string {
+	return s.appBaseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+func formatTTL(d time.Duration) string {
+	if d%time.Hour == 0 {
+		if h := int(d / time.Hour); h != 1 {
+			return fmt.Sprintf("%d hours", h)
+		}
+		return "1 hour"
+	}
+	return d.String()
+}
+
+func (s *CredentialService) record(ctx context.Context, action string, actorID *uuid.UUID, role domain.Role, userID uuid.UUID, metadata map[string]any) {
+	event := &domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    actorID,
+		ActorRole:  role,
+		Action:     action,
+		TargetType: "user",
+		TargetID:   userID.String(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if metadata != nil {
+		event.Metadata = auditJSON(metadata)
+	}
+	recordAudit(ctx, s.audit, s.logger, event)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type memoryAccountTokenRepo struct {
+	tokens []*AccountToken
+}
+
+func (r *memoryAccountTokenRepo) CreateTx(ctx context.Context, tx Tx, token *AccountToken) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryAccountTokenRepo) LockByTokenHashTx(ctx context.Context, tx Tx, purpose AccountTokenPurpose, tokenHash string) (*AccountToken, error) {
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash && t.Purpose == purpose {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, apperr.ErrInvalidAccountToken
+}
+
+func (r *memoryAccountTokenRepo) InvalidateTx(ctx context.Context, tx Tx, userID uuid.UUID, purpose AccountTokenPurpose, at time.Time) error {
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryAccountTokenRepo) DeleteExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+type memoryMailer struct {
+	sent []*MailMessage
+}
+
+func (m *memoryMailer) Send(ctx context.Context, msg *MailMessage) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// mailedToken returns the token of the link in the last email sent.
+func mailedToken(t *testing.T, m *memoryMailer) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatalf("no email sent")
+	}
+	for _, field := range strings.Fields(m.sent[len(m.sent)-1].Body) {
+		if strings.HasPrefix(field, "https://bank.test/") {
+			u, err := url.Parse(field)
+			if err != nil {
+				t.Fatalf("parse link: %v", err)
+			}
+			return u.Query().Get("token")
+		}
+	}
+	t.Fatalf("no link in email %q", m.sent[len(m.sent)-1].Body)
+	return ""
+}
+
+func TestEmailVerification(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+	principal := &domain.Principal{UserID: f.user.ID, Role: f.user.Role}
+
+	if err := f.credentials.RequestEmailVerification(ctx, principal); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	superseded := mailedToken(t, f.mail)
+	if err := f.credentials.RequestEmailVerification(ctx, principal); err != nil {
+		t.Fatalf("request again: %v", err)
+	}
+	current := mailedToken(t, f.mail)
+
+	if err := f.credentials.VerifyEmail(ctx, superseded); !errors.Is(err, apperr.ErrInvalidAccountToken) {
+		t.Fatalf("superseded link err=%v want=%v", err, apperr.ErrInvalidAccountToken)
+	}
+	if err := f.credentials.VerifyEmail(ctx, current); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if f.user.EmailVerifiedAt == nil {
+		t.Fatalf("email not marked verified")
+	}
+	if err := f.credentials.VerifyEmail(ctx, current); !errors.Is(err, apperr.ErrInvalidAccountToken) {
+		t.Fatalf("reused link err=%v want=%v", err, apperr.ErrInvalidAccountToken)
+	}
+	if err := f.credentials.RequestEmailVerification(ctx, principal); !errors.Is(err, apperr.ErrEmailAlreadyVerified) {
+		t.Fatalf("verified user err=%v want=%v", err, apperr.ErrEmailAlreadyVerified)
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	session, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	if err := f.credentials.RequestPasswordReset(ctx, "nobody@test.com"); err != nil || len(f.mail.sent) != 0 {
+		t.Fatalf("unknown email err=%v sent=%d want nil and no email", err, len(f.mail.sent))
+	}
+	if err := f.credentials.RequestPasswordReset(ctx, " Alice@Test.com "); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	token := mailedToken(t, f.mail)
+
+	if err := f.credentials.ResetPassword(ctx, &domain.ResetPasswordInput{Token: token, NewPassword: "new-password"}); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(f.user.PasswordHash), []byte("new-password")); err != nil {
+		t.Fatalf("password not replaced: %v", err)
+	}
+	if f.user.EmailVerifiedAt == nil {
+		t.Fatalf("reset link did not verify the email")
+	}
+	if _, err := f.service.RefreshToken(ctx, session.RefreshToken, domain.ClientInfo{}); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("refresh after reset err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.ValidateToken(ctx, session.AccessToken); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("access token after reset err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+
+	if err := f.credentials.ResetPassword(ctx, &domain.ResetPasswordInput{Token: token, NewPassword: "another-password"}); !errors.Is(err, apperr.ErrInvalidAccountToken) {
+		t.Fatalf("reused link err=%v want=%v", err, apperr.ErrInvalidAccountToken)
+	}
+}
+
+func TestChangePasswordRevokesOtherSessions(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	current, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	other, err := f.service.generateTokenPair(ctx, f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	principal, err := f.service.ValidateToken(ctx, current.AccessToken)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		in      *domain.ChangePasswordInput
+		wantErr error
+	}{
+		{name: "wrong_current", in: &domain.ChangePasswordInput{CurrentPassword: "wrong", NewPassword: "new-password"}, wantErr: apperr.ErrIncorrectPassword},
+		{name: "unchanged", in: &domain.ChangePasswordInput{CurrentPassword: "password123", NewPassword: "password123"}, wantErr: apperr.ErrPasswordUnchanged},
+		{name: "weak", in: &domain.ChangePasswordInput{CurrentPassword: "password123", NewPassword: "short"}, wantErr: apperr.ErrWeakPassword},
+		{name: "changed", in: &domain.ChangePasswordInput{CurrentPassword: "password123", NewPassword: "new-password"}, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := f.credentials.ChangePassword(ctx, principal, tt.in); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err=%v want=%v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := f.service.ValidateToken(ctx, other.AccessToken); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("other access token err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.RefreshToken(ctx, other.RefreshToken, domain.ClientInfo{}); !errors.Is(err, apperr.ErrInvalidToken) {
+		t.Fatalf("other refresh err=%v want=%v", err, apperr.ErrInvalidToken)
+	}
+	if _, err := f.service.ValidateToken(ctx, current.AccessToken); err != nil {
+		t.Fatalf("current access token: %v", err)
+	}
+	if _, err := f.service.RefreshToken(ctx, current.RefreshToken, domain.ClientInfo{}); err != nil {
+		t.Fatalf("current refresh: %v", err)
+	}
+}
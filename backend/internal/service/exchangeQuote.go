@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+// QuoteExchange prices an exchange and stores the result so it can be executed unchanged until it expires.
+func (s *TransactionService) QuoteExchange(ctx context.Context, userID uuid.UUID, in *domain.ExchangeInput) (*domain.ExchangeQuote, error) {
+	s.logger.Info("Quoting exchange", "user_id", userID, "from_currency", in.FromCurrency, "to_currency", in.ToCurrency, "amount_cents", in.AmountCents)
+
+	if in.FromCurrency == in.ToCurrency {
+		return nil, apperr.ErrCurrenciesMustDiffer
+	}
+	if !s.currencies.IsEnabled(in.FromCurrency) || !s.currencies.IsEnabled(in.ToCurrency) {
+		return nil, apperr.ErrInvalidCurrency
+	}
+
+	leg, err := s.priceExchange(ctx, in.FromCurrency, in.ToCurrency, in.AmountCents)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.quote: %w", err)
+	}
+
+	now := time.Now()
+	quote := &domain.ExchangeQuote{
+		ID:                   uuid.New(),
+		UserID:               userID,
+		FromCurrency:         leg.From,
+		ToCurrency:           leg.To,
+		AmountCents:          leg.AmountCents,
+		ConvertedAmountCents: leg.ConvertedCents,
+		RateNum:              leg.RateNum,
+		RateDen:              leg.RateDen,
+		SpreadBps:            leg.SpreadBps,
+		RateSource:           leg.RateSource,
+		RateVersion:          leg.RateVersion,
+		ExpiresAt:            now.Add(s.quoteTTL),
+		CreatedAt:            now,
+	}
+	if err := s.quoteRepo.Create(ctx, quote); err != nil {
+		return nil, fmt.Errorf("transaction.quote: store quote: %w", err)
+	}
+
+	s.logger.Info("Exchange quoted", "quote_id", quote.ID, "user_id", userID, "converted_amount_cents", quote.ConvertedAmountCents, "expires_at", quote.ExpiresAt)
+	return quote, nil
+}
+
+// ExecuteQuote books the exchange locked in by a quote. A quote can be executed once, by its owner, before it expires.
+// It is screened like Exchange; a held execution is booked at the quoted rate when it is approved.
+func (s *TransactionService) ExecuteQuote(ctx context.Context, userID uuid.UUID, in *domain.ExecuteQuoteInput) (*domain.TransactionInfo, error) {
+	s.logger.Info("Executing exchange quote", "user_id", userID, "quote_id", in.QuoteID)
+
+	if err := validateIdempotencyKey(in.IdempotencyKey); err != nil {
+		return nil, err
+	}
+	fingerprint := requestFingerprint(domain.IdempotencyScopeQuote, in.QuoteID.String())
+
+	// A retry of a booked execution gets its result back without being screened again.
+	replayID, err := s.replayedTransaction(ctx, userID, domain.IdempotencyScopeQuote, in.IdempotencyKey, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.execute_quote: %w", err)
+	}
+	if replayID != uuid.Nil {
+		s.logger.Info("Quote execution replayed by idempotency key", "transaction_id", replayID, "user_id", userID)
+		return s.getTransactionInfo(ctx, replayID)
+	}
+
+	if s.risk != nil && in.RiskReviewID == nil {
+		if err := s.screenQuote(ctx, userID, in); err != nil {
+			return nil, fmt.Errorf("transaction.execute_quote: %w", err)
+		}
+	}
+
+	var created *domain.Transaction
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		var err error
+		replayID, err = s.reserveIdempotencyKeyTx(ctx, tx, userID, domain.IdempotencyScopeQuote, in.IdempotencyKey, fingerprint)
+		if err != nil {
+			return fmt.Errorf("transaction.execute_quote: %w", err)
+		}
+		if replayID != uuid.Nil {
+			return nil
+		}
+
+		quote, err := s.quoteRepo.LockByIDTx(ctx, tx, in.QuoteID)
+		if err != nil {
+			return fmt.Errorf("transaction.execute_quote: lock quote: %w", err)
+		}
+		if quote.UserID != userID {
+			return apperr.ErrQuoteNotFound
+		}
+		if quote.UsedAt != nil {
+			return apperr.ErrQuoteAlreadyUsed
+		}
+		now := time.Now()
+		if in.RiskReviewID == nil && !now.Before(quote.ExpiresAt) {
+			return apperr.ErrQuoteExpired
+		}
+
+		created, err = s.exchangeTx(ctx, tx, userID, quoteLeg(quote))
+		if err != nil {
+			return err
+		}
+
+		if err := s.quoteRepo.MarkUsedTx(ctx, tx, quote.ID, created.ID, now); err != nil {
+			return fmt.Errorf("transaction.execute_quote: mark quote used: %w", err)
+		}
+
+		if in.IdempotencyKey != "" {
+			if err := s.idempotencyRepo.CompleteTx(ctx, tx, userID, domain.IdempotencyScopeQuote, in.IdempotencyKey, created.ID); err != nil {
+				return fmt.Errorf("transaction.execute_quote: complete idempotency key: %w", err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if replayID != uuid.Nil {
+		s.logger.Info("Quote execution replayed by idempotency key", "transaction_id", replayID, "user_id", userID)
+		return s.getTransactionInfo(ctx, replayID)
+	}
+
+	s.logger.Info("Exchange quote executed", "quote_id", in.QuoteID, "transaction_id", created.ID, "user_id", userID)
+	resp := s.exchangeResponse(ctx, userID, created)
+	s.recordMovement(ctx, AuditActionExchange, userID, resp)
+	return resp, nil
+}
+
+// screenQuote screens the quoted exchange before the quote is locked. A held execution is queued
+// once per quote, whatever key the client retries with; only a live quote can be queued.
+func (s *TransactionService) screenQuote(ctx context.Context, userID uuid.UUID, in *domain.ExecuteQuoteInput) error {
+	quote, err := s.quoteRepo.GetByID(ctx, in.QuoteID)
+	if err != nil {
+		return fmt.Errorf("get quote: %w", err)
+	}
+	if quote.UserID != userID {
+		return apperr.ErrQuoteNotFound
+	}
+	if quote.UsedAt != nil {
+		return apperr.ErrQuoteAlreadyUsed
+	}
+
+	reviewKey := "quote:" + quote.ID.String()
+	if !time.Now().Before(quote.ExpiresAt) {
+		// A retry of an execution held before the quote expired still answers with the review.
+		existing, err := s.reviews.GetByIdempotencyKey(ctx, userID, domain.TransactionTypeExchange, reviewKey)
+		if err == nil {
+			return reviewOutcome(existing)
+		}
+		if !errors.Is(err, apperr.ErrRiskReviewNotFound) {
+			return fmt.Errorf("get risk review: %w", err)
+		}
+		return apperr.ErrQuoteExpired
+	}
+
+	quoteID := quote.ID
+	return s.screen(ctx, &domain.RiskInput{
+		Type:        domain.TransactionTypeExchange,
+		UserID:      userID,
+		Currency:    quote.FromCurrency,
+		ToCurrency:  quote.ToCurrency,
+		AmountCents: quote.AmountCents,
+	}, &domain.RiskReview{
+		Exchange: &domain.ExchangeInput{
+			FromCurrency:   quote.FromCurrency,
+			ToCurrency:     quote.ToCurrency,
+			AmountCents:    quote.AmountCents,
+			IdempotencyKey: in.IdempotencyKey,
+			QuoteID:        &quoteID,
+		},
+		IdempotencyKey: reviewKey,
+	})
+}
+
+func quoteLeg(q *domain.ExchangeQuote) *exchangeLeg {
+	quoteID := q.ID
+	return &exchangeLeg{
+		From:           q.FromCurrency,
+		To:             q.ToCurrency,
+		AmountCents:    q.AmountCents,
+		ConvertedCents: q.ConvertedAmountCents,
+		Rate:           float64(q.RateNum) / float64(q.RateDen),
+		RateNum:        q.RateNum,
+		RateDen:        q.RateDen,
+		SpreadBps:      q.SpreadBps,
+		RateSource:     q.RateSource,
+		RateVersion:    q.RateVersion,
+		QuoteID:        &quoteID,
+	}
+}
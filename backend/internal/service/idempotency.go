@@ -0,0 +1,34 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"banking-platform/internal/apperr"
+	"github.com/google/uuid"
+)
+
+const maxIdempotencyKeyLength = 255
+
+func validateIdempotencyKey(key string) error {
+	if len(key) > maxIdempotencyKeyLength {
+		return apperr.BadRequest("Idempotency-Key must be at most 255 characters")
+	}
+	return nil
+}
+
+// requestFingerprint hashes the fields that identify a money-moving request, so a key
+// replayed with a different body can be told apart from a genuine retry.
+func requestFingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// optionalID renders an optional id as a fingerprint part.
+func optionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
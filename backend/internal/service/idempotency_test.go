@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestRequestFingerprint(t *testing.T) {
+	base := requestFingerprint("transfer", "22222222-2222-2222-2222-222222222222", "USD", "1025")
+
+	testCases := []struct {
+		name     string
+		parts    []string
+		wantSame bool
+	}{
+		{name: "same_request", parts: []string{"transfer", "22222222-2222-2222-2222-222222222222", "USD", "1025"}, wantSame: true},
+		{name: "different_amount", parts: []string{"transfer", "22222222-2222-2222-2222-222222222222", "USD", "1026"}, wantSame: false},
+		{name: "different_currency", parts: []string{"transfer", "22222222-2222-2222-2222-222222222222", "EUR", "1025"}, wantSame: false},
+		{name: "different_scope", parts: []string{"exchange", "22222222-2222-2222-2222-222222222222", "USD", "1025"}, wantSame: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := requestFingerprint(tc.parts...)
+			if len(got) != 64 {
+				t.Fatalf("len=%d want=64", len(got))
+			}
+			if (got == base) != tc.wantSame {
+				t.Fatalf("same=%v want=%v", got == base, tc.wantSame)
+			}
+		})
+	}
+}
+
+func TestValidateIdempotencyKey(t *testing.T) {
+	testCases := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "empty_is_allowed", key: ""},
+		{name: "uuid", key: "3f1c7a52-1f7e-4a55-9d0b-6b0b7a1f9c11"},
+		{name: "max_length", key: strings.Repeat("k", maxIdempotencyKeyLength)},
+		{name: "too_long", key: strings.Repeat("k", maxIdempotencyKeyLength+1), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateIdempotencyKey(tc.key)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+type memoryIdempotencyRepo struct {
+	keys map[string]*domain.IdempotencyKey
+}
+
+func (r *memoryIdempotencyRepo) ReserveTx(ctx context.Context, tx Tx, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	id := key.UserID.String() + "|" + key.Scope + "|" + key.Key
+	if existing, ok := r.keys[id]; ok {
+		return existing, nil
+	}
+	r.keys[id] = key
+	return nil, nil
+}
+
+func (r *memoryIdempotencyRepo) CompleteTx(ctx context.Context, tx Tx, userID uuid.UUID, scope string, key string, transactionID uuid.UUID) error {
+	if existing, ok := r.keys[userID.String()+"|"+scope+"|"+key]; ok {
+		existing.TransactionID = &transactionID
+	}
+	return nil
+}
+
+func (r *memoryIdempotencyRepo) Get(ctx context.Context, userID uuid.UUID, scope string, key string) (*domain.IdempotencyKey, error) {
+	return r.keys[userID.String()+"|"+scope+"|"+key], nil
+}
+
+// replayTransactionRepo serves the transaction a replay returns; nothing else is expected to be called.
+type replayTransactionRepo struct {
+	TransactionRepo
+	booked *domain.Transaction
+}
+
+func (r *replayTransactionRepo) GetWithEmailsByID(ctx context.Context, id uuid.UUID) (*domain.TransactionWithEmails, error) {
+	if r.booked == nil || r.booked.ID != id {
+		return nil, apperr.ErrTransactionNotFound
+	}
+	return &domain.TransactionWithEmails{Transaction: *r.booked}, nil
+}
+
+type refusingStepUp struct{}
+
+func (refusingStepUp) VerifyStepUp(ctx context.Context, userID uuid.UUID, code string) error {
+	return apperr.ErrMFACodeRequired
+}
+
+func TestTransferReplayBypassesGates(t *testing.T) {
+	ctx := context.Background()
+	userID, toUserID := uuid.New(), uuid.New()
+	booked := &domain.Transaction{ID: uuid.New(), Type: domain.TransactionTypeTransfer, AmountCents: 5_000_00, Currency: domain.CurrencyUSD}
+	in := &domain.TransferInput{ToUserID: &toUserID, Currency: domain.CurrencyUSD, AmountCents: 5_000_00, IdempotencyKey: "k"}
+	fingerprint := requestFingerprint(domain.IdempotencyScopeTransfer, toUserID.String(), "USD", "500000", "", "")
+
+	keys := &memoryIdempotencyRepo{keys: map[string]*domain.IdempotencyKey{}}
+	s := &TransactionService{
+		idempotencyRepo: keys,
+		transactionRepo: &replayTransactionRepo{booked: booked},
+		currencies:      domain.NewCurrencyRegistry([]domain.CurrencyInfo{{Code: domain.CurrencyUSD, MinorUnits: 2, Enabled: true}}),
+		stepUp:          refusingStepUp{},
+		stepUpThreshold: 1_000_00,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	// Without a booked result the gates run.
+	if _, err := s.Transfer(ctx, userID, in); !errors.Is(err, apperr.ErrMFACodeRequired) {
+		t.Fatalf("new request err=%v", err)
+	}
+
+	keys.keys[userID.String()+"|"+domain.IdempotencyScopeTransfer+"|k"] = &domain.IdempotencyKey{RequestHash: fingerprint, TransactionID: &booked.ID}
+	info, err := s.Transfer(ctx, userID, in)
+	if err != nil {
+		t.Fatalf("replay err=%v", err)
+	}
+	if info.ID != booked.ID {
+		t.Fatalf("replayed %s want %s", info.ID, booked.ID)
+	}
+
+	// The same key with a different body is not a replay and is gated like a new request.
+	other := *in
+	other.AmountCents = 6_000_00
+	if _, err := s.Transfer(ctx, userID, &other); !errors.Is(err, apperr.ErrMFACodeRequired) {
+		t.Fatalf("different request err=%v", err)
+	}
+}
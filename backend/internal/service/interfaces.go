@@ -26,6 +26,10 @@ type UserRepo interface {
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
 	GetAll(ctx context.Context) ([]*domain.User, error)
+	UpdatePasswordTx(ctx context.Context, tx Tx, id uuid.UUID, passwordHash string, at time.Time) error
+	MarkEmailVerifiedTx(ctx context.Context, tx Tx, id uuid.UUID, at time.Time) error
+	// ReplacePasswordHash swaps oldHash for newHash, doing nothing if the password changed meanwhile.
+	ReplacePasswordHash(ctx context.Context, id uuid.UUID, oldHash string, newHash string) error
 }
 
 type AccountRepo interface {
@@ -34,38 +38,388 @@ type AccountRepo interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Account, error)
 	GetByUserIDAndCurrency(ctx context.Context, userID uuid.UUID, currency domain.Currency) (*domain.Account, error)
 
+	// FindAccountIDTx returns the default account for (user, currency).
 	FindAccountIDTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency) (uuid.UUID, error)
+	FindSuccessorTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency) (uuid.UUID, error)
 	UpdateBalanceString(ctx context.Context, tx Tx, accountID uuid.UUID, newBalance string) error
+	UpdateStatusTx(ctx context.Context, tx Tx, accountID uuid.UUID, status domain.AccountStatus, at time.Time) error
+	SetDefaultTx(ctx context.Context, tx Tx, accountID uuid.UUID, isDefault bool) error
 	LockAccountForUpdate(ctx context.Context, tx Tx, accountID uuid.UUID) (*domain.Account, error)
 }
 
 type TransactionRepo interface {
 	Create(ctx context.Context, tx Tx, transaction *domain.Transaction) error
-	GetByUserID(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter) ([]*domain.TransactionWithEmails, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter, after *domain.TransactionCursor, limit int) ([]*domain.TransactionWithEmails, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter) (int64, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error)
+	LockByIDTx(ctx context.Context, tx Tx, id uuid.UUID) (*domain.Transaction, error)
+	SumReversalsTx(ctx context.Context, tx Tx, id uuid.UUID, currency domain.Currency) (int64, error)
+	GetWithEmailsByID(ctx context.Context, id uuid.UUID) (*domain.TransactionWithEmails, error)
+	SettleTx(ctx context.Context, tx Tx, transaction *domain.Transaction) error
+	VoidExpired(ctx context.Context, now time.Time) (int64, error)
 }
 
 type LedgerRepo interface {
 	CreateEntry(ctx context.Context, tx Tx, entry *domain.LedgerEntry) error
 	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.LedgerEntry, error)
+	// GetPostingsByTransactionID loads entries with account owner and ledger balance after each entry.
+	GetPostingsByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.TransactionPosting, error)
 	VerifyTransactionBalanceTx(ctx context.Context, tx Tx, transactionID uuid.UUID) error
+	// SumBefore returns the account balance from ledger entries booked before the given time.
+	SumBefore(ctx context.Context, accountID uuid.UUID, currency domain.Currency, before time.Time) (int64, error)
+	// ListAccountEntries returns ledger entries booked in [from, to) with running balances.
+	ListAccountEntries(ctx context.Context, accountID uuid.UUID, currency domain.Currency, from time.Time, to time.Time) ([]*domain.StatementLine, error)
 
 	FindUnbalancedTransactionIDs(ctx context.Context, limit int) ([]uuid.UUID, error)
 	FindAccountBalanceMismatches(ctx context.Context, limit int) ([]*domain.AccountBalanceMismatch, error)
 }
 
+type CurrencyRepo interface {
+	List(ctx context.Context) ([]domain.CurrencyInfo, error)
+}
+
+type ExchangeRateRepo interface {
+	// GetActive returns the latest base->quote rate whose validity window contains at.
+	GetActive(ctx context.Context, base domain.Currency, quote domain.Currency, at time.Time) (*domain.ExchangeRate, error)
+}
+
+type ExchangeQuoteRepo interface {
+	Create(ctx context.Context, quote *domain.ExchangeQuote) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ExchangeQuote, error)
+	LockByIDTx(ctx context.Context, tx Tx, id uuid.UUID) (*domain.ExchangeQuote, error)
+	MarkUsedTx(ctx context.Context, tx Tx, id uuid.UUID, transactionID uuid.UUID, usedAt time.Time) error
+}
+
+type ScheduledTransferRepo interface {
+	Create(ctx context.Context, st *domain.ScheduledTransfer) error
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.ScheduledTransfer, error)
+	LockByIDTx(ctx context.Context, tx Tx, id uuid.UUID) (*domain.ScheduledTransfer, error)
+	// ClaimDueTx leases due transfers using FOR UPDATE SKIP LOCKED so concurrent workers never share a row.
+	ClaimDueTx(ctx context.Context, tx Tx, now time.Time, leaseUntil time.Time, limit int) ([]*domain.ScheduledTransfer, error)
+	UpdateStateTx(ctx context.Context, tx Tx, st *domain.ScheduledTransfer) error
+	CreateRunTx(ctx context.Context, tx Tx, run *domain.ScheduledTransferRun) error
+}
+
+type PaymentRequestRepo interface {
+	Create(ctx context.Context, pr *domain.PaymentRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.PaymentRequest, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, filter *domain.PaymentRequestFilter, now time.Time, limit int) ([]*domain.PaymentRequest, error)
+	// GetByReviewID returns the request whose transfer is held in the review.
+	GetByReviewID(ctx context.Context, reviewID uuid.UUID) (*domain.PaymentRequest, error)
+	// Transition changes the status only if it is one of from, so concurrent decisions cannot both win.
+	// Moving a request back to pending clears its review.
+	Transition(ctx context.Context, id uuid.UUID, from []domain.PaymentRequestStatus, status domain.PaymentRequestStatus, transactionID *uuid.UUID, at time.Time) error
+	// HoldForReview records the review a processing request's transfer is held in.
+	HoldForReview(ctx context.Context, id uuid.UUID, reviewID uuid.UUID, at time.Time) error
+	// ClaimForAccept moves a pending request to processing. A processing request that is not held
+	// for review is claimed again, so an accept interrupted after claiming it can be retried.
+	ClaimForAccept(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type IdempotencyRepo interface {
+	// ReserveTx claims the key inside tx; if the key already exists the stored record is returned.
+	ReserveTx(ctx context.Context, tx Tx, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error)
+	CompleteTx(ctx context.Context, tx Tx, userID uuid.UUID, scope string, key string, transactionID uuid.UUID) error
+	// Get returns the committed record for the key, or nil if there is none.
+	Get(ctx context.Context, userID uuid.UUID, scope string, key string) (*domain.IdempotencyKey, error)
+}
+
+// AuditRecorder persists audit events.
+type AuditRecorder interface {
+	Record(ctx context.Context, event *domain.AuditEvent) error
+	RecordTx(ctx context.Context, tx Tx, event *domain.AuditEvent) error
+}
+
+type AuditEventRepo interface {
+	// AppendTx seals the event onto the chain tail; appends are serialized until tx ends.
+	AppendTx(ctx context.Context, tx Tx, event *domain.AuditEvent) error
+	ListAfter(ctx context.Context, afterSeq int64, limit int) ([]*domain.AuditEvent, error)
+}
+
+// RefreshToken is one link of a token family. A login starts a family; each refresh marks the
+// presented token rotated and issues a child with the same FamilyID. A family is a session.
 type RefreshToken struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	TokenHash string
-	ExpiresAt time.Time
-	CreatedAt time.Time
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	FamilyID         uuid.UUID
+	ParentID         *uuid.UUID
+	TokenHash        string
+	ExpiresAt        time.Time
+	CreatedAt        time.Time
+	RotatedAt        *time.Time
+	RevokedAt        *time.Time
+	UserAgent        string
+	IP               string
+	DeviceLabel      string
+	SessionCreatedAt time.Time
 }
 
 type RefreshTokenRepo interface {
 	Create(ctx context.Context, token *RefreshToken) error
+	CreateTx(ctx context.Context, tx Tx, token *RefreshToken) error
 	GetByTokenHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// LockByTokenHashTx returns a non-expired token, rotated or revoked ones included, locked FOR UPDATE.
+	LockByTokenHashTx(ctx context.Context, tx Tx, tokenHash string) (*RefreshToken, error)
+	MarkRotatedTx(ctx context.Context, tx Tx, id uuid.UUID, at time.Time) error
+	// RevokeFamilyTx revokes every live token of the family and returns how many were revoked.
+	RevokeFamilyTx(ctx context.Context, tx Tx, familyID uuid.UUID, at time.Time) (int64, error)
+	// ListActiveByUserID returns the live token of every session: not rotated, revoked or expired.
+	ListActiveByUserID(ctx context.Context, userID uuid.UUID) ([]*RefreshToken, error)
 	Delete(ctx context.Context, tokenHash string) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
 
+// RevokedAccessToken is an access token revoked before expiry, identified by its jti claim.
+type RevokedAccessToken struct {
+	JTI       string
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+// RevokedSession rejects every access token carrying the session ID until ExpiresAt, by which time
+// all of them have expired.
+type RevokedSession struct {
+	SessionID uuid.UUID
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+// AccessTokenCutoff invalidates every access token of the user issued at or before RevokedBefore.
+type AccessTokenCutoff struct {
+	UserID        uuid.UUID
+	RevokedBefore time.Time
+	UpdatedAt     time.Time
+}
+
+// TokenRevoker revokes access tokens before they expire. TokenRevocationStore implements it.
+type TokenRevoker interface {
+	RevokeToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) error
+}
+
+type TokenRevocationRepo interface {
+	RevokeToken(ctx context.Context, token *RevokedAccessToken) error
+	// SetCutoff moves the user's cutoff forward; an earlier cutoff never replaces a later one.
+	SetCutoff(ctx context.Context, cutoff *AccessTokenCutoff) error
+	// ListRevokedSince returns unexpired revocations recorded at or after since.
+	ListRevokedSince(ctx context.Context, since time.Time) ([]*RevokedAccessToken, error)
+	ListCutoffsSince(ctx context.Context, since time.Time) ([]*AccessTokenCutoff, error)
+	RevokeSession(ctx context.Context, session *RevokedSession) error
+	ListRevokedSessionsSince(ctx context.Context, since time.Time) ([]*RevokedSession, error)
+	// DeleteExpired removes revocations of expired tokens and sessions, and cutoffs older than cutoffsBefore.
+	DeleteExpired(ctx context.Context, now time.Time, cutoffsBefore time.Time) error
+}
+
+// UserMFA is a user's TOTP second factor. EnabledAt is nil until enrollment is confirmed.
+type UserMFA struct {
+	UserID       uuid.UUID
+	Secret       string
+	EnabledAt    *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type MFARepo interface {
+	// Get returns ErrMFANotEnabled when the user never started enrollment.
+	Get(ctx context.Context, userID uuid.UUID) (*UserMFA, error)
+	LockTx(ctx context.Context, tx Tx, userID uuid.UUID) (*UserMFA, error)
+	// SavePending stores an unconfirmed secret, replacing an earlier unconfirmed one. It returns
+	// ErrMFAAlreadyEnabled when the user's MFA is enabled.
+	SavePending(ctx context.Context, mfa *UserMFA) error
+	EnableTx(ctx context.Context, tx Tx, userID uuid.UUID, at time.Time, step int64) error
+	SetLastUsedStepTx(ctx context.Context, tx Tx, userID uuid.UUID, step int64) error
+	// DeleteTx removes the secret and all recovery codes.
+	DeleteTx(ctx context.Context, tx Tx, userID uuid.UUID) error
+	ReplaceRecoveryCodesTx(ctx context.Context, tx Tx, userID uuid.UUID, codeHashes []string, at time.Time) error
+	// UseRecoveryCodeTx marks an unused code as used and reports whether one matched.
+	UseRecoveryCodeTx(ctx context.Context, tx Tx, userID uuid.UUID, codeHash string, at time.Time) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// MFAChallengeToken is the server side of an MFA challenge; the token itself is only stored hashed.
+type MFAChallengeToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	TokenHash  string
+	Attempts   int
+	ExpiresAt  time.Time
+	ConsumedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type MFAChallengeRepo interface {
+	Create(ctx context.Context, challenge *MFAChallengeToken) error
+	// LockByTokenHashTx returns ErrInvalidMFAChallenge when no challenge has the hash.
+	LockByTokenHashTx(ctx context.Context, tx Tx, tokenHash string) (*MFAChallengeToken, error)
+	RecordAttemptTx(ctx context.Context, tx Tx, id uuid.UUID) error
+	ConsumeTx(ctx context.Context, tx Tx, id uuid.UUID, at time.Time) error
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// MFAGate is the second step of login. MFAService implements it.
+type MFAGate interface {
+	Enabled(ctx context.Context, userID uuid.UUID) (bool, error)
+	StartChallenge(ctx context.Context, userID uuid.UUID) (*domain.MFAChallenge, error)
+	// CompleteChallenge checks the code against the challenge's user, consumes the challenge and
+	// returns the user ID. A wrong code returns ErrInvalidMFACode together with the user ID.
+	CompleteChallenge(ctx context.Context, in *domain.LoginMFAInput) (uuid.UUID, error)
+}
+
+// StepUpVerifier checks the fresh TOTP code required for large transfers. MFAService implements it.
+type StepUpVerifier interface {
+	VerifyStepUp(ctx context.Context, userID uuid.UUID, code string) error
+}
+
+// AccountTokenPurpose says what a link sent by email is for.
+type AccountTokenPurpose string
+
+const (
+	AccountTokenEmailVerification AccountTokenPurpose = "email_verification"
+	AccountTokenPasswordReset     AccountTokenPurpose = "password_reset"
+)
+
+// AccountToken is a single-use link sent by email; the token itself is only stored hashed.
+type AccountToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Purpose   AccountTokenPurpose
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type AccountTokenRepo interface {
+	CreateTx(ctx context.Context, tx Tx, token *AccountToken) error
+	// LockByTokenHashTx returns ErrInvalidAccountToken when no token of the purpose has the hash.
+	LockByTokenHashTx(ctx context.Context, tx Tx, purpose AccountTokenPurpose, tokenHash string) (*AccountToken, error)
+	// InvalidateTx marks every unused token of the user and purpose as used.
+	InvalidateTx(ctx context.Context, tx Tx, userID uuid.UUID, purpose AccountTokenPurpose, at time.Time) error
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// MailMessage is a plain-text email.
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email. LogMailer and FileMailer are stand-ins until a provider is wired in.
+type Mailer interface {
+	Send(ctx context.Context, msg *MailMessage) error
+}
+
+// VerificationSender emails a new user the link that verifies their address. CredentialService
+// implements it.
+type VerificationSender interface {
+	SendVerification(ctx context.Context, user *domain.User) error
+}
+
+// LoginThrottleScope says what a login throttle counts failures for.
+type LoginThrottleScope string
+
+const (
+	LoginThrottleEmail LoginThrottleScope = "email"
+	LoginThrottleIP    LoginThrottleScope = "ip"
+)
+
+// LoginThrottleState counts the failed logins of one email address or client IP.
+type LoginThrottleState struct {
+	Scope         LoginThrottleScope
+	Key           string
+	Failures      int
+	FirstFailedAt time.Time
+	LastFailedAt  time.Time
+	LockedUntil   *time.Time
+}
+
+type LoginThrottleRepo interface {
+	// Get returns a state with no failures when the key has none recorded.
+	Get(ctx context.Context, scope LoginThrottleScope, key string) (*LoginThrottleState, error)
+	// LockTx creates the row if needed and returns it locked FOR UPDATE.
+	LockTx(ctx context.Context, tx Tx, scope LoginThrottleScope, key string) (*LoginThrottleState, error)
+	SaveTx(ctx context.Context, tx Tx, state *LoginThrottleState) error
+	Delete(ctx context.Context, scope LoginThrottleScope, key string) error
+	// ListEmails returns the email states with a failure at or after since, newest first, with the
+	// ID of the user owning the address.
+	ListEmails(ctx context.Context, since time.Time, limit int) ([]*domain.LoginLockout, error)
+	// DeleteStale removes states with no failure and no lock after before.
+	DeleteStale(ctx context.Context, before time.Time) error
+}
+
+// LoginGuard slows down and locks out password guessing. LoginThrottle implements it.
+type LoginGuard interface {
+	// Check returns a *apperr.ThrottledError while the email or the IP has to wait.
+	Check(ctx context.Context, email string, ip string) error
+	// RecordFailure counts a failed attempt and reports whether it locked the email.
+	RecordFailure(ctx context.Context, email string, ip string) (bool, error)
+	RecordSuccess(ctx context.Context, email string) error
+}
+
+// RateLimitStore keeps one theoretical arrival time (TAT) per key for the generic cell rate algorithm.
+// Take has to be atomic per key, so that replicas sharing the store share the limit.
+type RateLimitStore interface {
+	// Take admits a request if max(tat, now) + interval - tolerance <= now and then stores
+	// max(tat, now) + interval. It returns the key's TAT after the call and whether it was admitted.
+	Take(ctx context.Context, key string, now time.Time, interval time.Duration, tolerance time.Duration) (time.Time, bool, error)
+	// DeleteExpired removes keys whose TAT is before before; they are as good as new.
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+// TransferLimitRepo reads limits and ledger usage for TransferLimiter. Amounts are minor units.
+type TransferLimitRepo interface {
+	// LockUsageTx serializes limit checks of one user, in every currency, until tx ends.
+	LockUsageTx(ctx context.Context, tx Tx, userID uuid.UUID) error
+	// GetEffectiveTx returns the user's overrides laid over the user's tier.
+	GetEffectiveTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency) (*domain.TransferLimits, error)
+	// GetUsageTx counts transfers the user sent to other users: amounts in currency since dayStart
+	// and since monthStart, and the number sent in any currency since hourStart.
+	GetUsageTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency, dayStart time.Time, monthStart time.Time, hourStart time.Time) (*domain.TransferUsage, error)
+
+	GetTier(ctx context.Context, userID uuid.UUID) (string, error)
+	ListOverrides(ctx context.Context, userID uuid.UUID) ([]*domain.TransferLimits, error)
+	// ListEffective returns the effective limits in every enabled currency.
+	ListEffective(ctx context.Context, userID uuid.UUID) ([]*domain.TransferLimits, error)
+	TierExistsTx(ctx context.Context, tx Tx, tier string) (bool, error)
+	SetTierTx(ctx context.Context, tx Tx, userID uuid.UUID, tier string) error
+	ReplaceOverridesTx(ctx context.Context, tx Tx, userID uuid.UUID, overrides []*domain.TransferLimits) error
+}
+
+// TransferLimitChecker enforces outgoing transfer limits. TransferLimiter implements it.
+type TransferLimitChecker interface {
+	// CheckTx returns a *apperr.TransferLimitError if sending amountCents would break a limit. It
+	// runs in the transfer's transaction so that the usage it reads cannot change until commit.
+	CheckTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency, amountCents int64) error
+}
+
+// RiskEngine screens transfers and exchanges before any account is locked. RuleRiskEngine implements it.
+type RiskEngine interface {
+	Assess(ctx context.Context, in *domain.RiskInput) (*domain.RiskDecision, error)
+}
+
+// RiskSignalRepo answers the questions risk rules ask about a user's history.
+type RiskSignalRepo interface {
+	// HasSentTo reports whether the user ever transferred money to toUserID.
+	HasSentTo(ctx context.Context, userID uuid.UUID, toUserID uuid.UUID) (bool, error)
+	// RecipientsSince returns the distinct other users the user transferred to at or after since.
+	RecipientsSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]uuid.UUID, error)
+}
+
+type RiskReviewRepo interface {
+	// Create inserts a pending review. If the user already has a review of the same type under the
+	// same non-empty idempotency key, that review is returned instead.
+	Create(ctx context.Context, review *domain.RiskReview) (*domain.RiskReview, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.RiskReview, error)
+	GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, typ domain.TransactionType, key string) (*domain.RiskReview, error)
+	// List returns reviews with the status, or all reviews for an empty status, oldest first.
+	List(ctx context.Context, status domain.RiskReviewStatus, limit int) ([]*domain.RiskReview, error)
+	// Transition moves a review in one of the from statuses to status and overwrites its reviewer,
+	// note, time and transaction. It returns apperr.ErrRiskReviewNotPending if the review is in
+	// none of the from statuses.
+	Transition(ctx context.Context, id uuid.UUID, from []domain.RiskReviewStatus, status domain.RiskReviewStatus, reviewerID *uuid.UUID, note string, transactionID *uuid.UUID, at *time.Time) error
+}
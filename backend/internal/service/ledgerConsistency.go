@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
+
+	"banking-platform/internal/domain"
 )
 
 type LedgerConsistencyService struct {
@@ -43,3 +46,20 @@ func (s *LedgerConsistencyService) CheckAccountBalanceConsistency(ctx context.Co
 	s.logger.Error("Account balance consistency check FAILED: mismatches found", "count", len(mismatches), "mismatches", mismatches)
 	return fmt.Errorf("account balance mismatches found: %d", len(mismatches))
 }
+
+// Report runs both checks and returns their findings instead of only logging them.
+func (s *LedgerConsistencyService) Report(ctx context.Context, limit int) (*domain.ConsistencyReport, error) {
+	ids, err := s.ledgerRepo.FindUnbalancedTransactionIDs(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ledger consistency check failed: %w", err)
+	}
+	mismatches, err := s.ledgerRepo.FindAccountBalanceMismatches(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("account balance consistency check failed: %w", err)
+	}
+	return &domain.ConsistencyReport{
+		UnbalancedTransactionIDs: ids,
+		BalanceMismatches:        mismatches,
+		CheckedAt:                time.Now().UTC(),
+	}, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+)
+
+// adminLockoutListLimit caps the email states returned to operators.
+const adminLockoutListLimit = 200
+
+// LoginThrottlePolicy configures LoginThrottle.
+type LoginThrottlePolicy struct {
+	// FreeAttempts failures of an email are allowed back to back; after that each attempt has to
+	// wait BaseDelay, doubled per further failure up to MaxDelay.
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	// LockoutThreshold failures lock the email for LockoutDuration. A failure after the lock ends
+	// locks it again until the failures are forgotten.
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	// Window is how long failures are remembered: after Window without a failure for an email, or
+	// Window after the first failure of an IP.
+	Window time.Duration
+	// IPMaxFailures failures from one IP, across any emails, block it for the rest of its window.
+	IPMaxFailures int
+}
+
+// LoginThrottle keeps failed-login counters in Postgres, so every API instance sees the same state.
+// Unknown emails are counted like registered ones, so throttling does not reveal which exist.
+type LoginThrottle struct {
+	repo     LoginThrottleRepo
+	txRunner TxRunner
+	policy   LoginThrottlePolicy
+	logger   *slog.Logger
+}
+
+func NewLoginThrottle(repo LoginThrottleRepo, txRunner TxRunner, policy LoginThrottlePolicy, logger *slog.Logger) *LoginThrottle {
+	return &LoginThrottle{repo: repo, txRunner: txRunner, policy: policy, logger: logger}
+}
+
+// Check refuses the attempt while the email is locked or in its delay, or the IP is blocked.
+func (t *LoginThrottle) Check(ctx context.Context, email string, ip string) error {
+	now := time.Now()
+	var wait time.Duration
+	for _, k := range t.keys(email, ip) {
+		state, err := t.repo.Get(ctx, k.scope, k.key)
+		if err != nil {
+			return fmt.Errorf("login_throttle.check: %w", err)
+		}
+		wait = max(wait, t.policy.wait(state, now))
+	}
+	if wait > 0 {
+		return &apperr.ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt against the email and the IP.
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email string, ip string) (bool, error) {
+	now := time.Now()
+	var locked bool
+	if err := t.txRunner.WithTx(ctx, func(tx Tx) error {
+		// Always email before IP, so concurrent failures lock rows in the same order.
+		for _, k := range t.keys(email, ip) {
+			state, err := t.repo.LockTx(ctx, tx, k.scope, k.key)
+			if err != nil {
+				return err
+			}
+			if t.policy.fail(state, now) {
+				locked = true
+			}
+			if err := t.repo.SaveTx(ctx, tx, state); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return false, fmt.Errorf("login_throttle.record_failure: %w", err)
+	}
+
+	if err := t.repo.DeleteStale(ctx, now.Add(-t.policy.Window)); err != nil {
+		t.logger.Warn("Failed to delete stale login throttles", "error", err)
+	}
+	return locked, nil
+}
+
+// RecordSuccess forgets the email's failures. The IP's are kept, so one working account cannot be
+// used to reset the block of a client guessing at others.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, email string) error {
+	if err := t.repo.Delete(ctx, LoginThrottleEmail, normalizeEmail(email)); err != nil {
+		return fmt.Errorf("login_throttle.record_success: %w", err)
+	}
+	return nil
+}
+
+// ListLockouts returns the emails with failures that are still remembered.
+func (t *LoginThrottle) ListLockouts(ctx context.Context) ([]*domain.LoginLockout, error) {
+	now := time.Now()
+	lockouts, err := t.repo.ListEmails(ctx, now.Add(-t.policy.Window), adminLockoutListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("login_throttle.list_lockouts: %w", err)
+	}
+	for _, l := range lockouts {
+		if l.LockedUntil != nil && !now.Before(*l.LockedUntil) {
+			l.LockedUntil = nil
+		}
+	}
+	return lockouts, nil
+}
+
+// Unlock forgets the failures of the email, lifting its lock and delay.
+func (t *LoginThrottle) Unlock(ctx context.Context, email string) error {
+	if err := t.repo.Delete(ctx, LoginThrottleEmail, normalizeEmail(email)); err != nil {
+		return fmt.Errorf("login_throttle.unlock: %w", err)
+	}
+	return nil
+}
+
+type loginThrottleKey struct {
+	scope LoginThrottleScope
+	key   string
+}
+
+func (t *LoginThrottle) keys(email string, ip string) []loginThrottleKey {
+	keys := []loginThrottleKey{{scope: LoginThrottleEmail, key: normalizeEmail(email)}}
+	if ip != "" {
+		keys = append(keys, loginThrottleKey{scope: LoginThrottleIP, key: ip})
+	}
+	return keys
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// expired reports whether the state's failures are forgotten.
+func (p LoginThrottlePolicy) expired(s *LoginThrottleState, now time.Time) bool {
+	if s.Failures == 0 {
+		return true
+	}
+	if s.Scope == LoginThrottleIP {
+		return now.Sub(s.FirstFailedAt) > p.Window
+	}
+	return now.Sub(s.LastFailedAt) > p.Window
+}
+
+// wait returns how long the key has to wait before its next attempt.
+func (p LoginThrottlePolicy) wait(s *LoginThrottleState, now time.Time) time.Duration {
+	if s.LockedUntil != nil && now.Before(*s.LockedUntil) {
+		return s.LockedUntil.Sub(now)
+	}
+	if s.Scope != LoginThrottleEmail || p.expired(s, now) {
+		return 0
+	}
+	extra := s.Failures - p.FreeAttempts
+	if extra <= 0 {
+		return 0
+	}
+	return max(s.LastFailedAt.Add(p.delay(extra)).Sub(now), 0)
+}
+
+// delay is BaseDelay doubled for every failure past the first delayed one, capped at MaxDelay.
+func (p LoginThrottlePolicy) delay(extra int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < extra && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxDelay)
+}
+
+// fail adds a failure to the state and reports whether it locked an email that was not locked.
+func (p LoginThrottlePolicy) fail(s *LoginThrottleState, now time.Time) bool {
+	wasLocked := s.LockedUntil != nil && now.Before(*s.LockedUntil)
+	if p.expired(s, now) {
+		s.Failures = 0
+		s.FirstFailedAt = now
+		s.LockedUntil = nil
+	}
+	s.Failures++
+	s.LastFailedAt = now
+
+	switch s.Scope {
+	case LoginThrottleEmail:
+		if s.Failures >= p.LockoutThreshold {
+			until := now.Add(p.LockoutDuration)
+			s.LockedUntil = &until
+			return !wasLocked
+		}
+	case LoginThrottleIP:
+		if s.Failures >= p.IPMaxFailures {
+			until := s.FirstFailedAt.Add(p.Window)
+			s.LockedUntil = &until
+		}
+	}
+	return false
+}
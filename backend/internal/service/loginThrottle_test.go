@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+)
+
+var testLoginThrottlePolicy = LoginThrottlePolicy{
+	FreeAttempts:     3,
+	BaseDelay:        time.Second,
+	MaxDelay:         time.Minute,
+	LockoutThreshold: 10,
+	LockoutDuration:  15 * time.Minute,
+	Window:           time.Hour,
+	IPMaxFailures:    100,
+}
+
+type memoryLoginThrottleRepo struct {
+	states map[loginThrottleKey]*LoginThrottleState
+}
+
+func (r *memoryLoginThrottleRepo) Get(ctx context.Context, scope LoginThrottleScope, key string) (*LoginThrottleState, error) {
+	if s, ok := r.states[loginThrottleKey{scope: scope, key: key}]; ok {
+		copied := *s
+		return &copied, nil
+	}
+	return &LoginThrottleState{Scope: scope, Key: key}, nil
+}
+
+func (r *memoryLoginThrottleRepo) LockTx(ctx context.Context, tx Tx, scope LoginThrottleScope, key string) (*LoginThrottleState, error) {
+	return r.Get(ctx, scope, key)
+}
+
+func (r *memoryLoginThrottleRepo) SaveTx(ctx context.Context, tx Tx, state *LoginThrottleState) error {
+	if r.states == nil {
+		r.states = map[loginThrottleKey]*LoginThrottleState{}
+	}
+	copied := *state
+	r.states[loginThrottleKey{scope: state.Scope, key: state.Key}] = &copied
+	return nil
+}
+
+func (r *memoryLoginThrottleRepo) Delete(ctx context.Context, scope LoginThrottleScope, key string) error {
+	delete(r.states, loginThrottleKey{scope: scope, key: key})
+	return nil
+}
+
+func (r *memoryLoginThrottleRepo) ListEmails(ctx context.Context, since time.Time, limit int) ([]*domain.LoginLockout, error) {
+	var out []*domain.LoginLockout
+	for k, s := range r.states {
+		if k.scope == LoginThrottleEmail && s.Failures > 0 && !s.LastFailedAt.Before(since) {
+			out = append(out, &domain.LoginLockout{Email: k.key, Failures: s.Failures, LastFailedAt: s.LastFailedAt, LockedUntil: s.LockedUntil})
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryLoginThrottleRepo) DeleteStale(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+// failuresAt returns a state with n failures, the last one at last.
+func failuresAt(t *testing.T, scope LoginThrottleScope, n int, last time.Time) *LoginThrottleState {
+	t.Helper()
+	s := &LoginThrottleState{Scope: scope}
+	for i := 0; i < n; i++ {
+		testLoginThrottlePolicy.fail(s, last)
+	}
+	return s
+}
+
+func TestLoginThrottlePolicyWait(t *testing.T) {
+	now := time.Now()
+	p := testLoginThrottlePolicy
+
+	tests := []struct {
+		name  string
+		state *LoginThrottleState
+		at    time.Time
+		want  time.Duration
+	}{
+		{name: "free_attempts", state: failuresAt(t, LoginThrottleEmail, 3, now), at: now, want: 0},
+		{name: "first_delay", state: failuresAt(t, LoginThrottleEmail, 4, now), at: now, want: time.Second},
+		{name: "doubled_delay", state: failuresAt(t, LoginThrottleEmail, 6, now), at: now, want: 4 * time.Second},
+		{name: "delay_elapsed", state: failuresAt(t, LoginThrottleEmail, 6, now), at: now.Add(5 * time.Second), want: 0},
+		{name: "locked", state: failuresAt(t, LoginThrottleEmail, 10, now), at: now.Add(time.Minute), want: 14 * time.Minute},
+		{name: "lock_expired", state: failuresAt(t, LoginThrottleEmail, 10, now), at: now.Add(16 * time.Minute), want: 0},
+		{name: "ip_below_limit", state: failuresAt(t, LoginThrottleIP, 99, now), at: now, want: 0},
+		{name: "ip_blocked", state: failuresAt(t, LoginThrottleIP, 100, now), at: now.Add(time.Minute), want: 59 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.wait(tt.state, tt.at); got != tt.want {
+				t.Fatalf("wait=%v want=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginThrottlePolicyFail(t *testing.T) {
+	now := time.Now()
+	p := testLoginThrottlePolicy
+
+	s := failuresAt(t, LoginThrottleEmail, 9, now)
+	if locked := p.fail(s, now); !locked {
+		t.Fatalf("threshold failure did not report a lock")
+	}
+	if locked := p.fail(s, now); locked {
+		t.Fatalf("failure while locked reported a new lock")
+	}
+
+	// Once the lock ends, failures inside the window lock again straight away.
+	afterLock := now.Add(16 * time.Minute)
+	if locked := p.fail(s, afterLock); !locked {
+		t.Fatalf("failure after the lock did not lock again")
+	}
+
+	// Failures older than the window are forgotten.
+	later := afterLock.Add(2 * time.Hour)
+	if locked := p.fail(s, later); locked || s.Failures != 1 || s.LockedUntil != nil {
+		t.Fatalf("after window locked=%v failures=%d locked_until=%v want a fresh count", locked, s.Failures, s.LockedUntil)
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	client := domain.ClientInfo{IP: "203.0.113.7"}
+
+	for i := 0; i <= testLoginThrottlePolicy.FreeAttempts; i++ {
+		_, err := f.service.Login(ctx, &domain.LoginInput{Email: f.user.Email, Password: "wrong", Client: client})
+		if !errors.Is(err, apperr.ErrInvalidCredentials) {
+			t.Fatalf("attempt %d err=%v want=%v", i+1, err, apperr.ErrInvalidCredentials)
+		}
+	}
+
+	_, err := f.service.Login(ctx, &domain.LoginInput{Email: f.user.Email, Password: "password123", Client: client})
+	var throttled *apperr.ThrottledError
+	if !errors.As(err, &throttled) || !errors.Is(err, apperr.ErrLoginThrottled) || throttled.RetryAfter <= 0 {
+		t.Fatalf("delayed login err=%v want a ThrottledError with a retry time", err)
+	}
+
+	// The IP is below its own limit, so another email from it is not held back.
+	if err := f.throttle.Check(ctx, "bob@test.com", client.IP); err != nil {
+		t.Fatalf("other email from the same IP: %v", err)
+	}
+
+	// Record the rest directly so the test does not wait out the delays.
+	for i := testLoginThrottlePolicy.FreeAttempts + 1; i < testLoginThrottlePolicy.LockoutThreshold; i++ {
+		if _, err := f.throttle.RecordFailure(ctx, " Alice@Test.com", client.IP); err != nil {
+			t.Fatalf("record failure: %v", err)
+		}
+	}
+	lockouts, err := f.throttle.ListLockouts(ctx)
+	if err != nil || len(lockouts) != 1 || lockouts[0].LockedUntil == nil {
+		t.Fatalf("lockouts=%v err=%v want one locked email", lockouts, err)
+	}
+	if _, err := f.service.Login(ctx, &domain.LoginInput{Email: f.user.Email, Password: "password123", Client: client}); !errors.Is(err, apperr.ErrLoginThrottled) {
+		t.Fatalf("locked login err=%v want=%v", err, apperr.ErrLoginThrottled)
+	}
+
+	if err := f.throttle.Unlock(ctx, f.user.Email); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if _, err := f.service.Login(ctx, &domain.LoginInput{Email: f.user.Email, Password: "password123", Client: client}); err != nil {
+		t.Fatalf("login after unlock: %v", err)
+	}
+	if lockouts, _ := f.throttle.ListLockouts(ctx); len(lockouts) != 0 {
+		t.Fatalf("lockouts after unlock=%d want 0", len(lockouts))
+	}
+}
+
+func TestLoginThrottleCountsUnknownEmails(t *testing.T) {
+	f := newAuthFixture()
+	ctx := context.Background()
+
+	for i := 0; i <= testLoginThrottlePolicy.FreeAttempts; i++ {
+		_, err := f.service.Login(ctx, &domain.LoginInput{Email: "nobody@test.com", Password: "guess"})
+		if !errors.Is(err, apperr.ErrInvalidCredentials) {
+			t.Fatalf("attempt %d err=%v want=%v", i+1, err, apperr.ErrInvalidCredentials)
+		}
+	}
+	if _, err := f.service.Login(ctx, &domain.LoginInput{Email: "nobody@test.com", Password: "guess"}); !errors.Is(err, apperr.ErrLoginThrottled) {
+		t.Fatalf("unknown email err=%v want=%v", err, apperr.ErrLoginThrottled)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	MailDriverLog  = "log"
+	MailDriverFile = "file"
+)
+
+// LogMailer writes every message to the application log. The body contains live links, so it is
+// meant for development only.
+type LogMailer struct {
+	from   string
+	logger *slog.Logger
+}
+
+func NewLogMailer(from string, logger *slog.Logger) *LogMailer {
+	return &LogMailer{from: from, logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg *MailMessage) error {
+	m.logger.Info("Email sent", "from", m.from, "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// FileMailer appends every message to a local file, one RFC 5322-style message after another, so
+// tests and local setups can read the links that would have been emailed.
+type FileMailer struct {
+	from string
+	path string
+
+	mu sync.Mutex
+}
+
+func NewFileMailer(from string, path string) *FileMailer {
+	return &FileMailer{from: from, path: path}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg *MailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open mail file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().UTC().Format(time.RFC1123Z), m.from, msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return fmt.Errorf("write mail file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/pkg/hash"
+	"banking-platform/pkg/totp"
+	"github.com/google/uuid"
+)
+
+const (
+	// totpSkew accepts the codes of the neighbouring 30-second steps to absorb clock drift.
+	totpSkew = 1
+
+	mfaChallengeTTL         = 5 * time.Minute
+	mfaChallengeMaxAttempts = 5
+	mfaChallengeTokenBytes  = 32
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 5
+)
+
+// MFAService manages TOTP enrollment and recovery codes, and checks second factors for login and
+// transfer step-up.
+type MFAService struct {
+	mfaRepo       MFARepo
+	challengeRepo MFAChallengeRepo
+	userRepo      UserRepo
+	txRunner      TxRunner
+	hasher        *hash.Hasher
+	issuer        string
+	audit         AuditRecorder
+	logger        *slog.Logger
+}
+
+func NewMFAService(
+	mfaRepo MFARepo,
+	challengeRepo MFAChallengeRepo,
+	userRepo UserRepo,
+	txRunner TxRunner,
+	hasher *hash.Hasher,
+	issuer string,
+	audit AuditRecorder,
+	logger *slog.Logger,
+) *MFAService {
+	return &MFAService{
+		mfaRepo:       mfaRepo,
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		txRunner:      txRunner,
+		hasher:        hasher,
+		issuer:        issuer,
+		audit:         audit,
+		logger:        logger,
+	}
+}
+
+// Status reports whether the caller has a confirmed second factor.
+func (s *MFAService) Status(ctx context.Context, principal *domain.Principal) (*domain.MFAStatus, error) {
+	mfa, err := s.mfaRepo.Get(ctx, principal.UserID)
+	if errors.Is(err, apperr.ErrMFANotEnabled) || (err == nil && mfa.EnabledAt == nil) {
+		return &domain.MFAStatus{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mfa.status: %w", err)
+	}
+
+	remaining, err := s.mfaRepo.CountUnusedRecoveryCodes(ctx, principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("mfa.status: count recovery codes: %w", err)
+	}
+	return &domain.MFAStatus{Enabled: true, EnabledAt: mfa.EnabledAt, RecoveryCodesRemaining: remaining}, nil
+}
+
+// Enroll generates a new secret. It stays inactive until Confirm accepts a code generated from it,
+// so an abandoned enrollment never locks the user out.
+func (s *MFAService) Enroll(ctx context.Context, principal *domain.Principal) (*domain.MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("mfa.enroll: get user: %w", err)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("mfa.enroll: %w", err)
+	}
+
+	if err := s.mfaRepo.SavePending(ctx, &UserMFA{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("mfa.enroll: %w", err)
+	}
+
+	s.logger.Info("MFA enrollment started", "user_id", user.ID)
+	return &domain.MFAEnrollment{Secret: secret, URI: totp.URI(s.issuer, user.Email, secret)}, nil
+}
+
+// Confirm activates the pending secret and returns a fresh set of recovery codes. They are shown only
+// once; the database keeps their hashes.
+func (s *MFAService) Confirm(ctx context.Context, principal *domain.Principal, code string) ([]string, error) {
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("mfa.confirm: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		mfa, err := s.mfaRepo.LockTx(ctx, tx, principal.UserID)
+		if err != nil {
+			return err
+		}
+		if mfa.EnabledAt != nil {
+			return apperr.ErrMFAAlreadyEnabled
+		}
+		step, ok := totp.Validate(mfa.Secret, code, now, totpSkew)
+		if !ok {
+			return apperr.ErrInvalidMFACode
+		}
+		if err := s.mfaRepo.EnableTx(ctx, tx, principal.UserID, now, step); err != nil {
+			return err
+		}
+		return s.mfaRepo.ReplaceRecoveryCodesTx(ctx, tx, principal.UserID, hashes, now)
+	}); err != nil {
+		return nil, fmt.Errorf("mfa.confirm: %w", err)
+	}
+
+	s.logger.Info("MFA enabled", "user_id", principal.UserID)
+	s.record(ctx, AuditActionMFAEnabled, principal.UserID, principal.Role)
+	return codes, nil
+}
+
+// Disable removes the second factor. A current TOTP code or an unused recovery code is required, so
+// a stolen access token alone cannot turn MFA off.
+func (s *MFAService) Disable(ctx context.Context, principal *domain.Principal, code string, recoveryCode string) error {
+	now := time.Now()
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		mfa, err := s.enabledTx(ctx, tx, principal.UserID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.verifyCodeTx(ctx, tx, mfa, code, recoveryCode, now); err != nil {
+			return err
+		}
+		return s.mfaRepo.DeleteTx(ctx, tx, principal.UserID)
+	}); err != nil {
+		return fmt.Errorf("mfa.disable: %w", err)
+	}
+
+	s.logger.Info("MFA disabled", "user_id", principal.UserID)
+	s.record(ctx, AuditActionMFADisabled, principal.UserID, principal.Role)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code, used or not, after checking a TOTP code.
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, principal *domain.Principal, code string) ([]string, error) {
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("mfa.regenerate_recovery_codes: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		mfa, err := s.enabledTx(ctx, tx, principal.UserID)
+		if err != nil {
+			return err
+		}
+		if code == "" {
+			return apperr.ErrInvalidMFACode
+		}
+		if _, err := s.verifyCodeTx(ctx, tx, mfa, code, "", now); err != nil {
+			return err
+		}
+		return s.mfaRepo.ReplaceRecoveryCodesTx(ctx, tx, principal.UserID, hashes, now)
+	}); err != nil {
+		return nil, fmt.Errorf("mfa.regenerate_recovery_codes: %w", err)
+	}
+
+	s.record(ctx, AuditActionMFARecoveryCodesRegenerated, principal.UserID, principal.Role)
+	return codes, nil
+}
+
+// Enabled reports whether login must ask the user for a second factor.
+func (s *MFAService) Enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	mfa, err := s.mfaRepo.Get(ctx, userID)
+	if errors.Is(err, apperr.ErrMFANotEnabled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("mfa.enabled: %w", err)
+	}
+	return mfa.EnabledAt != nil, nil
+}
+
+// StartChallenge issues the single-use token a password login of an MFA user returns instead of a
+// token pair.
+func (s *MFAService) StartChallenge(ctx context.Context, userID uuid.UUID) (*domain.MFAChallenge, error) {
+	b := make([]byte, mfaChallengeTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("mfa.start_challenge: generate token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	challenge := &MFAChallengeToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: s.hasher.SHA256Hex(token),
+		ExpiresAt: now.Add(mfaChallengeTTL),
+		CreatedAt: now,
+	}
+	if err := s.challengeRepo.Create(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("mfa.start_challenge: %w", err)
+	}
+	if err := s.challengeRepo.DeleteExpired(ctx, now); err != nil {
+		s.logger.Warn("Failed to delete expired MFA challenges", "error", err)
+	}
+
+	return &domain.MFAChallenge{Token: token, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// CompleteChallenge checks a TOTP or recovery code against the challenge's user. Wrong codes count
+// against the challenge, which stops accepting codes after mfaChallengeMaxAttempts.
+func (s *MFAService) CompleteChallenge(ctx context.Context, in *domain.LoginMFAInput) (uuid.UUID, error) {
+	now := time.Now()
+	var userID uuid.UUID
+	var usedRecoveryCode bool
+	var codeErr error
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		challenge, err := s.challengeRepo.LockByTokenHashTx(ctx, tx, s.hasher.SHA256Hex(in.ChallengeToken))
+		if err != nil {
+			return err
+		}
+		if challenge.ConsumedAt != nil || !now.Before(challenge.ExpiresAt) || challenge.Attempts >= mfaChallengeMaxAttempts {
+			return apperr.ErrInvalidMFAChallenge
+		}
+		mfa, err := s.enabledTx(ctx, tx, challenge.UserID)
+		if errors.Is(err, apperr.ErrMFANotEnabled) {
+			// MFA was disabled after the password step; the challenge is stale.
+			return apperr.ErrInvalidMFAChallenge
+		}
+		if err != nil {
+			return err
+		}
+
+		userID = challenge.UserID
+		usedRecoveryCode, codeErr = s.verifyCodeTx(ctx, tx, mfa, in.Code, in.RecoveryCode, now)
+		if errors.Is(codeErr, apperr.ErrInvalidMFACode) {
+			// Commit the attempt; the code error is returned after the transaction.
+			return s.challengeRepo.RecordAttemptTx(ctx, tx, challenge.ID)
+		}
+		if codeErr != nil {
+			return codeErr
+		}
+		return s.challengeRepo.ConsumeTx(ctx, tx, challenge.ID, now)
+	}); err != nil {
+		return uuid.Nil, fmt.Errorf("mfa.complete_challenge: %w", err)
+	}
+	if codeErr != nil {
+		return userID, codeErr
+	}
+
+	if usedRecoveryCode {
+		s.record(ctx, AuditActionMFARecoveryCodeUsed, userID, "")
+	}
+	return userID, nil
+}
+
+// VerifyStepUp checks the TOTP code sent with a transfer above the step-up threshold. The code is
+// not marked as used, so retrying the same request with its idempotency key still succeeds.
+func (s *MFAService) VerifyStepUp(ctx context.Context, userID uuid.UUID, code string) error {
+	mfa, err := s.mfaRepo.Get(ctx, userID)
+	if errors.Is(err, apperr.ErrMFANotEnabled) || (err == nil && mfa.EnabledAt == nil) {
+		return apperr.ErrMFAEnrollmentRequired
+	}
+	if err != nil {
+		return fmt.Errorf("mfa.verify_step_up: %w", err)
+	}
+	if strings.TrimSpace(code) == "" {
+		return apperr.ErrMFACodeRequired
+	}
+	if _, ok := totp.Validate(mfa.Secret, code, time.Now(), totpSkew); !ok {
+		var role domain.Role
+		if p := domain.PrincipalFrom(ctx); p != nil {
+			role = p.Role
+		}
+		s.record(ctx, AuditActionMFAStepUpFailed, userID, role)
+		return apperr.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// enabledTx locks the user's MFA row and returns ErrMFANotEnabled unless enrollment was confirmed.
+func (s *MFAService) enabledTx(ctx context.Context, tx Tx, userID uuid.UUID) (*UserMFA, error) {
+	mfa, err := s.mfaRepo.LockTx(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.EnabledAt == nil {
+		return nil, apperr.ErrMFANotEnabled
+	}
+	return mfa, nil
+}
+
+// verifyCodeTx accepts either a TOTP code newer than the last one used, or an unused recovery code,
+// and records its use. It reports whether a recovery code was spent.
+func (s *MFAService) verifyCodeTx(ctx context.Context, tx Tx, mfa *UserMFA, code string, recoveryCode string, now time.Time) (bool, error) {
+	switch {
+	case code != "":
+		step, ok := totp.Validate(mfa.Secret, code, now, totpSkew)
+		if !ok || step <= mfa.LastUsedStep {
+			return false, apperr.ErrInvalidMFACode
+		}
+		return false, s.mfaRepo.SetLastUsedStepTx(ctx, tx, mfa.UserID, step)
+	case recoveryCode != "":
+		used, err := s.mfaRepo.UseRecoveryCodeTx(ctx, tx, mfa.UserID, s.hasher.SHA256Hex(normalizeRecoveryCode(recoveryCode)), now)
+		if err != nil {
+			return false, err
+		}
+		if !used {
+			return false, apperr.ErrInvalidMFACode
+		}
+		return true, nil
+	default:
+		return false, apperr.ErrInvalidMFACode
+	}
+}
+
+// newRecoveryCodes returns recovery codes formatted as xxxx-xxxx together with their hashes.
+func (s *MFAService) newRecoveryCodes() ([]string, []string, error) {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		raw := strings.ToLower(enc.EncodeToString(b))
+		codes[i] = raw[:4] + "-" + raw[4:]
+		hashes[i] = s.hasher.SHA256Hex(raw)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+func (s *MFAService) record(ctx context.Context, action string, userID uuid.UUID, role domain.Role) {
+	event := &domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    &userID,
+		ActorRole:  role,
+		Action:     action,
+		TargetType: "user",
+		TargetID:   userID.String(),
+		CreatedAt:  time.Now().UTC(),
+	}
+	recordAudit(ctx, s.audit, s.logger, event)
+}
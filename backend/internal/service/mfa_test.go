@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"banking-platform/pkg/totp"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type memoryMFARepo struct {
+	rows  map[uuid.UUID]*UserMFA
+	codes map[uuid.UUID]map[string]bool // code hash -> used
+}
+
+func (r *memoryMFARepo) Get(ctx context.Context, userID uuid.UUID) (*UserMFA, error) {
+	m, ok := r.rows[userID]
+	if !ok {
+		return nil, apperr.ErrMFANotEnabled
+	}
+	copied := *m
+	return &copied, nil
+}
+
+func (r *memoryMFARepo) LockTx(ctx context.Context, tx Tx, userID uuid.UUID) (*UserMFA, error) {
+	return r.Get(ctx, userID)
+}
+
+func (r *memoryMFARepo) SavePending(ctx context.Context, mfa *UserMFA) error {
+	if r.rows == nil {
+		r.rows = make(map[uuid.UUID]*UserMFA)
+	}
+	if cur, ok := r.rows[mfa.UserID]; ok && cur.EnabledAt != nil {
+		return apperr.ErrMFAAlreadyEnabled
+	}
+	r.rows[mfa.UserID] = mfa
+	return nil
+}
+
+func (r *memoryMFARepo) EnableTx(ctx context.Context, tx Tx, userID uuid.UUID, at time.Time, step int64) error {
+	r.rows[userID].EnabledAt = &at
+	r.rows[userID].LastUsedStep = step
+	return nil
+}
+
+func (r *memoryMFARepo) SetLastUsedStepTx(ctx context.Context, tx Tx, userID uuid.UUID, step int64) error {
+	r.rows[userID].LastUsedStep = step
+	return nil
+}
+
+func (r *memoryMFARepo) DeleteTx(ctx context.Context, tx Tx, userID uuid.UUID) error {
+	delete(r.rows, userID)
+	delete(r.codes, userID)
+	return nil
+}
+
+func (r *memoryMFARepo) ReplaceRecoveryCodesTx(ctx context.Context, tx Tx, userID uuid.UUID, codeHashes []string, at time.Time) error {
+	if r.codes == nil {
+		r.codes = make(map[uuid.UUID]map[string]bool)
+	}
+	r.codes[userID] = make(map[string]bool)
+	for _, h := range codeHashes {
+		r.codes[userID][h] = false
+	}
+	return nil
+}
+
+func (r *memoryMFARepo) UseRecoveryCodeTx(ctx context.Context, tx Tx, userID uuid.UUID, codeHash string, at time.Time) (bool, error) {
+	used, ok := r.codes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.codes[userID][codeHash] = true
+	return true, nil
+}
+
+func (r *memoryMFARepo) CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	n := 0
+	for _, used := range r.codes[userID] {
+		if !used {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type memoryMFAChallengeRepo struct {
+	challenges []*MFAChallengeToken
+}
+
+func (r *memoryMFAChallengeRepo) Create(ctx context.Context, challenge *MFAChallengeToken) error {
+	r.challenges = append(r.challenges, challenge)
+	return nil
+}
+
+func (r *memoryMFAChallengeRepo) LockByTokenHashTx(ctx context.Context, tx Tx, tokenHash string) (*MFAChallengeToken, error) {
+	for _, c := range r.challenges {
+		if c.TokenHash == tokenHash {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, apperr.ErrInvalidMFAChallenge
+}
+
+func (r *memoryMFAChallengeRepo) RecordAttemptTx(ctx context.Context, tx Tx, id uuid.UUID) error {
+	for _, c := range r.challenges {
+		if c.ID == id {
+			c.Attempts++
+		}
+	}
+	return nil
+}
+
+func (r *memoryMFAChallengeRepo) ConsumeTx(ctx context.Context, tx Tx, id uuid.UUID, at time.Time) error {
+	for _, c := range r.challenges {
+		if c.ID == id {
+			c.ConsumedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryMFAChallengeRepo) DeleteExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+// enableMFA enrolls the fixture user and returns the secret, the recovery codes and the step of
+// the confirming code.
+func enableMFA(t *testing.T, f *authFixture) (string, []string, int64) {
+	t.Helper()
+	ctx := context.Background()
+	principal := &domain.Principal{UserID: f.user.ID, Role: f.user.Role}
+
+	enrollment, err := f.mfa.Enroll(ctx, principal)
+	if err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(enrollment.Secret, step)
+	codes, err := f.mfa.Confirm(ctx, principal, code)
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	return enrollment.Secret, codes, step
+}
+
+func loginChallenge(t *testing.T, f *authFixture) string {
+	t.Helper()
+	out, err := f.service.Login(context.Background(), &domain.LoginInput{Email: f.user.Email, Password: "password123"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if out.MFAChallenge == nil || out.Tokens.AccessToken != "" {
+		t.Fatalf("login returned tokens instead of an MFA challenge")
+	}
+	return out.MFAChallenge.Token
+}
+
+func newMFAFixture(t *testing.T) *authFixture {
+	t.Helper()
+	f := newAuthFixture()
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	f.user.PasswordHash = string(passwordHash)
+	return f
+}
+
+func TestLoginWithTOTP(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	secret, _, step := enableMFA(t, f)
+
+	challenge := loginChallenge(t, f)
+	replayed, _ := totp.Code(secret, step)
+	if _, err := f.service.LoginMFA(ctx, &domain.LoginMFAInput{ChallengeToken: challenge, Code: replayed}); !errors.Is(err, apperr.ErrInvalidMFACode) {
+		t.Fatalf("replayed code err=%v want=%v", err, apperr.ErrInvalidMFACode)
+	}
+
+	next, _ := totp.Code(secret, step+1)
+	out, err := f.service.LoginMFA(ctx, &domain.LoginMFAInput{ChallengeToken: challenge, Code: next})
+	if err != nil {
+		t.Fatalf("login mfa: %v", err)
+	}
+	if _, err := f.service.ValidateToken(ctx, out.Tokens.AccessToken); err != nil {
+		t.Fatalf("access token: %v", err)
+	}
+
+	if _, err := f.service.LoginMFA(ctx, &domain.LoginMFAInput{ChallengeToken: challenge, Code: next}); !errors.Is(err, apperr.ErrInvalidMFAChallenge) {
+		t.Fatalf("reused challenge err=%v want=%v", err, apperr.ErrInvalidMFAChallenge)
+	}
+}
+
+func TestLoginMFAChallengeAttemptLimit(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	secret, _, step := enableMFA(t, f)
+	challenge := loginChallenge(t, f)
+
+	for i := 0; i < mfaChallengeMaxAttempts; i++ {
+		if _, err := f.service.LoginMFA(ctx, &domain.LoginMFAInput{ChallengeToken: challenge, Code: "000000"}); !errors.Is(err, apperr.ErrInvalidMFACode) {
+			t.Fatalf("attempt %d err=%v want=%v", i+1, err, apperr.ErrInvalidMFACode)
+		}
+	}
+	next, _ := totp.Code(secret, step+1)
+	if _, err := f.service.LoginMFA(ctx, &domain.LoginMFAInput{ChallengeToken: challenge, Code: next}); !errors.Is(err, apperr.ErrInvalidMFAChallenge) {
+		t.Fatalf("exhausted challenge err=%v want=%v", err, apperr.ErrInvalidMFAChallenge)
+	}
+}
+
+func TestLoginWithRecoveryCode(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	_, codes, _ := enableMFA(t, f)
+
+	if _, err := f.service.LoginMFA(ctx, &domain.LoginMFAInput{ChallengeToken: loginChallenge(t, f), RecoveryCode: " " + codes[0] + " "}); err != nil {
+		t.Fatalf("recovery code login: %v", err)
+	}
+	if _, err := f.service.LoginMFA(ctx, &domain.LoginMFAInput{ChallengeToken: loginChallenge(t, f), RecoveryCode: codes[0]}); !errors.Is(err, apperr.ErrInvalidMFACode) {
+		t.Fatalf("reused recovery code err=%v want=%v", err, apperr.ErrInvalidMFACode)
+	}
+
+	status, err := f.mfa.Status(ctx, &domain.Principal{UserID: f.user.ID})
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !status.Enabled || status.RecoveryCodesRemaining != recoveryCodeCount-1 {
+		t.Fatalf("status enabled=%v remaining=%d want remaining=%d", status.Enabled, status.RecoveryCodesRemaining, recoveryCodeCount-1)
+	}
+}
+
+func TestVerifyStepUp(t *testing.T) {
+	f := newMFAFixture(t)
+	ctx := context.Background()
+	if err := f.mfa.VerifyStepUp(ctx, f.user.ID, "123456"); !errors.Is(err, apperr.ErrMFAEnrollmentRequired) {
+		t.Fatalf("not enrolled err=%v want=%v", err, apperr.ErrMFAEnrollmentRequired)
+	}
+
+	secret, _, _ := enableMFA(t, f)
+	current, _ := totp.Code(secret, totp.Step(time.Now()))
+	stale, _ := totp.Code(secret, totp.Step(time.Now())-5)
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{name: "missing", code: "", wantErr: apperr.ErrMFACodeRequired},
+		{name: "stale", code: stale, wantErr: apperr.ErrInvalidMFACode},
+		{name: "current", code: current, wantErr: nil},
+		// Retrying a transfer with its idempotency key sends the same code again.
+		{name: "current_again", code: current, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := f.mfa.VerifyStepUp(ctx, f.user.ID, tt.code); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err=%v want=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequiresStepUp(t *testing.T) {
+	svc := &TransactionService{stepUp: &MFAService{}, stepUpThreshold: 100_00}
+
+	tests := []struct {
+		name string
+		svc  *TransactionService
+		in   *domain.TransferInput
+		want bool
+	}{
+		{name: "at_threshold", svc: svc, in: &domain.TransferInput{AmountCents: 100_00}, want: false},
+		{name: "above_threshold", svc: svc, in: &domain.TransferInput{AmountCents: 100_01}, want: true},
+		{name: "scheduled", svc: svc, in: &domain.TransferInput{AmountCents: 500_00, Scheduled: true}, want: false},
+		{name: "approved_review", svc: svc, in: &domain.TransferInput{AmountCents: 500_00, RiskReviewID: &uuid.UUID{}}, want: false},
+		{name: "disabled", svc: &TransactionService{stepUp: &MFAService{}}, in: &domain.TransferInput{AmountCents: 500_00}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.svc.requiresStepUp(tt.in); got != tt.want {
+				t.Fatalf("got=%v want=%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"banking-platform/internal/apperr"
+)
+
+// maxPasswordBytes is where bcrypt stops reading; longer passwords would be silently truncated.
+const maxPasswordBytes = 72
+
+const (
+	PasswordClassUpper  = "upper"
+	PasswordClassLower  = "lower"
+	PasswordClassDigit  = "digit"
+	PasswordClassSymbol = "symbol"
+)
+
+var passwordClassNames = map[string]string{
+	PasswordClassUpper:  "an uppercase letter",
+	PasswordClassLower:  "a lowercase letter",
+	PasswordClassDigit:  "a digit",
+	PasswordClassSymbol: "a symbol",
+}
+
+// PasswordPolicy decides which new passwords are accepted. Existing passwords are not re-checked,
+// so tightening the policy applies from the next change or reset.
+type PasswordPolicy struct {
+	minLength       int
+	requiredClasses []string
+	denylist        map[string]struct{}
+}
+
+// NewPasswordPolicy builds a policy. requiredClasses are PasswordClass* values; denylist entries are
+// compared case-insensitively.
+func NewPasswordPolicy(minLength int, requiredClasses []string, denylist []string) (*PasswordPolicy, error) {
+	if minLength < 1 || minLength > maxPasswordBytes {
+		return nil, fmt.Errorf("minimum password length must be between 1 and %d", maxPasswordBytes)
+	}
+	for _, class := range requiredClasses {
+		if _, ok := passwordClassNames[class]; !ok {
+			return nil, fmt.Errorf("unknown password character class %q", class)
+		}
+	}
+
+	denied := make(map[string]struct{}, len(denylist))
+	for _, p := range denylist {
+		denied[strings.ToLower(p)] = struct{}{}
+	}
+	return &PasswordPolicy{minLength: minLength, requiredClasses: requiredClasses, denylist: denied}, nil
+}
+
+// LoadPasswordDenylist reads one password per line. Blank lines and lines starting with # are skipped.
+func LoadPasswordDenylist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open password denylist: %w", err)
+	}
+	defer f.Close()
+
+	var out []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out = append(out, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read password denylist: %w", err)
+	}
+	return out, nil
+}
+
+// Validate returns a 400 PublicError wrapping apperr.ErrWeakPassword that names the first rule the
+// password breaks. email is the account's address; the password may not be the address or its
+// local part.
+func (p *PasswordPolicy) Validate(password string, email string) error {
+	if n := utf8.RuneCountInString(password); n < p.minLength {
+		return weakPassword(fmt.Sprintf("password must be at least %d characters", p.minLength))
+	}
+	if len(password) > maxPasswordBytes {
+		return weakPassword(fmt.Sprintf("password must be at most %d bytes", maxPasswordBytes))
+	}
+
+	for _, class := range p.requiredClasses {
+		if !strings.ContainsFunc(password, passwordClassMatcher(class)) {
+			return weakPassword("password must contain " + passwordClassNames[class])
+		}
+	}
+
+	lower := strings.ToLower(password)
+	if _, ok := p.denylist[lower]; ok {
+		return weakPassword("password is too common")
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	local, _, _ := strings.Cut(email, "@")
+	if email != "" && (lower == email || lower == local) {
+		return weakPassword("password must not be the email address")
+	}
+	return nil
+}
+
+func passwordClassMatcher(class string) func(rune) bool {
+	switch class {
+	case PasswordClassUpper:
+		return unicode.IsUpper
+	case PasswordClassLower:
+		return unicode.IsLower
+	case PasswordClassDigit:
+		return unicode.IsDigit
+	default:
+		return func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+		}
+	}
+}
+
+func weakPassword(message string) error {
+	return &apperr.PublicError{Status: 400, Message: message, Err: apperr.ErrWeakPassword}
+}
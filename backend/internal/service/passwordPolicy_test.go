@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"banking-platform/internal/apperr"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	if err := os.WriteFile(path, []byte("# common passwords\nqwerty12345\n\nLetMeIn2024!\n"), 0o600); err != nil {
+		t.Fatalf("write denylist: %v", err)
+	}
+	denylist, err := LoadPasswordDenylist(path)
+	if err != nil {
+		t.Fatalf("load denylist: %v", err)
+	}
+	policy, err := NewPasswordPolicy(10, []string{PasswordClassLower, PasswordClassDigit, PasswordClassSymbol}, denylist)
+	if err != nil {
+		t.Fatalf("new policy: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		wantMsg  string
+	}{
+		{name: "ok", password: "blue-lagoon-42", wantMsg: ""},
+		{name: "too_short", password: "a1-b2-c3", wantMsg: "password must be at least 10 characters"},
+		{name: "too_long", password: string(make([]byte, 73)), wantMsg: "password must be at most 72 bytes"},
+		{name: "no_digit", password: "blue-lagoon-xx", wantMsg: "password must contain a digit"},
+		{name: "no_symbol", password: "bluelagoon42", wantMsg: "password must contain a symbol"},
+		{name: "denylisted_any_case", password: "letmein2024!", wantMsg: "password is too common"},
+		{name: "email_local_part", password: "alice.smith-1", wantMsg: "password must not be the email address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password, "Alice.Smith-1@test.com")
+			if tt.wantMsg == "" {
+				if err != nil {
+					t.Fatalf("err=%v want nil", err)
+				}
+				return
+			}
+			var pub *apperr.PublicError
+			if !errors.As(err, &pub) || pub.Message != tt.wantMsg || !errors.Is(err, apperr.ErrWeakPassword) {
+				t.Fatalf("err=%v want %q wrapping %v", err, tt.wantMsg, apperr.ErrWeakPassword)
+			}
+		})
+	}
+}
+
+func TestNewPasswordPolicyRejectsUnknownClass(t *testing.T) {
+	if _, err := NewPasswordPolicy(8, []string{"emoji"}, nil); err == nil {
+		t.Fatalf("unknown class accepted")
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	maxPaymentRequestMemoLength = 140
+	paymentRequestListLimit     = 200
+)
+
+// PaymentRequestService lets users ask each other for money. The payer settles a request with a
+// regular transfer, so balances, limits, step-up and risk screening all apply to it.
+type PaymentRequestService struct {
+	repo        PaymentRequestRepo
+	accountRepo AccountRepo
+	userRepo    UserRepo
+	transferer  Transferer
+	currencies  *domain.CurrencyRegistry
+	logger      *slog.Logger
+
+	ttl time.Duration
+	now func() time.Time
+}
+
+func NewPaymentRequestService(
+	repo PaymentRequestRepo,
+	accountRepo AccountRepo,
+	userRepo UserRepo,
+	transferer Transferer,
+	currencies *domain.CurrencyRegistry,
+	ttl time.Duration,
+	logger *slog.Logger,
+) *PaymentRequestService {
+	return &PaymentRequestService{
+		repo:        repo,
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		transferer:  transferer,
+		currencies:  currencies,
+		logger:      logger,
+
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// Create asks the user with in.PayerEmail for money. The requester must hold an active default
+// account in the currency, which is where the money lands once the request is paid.
+func (s *PaymentRequestService) Create(ctx context.Context, requesterID uuid.UUID, in *domain.CreatePaymentRequestInput) (*domain.PaymentRequest, error) {
+	if in.AmountCents <= 0 {
+		return nil, apperr.BadRequest("amount_cents must be greater than 0")
+	}
+	if !s.currencies.IsEnabled(in.Currency) {
+		return nil, apperr.ErrInvalidCurrency
+	}
+	memo := strings.TrimSpace(in.Memo)
+	if len(memo) > maxPaymentRequestMemoLength {
+		return nil, apperr.BadRequest("memo must be at most 140 characters")
+	}
+	email := strings.ToLower(strings.TrimSpace(in.PayerEmail))
+	if email == "" {
+		return nil, apperr.BadRequest("payer_email cannot be empty")
+	}
+
+	payer, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("payment_request.create: get payer by email: %w", err)
+	}
+	if payer.ID == requesterID {
+		return nil, apperr.BadRequest("cannot request money from yourself")
+	}
+	requester, err := s.userRepo.GetByID(ctx, requesterID)
+	if err != nil {
+		return nil, fmt.Errorf("payment_request.create: get requester: %w", err)
+	}
+	account, err := s.accountRepo.GetByUserIDAndCurrency(ctx, requesterID, in.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("payment_request.create: get receiving account: %w", err)
+	}
+	if err := ensureAccountActive(account); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	pr := &domain.PaymentRequest{
+		ID:             uuid.New(),
+		RequesterID:    requesterID,
+		RequesterEmail: requester.Email,
+		PayerID:        payer.ID,
+		PayerEmail:     payer.Email,
+		Currency:       in.Currency,
+		AmountCents:    in.AmountCents,
+		Memo:           memo,
+		Status:         domain.PaymentRequestPending,
+		ExpiresAt:      now.Add(s.ttl),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.repo.Create(ctx, pr); err != nil {
+		return nil, fmt.Errorf("payment_request.create: %w", err)
+	}
+
+	s.logger.Info("Payment request created", "payment_request_id", pr.ID, "requester_id", requesterID, "payer_id", payer.ID, "amount_cents", pr.AmountCents, "currency", pr.Currency)
+	return pr, nil
+}
+
+// List returns up to 200 of the user's requests, newest first.
+func (s *PaymentRequestService) List(ctx context.Context, userID uuid.UUID, filter *domain.PaymentRequestFilter) ([]*domain.PaymentRequest, error) {
+	switch filter.Direction {
+	case "", domain.TransactionDirectionIncoming, domain.TransactionDirectionOutgoing:
+	default:
+		return nil, apperr.BadRequest("direction must be one of incoming, outgoing")
+	}
+	switch filter.Status {
+	case "", domain.PaymentRequestPending, domain.PaymentRequestProcessing, domain.PaymentRequestPaid,
+		domain.PaymentRequestDeclined, domain.PaymentRequestCancelled, domain.PaymentRequestExpired:
+	default:
+		return nil, apperr.BadRequest("status must be one of pending, processing, paid, declined, cancelled, expired")
+	}
+
+	now := s.now().UTC()
+	items, err := s.repo.ListByUserID(ctx, userID, filter, now, paymentRequestListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("payment_request.list: %w", err)
+	}
+	for _, pr := range items {
+		if pr.IsExpired(now) {
+			pr.Status = domain.PaymentRequestExpired
+		}
+	}
+	return items, nil
+}
+
+// Accept pays a pending request with a transfer from the payer to the requester. The transfer uses
+// an idempotency key derived from the request, so a retry after a failure in between books once: a
+// request left processing without a review, because the process died or marking it paid failed,
+// is claimed again and the transfer replays. If the transfer fails the request goes back to pending
+// and the error is returned. If it is held for review the request stays processing with the review,
+// which settles it when it is decided.
+func (s *PaymentRequestService) Accept(ctx context.Context, payerID uuid.UUID, in *domain.AcceptPaymentRequestInput) (*domain.PaymentRequest, *domain.TransactionInfo, error) {
+	pr, err := s.get(ctx, payerID, in.RequestID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("payment_request.accept: %w", err)
+	}
+	if pr.PayerID != payerID {
+		// The requester knows the request exists but cannot pay it on the payer's behalf.
+		return nil, nil, apperr.ErrUnauthorized
+	}
+	if err := s.expire(ctx, pr); err != nil {
+		return nil, nil, fmt.Errorf("payment_request.accept: %w", err)
+	}
+
+	processing := []domain.PaymentRequestStatus{domain.PaymentRequestProcessing}
+	if err := s.repo.ClaimForAccept(ctx, pr.ID, s.now().UTC()); err != nil {
+		return nil, nil, fmt.Errorf("payment_request.accept: %w", err)
+	}
+
+	requesterID := pr.RequesterID
+	info, err := s.transferer.Transfer(ctx, payerID, &domain.TransferInput{
+		ToUserID:       &requesterID,
+		FromAccountID:  in.FromAccountID,
+		Currency:       pr.Currency,
+		AmountCents:    pr.AmountCents,
+		IdempotencyKey: "payment-request:" + pr.ID.String(),
+		MFACode:        in.MFACode,
+	})
+	if err != nil {
+		var held *apperr.HeldForReviewError
+		if errors.As(err, &held) {
+			herr := s.holdForReview(ctx, pr.ID, held.ReviewID)
+			if herr == nil {
+				s.logger.Info("Payment request held for review", "payment_request_id", pr.ID, "review_id", held.ReviewID, "payer_id", payerID)
+				return nil, nil, fmt.Errorf("payment_request.accept: %w", err)
+			}
+			// Accepting again finds the same review and records it.
+			s.logger.Error("Failed to record payment request review", "payment_request_id", pr.ID, "review_id", held.ReviewID, "error", herr)
+		}
+		if errors.Is(err, apperr.ErrIdempotencyKeyConflict) {
+			// An earlier attempt booked the transfer with other details, such as another source
+			// account. The request stays processing so it cannot be declined after being paid;
+			// accepting again as before replays the transfer and marks it paid.
+			return nil, nil, fmt.Errorf("payment_request.accept: %w", err)
+		}
+		if rerr := s.repo.Transition(ctx, pr.ID, processing, domain.PaymentRequestPending, nil, s.now().UTC()); rerr != nil {
+			s.logger.Error("Failed to return payment request to pending", "payment_request_id", pr.ID, "error", rerr)
+		}
+		return nil, nil, fmt.Errorf("payment_request.accept: %w", err)
+	}
+
+	now := s.now().UTC()
+	if err := s.repo.Transition(ctx, pr.ID, processing, domain.PaymentRequestPaid, &info.ID, now); err != nil {
+		return nil, nil, fmt.Errorf("payment_request.accept: %w", err)
+	}
+	pr.Status = domain.PaymentRequestPaid
+	pr.TransactionID = &info.ID
+	pr.UpdatedAt = now
+
+	s.logger.Info("Payment request paid", "payment_request_id", pr.ID, "transaction_id", info.ID, "payer_id", payerID)
+	return pr, info, nil
+}
+
+func (s *PaymentRequestService) holdForReview(ctx context.Context, id uuid.UUID, reviewID string) error {
+	parsed, err := uuid.Parse(reviewID)
+	if err != nil {
+		return fmt.Errorf("parse review id: %w", err)
+	}
+	return s.repo.HoldForReview(ctx, id, parsed, s.now().UTC())
+}
+
+// ReviewApproved pays the request whose transfer was held in the review, if any.
+func (s *PaymentRequestService) ReviewApproved(ctx context.Context, review *domain.RiskReview, transactionID uuid.UUID) error {
+	pr, err := s.repo.GetByReviewID(ctx, review.ID)
+	if errors.Is(err, apperr.ErrPaymentRequestNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("payment_request.review_approved: %w", err)
+	}
+	if pr.Status != domain.PaymentRequestProcessing {
+		// Settled by an earlier attempt at approving.
+		return nil
+	}
+	if err := s.repo.Transition(ctx, pr.ID, []domain.PaymentRequestStatus{domain.PaymentRequestProcessing}, domain.PaymentRequestPaid, &transactionID, s.now().UTC()); err != nil {
+		return fmt.Errorf("payment_request.review_approved: %w", err)
+	}
+	s.logger.Info("Payment request paid", "payment_request_id", pr.ID, "transaction_id", transactionID, "review_id", review.ID)
+	return nil
+}
+
+// ReviewRejected returns the request whose transfer was held in the review to pending, if any. The
+// payer can still decline it; accepting again is refused, as the review stays rejected.
+func (s *PaymentRequestService) ReviewRejected(ctx context.Context, review *domain.RiskReview) error {
+	pr, err := s.repo.GetByReviewID(ctx, review.ID)
+	if errors.Is(err, apperr.ErrPaymentRequestNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("payment_request.review_rejected: %w", err)
+	}
+	if err := s.repo.Transition(ctx, pr.ID, []domain.PaymentRequestStatus{domain.PaymentRequestProcessing}, domain.PaymentRequestPending, nil, s.now().UTC()); err != nil {
+		return fmt.Errorf("payment_request.review_rejected: %w", err)
+	}
+	s.logger.Info("Payment request returned to pending", "payment_request_id", pr.ID, "review_id", review.ID)
+	return nil
+}
+
+// Decline refuses a pending request; only the payer can decline.
+func (s *PaymentRequestService) Decline(ctx context.Context, payerID uuid.UUID, id uuid.UUID) (*domain.PaymentRequest, error) {
+	pr, err := s.get(ctx, payerID, id)
+	if err != nil {
+		return nil, fmt.Errorf("payment_request.decline: %w", err)
+	}
+	if pr.PayerID != payerID {
+		return nil, apperr.ErrUnauthorized
+	}
+	if err := s.settle(ctx, pr, domain.PaymentRequestDeclined); err != nil {
+		return nil, fmt.Errorf("payment_request.decline: %w", err)
+	}
+	s.logger.Info("Payment request declined", "payment_request_id", id, "payer_id", payerID)
+	return pr, nil
+}
+
+// Cancel withdraws a pending request; only the requester can cancel.
+func (s *PaymentRequestService) Cancel(ctx context.Context, requesterID uuid.UUID, id uuid.UUID) (*domain.PaymentRequest, error) {
+	pr, err := s.get(ctx, requesterID, id)
+	if err != nil {
+		return nil, fmt.Errorf("payment_request.cancel: %w", err)
+	}
+	if pr.RequesterID != requesterID {
+		return nil, apperr.ErrUnauthorized
+	}
+	if err := s.settle(ctx, pr, domain.PaymentRequestCancelled); err != nil {
+		return nil, fmt.Errorf("payment_request.cancel: %w", err)
+	}
+	s.logger.Info("Payment request cancelled", "payment_request_id", id, "requester_id", requesterID)
+	return pr, nil
+}
+
+// get loads a request the user is a party to; others get the same answer as for a missing request.
+func (s *PaymentRequestService) get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.PaymentRequest, error) {
+	pr, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr.PayerID != userID && pr.RequesterID != userID {
+		return nil, apperr.ErrPaymentRequestNotFound
+	}
+	return pr, nil
+}
+
+// expire records that a pending request ran out and reports it as no longer pending.
+func (s *PaymentRequestService) expire(ctx context.Context, pr *domain.PaymentRequest) error {
+	now := s.now().UTC()
+	if !pr.IsExpired(now) {
+		return nil
+	}
+	err := s.repo.Transition(ctx, pr.ID, []domain.PaymentRequestStatus{domain.PaymentRequestPending}, domain.PaymentRequestExpired, nil, now)
+	if err != nil && !errors.Is(err, apperr.ErrPaymentRequestNotPending) {
+		return err
+	}
+	return apperr.ErrPaymentRequestNotPending
+}
+
+func (s *PaymentRequestService) settle(ctx context.Context, pr *domain.PaymentRequest, status domain.PaymentRequestStatus) error {
+	if err := s.expire(ctx, pr); err != nil {
+		return err
+	}
+	now := s.now().UTC()
+	if err := s.repo.Transition(ctx, pr.ID, []domain.PaymentRequestStatus{domain.PaymentRequestPending}, status, nil, now); err != nil {
+		return err
+	}
+	pr.Status = status
+	pr.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+type memoryPaymentRequestRepo struct {
+	requests map[uuid.UUID]*domain.PaymentRequest
+}
+
+func (r *memoryPaymentRequestRepo) Create(ctx context.Context, pr *domain.PaymentRequest) error {
+	r.requests[pr.ID] = pr
+	return nil
+}
+
+func (r *memoryPaymentRequestRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.PaymentRequest, error) {
+	pr, ok := r.requests[id]
+	if !ok {
+		return nil, apperr.ErrPaymentRequestNotFound
+	}
+	copied := *pr
+	return &copied, nil
+}
+
+func (r *memoryPaymentRequestRepo) ListByUserID(ctx context.Context, userID uuid.UUID, filter *domain.PaymentRequestFilter, now time.Time, limit int) ([]*domain.PaymentRequest, error) {
+	var out []*domain.PaymentRequest
+	for _, pr := range r.requests {
+		if pr.PayerID == userID || pr.RequesterID == userID {
+			copied := *pr
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryPaymentRequestRepo) Transition(ctx context.Context, id uuid.UUID, from []domain.PaymentRequestStatus, status domain.PaymentRequestStatus, transactionID *uuid.UUID, at time.Time) error {
+	pr, ok := r.requests[id]
+	if !ok {
+		return apperr.ErrPaymentRequestNotFound
+	}
+	if !slices.Contains(from, pr.Status) {
+		return apperr.ErrPaymentRequestNotPending
+	}
+	pr.Status = status
+	if transactionID != nil {
+		pr.TransactionID = transactionID
+	}
+	if status == domain.PaymentRequestPending {
+		pr.ReviewID = nil
+	}
+	pr.UpdatedAt = at
+	return nil
+}
+
+func (r *memoryPaymentRequestRepo) GetByReviewID(ctx context.Context, reviewID uuid.UUID) (*domain.PaymentRequest, error) {
+	for _, pr := range r.requests {
+		if pr.ReviewID != nil && *pr.ReviewID == reviewID {
+			copied := *pr
+			return &copied, nil
+		}
+	}
+	return nil, apperr.ErrPaymentRequestNotFound
+}
+
+func (r *memoryPaymentRequestRepo) HoldForReview(ctx context.Context, id uuid.UUID, reviewID uuid.UUID, at time.Time) error {
+	pr, ok := r.requests[id]
+	if !ok {
+		return apperr.ErrPaymentRequestNotFound
+	}
+	if pr.Status != domain.PaymentRequestProcessing {
+		return apperr.ErrPaymentRequestNotPending
+	}
+	pr.ReviewID = &reviewID
+	pr.UpdatedAt = at
+	return nil
+}
+
+func (r *memoryPaymentRequestRepo) ClaimForAccept(ctx context.Context, id uuid.UUID, at time.Time) error {
+	pr, ok := r.requests[id]
+	if !ok {
+		return apperr.ErrPaymentRequestNotFound
+	}
+	if pr.Status != domain.PaymentRequestPending && (pr.Status != domain.PaymentRequestProcessing || pr.ReviewID != nil) {
+		return apperr.ErrPaymentRequestNotPending
+	}
+	pr.Status = domain.PaymentRequestProcessing
+	pr.UpdatedAt = at
+	return nil
+}
+
+// failingPaidRepo fails the first move to paid, as if the database went away right after the
+// transfer committed.
+type failingPaidRepo struct {
+	*memoryPaymentRequestRepo
+	failed bool
+}
+
+func (r *failingPaidRepo) Transition(ctx context.Context, id uuid.UUID, from []domain.PaymentRequestStatus, status domain.PaymentRequestStatus, transactionID *uuid.UUID, at time.Time) error {
+	if status == domain.PaymentRequestPaid && !r.failed {
+		r.failed = true
+		return errors.New("connection reset")
+	}
+	return r.memoryPaymentRequestRepo.Transition(ctx, id, from, status, transactionID, at)
+}
+
+func TestPaymentRequestService(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	requester := &domain.User{ID: uuid.New(), Email: "alice@example.com"}
+	payer := &domain.User{ID: uuid.New(), Email: "bob@example.com"}
+
+	newServiceWithRepo := func(booker *fakeBooker, repo PaymentRequestRepo) *PaymentRequestService {
+		users := &memoryUserRepo{users: map[uuid.UUID]*domain.User{requester.ID: requester, payer.ID: payer}}
+		currencies := domain.NewCurrencyRegistry([]domain.CurrencyInfo{{Code: domain.CurrencyUSD, MinorUnits: 2, Enabled: true}})
+		s := NewPaymentRequestService(repo, nil, users, booker, currencies, 24*time.Hour, logger)
+		s.now = func() time.Time { return now }
+		return s
+	}
+	newService := func(booker *fakeBooker) (*PaymentRequestService, *memoryPaymentRequestRepo) {
+		repo := &memoryPaymentRequestRepo{requests: map[uuid.UUID]*domain.PaymentRequest{}}
+		return newServiceWithRepo(booker, repo), repo
+	}
+	pending := func(repo *memoryPaymentRequestRepo, expiresAt time.Time) *domain.PaymentRequest {
+		pr := &domain.PaymentRequest{
+			ID:          uuid.New(),
+			RequesterID: requester.ID,
+			PayerID:     payer.ID,
+			Currency:    domain.CurrencyUSD,
+			AmountCents: 25_00,
+			Status:      domain.PaymentRequestPending,
+			ExpiresAt:   expiresAt,
+		}
+		repo.requests[pr.ID] = pr
+		return pr
+	}
+
+	t.Run("create_validation", func(t *testing.T) {
+		s, _ := newService(&fakeBooker{})
+		for name, tc := range map[string]struct {
+			in   domain.CreatePaymentRequestInput
+			want error
+		}{
+			"zero_amount":   {in: domain.CreatePaymentRequestInput{PayerEmail: payer.Email, Currency: domain.CurrencyUSD}},
+			"disabled":      {in: domain.CreatePaymentRequestInput{PayerEmail: payer.Email, Currency: "GBP", AmountCents: 1}, want: apperr.ErrInvalidCurrency},
+			"unknown_payer": {in: domain.CreatePaymentRequestInput{PayerEmail: "carol@example.com", Currency: domain.CurrencyUSD, AmountCents: 1}, want: apperr.ErrUserNotFound},
+			"self":          {in: domain.CreatePaymentRequestInput{PayerEmail: " Alice@Example.com ", Currency: domain.CurrencyUSD, AmountCents: 1}},
+			"long_memo":     {in: domain.CreatePaymentRequestInput{PayerEmail: payer.Email, Currency: domain.CurrencyUSD, AmountCents: 1, Memo: string(make([]byte, 141))}},
+			"empty_payer":   {in: domain.CreatePaymentRequestInput{PayerEmail: "  ", Currency: domain.CurrencyUSD, AmountCents: 1}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := s.Create(ctx, requester.ID, &tc.in)
+				if tc.want != nil {
+					if !errors.Is(err, tc.want) {
+						t.Fatalf("err=%v want %v", err, tc.want)
+					}
+					return
+				}
+				var se *apperr.PublicError
+				if !errors.As(err, &se) || se.Status != 400 {
+					t.Fatalf("err=%v want bad request", err)
+				}
+			})
+		}
+	})
+
+	t.Run("accept_pays_requester", func(t *testing.T) {
+		booker := &fakeBooker{}
+		s, repo := newService(booker)
+		pr := pending(repo, now.Add(time.Hour))
+
+		got, info, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID, MFACode: "123456"})
+		if err != nil {
+			t.Fatalf("Accept: %v", err)
+		}
+		in := booker.transfers[0]
+		if *in.ToUserID != requester.ID || in.AmountCents != pr.AmountCents || in.IdempotencyKey != "payment-request:"+pr.ID.String() || in.MFACode != "123456" {
+			t.Fatalf("transfer=%+v", in)
+		}
+		stored := repo.requests[pr.ID]
+		if stored.Status != domain.PaymentRequestPaid || *stored.TransactionID != info.ID || got.Status != domain.PaymentRequestPaid {
+			t.Fatalf("stored=%+v", stored)
+		}
+		if _, _, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); !errors.Is(err, apperr.ErrPaymentRequestNotPending) {
+			t.Fatalf("second accept err=%v", err)
+		}
+	})
+
+	t.Run("transfer_failure_returns_to_pending", func(t *testing.T) {
+		s, repo := newService(&fakeBooker{err: apperr.ErrInsufficientFunds})
+		pr := pending(repo, now.Add(time.Hour))
+
+		if _, _, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); !errors.Is(err, apperr.ErrInsufficientFunds) {
+			t.Fatalf("err=%v", err)
+		}
+		if got := repo.requests[pr.ID].Status; got != domain.PaymentRequestPending {
+			t.Fatalf("status=%s want pending", got)
+		}
+	})
+
+	t.Run("failure_after_transfer_reclaimed", func(t *testing.T) {
+		booker := &fakeBooker{booked: map[string]uuid.UUID{}}
+		repo := &memoryPaymentRequestRepo{requests: map[uuid.UUID]*domain.PaymentRequest{}}
+		s := newServiceWithRepo(booker, &failingPaidRepo{memoryPaymentRequestRepo: repo})
+		pr := pending(repo, now.Add(time.Hour))
+
+		if _, _, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); err == nil {
+			t.Fatal("Accept succeeded although marking the request paid failed")
+		}
+		if stored := repo.requests[pr.ID]; stored.Status != domain.PaymentRequestProcessing || stored.ReviewID != nil {
+			t.Fatalf("stored=%+v want processing without review", stored)
+		}
+		// The payer cannot decline a request whose transfer may have been booked.
+		if _, err := s.Decline(ctx, payer.ID, pr.ID); !errors.Is(err, apperr.ErrPaymentRequestNotPending) {
+			t.Fatalf("decline err=%v", err)
+		}
+
+		got, info, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID})
+		if err != nil {
+			t.Fatalf("retry Accept: %v", err)
+		}
+		if len(booker.transfers) != 2 || booker.transfers[0].IdempotencyKey != booker.transfers[1].IdempotencyKey {
+			t.Fatalf("transfers=%+v want the same key twice", booker.transfers)
+		}
+		if info.ID != booker.booked["payment-request:"+pr.ID.String()] {
+			t.Fatalf("retry booked %s, not the replayed transfer", info.ID)
+		}
+		if stored := repo.requests[pr.ID]; stored.Status != domain.PaymentRequestPaid || *stored.TransactionID != info.ID || got.Status != domain.PaymentRequestPaid {
+			t.Fatalf("stored=%+v", stored)
+		}
+	})
+
+	t.Run("key_conflict_keeps_processing", func(t *testing.T) {
+		s, repo := newService(&fakeBooker{err: apperr.ErrIdempotencyKeyConflict})
+		pr := pending(repo, now.Add(time.Hour))
+		pr.Status = domain.PaymentRequestProcessing
+
+		if _, _, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); !errors.Is(err, apperr.ErrIdempotencyKeyConflict) {
+			t.Fatalf("err=%v", err)
+		}
+		if got := repo.requests[pr.ID].Status; got != domain.PaymentRequestProcessing {
+			t.Fatalf("status=%s want processing", got)
+		}
+	})
+
+	t.Run("held_transfer_settled_by_review", func(t *testing.T) {
+		for _, approve := range []bool{true, false} {
+			booker := &fakeBooker{}
+			s, repo := newService(booker)
+			pr := pending(repo, now.Add(time.Hour))
+			review := &domain.RiskReview{ID: uuid.New()}
+			booker.err = &apperr.HeldForReviewError{ReviewID: review.ID.String()}
+
+			if _, _, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); !errors.Is(err, apperr.ErrHeldForReview) {
+				t.Fatalf("err=%v", err)
+			}
+			if stored := repo.requests[pr.ID]; stored.Status != domain.PaymentRequestProcessing || *stored.ReviewID != review.ID {
+				t.Fatalf("stored=%+v", stored)
+			}
+			// While the review is pending the request cannot be accepted again.
+			if _, _, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); !errors.Is(err, apperr.ErrPaymentRequestNotPending) {
+				t.Fatalf("second accept err=%v", err)
+			}
+
+			if approve {
+				txID := uuid.New()
+				if err := s.ReviewApproved(ctx, review, txID); err != nil {
+					t.Fatalf("ReviewApproved: %v", err)
+				}
+				if stored := repo.requests[pr.ID]; stored.Status != domain.PaymentRequestPaid || *stored.TransactionID != txID {
+					t.Fatalf("stored=%+v", stored)
+				}
+				continue
+			}
+			if err := s.ReviewRejected(ctx, review); err != nil {
+				t.Fatalf("ReviewRejected: %v", err)
+			}
+			if stored := repo.requests[pr.ID]; stored.Status != domain.PaymentRequestPending || stored.ReviewID != nil {
+				t.Fatalf("stored=%+v", stored)
+			}
+		}
+	})
+
+	t.Run("only_parties_act", func(t *testing.T) {
+		booker := &fakeBooker{}
+		s, repo := newService(booker)
+		pr := pending(repo, now.Add(time.Hour))
+
+		if _, _, err := s.Accept(ctx, requester.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); !errors.Is(err, apperr.ErrUnauthorized) {
+			t.Fatalf("requester accept err=%v", err)
+		}
+		if _, _, err := s.Accept(ctx, uuid.New(), &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); !errors.Is(err, apperr.ErrPaymentRequestNotFound) {
+			t.Fatalf("stranger accept err=%v", err)
+		}
+		if _, err := s.Decline(ctx, requester.ID, pr.ID); !errors.Is(err, apperr.ErrUnauthorized) {
+			t.Fatalf("requester decline err=%v", err)
+		}
+		if _, err := s.Cancel(ctx, payer.ID, pr.ID); !errors.Is(err, apperr.ErrUnauthorized) {
+			t.Fatalf("payer cancel err=%v", err)
+		}
+		if len(booker.transfers) != 0 {
+			t.Fatal("transfer booked")
+		}
+	})
+
+	t.Run("decline_and_cancel", func(t *testing.T) {
+		s, repo := newService(&fakeBooker{})
+		declined, cancelled := pending(repo, now.Add(time.Hour)), pending(repo, now.Add(time.Hour))
+
+		if pr, err := s.Decline(ctx, payer.ID, declined.ID); err != nil || pr.Status != domain.PaymentRequestDeclined {
+			t.Fatalf("Decline: %v", err)
+		}
+		if pr, err := s.Cancel(ctx, requester.ID, cancelled.ID); err != nil || pr.Status != domain.PaymentRequestCancelled {
+			t.Fatalf("Cancel: %v", err)
+		}
+		if _, _, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: declined.ID}); !errors.Is(err, apperr.ErrPaymentRequestNotPending) {
+			t.Fatalf("accept declined err=%v", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		booker := &fakeBooker{}
+		s, repo := newService(booker)
+		pr := pending(repo, now)
+
+		items, err := s.List(ctx, payer.ID, &domain.PaymentRequestFilter{})
+		if err != nil || len(items) != 1 || items[0].Status != domain.PaymentRequestExpired {
+			t.Fatalf("List: %v %+v", err, items)
+		}
+		if _, _, err := s.Accept(ctx, payer.ID, &domain.AcceptPaymentRequestInput{RequestID: pr.ID}); !errors.Is(err, apperr.ErrPaymentRequestNotPending) {
+			t.Fatalf("err=%v", err)
+		}
+		if got := repo.requests[pr.ID].Status; got != domain.PaymentRequestExpired {
+			t.Fatalf("status=%s want expired", got)
+		}
+		if len(booker.transfers) != 0 {
+			t.Fatal("expired request was paid")
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"banking-platform/internal/domain"
+)
+
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+)
+
+// RateLimiter applies the generic cell rate algorithm (GCRA) on top of a RateLimitStore. GCRA is a
+// token bucket that needs a single timestamp per key, which keeps the shared Postgres store to one
+// upsert per request.
+type RateLimiter struct {
+	store    RateLimitStore
+	interval time.Duration
+	logger   *slog.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRateLimiter returns a limiter that deletes idle keys from store every cleanupInterval once started.
+func NewRateLimiter(store RateLimitStore, cleanupInterval time.Duration, logger *slog.Logger) *RateLimiter {
+	return &RateLimiter{store: store, interval: cleanupInterval, logger: logger}
+}
+
+// Allow counts one request against key.
+func (l *RateLimiter) Allow(ctx context.Context, key string, limit domain.RateLimit) (*domain.RateLimitDecision, error) {
+	now := time.Now()
+	emission := limit.Period / time.Duration(limit.Requests)
+	tolerance := emission * time.Duration(limit.Burst)
+
+	tat, allowed, err := l.store.Take(ctx, key, now, emission, tolerance)
+	if err != nil {
+		return nil, fmt.Errorf("rate_limiter.allow: %w", err)
+	}
+
+	d := &domain.RateLimitDecision{Allowed: allowed, Limit: limit.Burst, Reset: max(tat.Sub(now), 0)}
+	if allowed {
+		d.Remaining = int((tolerance - tat.Sub(now)) / emission)
+	} else {
+		d.RetryAfter = max(tat.Add(emission-tolerance).Sub(now), 0)
+	}
+	return d, nil
+}
+
+func (l *RateLimiter) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	l.done = make(chan struct{})
+
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				if err := l.store.DeleteExpired(ctx, now); err != nil {
+					l.logger.Error("Failed to delete expired rate limit keys", "error", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (l *RateLimiter) Stop(ctx context.Context) {
+	if l == nil || l.cancel == nil {
+		return
+	}
+	l.cancel()
+	select {
+	case <-l.done:
+	case <-ctx.Done():
+	}
+}
+
+// MemoryRateLimitStore keeps TATs in the process. Each replica then enforces the limits on its own;
+// use the Postgres store when running more than one.
+type MemoryRateLimitStore struct {
+	mu   sync.Mutex
+	tats map[string]time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{tats: make(map[string]time.Time)}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, now time.Time, interval time.Duration, tolerance time.Duration) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tat := s.tats[key]
+	if tat.Before(now) {
+		tat = now
+	}
+	next := tat.Add(interval)
+	if next.Add(-tolerance).After(now) {
+		return s.tats[key], false, nil
+	}
+	s.tats[key] = next
+	return next, true, nil
+}
+
+func (s *MemoryRateLimitStore) DeleteExpired(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, tat := range s.tats {
+		if tat.Before(before) {
+			delete(s.tats, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"banking-platform/internal/domain"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter := NewRateLimiter(NewMemoryRateLimitStore(), time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	// One request per hour keeps the clock from refilling the bucket during the test.
+	limit := domain.RateLimit{Requests: 1, Period: time.Hour, Burst: 3}
+
+	for i, wantRemaining := range []int{2, 1, 0} {
+		d, err := limiter.Allow(ctx, "user:a", limit)
+		if err != nil {
+			t.Fatalf("allow: %v", err)
+		}
+		if !d.Allowed || d.Limit != 3 || d.Remaining != wantRemaining {
+			t.Fatalf("request %d allowed=%v limit=%d remaining=%d want allowed, 3, %d", i+1, d.Allowed, d.Limit, d.Remaining, wantRemaining)
+		}
+	}
+
+	d, err := limiter.Allow(ctx, "user:a", limit)
+	if err != nil {
+		t.Fatalf("allow: %v", err)
+	}
+	if d.Allowed || d.Remaining != 0 {
+		t.Fatalf("over burst allowed=%v remaining=%d want refused", d.Allowed, d.Remaining)
+	}
+	if d.RetryAfter <= 59*time.Minute || d.RetryAfter > time.Hour {
+		t.Fatalf("retry after=%v want about one interval", d.RetryAfter)
+	}
+	if d.Reset <= 2*time.Hour+59*time.Minute || d.Reset > 3*time.Hour {
+		t.Fatalf("reset=%v want about three intervals", d.Reset)
+	}
+
+	if d, _ := limiter.Allow(ctx, "user:b", limit); !d.Allowed {
+		t.Fatalf("other key shares the bucket")
+	}
+}
+
+func TestMemoryRateLimitStoreDeleteExpired(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, ok, _ := store.Take(ctx, "k", now, time.Second, time.Second); !ok {
+		t.Fatalf("first take refused")
+	}
+	if _, ok, _ := store.Take(ctx, "k", now, time.Second, time.Second); ok {
+		t.Fatalf("second take within the interval allowed")
+	}
+	if err := store.DeleteExpired(ctx, now.Add(2*time.Second)); err != nil {
+		t.Fatalf("delete expired: %v", err)
+	}
+	if len(store.tats) != 0 {
+		t.Fatalf("keys left=%d want 0", len(store.tats))
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+)
+
+const (
+	RateSourceStatic = "static"
+	RateSourceDB     = "db"
+	RateSourceFile   = "file"
+)
+
+// RateProvider resolves the exchange rate to apply when converting from one currency to another.
+type RateProvider interface {
+	GetRate(ctx context.Context, from domain.Currency, to domain.Currency) (*domain.ExchangeRate, error)
+}
+
+// StaticRateProvider serves the single USD->EUR rate configured via EXCHANGE_RATE_USD_TO_EUR.
+type StaticRateProvider struct {
+	rate domain.ExchangeRate
+}
+
+func NewStaticRateProvider(usdToEUR string) *StaticRateProvider {
+	num, den := parseRateToFraction(usdToEUR)
+	return &StaticRateProvider{
+		rate: domain.ExchangeRate{
+			Base:    domain.CurrencyUSD,
+			Quote:   domain.CurrencyEUR,
+			Num:     num,
+			Den:     den,
+			Source:  RateSourceStatic,
+			Version: strconv.FormatInt(num, 10) + "/" + strconv.FormatInt(den, 10),
+		},
+	}
+}
+
+func (p *StaticRateProvider) GetRate(ctx context.Context, from domain.Currency, to domain.Currency) (*domain.ExchangeRate, error) {
+	return lookupRate([]domain.ExchangeRate{p.rate}, from, to)
+}
+
+// DBRateProvider reads rates from the exchange_rates table, honouring validity windows.
+type DBRateProvider struct {
+	repo ExchangeRateRepo
+}
+
+func NewDBRateProvider(repo ExchangeRateRepo) *DBRateProvider {
+	return &DBRateProvider{repo: repo}
+}
+
+func (p *DBRateProvider) GetRate(ctx context.Context, from domain.Currency, to domain.Currency) (*domain.ExchangeRate, error) {
+	now := time.Now()
+	rate, err := p.repo.GetActive(ctx, from, to, now)
+	if err == nil {
+		return rate, nil
+	}
+	if !errors.Is(err, apperr.ErrRateUnavailable) {
+		return nil, fmt.Errorf("rate.db: %w", err)
+	}
+
+	rate, err = p.repo.GetActive(ctx, to, from, now)
+	if err != nil {
+		if errors.Is(err, apperr.ErrRateUnavailable) {
+			return nil, apperr.ErrRateUnavailable
+		}
+		return nil, fmt.Errorf("rate.db: %w", err)
+	}
+	inv := rate.Inverse()
+	return &inv, nil
+}
+
+// CrossRateProvider derives rates for pairs without a direct quote by chaining from->pivot->to.
+type CrossRateProvider struct {
+	next  RateProvider
+	pivot domain.Currency
+}
+
+func NewCrossRateProvider(next RateProvider, pivot domain.Currency) *CrossRateProvider {
+	return &CrossRateProvider{next: next, pivot: pivot}
+}
+
+func (p *CrossRateProvider) GetRate(ctx context.Context, from domain.Currency, to domain.Currency) (*domain.ExchangeRate, error) {
+	rate, err := p.next.GetRate(ctx, from, to)
+	if err == nil || !errors.Is(err, apperr.ErrRateUnavailable) || from == p.pivot || to == p.pivot {
+		return rate, err
+	}
+
+	first, err := p.next.GetRate(ctx, from, p.pivot)
+	if err != nil {
+		return nil, err
+	}
+	second, err := p.next.GetRate(ctx, p.pivot, to)
+	if err != nil {
+		return nil, err
+	}
+	return crossRate(first, second)
+}
+
+// crossRate multiplies two chained rates (a: X->P, b: P->Y) into X->Y, keeping the fraction within int64.
+func crossRate(a *domain.ExchangeRate, b *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	num := new(big.Int).Mul(big.NewInt(a.Num), big.NewInt(b.Num))
+	den := new(big.Int).Mul(big.NewInt(a.Den), big.NewInt(b.Den))
+	if gcd := new(big.Int).GCD(nil, nil, num, den); gcd.Sign() > 0 {
+		num.Quo(num, gcd)
+		den.Quo(den, gcd)
+	}
+	if !num.IsInt64() || !den.IsInt64() {
+		// Re-quote with a fixed precision; rounding half up.
+		scale := big.NewInt(10_000_000_000)
+		num.Mul(num, scale)
+		num.Add(num.Mul(num, big.NewInt(2)), den)
+		num.Quo(num, den.Mul(den, big.NewInt(2)))
+		den = scale
+		if !num.IsInt64() || num.Sign() <= 0 {
+			return nil, apperr.ErrRateUnavailable
+		}
+	}
+
+	source := a.Source
+	if b.Source != a.Source {
+		source = a.Source + "+" + b.Source
+	}
+	out := &domain.ExchangeRate{
+		Base:      a.Base,
+		Quote:     b.Quote,
+		Num:       num.Int64(),
+		Den:       den.Int64(),
+		Source:    source,
+		Version:   a.Version + "*" + b.Version,
+		ValidFrom: a.ValidFrom,
+		ValidTo:   a.ValidTo,
+	}
+	if b.ValidFrom.After(out.ValidFrom) {
+		out.ValidFrom = b.ValidFrom
+	}
+	if b.ValidTo != nil && (out.ValidTo == nil || b.ValidTo.Before(*out.ValidTo)) {
+		out.ValidTo = b.ValidTo
+	}
+	return out, nil
+}
+
+// lookupRate picks the rate for from->to out of a set, inverting a stored to->from rate if needed.
+func lookupRate(rates []domain.ExchangeRate, from domain.Currency, to domain.Currency) (*domain.ExchangeRate, error) {
+	for _, r := range rates {
+		if r.Base == from && r.Quote == to {
+			out := r
+			return &out, nil
+		}
+	}
+	for _, r := range rates {
+		if r.Base == to && r.Quote == from {
+			out := r.Inverse()
+			return &out, nil
+		}
+	}
+	return nil, apperr.ErrRateUnavailable
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"banking-platform/internal/domain"
+)
+
+// FileRateProvider serves rates from a local feed file that is re-read whenever it changes.
+// Supported formats are the ECB daily XML (EUR based, *.xml) and CSV lines "base,quote,rate".
+type FileRateProvider struct {
+	path     string
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu      sync.RWMutex
+	rates   []domain.ExchangeRate
+	modTime time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewFileRateProvider loads the feed once; an unreadable or empty feed is a startup error.
+func NewFileRateProvider(path string, interval time.Duration, logger *slog.Logger) (*FileRateProvider, error) {
+	p := &FileRateProvider{
+		path:     path,
+		interval: interval,
+		logger:   logger,
+	}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *FileRateProvider) GetRate(ctx context.Context, from domain.Currency, to domain.Currency) (*domain.ExchangeRate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return lookupRate(p.rates, from, to)
+}
+
+// Start polls the feed file in background until Stop is called.
+func (p *FileRateProvider) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	go func() {
+		defer close(p.done)
+		p.logger.Info("Exchange rate file polling started", "path", p.path, "interval", p.interval.String())
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := p.reloadIfChanged(); err != nil {
+					p.logger.Error("Exchange rate file reload failed; keeping previous rates", "error", err, "path", p.path)
+				}
+			case <-ctx.Done():
+				p.logger.Info("Exchange rate file polling stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the poller to stop and waits until it finishes (or ctx is done).
+func (p *FileRateProvider) Stop(ctx context.Context) {
+	if p == nil || p.cancel == nil {
+		return
+	}
+	p.cancel()
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+	}
+}
+
+func (p *FileRateProvider) reloadIfChanged() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	p.mu.RLock()
+	unchanged := info.ModTime().Equal(p.modTime)
+	p.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+	return p.reload()
+}
+
+func (p *FileRateProvider) reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("rate.file: stat: %w", err)
+	}
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("rate.file: read: %w", err)
+	}
+
+	var rates []domain.ExchangeRate
+	if strings.EqualFold(filepath.Ext(p.path), ".xml") {
+		rates, err = parseECBRates(data)
+	} else {
+		rates, err = parseCSVRates(data)
+	}
+	if err != nil {
+		return fmt.Errorf("rate.file: parse %s: %w", p.path, err)
+	}
+	if len(rates) == 0 {
+		return fmt.Errorf("rate.file: no rates in %s", p.path)
+	}
+
+	p.mu.Lock()
+	p.rates = rates
+	p.modTime = info.ModTime()
+	p.mu.Unlock()
+
+	p.logger.Info("Exchange rates loaded from file", "path", p.path, "count", len(rates), "version", rates[0].Version)
+	return nil
+}
+
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+func parseECBRates(data []byte) ([]domain.ExchangeRate, error) {
+	var env ecbEnvelope
+	if err := xml.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if len(env.Cube.Days) == 0 {
+		return nil, fmt.Errorf("no rate cube found")
+	}
+
+	// The ECB feed lists the most recent day first.
+	day := env.Cube.Days[0]
+	version := day.Time
+	if version == "" {
+		version = contentVersion(data)
+	}
+
+	out := make([]domain.ExchangeRate, 0, len(day.Rates))
+	for _, r := range day.Rates {
+		num, den, err := domain.ParseRateFraction(r.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("currency %s: %w", r.Currency, err)
+		}
+		out = append(out, domain.ExchangeRate{
+			Base:    domain.CurrencyEUR,
+			Quote:   domain.Currency(strings.ToUpper(strings.TrimSpace(r.Currency))),
+			Num:     num,
+			Den:     den,
+			Source:  RateSourceFile,
+			Version: version,
+		})
+	}
+	return out, nil
+}
+
+func parseCSVRates(data []byte) ([]domain.ExchangeRate, error) {
+	version := contentVersion(data)
+
+	var out []domain.ExchangeRate
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected base,quote,rate", lineNo)
+		}
+		base := strings.ToUpper(strings.TrimSpace(fields[0]))
+		quote := strings.ToUpper(strings.TrimSpace(fields[1]))
+		if lineNo == 1 && base == "BASE" {
+			continue
+		}
+		num, den, err := domain.ParseRateFraction(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		out = append(out, domain.ExchangeRate{
+			Base:    domain.Currency(base),
+			Quote:   domain.Currency(quote),
+			Num:     num,
+			Den:     den,
+			Source:  RateSourceFile,
+			Version: version,
+		})
+	}
+	return out, scanner.Err()
+}
+
+func contentVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+)
+
+func TestStaticRateProvider(t *testing.T) {
+	p := NewStaticRateProvider("0.92")
+
+	testCases := []struct {
+		name    string
+		from    domain.Currency
+		to      domain.Currency
+		wantNum int64
+		wantDen int64
+		wantErr error
+	}{
+		{name: "direct", from: domain.CurrencyUSD, to: domain.CurrencyEUR, wantNum: 920000, wantDen: 1000000},
+		{name: "inverse", from: domain.CurrencyEUR, to: domain.CurrencyUSD, wantNum: 1000000, wantDen: 920000},
+		{name: "unknown_pair", from: domain.CurrencyUSD, to: "GBP", wantErr: apperr.ErrRateUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.GetRate(context.Background(), tc.from, tc.to)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err=%v want=%v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err=%v", err)
+			}
+			if got.Base != tc.from || got.Quote != tc.to || got.Num != tc.wantNum || got.Den != tc.wantDen {
+				t.Fatalf("got=%+v want=%s->%s %d/%d", got, tc.from, tc.to, tc.wantNum, tc.wantDen)
+			}
+			if got.Source != RateSourceStatic || got.Version == "" {
+				t.Fatalf("source=%q version=%q", got.Source, got.Version)
+			}
+		})
+	}
+}
+
+func TestParseECBRates(t *testing.T) {
+	feed := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2024-01-05">
+			<Cube currency="USD" rate="1.0921"/>
+			<Cube currency="JPY" rate="158.08"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`)
+
+	rates, err := parseECBRates(feed)
+	if err != nil {
+		t.Fatalf("err=%v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("len=%d want=2", len(rates))
+	}
+	usd := rates[0]
+	if usd.Base != domain.CurrencyEUR || usd.Quote != domain.CurrencyUSD || usd.Num != 10921 || usd.Den != 10000 {
+		t.Fatalf("usd=%+v", usd)
+	}
+	if usd.Version != "2024-01-05" || usd.Source != RateSourceFile {
+		t.Fatalf("version=%q source=%q", usd.Version, usd.Source)
+	}
+
+	got, err := lookupRate(rates, domain.CurrencyUSD, domain.CurrencyEUR)
+	if err != nil {
+		t.Fatalf("lookup err=%v", err)
+	}
+	if got.Num != 10000 || got.Den != 10921 {
+		t.Fatalf("inverse=%d/%d want=10000/10921", got.Num, got.Den)
+	}
+}
+
+func TestParseCSVRates(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{name: "with_header_and_comments", in: "base,quote,rate\n# comment\nUSD,EUR,0.92\n\nGBP,USD,1.27\n", want: 2},
+		{name: "lowercase_codes", in: "usd,eur,0.92\n", want: 1},
+		{name: "reject_missing_field", in: "USD,EUR\n", wantErr: true},
+		{name: "reject_bad_rate", in: "USD,EUR,abc\n", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rates, err := parseCSVRates([]byte(tc.in))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if len(rates) != tc.want {
+				t.Fatalf("len=%d want=%d", len(rates), tc.want)
+			}
+			if rates[0].Base != domain.CurrencyUSD || rates[0].Quote != domain.CurrencyEUR {
+				t.Fatalf("first=%+v", rates[0])
+			}
+		})
+	}
+}
+
+type staticRates []domain.ExchangeRate
+
+func (r staticRates) GetRate(ctx context.Context, from domain.Currency, to domain.Currency) (*domain.ExchangeRate, error) {
+	return lookupRate(r, from, to)
+}
+
+func TestCrossRateProvider(t *testing.T) {
+	p := NewCrossRateProvider(staticRates{
+		{Base: "EUR", Quote: "USD", Num: 11, Den: 10, Source: RateSourceFile, Version: "v1"},
+		{Base: "EUR", Quote: "JPY", Num: 160, Den: 1, Source: RateSourceFile, Version: "v1"},
+	}, domain.CurrencyEUR)
+
+	testCases := []struct {
+		name    string
+		from    domain.Currency
+		to      domain.Currency
+		wantNum int64
+		wantDen int64
+		wantErr error
+	}{
+		{name: "direct", from: "EUR", to: "USD", wantNum: 11, wantDen: 10},
+		{name: "cross_via_pivot", from: "USD", to: "JPY", wantNum: 1600, wantDen: 11},
+		{name: "cross_inverse", from: "JPY", to: "USD", wantNum: 11, wantDen: 1600},
+		{name: "missing_leg", from: "USD", to: "GBP", wantErr: apperr.ErrRateUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.GetRate(context.Background(), tc.from, tc.to)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err=%v want=%v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err=%v", err)
+			}
+			if got.Base != tc.from || got.Quote != tc.to || got.Num != tc.wantNum || got.Den != tc.wantDen {
+				t.Fatalf("got=%+v want=%s->%s %d/%d", got, tc.from, tc.to, tc.wantNum, tc.wantDen)
+			}
+		})
+	}
+}
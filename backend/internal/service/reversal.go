@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+const maxReversalReasonLength = 500
+
+// Reverse books a compensating transfer from the recipient of a transfer back to its sender.
+// Partial refunds are allowed until the whole original amount has been reversed.
+func (s *TransactionService) Reverse(ctx context.Context, actorID uuid.UUID, in *domain.ReverseInput) (*domain.TransactionInfo, error) {
+	s.logger.Info("Processing reversal", "transaction_id", in.TransactionID, "actor_id", actorID, "as_operator", in.AsOperator)
+
+	if in.AmountCents != nil && *in.AmountCents <= 0 {
+		return nil, apperr.BadRequest("amount_cents must be greater than 0")
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if len(reason) > maxReversalReasonLength {
+		return nil, apperr.BadRequest("reason must be at most 500 characters")
+	}
+	if err := validateIdempotencyKey(in.IdempotencyKey); err != nil {
+		return nil, err
+	}
+
+	requested := ""
+	if in.AmountCents != nil {
+		requested = strconv.FormatInt(*in.AmountCents, 10)
+	}
+	fingerprint := requestFingerprint(domain.IdempotencyScopeReversal, in.TransactionID.String(), requested)
+
+	var created *domain.Transaction
+	var replayID uuid.UUID
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		var err error
+		replayID, err = s.reserveIdempotencyKeyTx(ctx, tx, actorID, domain.IdempotencyScopeReversal, in.IdempotencyKey, fingerprint)
+		if err != nil {
+			return fmt.Errorf("transaction.reverse: %w", err)
+		}
+		if replayID != uuid.Nil {
+			return nil
+		}
+
+		// Locking the original serializes concurrent reversals of it.
+		original, err := s.transactionRepo.LockByIDTx(ctx, tx, in.TransactionID)
+		if err != nil {
+			return fmt.Errorf("transaction.reverse: lock original: %w", err)
+		}
+		// Others get the same answer as for a missing transaction, whatever its type or state.
+		if !in.AsOperator {
+			party, err := s.isParty(ctx, actorID, original)
+			if err != nil {
+				return fmt.Errorf("transaction.reverse: %w", err)
+			}
+			if !party {
+				return apperr.ErrTransactionNotFound
+			}
+		}
+		// Pending and voided authorizations never moved money; they are released, not reversed.
+		if original.Type != domain.TransactionTypeTransfer || original.FromAccountID == nil || original.Status != domain.TransactionStatusPosted {
+			return apperr.ErrTransactionNotReversible
+		}
+
+		reversed, err := s.transactionRepo.SumReversalsTx(ctx, tx, original.ID, original.Currency)
+		if err != nil {
+			return fmt.Errorf("transaction.reverse: sum reversals: %w", err)
+		}
+		remaining := original.PostedAmountCents() - reversed
+		if remaining <= 0 {
+			return apperr.ErrTransactionAlreadyReversed
+		}
+		amountCents := remaining
+		if in.AmountCents != nil {
+			if *in.AmountCents > remaining {
+				return apperr.ErrReversalExceedsRemaining
+			}
+			amountCents = *in.AmountCents
+		}
+
+		// Money flows back: the original recipient pays, the original sender receives.
+		payerAccountID := original.ToAccountID
+		payeeAccountID := *original.FromAccountID
+
+		// Lock deterministically to avoid deadlocks.
+		lockIDs := []uuid.UUID{payerAccountID, payeeAccountID}
+		sort.Slice(lockIDs, func(i, j int) bool { return lockIDs[i].String() < lockIDs[j].String() })
+
+		locked := make(map[uuid.UUID]*domain.Account, 2)
+		for _, id := range lockIDs {
+			acc, err := s.accountRepo.LockAccountForUpdate(ctx, tx, id)
+			if err != nil {
+				return fmt.Errorf("transaction.reverse: lock account: %w", err)
+			}
+			locked[id] = acc
+		}
+
+		payer := locked[payerAccountID]
+		payee := locked[payeeAccountID]
+		if payer == nil || payee == nil {
+			return fmt.Errorf("transaction.reverse: failed to lock accounts")
+		}
+
+		if !in.AsOperator && payer.UserID != actorID {
+			// The sender knows the transaction exists but cannot pull the money back.
+			return apperr.ErrUnauthorized
+		}
+		if !in.AsOperator {
+			// Money paid out by the bank, such as initial funding, is not returned to its internal
+			// accounts by the recipient.
+			sender, err := s.userRepo.GetByID(ctx, payee.UserID)
+			if err != nil {
+				return fmt.Errorf("transaction.reverse: get original sender: %w", err)
+			}
+			if sender.Role == domain.RoleSystem {
+				return apperr.ErrBankTransferNotReversible
+			}
+		}
+		if err := ensureAccountActive(payer); err != nil {
+			return err
+		}
+		if err := ensureAccountActive(payee); err != nil {
+			return err
+		}
+		if payer.AvailableCents() < amountCents {
+			s.logger.Warn("Insufficient funds for reversal", "account_id", payer.ID, "balance_cents", payer.BalanceCents, "held_cents", payer.HeldCents, "amount_cents", amountCents)
+			return apperr.ErrInsufficientFunds
+		}
+
+		transactionID := uuid.New()
+		createdAt := time.Now()
+		description := fmt.Sprintf("Reversal of %s: %s %s", original.ID, original.Currency, s.currencies.Format(original.Currency, amountCents))
+		if reason != "" {
+			description += " (" + reason + ")"
+		}
+		originalID := original.ID
+		created = &domain.Transaction{
+			ID:                    transactionID,
+			Type:                  domain.TransactionTypeReversal,
+			FromAccountID:         &payer.ID,
+			ToAccountID:           payee.ID,
+			AmountCents:           amountCents,
+			Currency:              original.Currency,
+			ToCurrency:            original.Currency,
+			ReversesTransactionID: &originalID,
+			Description:           description,
+			Status:                domain.TransactionStatusPosted,
+			CreatedAt:             createdAt,
+		}
+		if err := s.transactionRepo.Create(ctx, tx, created); err != nil {
+			return fmt.Errorf("transaction.reverse: create transaction: %w", err)
+		}
+
+		for _, e := range []*domain.LedgerEntry{
+			{ID: uuid.New(), TransactionID: transactionID, AccountID: payer.ID, Currency: original.Currency, AmountCents: -amountCents, CreatedAt: createdAt},
+			{ID: uuid.New(), TransactionID: transactionID, AccountID: payee.ID, Currency: original.Currency, AmountCents: amountCents, CreatedAt: createdAt},
+		} {
+			if err := s.ledgerRepo.CreateEntry(ctx, tx, e); err != nil {
+				return fmt.Errorf("transaction.reverse: create ledger entry: %w", err)
+			}
+		}
+
+		if err := s.ledgerRepo.VerifyTransactionBalanceTx(ctx, tx, transactionID); err != nil {
+			s.logger.Error("Ledger not balanced (reversal)", "error", err, "transaction_id", transactionID)
+			return err
+		}
+
+		if err := s.accountRepo.UpdateBalanceString(ctx, tx, payer.ID, s.currencies.Format(original.Currency, payer.BalanceCents-amountCents)); err != nil {
+			return fmt.Errorf("transaction.reverse: update payer balance: %w", err)
+		}
+		if err := s.accountRepo.UpdateBalanceString(ctx, tx, payee.ID, s.currencies.Format(original.Currency, payee.BalanceCents+amountCents)); err != nil {
+			return fmt.Errorf("transaction.reverse: update payee balance: %w", err)
+		}
+
+		if in.IdempotencyKey != "" {
+			if err := s.idempotencyRepo.CompleteTx(ctx, tx, actorID, domain.IdempotencyScopeReversal, in.IdempotencyKey, transactionID); err != nil {
+				return fmt.Errorf("transaction.reverse: complete idempotency key: %w", err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if replayID != uuid.Nil {
+		s.logger.Info("Reversal replayed by idempotency key", "transaction_id", replayID, "actor_id", actorID)
+		return s.getTransactionInfo(ctx, replayID)
+	}
+
+	s.logger.Info("Reversal completed", "transaction_id", created.ID, "original_transaction_id", in.TransactionID, "actor_id", actorID, "amount_cents", created.AmountCents)
+	info, err := s.getTransactionInfo(ctx, created.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Operator reversals are audited by AdminService together with the operator's reason.
+	if !in.AsOperator {
+		s.recordMovement(ctx, AuditActionReversal, actorID, info)
+	}
+	return info, nil
+}
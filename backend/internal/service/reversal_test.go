@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+// lockedTransactionRepo serves the transactions a test locks; nothing else is expected to be called.
+type lockedTransactionRepo struct {
+	TransactionRepo
+	transactions map[uuid.UUID]*domain.Transaction
+	reversed     int64
+}
+
+func (r *lockedTransactionRepo) LockByIDTx(ctx context.Context, tx Tx, id uuid.UUID) (*domain.Transaction, error) {
+	t, ok := r.transactions[id]
+	if !ok {
+		return nil, apperr.ErrTransactionNotFound
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (r *lockedTransactionRepo) SumReversalsTx(ctx context.Context, tx Tx, id uuid.UUID, currency domain.Currency) (int64, error) {
+	return r.reversed, nil
+}
+
+// memoryAccountRepo looks accounts up by id; nothing else is expected to be called.
+type memoryAccountRepo struct {
+	AccountRepo
+	accounts map[uuid.UUID]*domain.Account
+}
+
+func (r *memoryAccountRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Account, error) {
+	acc, ok := r.accounts[id]
+	if !ok {
+		return nil, apperr.ErrAccountNotFound
+	}
+	copied := *acc
+	return &copied, nil
+}
+
+func TestReverseHidesTransactionsFromNonParties(t *testing.T) {
+	ctx := context.Background()
+	sender, recipient := uuid.New(), uuid.New()
+	from := &domain.Account{ID: uuid.New(), UserID: sender, Currency: domain.CurrencyUSD}
+	to := &domain.Account{ID: uuid.New(), UserID: recipient, Currency: domain.CurrencyUSD}
+	expires := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		tx       domain.Transaction
+		reversed int64
+		want     error
+	}{
+		{name: "pending", tx: domain.Transaction{Status: domain.TransactionStatusPending, ExpiresAt: &expires}, want: apperr.ErrTransactionNotReversible},
+		{name: "exchange", tx: domain.Transaction{Type: domain.TransactionTypeExchange, Status: domain.TransactionStatusPosted}, want: apperr.ErrTransactionNotReversible},
+		{name: "fully_reversed", tx: domain.Transaction{Status: domain.TransactionStatusPosted}, reversed: 25_00, want: apperr.ErrTransactionAlreadyReversed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.tx
+			original.ID = uuid.New()
+			if original.Type == "" {
+				original.Type = domain.TransactionTypeTransfer
+			}
+			original.FromAccountID = &from.ID
+			original.ToAccountID = to.ID
+			original.Currency = domain.CurrencyUSD
+			original.AmountCents = 25_00
+
+			s := &TransactionService{
+				accountRepo:     &memoryAccountRepo{accounts: map[uuid.UUID]*domain.Account{from.ID: from, to.ID: to}},
+				transactionRepo: &lockedTransactionRepo{transactions: map[uuid.UUID]*domain.Transaction{original.ID: &original}, reversed: tt.reversed},
+				idempotencyRepo: &memoryIdempotencyRepo{keys: map[string]*domain.IdempotencyKey{}},
+				txRunner:        inlineTxRunner{},
+				logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+
+			if _, err := s.Reverse(ctx, uuid.New(), &domain.ReverseInput{TransactionID: original.ID}); !errors.Is(err, apperr.ErrTransactionNotFound) {
+				t.Fatalf("stranger err=%v want not found", err)
+			}
+			if _, err := s.Reverse(ctx, recipient, &domain.ReverseInput{TransactionID: original.ID}); !errors.Is(err, tt.want) {
+				t.Fatalf("recipient err=%v want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	maxRiskReviewNoteLength = 500
+	riskReviewListLimit     = 200
+)
+
+// HeldMovementBooker books approved reviews. TransactionService implements it.
+type HeldMovementBooker interface {
+	Transfer(ctx context.Context, fromUserID uuid.UUID, in *domain.TransferInput) (*domain.TransactionInfo, error)
+	Exchange(ctx context.Context, userID uuid.UUID, in *domain.ExchangeInput) (*domain.TransactionInfo, error)
+	ExecuteQuote(ctx context.Context, userID uuid.UUID, in *domain.ExecuteQuoteInput) (*domain.TransactionInfo, error)
+}
+
+// ReviewSettler is told how a review was decided, so whatever waits on the held movement can settle.
+// PaymentRequestService implements it.
+type ReviewSettler interface {
+	ReviewApproved(ctx context.Context, review *domain.RiskReview, transactionID uuid.UUID) error
+	ReviewRejected(ctx context.Context, review *domain.RiskReview) error
+}
+
+// RiskReviewQueue holds transfers and exchanges a RiskEngine did not let through, until an operator
+// approves or rejects them.
+type RiskReviewQueue struct {
+	repo    RiskReviewRepo
+	booker  HeldMovementBooker
+	settler ReviewSettler
+	logger  *slog.Logger
+}
+
+func NewRiskReviewQueue(repo RiskReviewRepo, booker HeldMovementBooker, settler ReviewSettler, logger *slog.Logger) *RiskReviewQueue {
+	return &RiskReviewQueue{repo: repo, booker: booker, settler: settler, logger: logger}
+}
+
+// List returns up to 200 reviews with the status, oldest first; an empty status lists all.
+func (q *RiskReviewQueue) List(ctx context.Context, status domain.RiskReviewStatus) ([]*domain.RiskReview, error) {
+	switch status {
+	case "", domain.RiskReviewPending, domain.RiskReviewProcessing, domain.RiskReviewApproved, domain.RiskReviewRejected:
+	default:
+		return nil, apperr.BadRequest("status must be one of pending, processing, approved, rejected")
+	}
+	reviews, err := q.repo.List(ctx, status, riskReviewListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("risk_review.list: %w", err)
+	}
+	return reviews, nil
+}
+
+// Approve books the held movement as its owner would have, with the current balances, limits and,
+// for exchanges, rate; a held quote execution keeps the quoted rate. The replay uses the original idempotency key, or one derived from the review,
+// so approving twice books once. If booking fails the review goes back to pending and the error is
+// returned, so the operator can retry or reject it.
+func (q *RiskReviewQueue) Approve(ctx context.Context, reviewerID uuid.UUID, id uuid.UUID, note string) (*domain.RiskReview, *domain.TransactionInfo, error) {
+	note, err := reviewNote(note)
+	if err != nil {
+		return nil, nil, err
+	}
+	review, err := q.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("risk_review.approve: %w", err)
+	}
+
+	// A review left in processing by a failed request can be approved again; booking is idempotent.
+	now := time.Now().UTC()
+	claimable := []domain.RiskReviewStatus{domain.RiskReviewPending, domain.RiskReviewProcessing}
+	if err := q.repo.Transition(ctx, id, claimable, domain.RiskReviewProcessing, &reviewerID, note, nil, &now); err != nil {
+		return nil, nil, fmt.Errorf("risk_review.approve: %w", err)
+	}
+
+	info, err := q.book(ctx, review)
+	if err != nil {
+		if rerr := q.repo.Transition(ctx, id, []domain.RiskReviewStatus{domain.RiskReviewProcessing}, domain.RiskReviewPending, nil, "", nil, nil); rerr != nil {
+			q.logger.Error("Failed to return risk review to pending", "review_id", id, "error", rerr)
+		}
+		return nil, nil, fmt.Errorf("risk_review.approve: %w", err)
+	}
+	if q.settler != nil {
+		// On failure the review stays in processing; approving again replays the booking and settles.
+		if err := q.settler.ReviewApproved(ctx, review, info.ID); err != nil {
+			return nil, nil, fmt.Errorf("risk_review.approve: settle: %w", err)
+		}
+	}
+
+	if err := q.repo.Transition(ctx, id, claimable, domain.RiskReviewApproved, &reviewerID, note, &info.ID, &now); err != nil {
+		return nil, nil, fmt.Errorf("risk_review.approve: %w", err)
+	}
+	review.Status = domain.RiskReviewApproved
+	review.ReviewerID = &reviewerID
+	review.ReviewNote = note
+	review.TransactionID = &info.ID
+	review.ReviewedAt = &now
+
+	q.logger.Info("Risk review approved", "review_id", id, "transaction_id", info.ID, "reviewer_id", reviewerID)
+	return review, info, nil
+}
+
+func (q *RiskReviewQueue) book(ctx context.Context, review *domain.RiskReview) (*domain.TransactionInfo, error) {
+	key := review.IdempotencyKey
+	if key == "" {
+		key = "risk-review:" + review.ID.String()
+	}
+
+	switch {
+	case review.Transfer != nil:
+		in := *review.Transfer
+		in.IdempotencyKey = key
+		in.RiskReviewID = &review.ID
+		return q.booker.Transfer(ctx, review.UserID, &in)
+	case review.Exchange != nil && review.Exchange.QuoteID != nil:
+		// The review is keyed by quote; the replay uses the client's key so its retry gets the result.
+		key := review.Exchange.IdempotencyKey
+		if key == "" {
+			key = "risk-review:" + review.ID.String()
+		}
+		return q.booker.ExecuteQuote(ctx, review.UserID, &domain.ExecuteQuoteInput{
+			QuoteID:        *review.Exchange.QuoteID,
+			IdempotencyKey: key,
+			RiskReviewID:   &review.ID,
+		})
+	case review.Exchange != nil:
+		in := *review.Exchange
+		in.IdempotencyKey = key
+		in.RiskReviewID = &review.ID
+		return q.booker.Exchange(ctx, review.UserID, &in)
+	default:
+		return nil, fmt.Errorf("risk review %s has no request", review.ID)
+	}
+}
+
+// Reject closes a pending review; nothing is booked.
+func (q *RiskReviewQueue) Reject(ctx context.Context, reviewerID uuid.UUID, id uuid.UUID, note string) (*domain.RiskReview, error) {
+	note, err := reviewNote(note)
+	if err != nil {
+		return nil, err
+	}
+	review, err := q.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("risk_review.reject: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if err := q.repo.Transition(ctx, id, []domain.RiskReviewStatus{domain.RiskReviewPending}, domain.RiskReviewRejected, &reviewerID, note, nil, &now); err != nil {
+		return nil, fmt.Errorf("risk_review.reject: %w", err)
+	}
+	review.Status = domain.RiskReviewRejected
+	review.ReviewerID = &reviewerID
+	review.ReviewNote = note
+	review.ReviewedAt = &now
+	if q.settler != nil {
+		if err := q.settler.ReviewRejected(ctx, review); err != nil {
+			q.logger.Error("Failed to settle rejected risk review", "review_id", id, "error", err)
+		}
+	}
+
+	q.logger.Info("Risk review rejected", "review_id", id, "reviewer_id", reviewerID)
+	return review, nil
+}
+
+func reviewNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if len(note) > maxRiskReviewNoteLength {
+		return "", apperr.BadRequest("note must be at most 500 characters")
+	}
+	return note, nil
+}
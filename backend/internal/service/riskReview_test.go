@@ -0,0 +1,488 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+type memoryRiskReviewRepo struct {
+	reviews map[uuid.UUID]*domain.RiskReview
+}
+
+func newMemoryRiskReviewRepo() *memoryRiskReviewRepo {
+	return &memoryRiskReviewRepo{reviews: map[uuid.UUID]*domain.RiskReview{}}
+}
+
+func (r *memoryRiskReviewRepo) Create(ctx context.Context, review *domain.RiskReview) (*domain.RiskReview, error) {
+	if existing, err := r.GetByIdempotencyKey(ctx, review.UserID, review.Type, review.IdempotencyKey); err == nil {
+		return existing, nil
+	}
+	r.reviews[review.ID] = review
+	return review, nil
+}
+
+func (r *memoryRiskReviewRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.RiskReview, error) {
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, apperr.ErrRiskReviewNotFound
+	}
+	copied := *review
+	return &copied, nil
+}
+
+func (r *memoryRiskReviewRepo) GetByIdempotencyKey(ctx context.Context, userID uuid.UUID, typ domain.TransactionType, key string) (*domain.RiskReview, error) {
+	for _, review := range r.reviews {
+		if key != "" && review.UserID == userID && review.Type == typ && review.IdempotencyKey == key {
+			return review, nil
+		}
+	}
+	return nil, apperr.ErrRiskReviewNotFound
+}
+
+func (r *memoryRiskReviewRepo) List(ctx context.Context, status domain.RiskReviewStatus, limit int) ([]*domain.RiskReview, error) {
+	var out []*domain.RiskReview
+	for _, review := range r.reviews {
+		if status == "" || review.Status == status {
+			out = append(out, review)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryRiskReviewRepo) Transition(ctx context.Context, id uuid.UUID, from []domain.RiskReviewStatus, status domain.RiskReviewStatus, reviewerID *uuid.UUID, note string, transactionID *uuid.UUID, at *time.Time) error {
+	review, ok := r.reviews[id]
+	if !ok {
+		return apperr.ErrRiskReviewNotFound
+	}
+	if !slices.Contains(from, review.Status) {
+		return apperr.ErrRiskReviewNotPending
+	}
+	review.Status = status
+	review.ReviewerID = reviewerID
+	review.ReviewNote = note
+	review.TransactionID = transactionID
+	review.ReviewedAt = at
+	return nil
+}
+
+// fakeBooker records the transfers and quote executions it is asked to book and fails transfers
+// while err is set. With booked set, a transfer key seen before replays its transaction.
+type fakeBooker struct {
+	transfers []*domain.TransferInput
+	quotes    []*domain.ExecuteQuoteInput
+	booked    map[string]uuid.UUID
+	err       error
+}
+
+func (b *fakeBooker) Transfer(ctx context.Context, fromUserID uuid.UUID, in *domain.TransferInput) (*domain.TransactionInfo, error) {
+	b.transfers = append(b.transfers, in)
+	if b.err != nil {
+		return nil, b.err
+	}
+	if id, ok := b.booked[in.IdempotencyKey]; ok {
+		return &domain.TransactionInfo{ID: id, Type: domain.TransactionTypeTransfer}, nil
+	}
+	id := uuid.New()
+	if b.booked != nil {
+		b.booked[in.IdempotencyKey] = id
+	}
+	return &domain.TransactionInfo{ID: id, Type: domain.TransactionTypeTransfer}, nil
+}
+
+func (b *fakeBooker) Exchange(ctx context.Context, userID uuid.UUID, in *domain.ExchangeInput) (*domain.TransactionInfo, error) {
+	return &domain.TransactionInfo{ID: uuid.New(), Type: domain.TransactionTypeExchange}, nil
+}
+
+func (b *fakeBooker) ExecuteQuote(ctx context.Context, userID uuid.UUID, in *domain.ExecuteQuoteInput) (*domain.TransactionInfo, error) {
+	b.quotes = append(b.quotes, in)
+	return &domain.TransactionInfo{ID: uuid.New(), Type: domain.TransactionTypeExchange}, nil
+}
+
+// memoryQuoteRepo holds quotes by id and counts how often one was locked.
+type memoryQuoteRepo struct {
+	quotes map[uuid.UUID]*domain.ExchangeQuote
+	locks  int
+}
+
+func (r *memoryQuoteRepo) Create(ctx context.Context, quote *domain.ExchangeQuote) error {
+	r.quotes[quote.ID] = quote
+	return nil
+}
+
+func (r *memoryQuoteRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ExchangeQuote, error) {
+	quote, ok := r.quotes[id]
+	if !ok {
+		return nil, apperr.ErrQuoteNotFound
+	}
+	copied := *quote
+	return &copied, nil
+}
+
+func (r *memoryQuoteRepo) LockByIDTx(ctx context.Context, tx Tx, id uuid.UUID) (*domain.ExchangeQuote, error) {
+	r.locks++
+	return r.GetByID(ctx, id)
+}
+
+func (r *memoryQuoteRepo) MarkUsedTx(ctx context.Context, tx Tx, id uuid.UUID, transactionID uuid.UUID, usedAt time.Time) error {
+	r.quotes[id].UsedAt = &usedAt
+	r.quotes[id].TransactionID = &transactionID
+	return nil
+}
+
+var errNoLedger = errors.New("no ledger in this test")
+
+// unbookableAccountRepo fails the first account lookup of a booking and records its currency, so a
+// test can see that booking started without a ledger behind it.
+type unbookableAccountRepo struct {
+	AccountRepo
+	currencies []domain.Currency
+}
+
+func (r *unbookableAccountRepo) FindAccountIDTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency) (uuid.UUID, error) {
+	r.currencies = append(r.currencies, currency)
+	return uuid.Nil, errNoLedger
+}
+
+type fixedRiskEngine struct {
+	decision domain.RiskDecision
+}
+
+func (e *fixedRiskEngine) Assess(ctx context.Context, in *domain.RiskInput) (*domain.RiskDecision, error) {
+	d := e.decision
+	return &d, nil
+}
+
+func pendingTransferReview(repo *memoryRiskReviewRepo, key string) *domain.RiskReview {
+	to := uuid.New()
+	review := &domain.RiskReview{
+		ID:             uuid.New(),
+		UserID:         uuid.New(),
+		Type:           domain.TransactionTypeTransfer,
+		Currency:       domain.CurrencyUSD,
+		AmountCents:    5_000_00,
+		ToUserID:       &to,
+		Rule:           "large_to_new",
+		Transfer:       &domain.TransferInput{ToUserID: &to, Currency: domain.CurrencyUSD, AmountCents: 5_000_00},
+		IdempotencyKey: key,
+		Status:         domain.RiskReviewPending,
+	}
+	repo.reviews[review.ID] = review
+	return review
+}
+
+// recordingSettler records the decisions it is told about.
+type recordingSettler struct {
+	approved map[uuid.UUID]uuid.UUID
+	rejected []uuid.UUID
+}
+
+func (r *recordingSettler) ReviewApproved(ctx context.Context, review *domain.RiskReview, transactionID uuid.UUID) error {
+	r.approved[review.ID] = transactionID
+	return nil
+}
+
+func (r *recordingSettler) ReviewRejected(ctx context.Context, review *domain.RiskReview) error {
+	r.rejected = append(r.rejected, review.ID)
+	return nil
+}
+
+func TestRiskReviewQueueApprove(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reviewer := uuid.New()
+
+	t.Run("books_with_derived_key", func(t *testing.T) {
+		repo, booker := newMemoryRiskReviewRepo(), &fakeBooker{}
+		held := pendingTransferReview(repo, "")
+		q := NewRiskReviewQueue(repo, booker, nil, logger)
+
+		review, info, err := q.Approve(ctx, reviewer, held.ID, "  called the customer  ")
+		if err != nil {
+			t.Fatalf("Approve: %v", err)
+		}
+		if len(booker.transfers) != 1 {
+			t.Fatalf("booked %d transfers", len(booker.transfers))
+		}
+		in := booker.transfers[0]
+		if in.IdempotencyKey != "risk-review:"+held.ID.String() || in.RiskReviewID == nil || *in.RiskReviewID != held.ID {
+			t.Fatalf("booked with key=%q review=%v", in.IdempotencyKey, in.RiskReviewID)
+		}
+		stored := repo.reviews[held.ID]
+		if stored.Status != domain.RiskReviewApproved || *stored.TransactionID != info.ID || stored.ReviewNote != "called the customer" {
+			t.Fatalf("stored=%+v", stored)
+		}
+		if review.Status != domain.RiskReviewApproved {
+			t.Fatalf("returned status=%s", review.Status)
+		}
+	})
+
+	t.Run("keeps_original_key", func(t *testing.T) {
+		repo, booker := newMemoryRiskReviewRepo(), &fakeBooker{}
+		held := pendingTransferReview(repo, "client-key")
+		if _, _, err := NewRiskReviewQueue(repo, booker, nil, logger).Approve(ctx, reviewer, held.ID, ""); err != nil {
+			t.Fatalf("Approve: %v", err)
+		}
+		if got := booker.transfers[0].IdempotencyKey; got != "client-key" {
+			t.Fatalf("key=%q", got)
+		}
+	})
+
+	t.Run("booking_failure_returns_to_pending", func(t *testing.T) {
+		repo, booker := newMemoryRiskReviewRepo(), &fakeBooker{err: apperr.ErrInsufficientFunds}
+		held := pendingTransferReview(repo, "")
+		q := NewRiskReviewQueue(repo, booker, nil, logger)
+
+		if _, _, err := q.Approve(ctx, reviewer, held.ID, ""); !errors.Is(err, apperr.ErrInsufficientFunds) {
+			t.Fatalf("err=%v", err)
+		}
+		if got := repo.reviews[held.ID].Status; got != domain.RiskReviewPending {
+			t.Fatalf("status=%s want pending", got)
+		}
+	})
+
+	t.Run("settles_decisions", func(t *testing.T) {
+		repo, booker := newMemoryRiskReviewRepo(), &fakeBooker{}
+		settler := &recordingSettler{approved: map[uuid.UUID]uuid.UUID{}}
+		approved, rejected := pendingTransferReview(repo, "a"), pendingTransferReview(repo, "b")
+		q := NewRiskReviewQueue(repo, booker, settler, logger)
+
+		_, info, err := q.Approve(ctx, reviewer, approved.ID, "")
+		if err != nil {
+			t.Fatalf("Approve: %v", err)
+		}
+		if settler.approved[approved.ID] != info.ID {
+			t.Fatalf("approved=%v", settler.approved)
+		}
+		if _, err := q.Reject(ctx, reviewer, rejected.ID, ""); err != nil {
+			t.Fatalf("Reject: %v", err)
+		}
+		if len(settler.rejected) != 1 || settler.rejected[0] != rejected.ID {
+			t.Fatalf("rejected=%v", settler.rejected)
+		}
+	})
+
+	t.Run("rejected_cannot_be_approved", func(t *testing.T) {
+		repo, booker := newMemoryRiskReviewRepo(), &fakeBooker{}
+		held := pendingTransferReview(repo, "")
+		q := NewRiskReviewQueue(repo, booker, nil, logger)
+		if _, err := q.Reject(ctx, reviewer, held.ID, "fraud"); err != nil {
+			t.Fatalf("Reject: %v", err)
+		}
+
+		if _, _, err := q.Approve(ctx, reviewer, held.ID, ""); !errors.Is(err, apperr.ErrRiskReviewNotPending) {
+			t.Fatalf("err=%v", err)
+		}
+		if _, err := q.Reject(ctx, reviewer, held.ID, ""); !errors.Is(err, apperr.ErrRiskReviewNotPending) {
+			t.Fatalf("second reject err=%v", err)
+		}
+		if len(booker.transfers) != 0 {
+			t.Fatal("rejected review was booked")
+		}
+	})
+}
+
+func TestTransactionServiceScreen(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	userID, toUserID := uuid.New(), uuid.New()
+	in := &domain.RiskInput{Type: domain.TransactionTypeTransfer, UserID: userID, ToUserID: &toUserID, Currency: domain.CurrencyUSD, AmountCents: 5_000_00}
+	review := func(key string) *domain.RiskReview {
+		return &domain.RiskReview{ToUserID: &toUserID, Transfer: &domain.TransferInput{ToUserID: &toUserID}, IdempotencyKey: key}
+	}
+	newService := func(action domain.RiskAction, repo *memoryRiskReviewRepo) *TransactionService {
+		audit := NewAuditLog(&memoryAuditRepo{}, inlineTxRunner{}, logger)
+		return &TransactionService{
+			risk:    &fixedRiskEngine{decision: domain.RiskDecision{Action: action, Rule: "r", Reason: "because"}},
+			reviews: repo,
+			audit:   audit,
+			logger:  logger,
+		}
+	}
+
+	t.Run("allow", func(t *testing.T) {
+		repo := newMemoryRiskReviewRepo()
+		if err := newService(domain.RiskAllow, repo).screen(ctx, in, review("k")); err != nil {
+			t.Fatalf("err=%v", err)
+		}
+		if len(repo.reviews) != 0 {
+			t.Fatal("review created for an allowed movement")
+		}
+	})
+
+	t.Run("deny", func(t *testing.T) {
+		if err := newService(domain.RiskDeny, newMemoryRiskReviewRepo()).screen(ctx, in, review("")); !errors.Is(err, apperr.ErrTransactionDeclined) {
+			t.Fatalf("err=%v", err)
+		}
+	})
+
+	t.Run("hold_then_retry", func(t *testing.T) {
+		repo := newMemoryRiskReviewRepo()
+		svc := newService(domain.RiskHold, repo)
+
+		err := svc.screen(ctx, in, review("k"))
+		var held *apperr.HeldForReviewError
+		if !errors.As(err, &held) {
+			t.Fatalf("err=%v", err)
+		}
+		if len(repo.reviews) != 1 {
+			t.Fatalf("reviews=%d", len(repo.reviews))
+		}
+		stored := repo.reviews[uuid.MustParse(held.ReviewID)]
+		if stored.Status != domain.RiskReviewPending || stored.Rule != "r" || stored.AmountCents != in.AmountCents {
+			t.Fatalf("stored=%+v", stored)
+		}
+
+		var again *apperr.HeldForReviewError
+		if err := svc.screen(ctx, in, review("k")); !errors.As(err, &again) || again.ReviewID != held.ReviewID {
+			t.Fatalf("retry err=%v", err)
+		}
+		if len(repo.reviews) != 1 {
+			t.Fatalf("retry created another review")
+		}
+
+		stored.Status = domain.RiskReviewApproved
+		if err := svc.screen(ctx, in, review("k")); err != nil {
+			t.Fatalf("approved retry err=%v", err)
+		}
+		stored.Status = domain.RiskReviewRejected
+		if err := svc.screen(ctx, in, review("k")); !errors.Is(err, apperr.ErrTransactionDeclined) {
+			t.Fatalf("rejected retry err=%v", err)
+		}
+	})
+}
+
+func TestExecuteQuoteScreened(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	userID := uuid.New()
+	newService := func(action domain.RiskAction) (*TransactionService, *memoryQuoteRepo, *domain.ExchangeQuote) {
+		quote := &domain.ExchangeQuote{
+			ID:                   uuid.New(),
+			UserID:               userID,
+			FromCurrency:         domain.CurrencyUSD,
+			ToCurrency:           domain.CurrencyEUR,
+			AmountCents:          5_000_00,
+			ConvertedAmountCents: 4_600_00,
+			RateNum:              92,
+			RateDen:              100,
+			ExpiresAt:            time.Now().Add(time.Minute),
+		}
+		quotes := &memoryQuoteRepo{quotes: map[uuid.UUID]*domain.ExchangeQuote{quote.ID: quote}}
+		return &TransactionService{
+			accountRepo:     &unbookableAccountRepo{},
+			quoteRepo:       quotes,
+			idempotencyRepo: &memoryIdempotencyRepo{keys: map[string]*domain.IdempotencyKey{}},
+			txRunner:        inlineTxRunner{},
+			risk:            &fixedRiskEngine{decision: domain.RiskDecision{Action: action, Rule: "r", Reason: "because"}},
+			reviews:         newMemoryRiskReviewRepo(),
+			audit:           NewAuditLog(&memoryAuditRepo{}, inlineTxRunner{}, logger),
+			logger:          logger,
+		}, quotes, quote
+	}
+
+	t.Run("deny", func(t *testing.T) {
+		s, quotes, quote := newService(domain.RiskDeny)
+		if _, err := s.ExecuteQuote(ctx, userID, &domain.ExecuteQuoteInput{QuoteID: quote.ID}); !errors.Is(err, apperr.ErrTransactionDeclined) {
+			t.Fatalf("err=%v want declined", err)
+		}
+		if quotes.locks != 0 || quotes.quotes[quote.ID].UsedAt != nil {
+			t.Fatalf("declined execution locked the quote %d times", quotes.locks)
+		}
+	})
+
+	t.Run("hold_then_approve_books_quoted_leg", func(t *testing.T) {
+		s, quotes, quote := newService(domain.RiskHold)
+		reviews := s.reviews.(*memoryRiskReviewRepo)
+
+		_, err := s.ExecuteQuote(ctx, userID, &domain.ExecuteQuoteInput{QuoteID: quote.ID, IdempotencyKey: "client-key"})
+		var held *apperr.HeldForReviewError
+		if !errors.As(err, &held) {
+			t.Fatalf("err=%v want held", err)
+		}
+		if quotes.locks != 0 {
+			t.Fatalf("held execution locked the quote")
+		}
+		review := reviews.reviews[uuid.MustParse(held.ReviewID)]
+		if review.Type != domain.TransactionTypeExchange || review.Currency != quote.FromCurrency || review.AmountCents != quote.AmountCents ||
+			review.Exchange == nil || review.Exchange.QuoteID == nil || *review.Exchange.QuoteID != quote.ID {
+			t.Fatalf("review=%+v exchange=%+v", review, review.Exchange)
+		}
+
+		// A retry with another key after the quote expired still answers with the same review.
+		quote.ExpiresAt = time.Now().Add(-time.Second)
+		var again *apperr.HeldForReviewError
+		if _, err := s.ExecuteQuote(ctx, userID, &domain.ExecuteQuoteInput{QuoteID: quote.ID, IdempotencyKey: "other-key"}); !errors.As(err, &again) || again.ReviewID != held.ReviewID {
+			t.Fatalf("retry err=%v want review %s", err, held.ReviewID)
+		}
+		if len(reviews.reviews) != 1 {
+			t.Fatalf("retry created another review")
+		}
+
+		// Approving executes the expired quote with the client's key, skipping screening.
+		booker := &fakeBooker{}
+		if _, _, err := NewRiskReviewQueue(reviews, booker, nil, logger).Approve(ctx, uuid.New(), review.ID, ""); err != nil {
+			t.Fatalf("Approve: %v", err)
+		}
+		if len(booker.quotes) != 1 {
+			t.Fatalf("booked %d quote executions", len(booker.quotes))
+		}
+		in := booker.quotes[0]
+		if in.QuoteID != quote.ID || in.IdempotencyKey != "client-key" || in.RiskReviewID == nil || *in.RiskReviewID != review.ID {
+			t.Fatalf("booked %+v", in)
+		}
+
+		// The service books such an approval at the quote's leg instead of refusing the expired quote.
+		accounts := s.accountRepo.(*unbookableAccountRepo)
+		if _, err := s.ExecuteQuote(ctx, userID, in); !errors.Is(err, errNoLedger) {
+			t.Fatalf("approved execution err=%v want to reach booking", err)
+		}
+		if quotes.locks != 1 || len(accounts.currencies) != 1 || accounts.currencies[0] != quote.FromCurrency {
+			t.Fatalf("locks=%d account lookups=%v", quotes.locks, accounts.currencies)
+		}
+	})
+
+	t.Run("expired_quote_is_not_queued", func(t *testing.T) {
+		s, _, quote := newService(domain.RiskHold)
+		quote.ExpiresAt = time.Now().Add(-time.Second)
+		if _, err := s.ExecuteQuote(ctx, userID, &domain.ExecuteQuoteInput{QuoteID: quote.ID}); !errors.Is(err, apperr.ErrQuoteExpired) {
+			t.Fatalf("err=%v want expired", err)
+		}
+		if n := len(s.reviews.(*memoryRiskReviewRepo).reviews); n != 0 {
+			t.Fatalf("expired quote queued %d reviews", n)
+		}
+	})
+}
+
+func TestScheduledTransferRunScreened(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	userID, toUserID := uuid.New(), uuid.New()
+	s := &TransactionService{
+		idempotencyRepo: &memoryIdempotencyRepo{keys: map[string]*domain.IdempotencyKey{}},
+		currencies:      domain.NewCurrencyRegistry([]domain.CurrencyInfo{{Code: domain.CurrencyUSD, MinorUnits: 2, Enabled: true}}),
+		risk:            &fixedRiskEngine{decision: domain.RiskDecision{Action: domain.RiskDeny, Rule: "r", Reason: "because"}},
+		reviews:         newMemoryRiskReviewRepo(),
+		audit:           NewAuditLog(&memoryAuditRepo{}, inlineTxRunner{}, logger),
+		logger:          logger,
+	}
+
+	_, err := s.Transfer(ctx, userID, &domain.TransferInput{
+		ToUserID:       &toUserID,
+		Currency:       domain.CurrencyUSD,
+		AmountCents:    5_000_00,
+		IdempotencyKey: "scheduled:order:1767258000",
+		Scheduled:      true,
+	})
+	if !errors.Is(err, apperr.ErrTransactionDeclined) {
+		t.Fatalf("err=%v want declined", err)
+	}
+}
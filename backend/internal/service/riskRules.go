@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"banking-platform/internal/domain"
+)
+
+const (
+	// RiskRuleNewRecipientAmount matches the first transfer to a recipient.
+	RiskRuleNewRecipientAmount = "new_recipient_amount"
+	// RiskRuleAccountAge matches transfers and exchanges by users registered less than MaxAgeDays ago.
+	RiskRuleAccountAge = "account_age"
+	// RiskRuleRecipientVelocity matches a transfer that makes more than MaxRecipients distinct
+	// recipients within WindowMinutes.
+	RiskRuleRecipientVelocity = "recipient_velocity"
+)
+
+const defaultRiskWindowMinutes = 60
+
+// RiskRule is one entry of the rules file.
+type RiskRule struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Action domain.RiskAction `json:"action"`
+
+	// AmountAboveCents limits the rule to amounts above a threshold per currency, in minor units.
+	// Without it the rule applies to any amount; with it, unlisted currencies are not checked.
+	AmountAboveCents map[domain.Currency]int64 `json:"amount_above_cents,omitempty"`
+
+	MaxAgeDays    int `json:"max_age_days,omitempty"`
+	MaxRecipients int `json:"max_recipients,omitempty"`
+	WindowMinutes int `json:"window_minutes,omitempty"`
+}
+
+type riskRulesFile struct {
+	Rules []RiskRule `json:"rules"`
+}
+
+// LoadRiskRules reads a JSON file of the form {"rules": [...]}.
+func LoadRiskRules(path string) ([]RiskRule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read risk rules: %w", err)
+	}
+	var f riskRulesFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse risk rules: %w", err)
+	}
+	return f.Rules, nil
+}
+
+// RuleRiskEngine evaluates every rule against a movement. A matching deny rule wins over a
+// matching hold rule; among rules with the same action the first one in the file is reported.
+type RuleRiskEngine struct {
+	rules   []RiskRule
+	signals RiskSignalRepo
+	users   UserRepo
+	now     func() time.Time
+}
+
+func NewRuleRiskEngine(rules []RiskRule, signals RiskSignalRepo, users UserRepo) (*RuleRiskEngine, error) {
+	names := make(map[string]bool, len(rules))
+	for i := range rules {
+		r := &rules[i]
+		if r.Name == "" || names[r.Name] {
+			return nil, fmt.Errorf("risk rule %d: name is required and must be unique", i+1)
+		}
+		names[r.Name] = true
+		if r.Action != domain.RiskDeny && r.Action != domain.RiskHold {
+			return nil, fmt.Errorf("risk rule %q: action must be %q or %q", r.Name, domain.RiskDeny, domain.RiskHold)
+		}
+		for currency, amount := range r.AmountAboveCents {
+			if amount < 0 {
+				return nil, fmt.Errorf("risk rule %q: amount_above_cents for %s cannot be negative", r.Name, currency)
+			}
+		}
+
+		switch r.Type {
+		case RiskRuleNewRecipientAmount:
+		case RiskRuleAccountAge:
+			if r.MaxAgeDays <= 0 {
+				return nil, fmt.Errorf("risk rule %q: max_age_days must be positive", r.Name)
+			}
+		case RiskRuleRecipientVelocity:
+			if r.MaxRecipients <= 0 {
+				return nil, fmt.Errorf("risk rule %q: max_recipients must be positive", r.Name)
+			}
+			if r.WindowMinutes < 0 {
+				return nil, fmt.Errorf("risk rule %q: window_minutes cannot be negative", r.Name)
+			}
+			if r.WindowMinutes == 0 {
+				r.WindowMinutes = defaultRiskWindowMinutes
+			}
+		default:
+			return nil, fmt.Errorf("risk rule %q: unknown type %q", r.Name, r.Type)
+		}
+	}
+	return &RuleRiskEngine{rules: rules, signals: signals, users: users, now: time.Now}, nil
+}
+
+func (e *RuleRiskEngine) Assess(ctx context.Context, in *domain.RiskInput) (*domain.RiskDecision, error) {
+	decision := &domain.RiskDecision{Action: domain.RiskAllow}
+	facts := &riskFacts{engine: e, in: in}
+
+	for i := range e.rules {
+		r := &e.rules[i]
+		// Once held, only a deny can change the outcome.
+		if decision.Action == domain.RiskHold && r.Action == domain.RiskHold {
+			continue
+		}
+		reason, err := e.match(ctx, r, facts)
+		if err != nil {
+			return nil, fmt.Errorf("risk rule %q: %w", r.Name, err)
+		}
+		if reason == "" {
+			continue
+		}
+		decision = &domain.RiskDecision{Action: r.Action, Rule: r.Name, Reason: reason}
+		if r.Action == domain.RiskDeny {
+			break
+		}
+	}
+	return decision, nil
+}
+
+// match returns why the rule matches, or "" if it does not.
+func (e *RuleRiskEngine) match(ctx context.Context, r *RiskRule, facts *riskFacts) (string, error) {
+	in := facts.in
+	if len(r.AmountAboveCents) > 0 {
+		threshold, ok := r.AmountAboveCents[in.Currency]
+		if !ok || in.AmountCents <= threshold {
+			return "", nil
+		}
+	}
+
+	switch r.Type {
+	case RiskRuleNewRecipientAmount:
+		if in.ToUserID == nil {
+			return "", nil
+		}
+		sent, err := e.signals.HasSentTo(ctx, in.UserID, *in.ToUserID)
+		if err != nil || sent {
+			return "", err
+		}
+		return "first transfer to this recipient", nil
+
+	case RiskRuleAccountAge:
+		user, err := facts.user(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !user.CreatedAt.After(e.now().AddDate(0, 0, -r.MaxAgeDays)) {
+			return "", nil
+		}
+		return fmt.Sprintf("account is less than %d days old", r.MaxAgeDays), nil
+
+	case RiskRuleRecipientVelocity:
+		if in.ToUserID == nil {
+			return "", nil
+		}
+		recipients, err := e.signals.RecipientsSince(ctx, in.UserID, e.now().Add(-time.Duration(r.WindowMinutes)*time.Minute))
+		if err != nil {
+			return "", err
+		}
+		count := len(recipients)
+		if !slices.Contains(recipients, *in.ToUserID) {
+			count++
+		}
+		if count <= r.MaxRecipients {
+			return "", nil
+		}
+		return fmt.Sprintf("%d distinct recipients within %d minutes", count, r.WindowMinutes), nil
+	}
+	return "", nil
+}
+
+// riskFacts loads what several rules need at most once per assessment.
+type riskFacts struct {
+	engine *RuleRiskEngine
+	in     *domain.RiskInput
+
+	sender *domain.User
+}
+
+func (f *riskFacts) user(ctx context.Context) (*domain.User, error) {
+	if f.sender == nil {
+		u, err := f.engine.users.GetByID(ctx, f.in.UserID)
+		if err != nil {
+			return nil, err
+		}
+		f.sender = u
+	}
+	return f.sender, nil
+}
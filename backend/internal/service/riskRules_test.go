@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+// fakeRiskSignals answers from fixed history: recipients the user already paid, and those paid
+// within any window.
+type fakeRiskSignals struct {
+	sentTo  map[uuid.UUID]bool
+	recent  []uuid.UUID
+	queries int
+}
+
+func (s *fakeRiskSignals) HasSentTo(ctx context.Context, userID uuid.UUID, toUserID uuid.UUID) (bool, error) {
+	s.queries++
+	return s.sentTo[toUserID], nil
+}
+
+func (s *fakeRiskSignals) RecipientsSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]uuid.UUID, error) {
+	s.queries++
+	return s.recent, nil
+}
+
+func TestRuleRiskEngineAssess(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+	oldUser := &domain.User{ID: uuid.New(), CreatedAt: now.AddDate(-1, 0, 0)}
+	newUser := &domain.User{ID: uuid.New(), CreatedAt: now.AddDate(0, 0, -2)}
+	known, stranger := uuid.New(), uuid.New()
+	recent := []uuid.UUID{uuid.New(), uuid.New(), known}
+
+	newRecipient := RiskRule{Name: "large_to_new", Type: RiskRuleNewRecipientAmount, Action: domain.RiskHold,
+		AmountAboveCents: map[domain.Currency]int64{domain.CurrencyUSD: 1_000_00}}
+	young := RiskRule{Name: "young_account", Type: RiskRuleAccountAge, Action: domain.RiskHold, MaxAgeDays: 7}
+	velocity := RiskRule{Name: "fan_out", Type: RiskRuleRecipientVelocity, Action: domain.RiskDeny, MaxRecipients: 3}
+
+	transfer := func(user *domain.User, to uuid.UUID, currency domain.Currency, amount int64) *domain.RiskInput {
+		return &domain.RiskInput{Type: domain.TransactionTypeTransfer, UserID: user.ID, ToUserID: &to, Currency: currency, ToCurrency: currency, AmountCents: amount}
+	}
+
+	testCases := []struct {
+		name       string
+		rules      []RiskRule
+		in         *domain.RiskInput
+		wantAction domain.RiskAction
+		wantRule   string
+	}{
+		{name: "no_rules", in: transfer(newUser, stranger, domain.CurrencyUSD, 5_000_00), wantAction: domain.RiskAllow},
+		{name: "new_recipient_above_threshold", rules: []RiskRule{newRecipient}, in: transfer(oldUser, stranger, domain.CurrencyUSD, 1_000_01),
+			wantAction: domain.RiskHold, wantRule: "large_to_new"},
+		{name: "new_recipient_at_threshold", rules: []RiskRule{newRecipient}, in: transfer(oldUser, stranger, domain.CurrencyUSD, 1_000_00), wantAction: domain.RiskAllow},
+		{name: "known_recipient", rules: []RiskRule{newRecipient}, in: transfer(oldUser, known, domain.CurrencyUSD, 5_000_00), wantAction: domain.RiskAllow},
+		{name: "unlisted_currency", rules: []RiskRule{newRecipient}, in: transfer(oldUser, stranger, domain.CurrencyEUR, 5_000_00), wantAction: domain.RiskAllow},
+		{name: "young_account", rules: []RiskRule{young}, in: transfer(newUser, known, domain.CurrencyUSD, 1_00),
+			wantAction: domain.RiskHold, wantRule: "young_account"},
+		{name: "young_account_exchange", rules: []RiskRule{young},
+			in:         &domain.RiskInput{Type: domain.TransactionTypeExchange, UserID: newUser.ID, Currency: domain.CurrencyUSD, ToCurrency: domain.CurrencyEUR, AmountCents: 1_00},
+			wantAction: domain.RiskHold, wantRule: "young_account"},
+		{name: "old_account", rules: []RiskRule{young}, in: transfer(oldUser, stranger, domain.CurrencyUSD, 1_00), wantAction: domain.RiskAllow},
+		{name: "velocity_repeat_recipient", rules: []RiskRule{velocity}, in: transfer(oldUser, known, domain.CurrencyUSD, 1_00), wantAction: domain.RiskAllow},
+		{name: "velocity_exceeded", rules: []RiskRule{velocity}, in: transfer(oldUser, stranger, domain.CurrencyUSD, 1_00),
+			wantAction: domain.RiskDeny, wantRule: "fan_out"},
+		{name: "first_hold_reported", rules: []RiskRule{young, newRecipient}, in: transfer(newUser, stranger, domain.CurrencyUSD, 5_000_00),
+			wantAction: domain.RiskHold, wantRule: "young_account"},
+		{name: "deny_wins_over_hold", rules: []RiskRule{young, velocity}, in: transfer(newUser, stranger, domain.CurrencyUSD, 1_00),
+			wantAction: domain.RiskDeny, wantRule: "fan_out"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			signals := &fakeRiskSignals{sentTo: map[uuid.UUID]bool{known: true}, recent: recent}
+			users := &memoryUserRepo{users: map[uuid.UUID]*domain.User{oldUser.ID: oldUser, newUser.ID: newUser}}
+			engine, err := NewRuleRiskEngine(tc.rules, signals, users)
+			if err != nil {
+				t.Fatalf("NewRuleRiskEngine: %v", err)
+			}
+			engine.now = func() time.Time { return now }
+
+			decision, err := engine.Assess(context.Background(), tc.in)
+			if err != nil {
+				t.Fatalf("Assess: %v", err)
+			}
+			if decision.Action != tc.wantAction || decision.Rule != tc.wantRule {
+				t.Fatalf("decision=%+v want action=%s rule=%q", decision, tc.wantAction, tc.wantRule)
+			}
+			if decision.Action != domain.RiskAllow && decision.Reason == "" {
+				t.Fatal("expected a reason")
+			}
+		})
+	}
+}
+
+func TestNewRuleRiskEngineValidates(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule RiskRule
+	}{
+		{name: "missing_name", rule: RiskRule{Type: RiskRuleNewRecipientAmount, Action: domain.RiskHold}},
+		{name: "allow_action", rule: RiskRule{Name: "r", Type: RiskRuleNewRecipientAmount, Action: domain.RiskAllow}},
+		{name: "unknown_type", rule: RiskRule{Name: "r", Type: "country", Action: domain.RiskHold}},
+		{name: "negative_threshold", rule: RiskRule{Name: "r", Type: RiskRuleNewRecipientAmount, Action: domain.RiskHold,
+			AmountAboveCents: map[domain.Currency]int64{domain.CurrencyUSD: -1}}},
+		{name: "age_without_days", rule: RiskRule{Name: "r", Type: RiskRuleAccountAge, Action: domain.RiskHold}},
+		{name: "velocity_without_max", rule: RiskRule{Name: "r", Type: RiskRuleRecipientVelocity, Action: domain.RiskDeny}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewRuleRiskEngine([]RiskRule{tc.rule}, &fakeRiskSignals{}, &memoryUserRepo{}); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	dup := RiskRule{Name: "r", Type: RiskRuleAccountAge, Action: domain.RiskHold, MaxAgeDays: 1}
+	if _, err := NewRuleRiskEngine([]RiskRule{dup, dup}, &fakeRiskSignals{}, &memoryUserRepo{}); err == nil {
+		t.Fatal("expected an error for duplicate names")
+	}
+}
+
+func TestLoadRiskRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	raw := `{"rules": [{"name": "fan_out", "type": "recipient_velocity", "action": "hold", "max_recipients": 5}]}`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadRiskRules(path)
+	if err != nil {
+		t.Fatalf("LoadRiskRules: %v", err)
+	}
+	engine, err := NewRuleRiskEngine(rules, &fakeRiskSignals{}, &memoryUserRepo{})
+	if err != nil {
+		t.Fatalf("NewRuleRiskEngine: %v", err)
+	}
+	if got := engine.rules[0].WindowMinutes; got != defaultRiskWindowMinutes {
+		t.Fatalf("window=%d want=%d", got, defaultRiskWindowMinutes)
+	}
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	maxScheduledTransferDescriptionLength = 140
+
+	// scheduledStartGrace tolerates clock skew between the client and the API for "run now" schedules.
+	scheduledStartGrace = time.Minute
+)
+
+// Transferer executes a single transfer on behalf of a user. TransactionService implements it.
+type Transferer interface {
+	Transfer(ctx context.Context, fromUserID uuid.UUID, in *domain.TransferInput) (*domain.TransactionInfo, error)
+}
+
+type ScheduledTransferService struct {
+	repo        ScheduledTransferRepo
+	accountRepo AccountRepo
+	userRepo    UserRepo
+	transferer  Transferer
+	txRunner    TxRunner
+	currencies  *domain.CurrencyRegistry
+	logger      *slog.Logger
+
+	maxAttempts  int
+	retryBackoff time.Duration
+	lease        time.Duration
+
+	// stepUp is asked for a TOTP check when an order above stepUpThreshold minor units is created;
+	// its runs are not asked again. 0 disables it.
+	stepUp          StepUpVerifier
+	stepUpThreshold int64
+}
+
+func NewScheduledTransferService(
+	repo ScheduledTransferRepo,
+	accountRepo AccountRepo,
+	userRepo UserRepo,
+	transferer Transferer,
+	txRunner TxRunner,
+	currencies *domain.CurrencyRegistry,
+	maxAttempts int,
+	retryBackoff time.Duration,
+	lease time.Duration,
+	stepUp StepUpVerifier,
+	stepUpThreshold int64,
+	logger *slog.Logger,
+) *ScheduledTransferService {
+	return &ScheduledTransferService{
+		repo:        repo,
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		transferer:  transferer,
+		txRunner:    txRunner,
+		currencies:  currencies,
+		logger:      logger,
+
+		maxAttempts:  maxAttempts,
+		retryBackoff: retryBackoff,
+		lease:        lease,
+
+		stepUp:          stepUp,
+		stepUpThreshold: stepUpThreshold,
+	}
+}
+
+// Create validates and stores a standing order. The recipient is resolved to a user or account id up
+// front. Orders above the step-up threshold need a TOTP code now, as their runs are unattended.
+func (s *ScheduledTransferService) Create(ctx context.Context, userID uuid.UUID, in *domain.CreateScheduledTransferInput) (*domain.ScheduledTransfer, error) {
+	if in.AmountCents <= 0 {
+		return nil, apperr.BadRequest("amount_cents must be greater than 0")
+	}
+	if !s.currencies.IsEnabled(in.Currency) {
+		return nil, apperr.ErrInvalidCurrency
+	}
+	description := strings.TrimSpace(in.Description)
+	if len(description) > maxScheduledTransferDescriptionLength {
+		return nil, apperr.BadRequest("description must be at most 140 characters")
+	}
+	if err := in.Schedule.Validate(); err != nil {
+		return nil, apperr.BadRequest(err.Error())
+	}
+
+	now := time.Now().UTC()
+	firstRun := in.Schedule.FirstRun()
+	if firstRun.Before(now.Add(-scheduledStartGrace)) {
+		return nil, apperr.BadRequest("start_at must not be in the past")
+	}
+	if in.Schedule.EndAt != nil && firstRun.After(*in.Schedule.EndAt) {
+		return nil, apperr.BadRequest("schedule has no occurrence before end_at")
+	}
+
+	if countSetRecipients(in.ToUserID != nil, in.ToUserEmail != nil, in.ToAccountID != nil) != 1 {
+		return nil, apperr.BadRequest("provide exactly one of to_user_id, to_user_email or to_account_id")
+	}
+
+	st := &domain.ScheduledTransfer{
+		ID:            uuid.New(),
+		UserID:        userID,
+		FromAccountID: in.FromAccountID,
+		ToAccountID:   in.ToAccountID,
+		Currency:      in.Currency,
+		AmountCents:   in.AmountCents,
+		Description:   description,
+		Schedule:      in.Schedule,
+		Status:        domain.ScheduledTransferActive,
+		NextRunAt:     firstRun,
+		NextAttemptAt: firstRun,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	switch {
+	case in.ToUserID != nil:
+		if _, err := s.userRepo.GetByID(ctx, *in.ToUserID); err != nil {
+			return nil, fmt.Errorf("scheduled_transfer.create: get recipient: %w", err)
+		}
+		st.ToUserID = in.ToUserID
+	case in.ToUserEmail != nil:
+		email := strings.ToLower(strings.TrimSpace(*in.ToUserEmail))
+		if email == "" {
+			return nil, apperr.BadRequest("to_user_email cannot be empty")
+		}
+		u, err := s.userRepo.GetByEmail(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled_transfer.create: get recipient by email: %w", err)
+		}
+		st.ToUserID = &u.ID
+	}
+	if st.ToUserID != nil && *st.ToUserID == userID {
+		return nil, apperr.ErrCannotTransferToSelf
+	}
+
+	if in.FromAccountID != nil {
+		if err := s.checkAccount(ctx, *in.FromAccountID, &userID, in.Currency); err != nil {
+			return nil, fmt.Errorf("scheduled_transfer.create: source account: %w", err)
+		}
+	}
+	if in.ToAccountID != nil {
+		if in.FromAccountID != nil && *in.FromAccountID == *in.ToAccountID {
+			return nil, apperr.ErrCannotTransferToSelf
+		}
+		if err := s.checkAccount(ctx, *in.ToAccountID, nil, in.Currency); err != nil {
+			return nil, fmt.Errorf("scheduled_transfer.create: recipient account: %w", err)
+		}
+	}
+
+	if s.stepUp != nil && s.stepUpThreshold > 0 && in.AmountCents > s.stepUpThreshold {
+		if err := s.stepUp.VerifyStepUp(ctx, userID, in.MFACode); err != nil {
+			s.logger.Warn("Scheduled transfer step-up failed", "user_id", userID, "amount_cents", in.AmountCents, "error", err)
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(ctx, st); err != nil {
+		return nil, fmt.Errorf("scheduled_transfer.create: %w", err)
+	}
+
+	s.logger.Info("Scheduled transfer created", "scheduled_transfer_id", st.ID, "user_id", userID, "kind", st.Schedule.Kind, "next_run_at", st.NextRunAt)
+	return st, nil
+}
+
+// checkAccount verifies the account exists, is active, holds currency and (when owner is set) belongs to owner.
+func (s *ScheduledTransferService) checkAccount(ctx context.Context, accountID uuid.UUID, owner *uuid.UUID, currency domain.Currency) error {
+	acc, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if owner != nil && acc.UserID != *owner {
+		return apperr.ErrAccountNotFound
+	}
+	if acc.Currency != currency {
+		return apperr.ErrInvalidCurrency
+	}
+	return ensureAccountActive(acc)
+}
+
+func (s *ScheduledTransferService) List(ctx context.Context, userID uuid.UUID) ([]*domain.ScheduledTransfer, error) {
+	items, err := s.repo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("scheduled_transfer.list: %w", err)
+	}
+	return items, nil
+}
+
+// Cancel stops future runs. A run already in flight still completes, but the order stays cancelled.
+func (s *ScheduledTransferService) Cancel(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.ScheduledTransfer, error) {
+	var st *domain.ScheduledTransfer
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		var err error
+		st, err = s.repo.LockByIDTx(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if st.UserID != userID {
+			return apperr.ErrScheduledTransferNotFound
+		}
+		if st.Status != domain.ScheduledTransferActive {
+			return apperr.ErrScheduledTransferNotCancellable
+		}
+		st.Status = domain.ScheduledTransferCancelled
+		st.UpdatedAt = time.Now().UTC()
+		return s.repo.UpdateStateTx(ctx, tx, st)
+	}); err != nil {
+		return nil, fmt.Errorf("scheduled_transfer.cancel: %w", err)
+	}
+
+	s.logger.Info("Scheduled transfer cancelled", "scheduled_transfer_id", id, "user_id", userID)
+	return st, nil
+}
+
+// RunDue claims up to limit due transfers and executes them. Claims are leased, so concurrent
+// workers on other instances skip them; each occurrence uses a deterministic idempotency key,
+// so re-running an occurrence after a crash replays the original transaction instead of booking twice.
+func (s *ScheduledTransferService) RunDue(ctx context.Context, now time.Time, limit int) (int, error) {
+	var claimed []*domain.ScheduledTransfer
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		var err error
+		claimed, err = s.repo.ClaimDueTx(ctx, tx, now, now.Add(s.lease), limit)
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("scheduled_transfer.run_due: claim: %w", err)
+	}
+
+	for _, st := range claimed {
+		if err := s.execute(ctx, st); err != nil {
+			// The lease expires on its own, so the occurrence is picked up again later.
+			s.logger.Error("Failed to record scheduled transfer run", "scheduled_transfer_id", st.ID, "error", err)
+		}
+	}
+	return len(claimed), nil
+}
+
+func (s *ScheduledTransferService) execute(ctx context.Context, st *domain.ScheduledTransfer) error {
+	in := &domain.TransferInput{
+		ToUserID:       st.ToUserID,
+		ToAccountID:    st.ToAccountID,
+		FromAccountID:  st.FromAccountID,
+		Currency:       st.Currency,
+		AmountCents:    st.AmountCents,
+		IdempotencyKey: occurrenceIdempotencyKey(st),
+		Scheduled:      true,
+	}
+	info, runErr := s.transferer.Transfer(ctx, st.UserID, in)
+	if runErr != nil && ctx.Err() != nil {
+		// Shutting down: leave the claim to expire instead of counting an attempt.
+		return runErr
+	}
+
+	var txID *uuid.UUID
+	if runErr == nil {
+		txID = &info.ID
+		s.logger.Info("Scheduled transfer executed", "scheduled_transfer_id", st.ID, "transaction_id", info.ID, "occurrence_at", st.NextRunAt)
+	} else {
+		s.logger.Warn("Scheduled transfer attempt failed", "scheduled_transfer_id", st.ID, "occurrence_at", st.NextRunAt, "attempt", st.Attempts+1, "error", runErr)
+	}
+
+	return s.txRunner.WithTx(ctx, func(tx Tx) error {
+		current, err := s.repo.LockByIDTx(ctx, tx, st.ID)
+		if err != nil {
+			return err
+		}
+		run := applyRunResult(current, time.Now().UTC(), txID, runErr, s.maxAttempts, s.retryBackoff)
+		if err := s.repo.CreateRunTx(ctx, tx, run); err != nil {
+			return err
+		}
+		return s.repo.UpdateStateTx(ctx, tx, current)
+	})
+}
+
+// occurrenceIdempotencyKey is stable per scheduled transfer and occurrence, but not per attempt.
+func occurrenceIdempotencyKey(st *domain.ScheduledTransfer) string {
+	return fmt.Sprintf("scheduled:%s:%d", st.ID.String(), st.NextRunAt.Unix())
+}
+
+// applyRunResult advances st after an attempt and returns the run record. On success the schedule moves
+// to its next occurrence (or completes); on failure the attempt is retried with linear backoff until
+// maxAttempts, after which recurring orders skip to the next occurrence and one-off orders fail.
+// Occurrences declined by risk rules are not retried, and held ones are left to the review queue,
+// which books them if an operator approves; a held one-off order completes.
+// A transfer cancelled while the attempt was in flight keeps its status.
+func applyRunResult(st *domain.ScheduledTransfer, now time.Time, txID *uuid.UUID, runErr error, maxAttempts int, retryBackoff time.Duration) *domain.ScheduledTransferRun {
+	st.Attempts++
+	run := &domain.ScheduledTransferRun{
+		ID:                  uuid.New(),
+		ScheduledTransferID: st.ID,
+		OccurrenceAt:        st.NextRunAt,
+		Attempt:             st.Attempts,
+		TransactionID:       txID,
+		CreatedAt:           now,
+	}
+	st.LockedUntil = nil
+	st.UpdatedAt = now
+
+	held := errors.Is(runErr, apperr.ErrHeldForReview)
+	if runErr == nil {
+		run.Status = domain.ScheduledTransferRunSucceeded
+		st.LastTransactionID = txID
+		st.LastError = nil
+	} else {
+		msg := publicMessage(runErr)
+		run.Status = domain.ScheduledTransferRunFailed
+		run.Error = &msg
+		st.LastError = &msg
+		final := held || errors.Is(runErr, apperr.ErrTransactionDeclined)
+		if !final && st.Attempts < maxAttempts {
+			st.NextAttemptAt = now.Add(retryBackoff * time.Duration(st.Attempts))
+			return run
+		}
+	}
+
+	if st.Status != domain.ScheduledTransferActive {
+		return run
+	}
+	next, ok := st.Schedule.NextAfter(st.NextRunAt)
+	switch {
+	case ok:
+		st.NextRunAt = next
+		st.NextAttemptAt = next
+		st.Attempts = 0
+	case runErr == nil || held:
+		st.Status = domain.ScheduledTransferCompleted
+	default:
+		st.Status = domain.ScheduledTransferFailed
+	}
+	return run
+}
+
+// publicMessage returns a message suitable to show to the owner of the scheduled transfer.
+func publicMessage(err error) string {
+	var pub *apperr.PublicError
+	if errors.As(err, &pub) && pub != nil {
+		return pub.Message
+	}
+	for _, known := range scheduledRunUserErrors {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return "transfer failed"
+}
+
+// scheduledRunUserErrors are failures the owner can act on; anything else is reported generically.
+var scheduledRunUserErrors = []error{
+	apperr.ErrInsufficientFunds,
+	apperr.ErrAccountFrozen,
+	apperr.ErrAccountClosed,
+	apperr.ErrAccountNotFound,
+	apperr.ErrUserNotFound,
+	apperr.ErrInvalidCurrency,
+	apperr.ErrUnauthorized,
+	apperr.ErrCannotTransferToSelf,
+	apperr.ErrTransactionDeclined,
+	apperr.ErrHeldForReview,
+}
+
+func countSetRecipients(set ...bool) int {
+	n := 0
+	for _, v := range set {
+		if v {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+func TestApplyRunResult(t *testing.T) {
+	start := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)
+	now := start.Add(time.Minute)
+	txID := uuid.New()
+	failure := fmt.Errorf("transaction.transfer: %w", apperr.ErrInsufficientFunds)
+
+	tests := []struct {
+		name        string
+		kind        domain.ScheduleKind
+		attempts    int
+		status      domain.ScheduledTransferStatus
+		txID        *uuid.UUID
+		err         error
+		wantStatus  domain.ScheduledTransferStatus
+		wantRun     domain.ScheduledTransferRunStatus
+		wantNextRun time.Time
+		wantAttempt time.Time
+		wantCount   int
+	}{
+		{
+			name: "once succeeds", kind: domain.ScheduleOnce, status: domain.ScheduledTransferActive, txID: &txID,
+			wantStatus: domain.ScheduledTransferCompleted, wantRun: domain.ScheduledTransferRunSucceeded,
+			wantNextRun: start, wantAttempt: start, wantCount: 1,
+		},
+		{
+			name: "weekly succeeds", kind: domain.ScheduleWeekly, status: domain.ScheduledTransferActive, txID: &txID,
+			wantStatus: domain.ScheduledTransferActive, wantRun: domain.ScheduledTransferRunSucceeded,
+			wantNextRun: start.AddDate(0, 0, 7), wantAttempt: start.AddDate(0, 0, 7), wantCount: 0,
+		},
+		{
+			name: "first failure retries", kind: domain.ScheduleOnce, status: domain.ScheduledTransferActive, err: failure,
+			wantStatus: domain.ScheduledTransferActive, wantRun: domain.ScheduledTransferRunFailed,
+			wantNextRun: start, wantAttempt: now.Add(5 * time.Minute), wantCount: 1,
+		},
+		{
+			name: "second failure backs off", kind: domain.ScheduleOnce, attempts: 1, status: domain.ScheduledTransferActive, err: failure,
+			wantStatus: domain.ScheduledTransferActive, wantRun: domain.ScheduledTransferRunFailed,
+			wantNextRun: start, wantAttempt: now.Add(10 * time.Minute), wantCount: 2,
+		},
+		{
+			name: "once exhausts retries", kind: domain.ScheduleOnce, attempts: 2, status: domain.ScheduledTransferActive, err: failure,
+			wantStatus: domain.ScheduledTransferFailed, wantRun: domain.ScheduledTransferRunFailed,
+			wantNextRun: start, wantAttempt: start, wantCount: 3,
+		},
+		{
+			name: "weekly exhausts retries skips occurrence", kind: domain.ScheduleWeekly, attempts: 2, status: domain.ScheduledTransferActive, err: failure,
+			wantStatus: domain.ScheduledTransferActive, wantRun: domain.ScheduledTransferRunFailed,
+			wantNextRun: start.AddDate(0, 0, 7), wantAttempt: start.AddDate(0, 0, 7), wantCount: 0,
+		},
+		{
+			name: "declined once fails without retry", kind: domain.ScheduleOnce, status: domain.ScheduledTransferActive,
+			err:        fmt.Errorf("transaction.transfer: %w", apperr.ErrTransactionDeclined),
+			wantStatus: domain.ScheduledTransferFailed, wantRun: domain.ScheduledTransferRunFailed,
+			wantNextRun: start, wantAttempt: start, wantCount: 1,
+		},
+		{
+			name: "held weekly moves to next occurrence", kind: domain.ScheduleWeekly, status: domain.ScheduledTransferActive,
+			err:        fmt.Errorf("transaction.transfer: %w", &apperr.HeldForReviewError{ReviewID: "r"}),
+			wantStatus: domain.ScheduledTransferActive, wantRun: domain.ScheduledTransferRunFailed,
+			wantNextRun: start.AddDate(0, 0, 7), wantAttempt: start.AddDate(0, 0, 7), wantCount: 0,
+		},
+		{
+			name: "held once completes", kind: domain.ScheduleOnce, status: domain.ScheduledTransferActive,
+			err:        fmt.Errorf("transaction.transfer: %w", &apperr.HeldForReviewError{ReviewID: "r"}),
+			wantStatus: domain.ScheduledTransferCompleted, wantRun: domain.ScheduledTransferRunFailed,
+			wantNextRun: start, wantAttempt: start, wantCount: 1,
+		},
+		{
+			name: "cancelled while running", kind: domain.ScheduleWeekly, status: domain.ScheduledTransferCancelled, txID: &txID,
+			wantStatus: domain.ScheduledTransferCancelled, wantRun: domain.ScheduledTransferRunSucceeded,
+			wantNextRun: start, wantAttempt: start, wantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lease := now.Add(time.Minute)
+			st := &domain.ScheduledTransfer{
+				ID:            uuid.New(),
+				Schedule:      domain.Schedule{Kind: tt.kind, StartAt: start},
+				Status:        tt.status,
+				NextRunAt:     start,
+				NextAttemptAt: start,
+				Attempts:      tt.attempts,
+				LockedUntil:   &lease,
+			}
+
+			run := applyRunResult(st, now, tt.txID, tt.err, 3, 5*time.Minute)
+
+			if st.Status != tt.wantStatus {
+				t.Fatalf("status got=%s want=%s", st.Status, tt.wantStatus)
+			}
+			if run.Status != tt.wantRun {
+				t.Fatalf("run status got=%s want=%s", run.Status, tt.wantRun)
+			}
+			if !run.OccurrenceAt.Equal(start) {
+				t.Fatalf("run occurrence got=%s want=%s", run.OccurrenceAt, start)
+			}
+			if !st.NextRunAt.Equal(tt.wantNextRun) {
+				t.Fatalf("next run got=%s want=%s", st.NextRunAt, tt.wantNextRun)
+			}
+			if !st.NextAttemptAt.Equal(tt.wantAttempt) {
+				t.Fatalf("next attempt got=%s want=%s", st.NextAttemptAt, tt.wantAttempt)
+			}
+			if st.Attempts != tt.wantCount {
+				t.Fatalf("attempts got=%d want=%d", st.Attempts, tt.wantCount)
+			}
+			if st.LockedUntil != nil {
+				t.Fatalf("lease not released")
+			}
+			if want := apperr.RootCause(tt.err); want != nil && (run.Error == nil || *run.Error != want.Error()) {
+				t.Fatalf("run error got=%v want=%q", run.Error, want.Error())
+			}
+		})
+	}
+}
+
+func TestOccurrenceIdempotencyKeyStableAcrossAttempts(t *testing.T) {
+	st := &domain.ScheduledTransfer{ID: uuid.New(), NextRunAt: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)}
+	first := occurrenceIdempotencyKey(st)
+	st.Attempts = 2
+	if got := occurrenceIdempotencyKey(st); got != first {
+		t.Fatalf("got=%s want=%s", got, first)
+	}
+	st.NextRunAt = st.NextRunAt.AddDate(0, 1, 0)
+	if got := occurrenceIdempotencyKey(st); got == first {
+		t.Fatalf("key did not change for a new occurrence: %s", got)
+	}
+}
+
+// createdScheduledTransfers keeps the orders Create stores; nothing else is expected to be called.
+type createdScheduledTransfers struct {
+	ScheduledTransferRepo
+	created []*domain.ScheduledTransfer
+}
+
+func (r *createdScheduledTransfers) Create(ctx context.Context, st *domain.ScheduledTransfer) error {
+	r.created = append(r.created, st)
+	return nil
+}
+
+// codeStepUp accepts only code.
+type codeStepUp struct {
+	code string
+}
+
+func (v codeStepUp) VerifyStepUp(ctx context.Context, userID uuid.UUID, code string) error {
+	switch code {
+	case "":
+		return apperr.ErrMFACodeRequired
+	case v.code:
+		return nil
+	default:
+		return apperr.ErrInvalidMFACode
+	}
+}
+
+func TestScheduledTransferCreateStepUp(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	userID := uuid.New()
+	recipient := &domain.User{ID: uuid.New(), Email: "bob@test.com"}
+	users := &memoryUserRepo{users: map[uuid.UUID]*domain.User{recipient.ID: recipient}}
+	currencies := domain.NewCurrencyRegistry([]domain.CurrencyInfo{{Code: domain.CurrencyUSD, MinorUnits: 2, Enabled: true}})
+
+	tests := []struct {
+		name    string
+		amount  int64
+		code    string
+		wantErr error
+	}{
+		{name: "below_threshold_without_code", amount: 1_000_00},
+		{name: "above_threshold_without_code", amount: 5_000_00, wantErr: apperr.ErrMFACodeRequired},
+		{name: "above_threshold_wrong_code", amount: 5_000_00, code: "000000", wantErr: apperr.ErrInvalidMFACode},
+		{name: "above_threshold_with_code", amount: 5_000_00, code: "123456"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &createdScheduledTransfers{}
+			s := NewScheduledTransferService(repo, nil, users, nil, nil, currencies, 3, time.Minute, time.Minute, codeStepUp{code: "123456"}, 1_000_00, logger)
+
+			// Starting now, the first run would be picked up by the next worker tick.
+			_, err := s.Create(ctx, userID, &domain.CreateScheduledTransferInput{
+				ToUserID:    &recipient.ID,
+				Currency:    domain.CurrencyUSD,
+				AmountCents: tt.amount,
+				Schedule:    domain.Schedule{Kind: domain.ScheduleOnce, StartAt: time.Now().UTC()},
+				MFACode:     tt.code,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err=%v want=%v", err, tt.wantErr)
+			}
+			if stored := len(repo.created) == 1; stored != (tt.wantErr == nil) {
+				t.Fatalf("stored=%v", stored)
+			}
+		})
+	}
+}
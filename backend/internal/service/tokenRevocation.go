@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"banking-platform/internal/jwt"
+	"github.com/google/uuid"
+)
+
+// revocationSyncOverlap re-reads recent rows on every sync so revocations written by other instances
+// with a slightly skewed clock are not missed.
+const revocationSyncOverlap = 30 * time.Second
+
+// TokenRevocationStore answers "is this access token revoked?" from memory. Revocations are written
+// to Postgres and to the local cache at once; other instances pick them up on their next sync.
+type TokenRevocationStore struct {
+	repo      TokenRevocationRepo
+	accessTTL time.Duration
+	interval  time.Duration
+	logger    *slog.Logger
+
+	mu       sync.RWMutex
+	tokens   map[string]time.Time
+	sessions map[uuid.UUID]time.Time
+	cutoffs  map[uuid.UUID]time.Time
+	syncedAt time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewTokenRevocationStore(repo TokenRevocationRepo, accessTTL time.Duration, interval time.Duration, logger *slog.Logger) *TokenRevocationStore {
+	return &TokenRevocationStore{
+		repo:      repo,
+		accessTTL: accessTTL,
+		interval:  interval,
+		logger:    logger,
+		tokens:    make(map[string]time.Time),
+		sessions:  make(map[uuid.UUID]time.Time),
+		cutoffs:   make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Load reads all current revocations. Call it once before serving requests.
+func (s *TokenRevocationStore) Load(ctx context.Context) error {
+	if err := s.sync(ctx); err != nil {
+		return fmt.Errorf("load token revocations: %w", err)
+	}
+	return nil
+}
+
+// Start syncs with Postgres in background until Stop is called.
+func (s *TokenRevocationStore) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		s.logger.Info("Token revocation sync started", "interval", s.interval.String())
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.sync(ctx); err != nil {
+					s.logger.Error("Token revocation sync failed; keeping cached revocations", "error", err)
+				}
+			case <-ctx.Done():
+				s.logger.Info("Token revocation sync stopped")
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the sync loop to stop and waits until it finishes (or ctx is done).
+func (s *TokenRevocationStore) Stop(ctx context.Context) {
+	if s == nil || s.cancel == nil {
+		return
+	}
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+	}
+}
+
+// IsRevoked implements jwt.RevocationChecker. Tokens issued in the same second as a user's cutoff
+// are revoked too, since iat has one-second precision.
+func (s *TokenRevocationStore) IsRevoked(ctx context.Context, claims *jwt.Claims) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if claims.ID != "" {
+		if _, ok := s.tokens[claims.ID]; ok {
+			return true, nil
+		}
+	}
+	if sessionID, err := uuid.Parse(claims.SessionID); err == nil {
+		if _, ok := s.sessions[sessionID]; ok {
+			return true, nil
+		}
+	}
+	cutoff, ok := s.cutoffs[claims.UserID]
+	if !ok {
+		return false, nil
+	}
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+	return !claims.IssuedAt.Time.After(cutoff.Truncate(time.Second)), nil
+}
+
+// RevokeToken revokes a single access token until it expires.
+func (s *TokenRevocationStore) RevokeToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error {
+	if err := s.repo.RevokeToken(ctx, &RevokedAccessToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt.UTC(),
+		RevokedAt: time.Now().UTC(),
+	}); err != nil {
+		return fmt.Errorf("revoke access token: %w", err)
+	}
+
+	s.mu.Lock()
+	s.tokens[jti] = expiresAt
+	s.mu.Unlock()
+	return nil
+}
+
+// RevokeSession rejects every access token issued for the session. The entry is kept for one access
+// token TTL, after which all of them have expired.
+func (s *TokenRevocationStore) RevokeSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) error {
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.accessTTL)
+	if err := s.repo.RevokeSession(ctx, &RevokedSession{
+		SessionID: sessionID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		RevokedAt: now,
+	}); err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+
+	s.mu.Lock()
+	s.sessions[sessionID] = expiresAt
+	s.mu.Unlock()
+	return nil
+}
+
+// RevokeAllForUser revokes every access token of the user issued up to at.
+func (s *TokenRevocationStore) RevokeAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	at = at.UTC()
+	if err := s.repo.SetCutoff(ctx, &AccessTokenCutoff{UserID: userID, RevokedBefore: at, UpdatedAt: at}); err != nil {
+		return fmt.Errorf("revoke user access tokens: %w", err)
+	}
+
+	s.mu.Lock()
+	s.setCutoffLocked(userID, at)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *TokenRevocationStore) sync(ctx context.Context) error {
+	now := time.Now().UTC()
+	s.mu.RLock()
+	since := s.syncedAt
+	s.mu.RUnlock()
+	if !since.IsZero() {
+		since = since.Add(-revocationSyncOverlap)
+	}
+
+	tokens, err := s.repo.ListRevokedSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	sessions, err := s.repo.ListRevokedSessionsSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	cutoffs, err := s.repo.ListCutoffsSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	// Cutoffs older than the access token TTL cannot match any unexpired token.
+	staleBefore := now.Add(-s.accessTTL)
+	if err := s.repo.DeleteExpired(ctx, now, staleBefore); err != nil {
+		s.logger.Warn("Failed to purge expired token revocations", "error", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range tokens {
+		s.tokens[t.JTI] = t.ExpiresAt
+	}
+	for _, rs := range sessions {
+		s.sessions[rs.SessionID] = rs.ExpiresAt
+	}
+	for _, c := range cutoffs {
+		s.setCutoffLocked(c.UserID, c.RevokedBefore)
+	}
+	for jti, exp := range s.tokens {
+		if !exp.After(now) {
+			delete(s.tokens, jti)
+		}
+	}
+	for sessionID, exp := range s.sessions {
+		if !exp.After(now) {
+			delete(s.sessions, sessionID)
+		}
+	}
+	for userID, cutoff := range s.cutoffs {
+		if cutoff.Before(staleBefore) {
+			delete(s.cutoffs, userID)
+		}
+	}
+	s.syncedAt = now
+	return nil
+}
+
+func (s *TokenRevocationStore) setCutoffLocked(userID uuid.UUID, at time.Time) {
+	if cur, ok := s.cutoffs[userID]; !ok || at.After(cur) {
+		s.cutoffs[userID] = at
+	}
+}
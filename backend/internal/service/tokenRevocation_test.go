@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"banking-platform/internal/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// memoryTokenRevocationRepo stands in for the shared Postgres tables.
+type memoryTokenRevocationRepo struct {
+	tokens   []*RevokedAccessToken
+	sessions []*RevokedSession
+	cutoffs  map[uuid.UUID]*AccessTokenCutoff
+}
+
+func (r *memoryTokenRevocationRepo) RevokeToken(ctx context.Context, token *RevokedAccessToken) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryTokenRevocationRepo) SetCutoff(ctx context.Context, cutoff *AccessTokenCutoff) error {
+	if r.cutoffs == nil {
+		r.cutoffs = make(map[uuid.UUID]*AccessTokenCutoff)
+	}
+	if cur, ok := r.cutoffs[cutoff.UserID]; !ok || cutoff.RevokedBefore.After(cur.RevokedBefore) {
+		r.cutoffs[cutoff.UserID] = cutoff
+	}
+	return nil
+}
+
+func (r *memoryTokenRevocationRepo) ListRevokedSince(ctx context.Context, since time.Time) ([]*RevokedAccessToken, error) {
+	var out []*RevokedAccessToken
+	for _, t := range r.tokens {
+		if !t.RevokedAt.Before(since) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryTokenRevocationRepo) ListCutoffsSince(ctx context.Context, since time.Time) ([]*AccessTokenCutoff, error) {
+	var out []*AccessTokenCutoff
+	for _, c := range r.cutoffs {
+		if !c.UpdatedAt.Before(since) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryTokenRevocationRepo) RevokeSession(ctx context.Context, session *RevokedSession) error {
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+func (r *memoryTokenRevocationRepo) ListRevokedSessionsSince(ctx context.Context, since time.Time) ([]*RevokedSession, error) {
+	var out []*RevokedSession
+	for _, s := range r.sessions {
+		if !s.RevokedAt.Before(since) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryTokenRevocationRepo) DeleteExpired(ctx context.Context, now time.Time, cutoffsBefore time.Time) error {
+	return nil
+}
+
+func testClaims(userID uuid.UUID, jti string, issuedAt time.Time) *jwt.Claims {
+	return &jwt.Claims{
+		UserID: userID,
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  gojwt.NewNumericDate(issuedAt),
+			ExpiresAt: gojwt.NewNumericDate(issuedAt.Add(15 * time.Minute)),
+		},
+	}
+}
+
+func TestTokenRevocationStoreIsRevoked(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	userID := uuid.New()
+	cutoff := time.Now().UTC().Truncate(time.Second)
+
+	store := NewTokenRevocationStore(&memoryTokenRevocationRepo{}, 15*time.Minute, time.Second, logger)
+	ctx := context.Background()
+	if err := store.RevokeToken(ctx, "revoked-jti", userID, cutoff.Add(time.Hour)); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := store.RevokeAllForUser(ctx, userID, cutoff.Add(-time.Minute)); err != nil {
+		t.Fatalf("revoke all: %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		claims *jwt.Claims
+		want   bool
+	}{
+		{name: "revoked_jti", claims: testClaims(userID, "revoked-jti", cutoff), want: true},
+		{name: "issued_before_cutoff", claims: testClaims(userID, "a", cutoff.Add(-2*time.Minute)), want: true},
+		{name: "issued_in_cutoff_second", claims: testClaims(userID, "b", cutoff.Add(-time.Minute)), want: true},
+		{name: "issued_after_cutoff", claims: testClaims(userID, "c", cutoff), want: false},
+		{name: "other_user", claims: testClaims(uuid.New(), "d", cutoff.Add(-2*time.Minute)), want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := store.IsRevoked(ctx, tc.claims)
+			if err != nil {
+				t.Fatalf("err=%v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got=%v want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTokenRevocationStoreSyncsOtherInstances(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	shared := &memoryTokenRevocationRepo{}
+	ctx := context.Background()
+	writer := NewTokenRevocationStore(shared, 15*time.Minute, time.Second, logger)
+	reader := NewTokenRevocationStore(shared, 15*time.Minute, time.Second, logger)
+	if err := reader.Load(ctx); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	userID := uuid.New()
+	issuedAt := time.Now().UTC().Add(-time.Minute)
+	if err := writer.RevokeAllForUser(ctx, userID, time.Now()); err != nil {
+		t.Fatalf("revoke all: %v", err)
+	}
+	claims := testClaims(userID, "x", issuedAt)
+	if revoked, _ := reader.IsRevoked(ctx, claims); revoked {
+		t.Fatalf("revoked before sync")
+	}
+	if err := reader.sync(ctx); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if revoked, _ := reader.IsRevoked(ctx, claims); !revoked {
+		t.Fatalf("not revoked after sync")
+	}
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"sort"
 	"strconv"
 	"strings"
@@ -16,15 +18,41 @@ import (
 
 var systemBankUserID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
 
+const (
+	defaultTransactionPageSize = 50
+	maxTransactionPageSize     = 100
+)
+
 type TransactionService struct {
 	txRunner        TxRunner
 	accountRepo     AccountRepo
 	transactionRepo TransactionRepo
 	ledgerRepo      LedgerRepo
 	userRepo        UserRepo
+	idempotencyRepo IdempotencyRepo
+	quoteRepo       ExchangeQuoteRepo
+	rateProvider    RateProvider
+	currencies      *domain.CurrencyRegistry
+	audit           AuditRecorder
 	logger          *slog.Logger
 
-	exchangeRateUSDtoEUR string
+	exchangeSpreadBps int64
+	quoteTTL          time.Duration
+
+	// authorizationTTL is how long an authorized transfer holds funds before it lapses.
+	authorizationTTL time.Duration
+
+	// stepUp is asked for a TOTP check on transfers above stepUpThreshold minor units; 0 disables it.
+	stepUp          StepUpVerifier
+	stepUpThreshold int64
+
+	// limits is checked for transfers to other users; nil disables transfer limits.
+	limits TransferLimitChecker
+
+	// risk screens transfers to other users and exchanges; nil disables screening. Held movements
+	// are queued in reviews.
+	risk    RiskEngine
+	reviews RiskReviewRepo
 }
 
 // Money is cents; balance changes are transactional; each transaction must be ledger-balanced.
@@ -35,29 +63,68 @@ func NewTransactionService(
 	transactionRepo TransactionRepo,
 	ledgerRepo LedgerRepo,
 	userRepo UserRepo,
-	exchangeRateUSDtoEUR string,
+	idempotencyRepo IdempotencyRepo,
+	quoteRepo ExchangeQuoteRepo,
+	rateProvider RateProvider,
+	currencies *domain.CurrencyRegistry,
+	exchangeSpreadBps int64,
+	quoteTTL time.Duration,
+	authorizationTTL time.Duration,
+	stepUp StepUpVerifier,
+	stepUpThreshold int64,
+	limits TransferLimitChecker,
+	risk RiskEngine,
+	reviews RiskReviewRepo,
+	audit AuditRecorder,
 	logger *slog.Logger,
 ) *TransactionService {
 	return &TransactionService{
-		txRunner:             txRunner,
-		accountRepo:          accountRepo,
-		transactionRepo:      transactionRepo,
-		ledgerRepo:           ledgerRepo,
-		userRepo:             userRepo,
-		logger:               logger,
-		exchangeRateUSDtoEUR: exchangeRateUSDtoEUR,
+		txRunner:        txRunner,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		ledgerRepo:      ledgerRepo,
+		userRepo:        userRepo,
+		idempotencyRepo: idempotencyRepo,
+		quoteRepo:       quoteRepo,
+		rateProvider:    rateProvider,
+		currencies:      currencies,
+		audit:           audit,
+		logger:          logger,
+
+		exchangeSpreadBps: exchangeSpreadBps,
+		quoteTTL:          quoteTTL,
+
+		authorizationTTL: authorizationTTL,
+
+		stepUp:          stepUp,
+		stepUpThreshold: stepUpThreshold,
+
+		limits: limits,
+
+		risk:    risk,
+		reviews: reviews,
 	}
 }
 
-// Transfer moves funds between users in the same currency.
+// Transfer moves funds between users in the same currency. With in.Authorize the funds are only
+// held: the transaction stays pending, posts no ledger entries and reduces the sender's available
+// balance until the recipient captures it, either party releases it or it expires.
 func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID, in *domain.TransferInput) (*domain.TransactionInfo, error) {
-	var toUserID uuid.UUID
-	if in.ToUserID != nil && in.ToUserEmail != nil {
-		return nil, apperr.BadRequest("provide either to_user_id or to_user_email")
+	recipients := 0
+	for _, set := range []bool{in.ToUserID != nil, in.ToUserEmail != nil, in.ToAccountID != nil} {
+		if set {
+			recipients++
+		}
+	}
+	if recipients > 1 {
+		return nil, apperr.BadRequest("provide only one of to_user_id, to_user_email or to_account_id")
 	}
-	if in.ToUserID != nil {
+
+	var toUserID uuid.UUID
+	switch {
+	case in.ToUserID != nil:
 		toUserID = *in.ToUserID
-	} else if in.ToUserEmail != nil {
+	case in.ToUserEmail != nil:
 		email := strings.ToLower(strings.TrimSpace(*in.ToUserEmail))
 		if email == "" {
 			return nil, apperr.BadRequest("to_user_email cannot be empty")
@@ -67,21 +134,73 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID,
 			return nil, fmt.Errorf("transaction.transfer: get recipient by email: %w", err)
 		}
 		toUserID = u.ID
-	} else {
+	case in.ToAccountID != nil:
+		acc, err := s.accountRepo.GetByID(ctx, *in.ToAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("transaction.transfer: get recipient account: %w", err)
+		}
+		toUserID = acc.UserID
+	default:
 		return nil, apperr.BadRequest("recipient is required")
 	}
-	if toUserID == fromUserID {
+	// Moving money between one's own accounts is allowed only when the target account is explicit.
+	if toUserID == fromUserID && in.ToAccountID == nil {
+		return nil, apperr.ErrCannotTransferToSelf
+	}
+	if in.ToAccountID != nil && in.FromAccountID != nil && *in.ToAccountID == *in.FromAccountID {
 		return nil, apperr.ErrCannotTransferToSelf
 	}
 
 	s.logger.Info("Processing transfer", "from_user_id", fromUserID, "to_user_id", toUserID, "amount_cents", in.AmountCents, "currency", in.Currency)
 
-	if in.Currency != domain.CurrencyUSD && in.Currency != domain.CurrencyEUR {
+	if !s.currencies.IsEnabled(in.Currency) {
 		s.logger.Warn("Invalid currency", "currency", in.Currency)
 		return nil, apperr.ErrInvalidCurrency
 	}
+	if err := validateIdempotencyKey(in.IdempotencyKey); err != nil {
+		return nil, err
+	}
 
 	amountCents := in.AmountCents
+	fingerprintParts := []string{domain.IdempotencyScopeTransfer, toUserID.String(), string(in.Currency), strconv.FormatInt(amountCents, 10),
+		optionalID(in.FromAccountID), optionalID(in.ToAccountID)}
+	if in.Authorize {
+		fingerprintParts = append(fingerprintParts, "authorize")
+	}
+	fingerprint := requestFingerprint(fingerprintParts...)
+
+	// A retry of a booked transfer gets its result back without being gated again.
+	replayID, err := s.replayedTransaction(ctx, fromUserID, domain.IdempotencyScopeTransfer, in.IdempotencyKey, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.transfer: %w", err)
+	}
+	if replayID != uuid.Nil {
+		s.logger.Info("Transfer replayed by idempotency key", "transaction_id", replayID, "from_user_id", fromUserID)
+		return s.getTransactionInfo(ctx, replayID)
+	}
+
+	if s.requiresStepUp(in) {
+		if err := s.stepUp.VerifyStepUp(ctx, fromUserID, in.MFACode); err != nil {
+			s.logger.Warn("Transfer step-up failed", "from_user_id", fromUserID, "amount_cents", in.AmountCents, "error", err)
+			return nil, err
+		}
+	}
+	// Standing order runs are screened like any transfer; approved reviews were screened when held.
+	if s.risk != nil && in.RiskReviewID == nil && toUserID != fromUserID {
+		held := *in
+		held.MFACode = ""
+		err := s.screen(ctx, &domain.RiskInput{
+			Type:        domain.TransactionTypeTransfer,
+			UserID:      fromUserID,
+			ToUserID:    &toUserID,
+			Currency:    in.Currency,
+			ToCurrency:  in.Currency,
+			AmountCents: in.AmountCents,
+		}, &domain.RiskReview{ToUserID: &toUserID, Transfer: &held, IdempotencyKey: in.IdempotencyKey})
+		if err != nil {
+			return nil, fmt.Errorf("transaction.transfer: %w", err)
+		}
+	}
 
 	var created *domain.Transaction
 	var fromAccountID uuid.UUID
@@ -91,13 +210,32 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID,
 	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
 		var err error
 
-		fromAccountID, err = s.accountRepo.FindAccountIDTx(ctx, tx, fromUserID, in.Currency)
+		replayID, err = s.reserveIdempotencyKeyTx(ctx, tx, fromUserID, domain.IdempotencyScopeTransfer, in.IdempotencyKey, fingerprint)
 		if err != nil {
-			return fmt.Errorf("transaction.transfer: find sender account: %w", err)
+			return fmt.Errorf("transaction.transfer: %w", err)
 		}
-		toAccountID, err = s.accountRepo.FindAccountIDTx(ctx, tx, toUserID, in.Currency)
-		if err != nil {
-			return fmt.Errorf("transaction.transfer: find recipient account: %w", err)
+		if replayID != uuid.Nil {
+			return nil
+		}
+
+		if in.FromAccountID != nil {
+			fromAccountID = *in.FromAccountID
+		} else {
+			fromAccountID, err = s.accountRepo.FindAccountIDTx(ctx, tx, fromUserID, in.Currency)
+			if err != nil {
+				return fmt.Errorf("transaction.transfer: find sender account: %w", err)
+			}
+		}
+		if in.ToAccountID != nil {
+			toAccountID = *in.ToAccountID
+		} else {
+			toAccountID, err = s.accountRepo.FindAccountIDTx(ctx, tx, toUserID, in.Currency)
+			if err != nil {
+				return fmt.Errorf("transaction.transfer: find recipient account: %w", err)
+			}
+		}
+		if fromAccountID == toAccountID {
+			return apperr.ErrCannotTransferToSelf
 		}
 
 		// Lock deterministically to avoid deadlocks.
@@ -122,18 +260,35 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID,
 		if fromAccount.UserID != fromUserID || toAccount.UserID != toUserID {
 			return apperr.ErrUnauthorized
 		}
+		if fromAccount.Currency != in.Currency || toAccount.Currency != in.Currency {
+			return apperr.ErrInvalidCurrency
+		}
+		if err := ensureAccountActive(fromAccount); err != nil {
+			return err
+		}
+		if err := ensureAccountActive(toAccount); err != nil {
+			return err
+		}
 
 		fromBalanceCents := fromAccount.BalanceCents
 		toBalanceCents := toAccount.BalanceCents
 
-		if fromBalanceCents < amountCents {
-			s.logger.Warn("Insufficient funds", "user_id", fromUserID, "balance_cents", fromBalanceCents, "amount_cents", amountCents)
+		if fromAccount.AvailableCents() < amountCents {
+			s.logger.Warn("Insufficient funds", "user_id", fromUserID, "balance_cents", fromBalanceCents, "held_cents", fromAccount.HeldCents, "amount_cents", amountCents)
 			return apperr.ErrInsufficientFunds
 		}
+		// Moving money between one's own accounts is not outgoing and does not count. Authorizations
+		// count once captured, and are checked again then.
+		if s.limits != nil && toUserID != fromUserID && !in.Authorize {
+			if err := s.limits.CheckTx(ctx, tx, fromUserID, in.Currency, amountCents); err != nil {
+				s.logger.Warn("Transfer limit exceeded", "user_id", fromUserID, "amount_cents", amountCents, "currency", in.Currency, "error", err)
+				return err
+			}
+		}
 
 		transactionID := uuid.New()
 		createdAt = time.Now()
-		amountStr := domain.CentsToDecimalString(amountCents)
+		amountStr := s.currencies.Format(in.Currency, amountCents)
 		created = &domain.Transaction{
 			ID:            transactionID,
 			Type:          domain.TransactionTypeTransfer,
@@ -141,18 +296,29 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID,
 			ToAccountID:   toAccount.ID,
 			AmountCents:   amountCents,
 			Currency:      in.Currency,
+			ToCurrency:    in.Currency,
 			Description:   fmt.Sprintf("Transfer %s %s from %s to %s", in.Currency, amountStr, fromUserID, toUserID),
+			Status:        domain.TransactionStatusPosted,
 			CreatedAt:     createdAt,
 		}
+		if in.Authorize {
+			expiresAt := createdAt.UTC().Add(s.authorizationTTL)
+			created.Status = domain.TransactionStatusPending
+			created.ExpiresAt = &expiresAt
+		}
 
 		if err := s.transactionRepo.Create(ctx, tx, created); err != nil {
 			return fmt.Errorf("transaction.transfer: create transaction: %w", err)
 		}
+		if in.Authorize {
+			return s.completeIdempotencyKeyTx(ctx, tx, fromUserID, in.IdempotencyKey, transactionID)
+		}
 
 		fromEntry := &domain.LedgerEntry{
 			ID:            uuid.New(),
 			TransactionID: transactionID,
 			AccountID:     fromAccount.ID,
+			Currency:      in.Currency,
 			AmountCents:   -amountCents,
 			CreatedAt:     createdAt,
 		}
@@ -164,6 +330,7 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID,
 			ID:            uuid.New(),
 			TransactionID: transactionID,
 			AccountID:     toAccount.ID,
+			Currency:      in.Currency,
 			AmountCents:   amountCents,
 			CreatedAt:     createdAt,
 		}
@@ -179,20 +346,25 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID,
 		newFromBalanceCents := fromBalanceCents - amountCents
 		newToBalanceCents := toBalanceCents + amountCents
 
-		if err := s.accountRepo.UpdateBalanceString(ctx, tx, fromAccount.ID, domain.CentsToDecimalString(newFromBalanceCents)); err != nil {
+		if err := s.accountRepo.UpdateBalanceString(ctx, tx, fromAccount.ID, s.currencies.Format(in.Currency, newFromBalanceCents)); err != nil {
 			return fmt.Errorf("transaction.transfer: update sender balance: %w", err)
 		}
 
-		if err := s.accountRepo.UpdateBalanceString(ctx, tx, toAccount.ID, domain.CentsToDecimalString(newToBalanceCents)); err != nil {
+		if err := s.accountRepo.UpdateBalanceString(ctx, tx, toAccount.ID, s.currencies.Format(in.Currency, newToBalanceCents)); err != nil {
 			return fmt.Errorf("transaction.transfer: update recipient balance: %w", err)
 		}
 
-		return nil
+		return s.completeIdempotencyKeyTx(ctx, tx, fromUserID, in.IdempotencyKey, transactionID)
 	}); err != nil {
 		return nil, err
 	}
 
-	s.logger.Info("Transfer completed successfully", "transaction_id", created.ID, "from_user_id", fromUserID, "to_user_id", toUserID, "amount_cents", created.AmountCents, "currency", in.Currency)
+	if replayID != uuid.Nil {
+		s.logger.Info("Transfer replayed by idempotency key", "transaction_id", replayID, "from_user_id", fromUserID)
+		return s.getTransactionInfo(ctx, replayID)
+	}
+
+	s.logger.Info("Transfer completed successfully", "transaction_id", created.ID, "from_user_id", fromUserID, "to_user_id", toUserID, "amount_cents", created.AmountCents, "currency", in.Currency, "status", created.Status)
 
 	fromUser, _ := s.userRepo.GetByID(ctx, fromUserID)
 	toUser, _ := s.userRepo.GetByID(ctx, toUserID)
@@ -204,7 +376,10 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID,
 		ToAccountID:   created.ToAccountID,
 		AmountCents:   created.AmountCents,
 		Currency:      created.Currency,
+		ToCurrency:    created.ToCurrency,
 		Description:   created.Description,
+		Status:        created.Status,
+		ExpiresAt:     created.ExpiresAt,
 		CreatedAt:     createdAt,
 	}
 	if fromUser != nil {
@@ -213,10 +388,134 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID uuid.UUID,
 	if toUser != nil {
 		resp.ToUserEmail = &toUser.Email
 	}
+	action := AuditActionTransfer
+	if in.Authorize {
+		action = AuditActionAuthorize
+	}
+	s.recordMovement(ctx, action, fromUserID, resp)
 	return resp, nil
 }
 
-// Exchange converts between USD and EUR using a fixed rate.
+func (s *TransactionService) completeIdempotencyKeyTx(ctx context.Context, tx Tx, userID uuid.UUID, key string, transactionID uuid.UUID) error {
+	if key == "" {
+		return nil
+	}
+	if err := s.idempotencyRepo.CompleteTx(ctx, tx, userID, domain.IdempotencyScopeTransfer, key, transactionID); err != nil {
+		return fmt.Errorf("transaction.transfer: complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// CheckExchangeLiquidity verifies the bank holds an account in every enabled currency. Exchanges
+// into or out of an enabled currency without one would fail on every request, so the server refuses
+// to start instead.
+func (s *TransactionService) CheckExchangeLiquidity(ctx context.Context) error {
+	for _, c := range s.currencies.Enabled() {
+		if _, err := s.accountRepo.GetByUserIDAndCurrency(ctx, systemBankUserID, c.Code); err != nil {
+			if errors.Is(err, apperr.ErrAccountNotFound) {
+				return fmt.Errorf("currency %s is enabled but the bank has no account in it", c.Code)
+			}
+			return fmt.Errorf("check bank account for %s: %w", c.Code, err)
+		}
+	}
+	return nil
+}
+
+// requiresStepUp reports whether the transfer needs a fresh TOTP code. Standing orders were
+// stepped up when they were created and run unattended; approved reviews were checked when held.
+func (s *TransactionService) requiresStepUp(in *domain.TransferInput) bool {
+	return s.stepUp != nil && s.stepUpThreshold > 0 && !in.Scheduled && in.RiskReviewID == nil && in.AmountCents > s.stepUpThreshold
+}
+
+// screen asks the risk engine about a movement before any lock is taken. It returns nil to go ahead,
+// apperr.ErrTransactionDeclined for a deny, and a *apperr.HeldForReviewError after queueing review
+// for a hold. A retry with the idempotency key of a held request gets that review's outcome instead
+// of a second review; once approved, the retry goes ahead and replays the booked transaction.
+func (s *TransactionService) screen(ctx context.Context, in *domain.RiskInput, review *domain.RiskReview) error {
+	if review.IdempotencyKey != "" {
+		existing, err := s.reviews.GetByIdempotencyKey(ctx, in.UserID, in.Type, review.IdempotencyKey)
+		if err == nil {
+			return reviewOutcome(existing)
+		}
+		if !errors.Is(err, apperr.ErrRiskReviewNotFound) {
+			return fmt.Errorf("get risk review: %w", err)
+		}
+	}
+
+	decision, err := s.risk.Assess(ctx, in)
+	if err != nil {
+		return fmt.Errorf("assess risk: %w", err)
+	}
+
+	switch decision.Action {
+	case domain.RiskDeny:
+		s.logger.Warn("Movement declined by risk rules", "user_id", in.UserID, "type", in.Type, "rule", decision.Rule, "reason", decision.Reason)
+		s.recordRisk(ctx, AuditActionRiskDeclined, in, decision, "")
+		return apperr.ErrTransactionDeclined
+
+	case domain.RiskHold:
+		review.ID = uuid.New()
+		review.UserID = in.UserID
+		review.Type = in.Type
+		review.Currency = in.Currency
+		review.AmountCents = in.AmountCents
+		review.Rule = decision.Rule
+		review.Reason = decision.Reason
+		review.Status = domain.RiskReviewPending
+		review.CreatedAt = time.Now()
+
+		saved, err := s.reviews.Create(ctx, review)
+		if err != nil {
+			return fmt.Errorf("create risk review: %w", err)
+		}
+		if saved.ID != review.ID {
+			// A concurrent retry with the same key queued it first.
+			return reviewOutcome(saved)
+		}
+		s.logger.Warn("Movement held for review", "user_id", in.UserID, "type", in.Type, "review_id", saved.ID, "rule", decision.Rule, "reason", decision.Reason)
+		s.recordRisk(ctx, AuditActionRiskHeld, in, decision, saved.ID.String())
+		return &apperr.HeldForReviewError{ReviewID: saved.ID.String()}
+	}
+	return nil
+}
+
+func reviewOutcome(review *domain.RiskReview) error {
+	switch review.Status {
+	case domain.RiskReviewApproved:
+		return nil
+	case domain.RiskReviewRejected:
+		return apperr.ErrTransactionDeclined
+	default:
+		return &apperr.HeldForReviewError{ReviewID: review.ID.String()}
+	}
+}
+
+func (s *TransactionService) recordRisk(ctx context.Context, action string, in *domain.RiskInput, decision *domain.RiskDecision, reviewID string) {
+	actorID := in.UserID
+	event := &domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    &actorID,
+		Action:     action,
+		TargetType: "risk_review",
+		TargetID:   reviewID,
+		Metadata: auditJSON(map[string]any{
+			"type":         in.Type,
+			"to_user_id":   in.ToUserID,
+			"currency":     in.Currency,
+			"to_currency":  in.ToCurrency,
+			"amount_cents": in.AmountCents,
+			"rule":         decision.Rule,
+			"reason":       decision.Reason,
+		}),
+		CreatedAt: time.Now().UTC(),
+	}
+	if p := domain.PrincipalFrom(ctx); p != nil && p.UserID == actorID {
+		event.ActorRole = p.Role
+	}
+	recordAudit(ctx, s.audit, s.logger, event)
+}
+
+// Exchange converts between currencies using the rate served by the configured RateProvider.
 func (s *TransactionService) Exchange(ctx context.Context, userID uuid.UUID, in *domain.ExchangeInput) (*domain.TransactionInfo, error) {
 	s.logger.Info("Processing exchange", "user_id", userID, "from_currency", in.FromCurrency, "to_currency", in.ToCurrency, "amount_cents", in.AmountCents)
 
@@ -224,173 +523,291 @@ func (s *TransactionService) Exchange(ctx context.Context, userID uuid.UUID, in
 		s.logger.Warn("Same currency for exchange", "currency", in.FromCurrency)
 		return nil, apperr.ErrCurrenciesMustDiffer
 	}
+	if !s.currencies.IsEnabled(in.FromCurrency) || !s.currencies.IsEnabled(in.ToCurrency) {
+		s.logger.Warn("Invalid currency for exchange", "from_currency", in.FromCurrency, "to_currency", in.ToCurrency)
+		return nil, apperr.ErrInvalidCurrency
+	}
 
-	amountCents := in.AmountCents
+	if err := validateIdempotencyKey(in.IdempotencyKey); err != nil {
+		return nil, err
+	}
+
+	fingerprint := requestFingerprint(domain.IdempotencyScopeExchange, string(in.FromCurrency), string(in.ToCurrency), strconv.FormatInt(in.AmountCents, 10))
 
-	rateNum, rateDen := parseRateToFraction(s.exchangeRateUSDtoEUR)
-	exchangeRate, convertedCents, err := convertExchange(amountCents, in.FromCurrency, in.ToCurrency, rateNum, rateDen)
+	// A retry of a booked exchange gets its result back without being screened or priced again.
+	replayID, err := s.replayedTransaction(ctx, userID, domain.IdempotencyScopeExchange, in.IdempotencyKey, fingerprint)
 	if err != nil {
-		return nil, fmt.Errorf("transaction.exchange: convert: %w", err)
+		return nil, fmt.Errorf("transaction.exchange: %w", err)
+	}
+	if replayID != uuid.Nil {
+		s.logger.Info("Exchange replayed by idempotency key", "transaction_id", replayID, "user_id", userID)
+		return s.getTransactionInfo(ctx, replayID)
 	}
-	convertedAmountCents := convertedCents
 
-	var created *domain.Transaction
-	var createdAt time.Time
-	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
-		userFromID, err := s.accountRepo.FindAccountIDTx(ctx, tx, userID, in.FromCurrency)
+	if s.risk != nil && in.RiskReviewID == nil {
+		held := *in
+		err := s.screen(ctx, &domain.RiskInput{
+			Type:        domain.TransactionTypeExchange,
+			UserID:      userID,
+			Currency:    in.FromCurrency,
+			ToCurrency:  in.ToCurrency,
+			AmountCents: in.AmountCents,
+		}, &domain.RiskReview{Exchange: &held, IdempotencyKey: in.IdempotencyKey})
 		if err != nil {
-			return fmt.Errorf("transaction.exchange: find user from account: %w", err)
+			return nil, fmt.Errorf("transaction.exchange: %w", err)
 		}
-		userToID, err := s.accountRepo.FindAccountIDTx(ctx, tx, userID, in.ToCurrency)
+	}
+
+	leg, err := s.priceExchange(ctx, in.FromCurrency, in.ToCurrency, in.AmountCents)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.exchange: %w", err)
+	}
+
+	var created *domain.Transaction
+	if err := s.txRunner.WithTx(ctx, func(tx Tx) error {
+		replayID, err = s.reserveIdempotencyKeyTx(ctx, tx, userID, domain.IdempotencyScopeExchange, in.IdempotencyKey, fingerprint)
 		if err != nil {
-			return fmt.Errorf("transaction.exchange: find user to account: %w", err)
+			return fmt.Errorf("transaction.exchange: %w", err)
 		}
-		bankFromID, err := s.accountRepo.FindAccountIDTx(ctx, tx, systemBankUserID, in.FromCurrency)
-		if err != nil {
-			return fmt.Errorf("transaction.exchange: find bank from account: %w", err)
+		if replayID != uuid.Nil {
+			return nil
 		}
-		bankToID, err := s.accountRepo.FindAccountIDTx(ctx, tx, systemBankUserID, in.ToCurrency)
+
+		created, err = s.exchangeTx(ctx, tx, userID, leg)
 		if err != nil {
-			return fmt.Errorf("transaction.exchange: find bank to account: %w", err)
+			return err
 		}
 
-		// Lock deterministically to avoid deadlocks.
-		lockIDs := []uuid.UUID{userFromID, userToID, bankFromID, bankToID}
-		sort.Slice(lockIDs, func(i, j int) bool { return lockIDs[i].String() < lockIDs[j].String() })
-
-		locked := make(map[uuid.UUID]*domain.Account, 4)
-		for _, id := range lockIDs {
-			acc, err := s.accountRepo.LockAccountForUpdate(ctx, tx, id)
-			if err != nil {
-				return fmt.Errorf("transaction.exchange: lock account: %w", err)
+		if in.IdempotencyKey != "" {
+			if err := s.idempotencyRepo.CompleteTx(ctx, tx, userID, domain.IdempotencyScopeExchange, in.IdempotencyKey, created.ID); err != nil {
+				return fmt.Errorf("transaction.exchange: complete idempotency key: %w", err)
 			}
-			locked[id] = acc
 		}
 
-		fromAccount := locked[userFromID]
-		toAccount := locked[userToID]
-		bankFrom := locked[bankFromID]
-		bankTo := locked[bankToID]
-		if fromAccount == nil || toAccount == nil || bankFrom == nil || bankTo == nil {
-			return fmt.Errorf("transaction.exchange: failed to lock accounts")
-		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
 
-		if fromAccount.UserID != userID || toAccount.UserID != userID || bankFrom.UserID != systemBankUserID || bankTo.UserID != systemBankUserID {
-			return apperr.ErrUnauthorized
-		}
+	if replayID != uuid.Nil {
+		s.logger.Info("Exchange replayed by idempotency key", "transaction_id", replayID, "user_id", userID)
+		return s.getTransactionInfo(ctx, replayID)
+	}
 
-		fromBalanceCents := fromAccount.BalanceCents
-		toBalanceCents := toAccount.BalanceCents
-		bankFromBalanceCents := bankFrom.BalanceCents
-		bankToBalanceCents := bankTo.BalanceCents
+	s.logger.Info("Exchange completed successfully", "transaction_id", created.ID, "user_id", userID, "amount_cents", in.AmountCents, "converted_amount_cents", leg.ConvertedCents)
 
-		if fromBalanceCents < amountCents {
-			s.logger.Warn("Insufficient funds for exchange", "user_id", userID, "balance_cents", fromBalanceCents, "amount_cents", amountCents)
-			return apperr.ErrInsufficientFunds
-		}
-		if bankToBalanceCents < convertedAmountCents {
-			s.logger.Error("Bank has insufficient liquidity", "currency", in.ToCurrency, "bank_balance_cents", bankToBalanceCents, "needed_cents", convertedCents)
-			return apperr.ErrLiquidityUnavailable
-		}
+	resp := s.exchangeResponse(ctx, userID, created)
+	s.recordMovement(ctx, AuditActionExchange, userID, resp)
+	return resp, nil
+}
 
-		transactionID := uuid.New()
-		createdAt = time.Now()
-		amountStr := domain.CentsToDecimalString(amountCents)
-		convertedStr := domain.CentsToDecimalString(convertedAmountCents)
-		created = &domain.Transaction{
-			ID:                   transactionID,
-			Type:                 domain.TransactionTypeExchange,
-			FromAccountID:        &fromAccount.ID,
-			ToAccountID:          toAccount.ID,
-			AmountCents:          amountCents,
-			Currency:             in.FromCurrency,
-			ExchangeRate:         &exchangeRate,
-			ConvertedAmountCents: &convertedAmountCents,
-			Description:          fmt.Sprintf("Exchange %s %s to %s %s", amountStr, in.FromCurrency, convertedStr, in.ToCurrency),
-			CreatedAt:            createdAt,
-		}
+// exchangeLeg is a fully priced conversion ready to be booked.
+type exchangeLeg struct {
+	From           domain.Currency
+	To             domain.Currency
+	AmountCents    int64
+	ConvertedCents int64
+	Rate           float64
+	RateNum        int64
+	RateDen        int64
+	SpreadBps      int64
+	RateSource     string
+	RateVersion    string
+	QuoteID        *uuid.UUID
+}
 
-		if err := s.transactionRepo.Create(ctx, tx, created); err != nil {
-			return fmt.Errorf("transaction.exchange: create transaction: %w", err)
-		}
+// priceExchange fetches the current rate and computes the converted amount net of spread.
+func (s *TransactionService) priceExchange(ctx context.Context, from domain.Currency, to domain.Currency, amountCents int64) (*exchangeLeg, error) {
+	rate, err := s.rateProvider.GetRate(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get rate: %w", err)
+	}
+	effectiveRate, convertedCents, err := convertExchange(amountCents, from, to, rate, s.currencies.MinorUnits(from), s.currencies.MinorUnits(to))
+	if err != nil {
+		return nil, fmt.Errorf("convert: %w", err)
+	}
+	return &exchangeLeg{
+		From:           from,
+		To:             to,
+		AmountCents:    amountCents,
+		ConvertedCents: applySpread(convertedCents, s.exchangeSpreadBps),
+		Rate:           effectiveRate,
+		RateNum:        rate.Num,
+		RateDen:        rate.Den,
+		SpreadBps:      s.exchangeSpreadBps,
+		RateSource:     rate.Source,
+		RateVersion:    rate.Version,
+	}, nil
+}
 
-		fromEntry := &domain.LedgerEntry{
-			ID:            uuid.New(),
-			TransactionID: transactionID,
-			AccountID:     fromAccount.ID,
-			AmountCents:   -amountCents,
-			CreatedAt:     createdAt,
-		}
-		if err := s.ledgerRepo.CreateEntry(ctx, tx, fromEntry); err != nil {
-			return fmt.Errorf("transaction.exchange: create ledger entry (user from): %w", err)
-		}
+// exchangeTx books a priced exchange inside tx using the system bank as counterparty.
+func (s *TransactionService) exchangeTx(ctx context.Context, tx Tx, userID uuid.UUID, leg *exchangeLeg) (*domain.Transaction, error) {
+	userFromID, err := s.accountRepo.FindAccountIDTx(ctx, tx, userID, leg.From)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.exchange: find user from account: %w", err)
+	}
+	userToID, err := s.accountRepo.FindAccountIDTx(ctx, tx, userID, leg.To)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.exchange: find user to account: %w", err)
+	}
+	bankFromID, err := s.accountRepo.FindAccountIDTx(ctx, tx, systemBankUserID, leg.From)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.exchange: find bank from account: %w", err)
+	}
+	bankToID, err := s.accountRepo.FindAccountIDTx(ctx, tx, systemBankUserID, leg.To)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.exchange: find bank to account: %w", err)
+	}
 
-		bankFromEntry := &domain.LedgerEntry{
-			ID:            uuid.New(),
-			TransactionID: transactionID,
-			AccountID:     bankFrom.ID,
-			AmountCents:   amountCents,
-			CreatedAt:     createdAt,
-		}
-		if err := s.ledgerRepo.CreateEntry(ctx, tx, bankFromEntry); err != nil {
-			return fmt.Errorf("transaction.exchange: create ledger entry (bank from): %w", err)
-		}
+	// Lock deterministically to avoid deadlocks.
+	lockIDs := []uuid.UUID{userFromID, userToID, bankFromID, bankToID}
+	sort.Slice(lockIDs, func(i, j int) bool { return lockIDs[i].String() < lockIDs[j].String() })
 
-		bankToEntry := &domain.LedgerEntry{
-			ID:            uuid.New(),
-			TransactionID: transactionID,
-			AccountID:     bankTo.ID,
-			AmountCents:   -convertedAmountCents,
-			CreatedAt:     createdAt,
-		}
-		if err := s.ledgerRepo.CreateEntry(ctx, tx, bankToEntry); err != nil {
-			return fmt.Errorf("transaction.exchange: create ledger entry (bank to): %w", err)
+	locked := make(map[uuid.UUID]*domain.Account, 4)
+	for _, id := range lockIDs {
+		acc, err := s.accountRepo.LockAccountForUpdate(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("transaction.exchange: lock account: %w", err)
 		}
+		locked[id] = acc
+	}
 
-		toEntry := &domain.LedgerEntry{
-			ID:            uuid.New(),
-			TransactionID: transactionID,
-			AccountID:     toAccount.ID,
-			AmountCents:   convertedAmountCents,
-			CreatedAt:     createdAt,
-		}
-		if err := s.ledgerRepo.CreateEntry(ctx, tx, toEntry); err != nil {
-			return fmt.Errorf("transaction.exchange: create ledger entry (user to): %w", err)
-		}
+	fromAccount := locked[userFromID]
+	toAccount := locked[userToID]
+	bankFrom := locked[bankFromID]
+	bankTo := locked[bankToID]
+	if fromAccount == nil || toAccount == nil || bankFrom == nil || bankTo == nil {
+		return nil, fmt.Errorf("transaction.exchange: failed to lock accounts")
+	}
 
-		if err := s.ledgerRepo.VerifyTransactionBalanceTx(ctx, tx, transactionID); err != nil {
-			s.logger.Error("Ledger not balanced (exchange)", "error", err, "transaction_id", transactionID)
-			return err
-		}
+	if fromAccount.UserID != userID || toAccount.UserID != userID || bankFrom.UserID != systemBankUserID || bankTo.UserID != systemBankUserID {
+		return nil, apperr.ErrUnauthorized
+	}
+	if err := ensureAccountActive(fromAccount); err != nil {
+		return nil, err
+	}
+	if err := ensureAccountActive(toAccount); err != nil {
+		return nil, err
+	}
 
-		newFromBalanceCents := fromBalanceCents - amountCents
-		newToBalanceCents := toBalanceCents + convertedAmountCents
-		newBankFromBalanceCents := bankFromBalanceCents + amountCents
-		newBankToBalanceCents := bankToBalanceCents - convertedAmountCents
+	fromBalanceCents := fromAccount.BalanceCents
+	toBalanceCents := toAccount.BalanceCents
+	bankFromBalanceCents := bankFrom.BalanceCents
+	bankToBalanceCents := bankTo.BalanceCents
 
-		if err := s.accountRepo.UpdateBalanceString(ctx, tx, fromAccount.ID, domain.CentsToDecimalString(newFromBalanceCents)); err != nil {
-			return fmt.Errorf("transaction.exchange: update user from balance: %w", err)
-		}
+	if fromAccount.AvailableCents() < leg.AmountCents {
+		s.logger.Warn("Insufficient funds for exchange", "user_id", userID, "balance_cents", fromBalanceCents, "held_cents", fromAccount.HeldCents, "amount_cents", leg.AmountCents)
+		return nil, apperr.ErrInsufficientFunds
+	}
+	if bankToBalanceCents < leg.ConvertedCents {
+		s.logger.Error("Bank has insufficient liquidity", "currency", leg.To, "bank_balance_cents", bankToBalanceCents, "needed_cents", leg.ConvertedCents)
+		return nil, apperr.ErrLiquidityUnavailable
+	}
 
-		if err := s.accountRepo.UpdateBalanceString(ctx, tx, toAccount.ID, domain.CentsToDecimalString(newToBalanceCents)); err != nil {
-			return fmt.Errorf("transaction.exchange: update user to balance: %w", err)
-		}
+	transactionID := uuid.New()
+	createdAt := time.Now()
+	amountStr := s.currencies.Format(leg.From, leg.AmountCents)
+	convertedStr := s.currencies.Format(leg.To, leg.ConvertedCents)
+	created := &domain.Transaction{
+		ID:                   transactionID,
+		Type:                 domain.TransactionTypeExchange,
+		FromAccountID:        &fromAccount.ID,
+		ToAccountID:          toAccount.ID,
+		AmountCents:          leg.AmountCents,
+		Currency:             leg.From,
+		ExchangeRate:         &leg.Rate,
+		ConvertedAmountCents: &leg.ConvertedCents,
+		ToCurrency:           leg.To,
+		RateSource:           &leg.RateSource,
+		RateVersion:          &leg.RateVersion,
+		QuoteID:              leg.QuoteID,
+		Description:          fmt.Sprintf("Exchange %s %s to %s %s", amountStr, leg.From, convertedStr, leg.To),
+		Status:               domain.TransactionStatusPosted,
+		CreatedAt:            createdAt,
+	}
 
-		if err := s.accountRepo.UpdateBalanceString(ctx, tx, bankFrom.ID, domain.CentsToDecimalString(newBankFromBalanceCents)); err != nil {
-			return fmt.Errorf("transaction.exchange: update bank from balance: %w", err)
-		}
+	if err := s.transactionRepo.Create(ctx, tx, created); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: create transaction: %w", err)
+	}
 
-		if err := s.accountRepo.UpdateBalanceString(ctx, tx, bankTo.ID, domain.CentsToDecimalString(newBankToBalanceCents)); err != nil {
-			return fmt.Errorf("transaction.exchange: update bank to balance: %w", err)
-		}
+	fromEntry := &domain.LedgerEntry{
+		ID:            uuid.New(),
+		TransactionID: transactionID,
+		AccountID:     fromAccount.ID,
+		Currency:      leg.From,
+		AmountCents:   -leg.AmountCents,
+		CreatedAt:     createdAt,
+	}
+	if err := s.ledgerRepo.CreateEntry(ctx, tx, fromEntry); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: create ledger entry (user from): %w", err)
+	}
 
-		return nil
-	}); err != nil {
+	bankFromEntry := &domain.LedgerEntry{
+		ID:            uuid.New(),
+		TransactionID: transactionID,
+		AccountID:     bankFrom.ID,
+		Currency:      leg.From,
+		AmountCents:   leg.AmountCents,
+		CreatedAt:     createdAt,
+	}
+	if err := s.ledgerRepo.CreateEntry(ctx, tx, bankFromEntry); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: create ledger entry (bank from): %w", err)
+	}
+
+	bankToEntry := &domain.LedgerEntry{
+		ID:            uuid.New(),
+		TransactionID: transactionID,
+		AccountID:     bankTo.ID,
+		Currency:      leg.To,
+		AmountCents:   -leg.ConvertedCents,
+		CreatedAt:     createdAt,
+	}
+	if err := s.ledgerRepo.CreateEntry(ctx, tx, bankToEntry); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: create ledger entry (bank to): %w", err)
+	}
+
+	toEntry := &domain.LedgerEntry{
+		ID:            uuid.New(),
+		TransactionID: transactionID,
+		AccountID:     toAccount.ID,
+		Currency:      leg.To,
+		AmountCents:   leg.ConvertedCents,
+		CreatedAt:     createdAt,
+	}
+	if err := s.ledgerRepo.CreateEntry(ctx, tx, toEntry); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: create ledger entry (user to): %w", err)
+	}
+
+	if err := s.ledgerRepo.VerifyTransactionBalanceTx(ctx, tx, transactionID); err != nil {
+		s.logger.Error("Ledger not balanced (exchange)", "error", err, "transaction_id", transactionID)
 		return nil, err
 	}
 
-	s.logger.Info("Exchange completed successfully", "transaction_id", created.ID, "user_id", userID, "amount_cents", in.AmountCents, "converted_amount_cents", convertedAmountCents)
+	newFromBalanceCents := fromBalanceCents - leg.AmountCents
+	newToBalanceCents := toBalanceCents + leg.ConvertedCents
+	newBankFromBalanceCents := bankFromBalanceCents + leg.AmountCents
+	newBankToBalanceCents := bankToBalanceCents - leg.ConvertedCents
 
+	if err := s.accountRepo.UpdateBalanceString(ctx, tx, fromAccount.ID, s.currencies.Format(leg.From, newFromBalanceCents)); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: update user from balance: %w", err)
+	}
+
+	if err := s.accountRepo.UpdateBalanceString(ctx, tx, toAccount.ID, s.currencies.Format(leg.To, newToBalanceCents)); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: update user to balance: %w", err)
+	}
+
+	if err := s.accountRepo.UpdateBalanceString(ctx, tx, bankFrom.ID, s.currencies.Format(leg.From, newBankFromBalanceCents)); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: update bank from balance: %w", err)
+	}
+
+	if err := s.accountRepo.UpdateBalanceString(ctx, tx, bankTo.ID, s.currencies.Format(leg.To, newBankToBalanceCents)); err != nil {
+		return nil, fmt.Errorf("transaction.exchange: update bank to balance: %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *TransactionService) exchangeResponse(ctx context.Context, userID uuid.UUID, created *domain.Transaction) *domain.TransactionInfo {
 	user, _ := s.userRepo.GetByID(ctx, userID)
 
 	response := &domain.TransactionInfo{
@@ -402,53 +819,218 @@ func (s *TransactionService) Exchange(ctx context.Context, userID uuid.UUID, in
 		Currency:             created.Currency,
 		ExchangeRate:         created.ExchangeRate,
 		ConvertedAmountCents: created.ConvertedAmountCents,
+		ToCurrency:           created.ToCurrency,
+		RateSource:           created.RateSource,
+		RateVersion:          created.RateVersion,
+		QuoteID:              created.QuoteID,
 		Description:          created.Description,
-		CreatedAt:            createdAt,
+		Status:               created.Status,
+		CreatedAt:            created.CreatedAt,
 	}
 	if user != nil {
 		response.FromUserEmail = &user.Email
 		response.ToUserEmail = &user.Email
 	}
 
-	return response, nil
+	return response
 }
 
-// GetUserTransactions returns a paginated list of transactions visible to the user.
-func (s *TransactionService) GetUserTransactions(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter) ([]*domain.TransactionInfo, error) {
+// GetUserTransactions returns one keyset page of the transactions visible to the user, newest first.
+func (s *TransactionService) GetUserTransactions(ctx context.Context, userID uuid.UUID, filter *domain.TransactionFilter) (*domain.TransactionPage, error) {
 	if filter == nil {
 		filter = &domain.TransactionFilter{}
 	}
 	f := filter
-	if f.Page < 1 {
-		f.Page = 1
-	}
 	if f.Limit < 1 {
-		f.Limit = 50
+		f.Limit = defaultTransactionPageSize
+	}
+	if f.Limit > maxTransactionPageSize {
+		f.Limit = maxTransactionPageSize
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+		return nil, apperr.BadRequest("min_amount_cents must not exceed max_amount_cents")
 	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return nil, apperr.BadRequest("from must be before to")
+	}
+	f.Counterparty = strings.ToLower(strings.TrimSpace(f.Counterparty))
 
-	items, err := s.transactionRepo.GetByUserID(ctx, userID, f)
+	var after *domain.TransactionCursor
+	if f.Cursor != "" {
+		c, err := domain.DecodeTransactionCursor(f.Cursor)
+		if err != nil {
+			return nil, apperr.BadRequest("invalid cursor")
+		}
+		after = c
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	items, err := s.transactionRepo.GetByUserID(ctx, userID, f, after, f.Limit+1)
 	if err != nil {
 		return nil, fmt.Errorf("transaction.list: %w", err)
 	}
-	out := make([]*domain.TransactionInfo, 0, len(items))
+	total, err := s.transactionRepo.CountByUserID(ctx, userID, f)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.list: count: %w", err)
+	}
+
+	page := &domain.TransactionPage{TotalCount: total}
+	if len(items) > f.Limit {
+		items = items[:f.Limit]
+		last := items[len(items)-1].Transaction
+		page.NextCursor = domain.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	page.Items = make([]*domain.TransactionInfo, 0, len(items))
 	for _, it := range items {
-		tx := it.Transaction
-		out = append(out, &domain.TransactionInfo{
-			ID:                   tx.ID,
-			Type:                 tx.Type,
-			FromAccountID:        tx.FromAccountID,
-			ToAccountID:          tx.ToAccountID,
-			AmountCents:          tx.AmountCents,
-			Currency:             tx.Currency,
-			ExchangeRate:         tx.ExchangeRate,
-			ConvertedAmountCents: tx.ConvertedAmountCents,
-			Description:          tx.Description,
-			CreatedAt:            tx.CreatedAt,
-			FromUserEmail:        it.FromUserEmail,
-			ToUserEmail:          it.ToUserEmail,
-		})
-	}
-	return out, nil
+		page.Items = append(page.Items, toTransactionInfo(it))
+	}
+	return page, nil
+}
+
+// reserveIdempotencyKeyTx claims key for the request inside tx. It returns the id of the
+// transaction created by an earlier identical request, or uuid.Nil if the caller should proceed.
+func (s *TransactionService) reserveIdempotencyKeyTx(ctx context.Context, tx Tx, userID uuid.UUID, scope string, key string, fingerprint string) (uuid.UUID, error) {
+	if key == "" {
+		return uuid.Nil, nil
+	}
+	existing, err := s.idempotencyRepo.ReserveTx(ctx, tx, &domain.IdempotencyKey{
+		UserID:      userID,
+		Scope:       scope,
+		Key:         key,
+		RequestHash: fingerprint,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if existing == nil {
+		return uuid.Nil, nil
+	}
+	if existing.RequestHash != fingerprint || existing.TransactionID == nil {
+		s.logger.Warn("Idempotency key reused with different request", "user_id", userID, "scope", scope)
+		return uuid.Nil, apperr.ErrIdempotencyKeyConflict
+	}
+	return *existing.TransactionID, nil
+}
+
+// replayedTransaction returns the transaction booked by an earlier identical request with key, or
+// uuid.Nil if there is none. It runs before step-up and risk screening so that a retry of a booked
+// request is answered with its result; a key reused for a different request is left to
+// reserveIdempotencyKeyTx to refuse.
+func (s *TransactionService) replayedTransaction(ctx context.Context, userID uuid.UUID, scope string, key string, fingerprint string) (uuid.UUID, error) {
+	if key == "" {
+		return uuid.Nil, nil
+	}
+	existing, err := s.idempotencyRepo.Get(ctx, userID, scope, key)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+	if existing == nil || existing.TransactionID == nil || existing.RequestHash != fingerprint {
+		return uuid.Nil, nil
+	}
+	return *existing.TransactionID, nil
+}
+
+// recordMovement audits a committed money movement. Idempotent replays are not recorded again.
+func (s *TransactionService) recordMovement(ctx context.Context, action string, actorID uuid.UUID, info *domain.TransactionInfo) {
+	event := &domain.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    &actorID,
+		Action:     action,
+		TargetType: "transaction",
+		TargetID:   info.ID.String(),
+		After:      auditTransaction(info),
+		CreatedAt:  time.Now().UTC(),
+	}
+	// Background work such as scheduled transfers runs without an authenticated principal.
+	if p := domain.PrincipalFrom(ctx); p != nil && p.UserID == actorID {
+		event.ActorRole = p.Role
+	}
+	recordAudit(ctx, s.audit, s.logger, event)
+}
+
+func (s *TransactionService) getTransactionInfo(ctx context.Context, id uuid.UUID) (*domain.TransactionInfo, error) {
+	it, err := s.transactionRepo.GetWithEmailsByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.get: %w", err)
+	}
+	return toTransactionInfo(it), nil
+}
+
+// GetTransactionDetail returns a transaction with its ledger postings. Only users owning one of the
+// posted accounts, or for authorizations one of its accounts, can see it; balances after each
+// posting are disclosed for their own accounts only.
+func (s *TransactionService) GetTransactionDetail(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.TransactionDetail, error) {
+	it, err := s.transactionRepo.GetWithEmailsByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.detail: %w", err)
+	}
+	postings, err := s.ledgerRepo.GetPostingsByTransactionID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("transaction.detail: load postings: %w", err)
+	}
+
+	participant := false
+	for _, p := range postings {
+		if p.OwnerID == userID {
+			participant = true
+			break
+		}
+	}
+	if !participant && it.Transaction.Status != domain.TransactionStatusPosted {
+		if participant, err = s.isParty(ctx, userID, &it.Transaction); err != nil {
+			return nil, fmt.Errorf("transaction.detail: %w", err)
+		}
+	}
+	if !participant {
+		// Same answer as for a missing transaction so ids cannot be probed.
+		s.logger.Warn("Transaction detail requested by non-participant", "transaction_id", id, "user_id", userID)
+		return nil, apperr.ErrTransactionNotFound
+	}
+
+	detail := &domain.TransactionDetail{
+		Transaction: *toTransactionInfo(it),
+		Postings:    postings,
+	}
+	for _, p := range postings {
+		if p.OwnerID != userID {
+			p.BalanceAfterCents = nil
+		}
+		name := p.OwnerName
+		if it.Transaction.FromAccountID != nil && p.AccountID == *it.Transaction.FromAccountID && detail.FromUserName == nil {
+			detail.FromUserName = &name
+		}
+		if p.AccountID == it.Transaction.ToAccountID && detail.ToUserName == nil {
+			detail.ToUserName = &name
+		}
+	}
+	return detail, nil
+}
+
+func toTransactionInfo(it *domain.TransactionWithEmails) *domain.TransactionInfo {
+	tx := it.Transaction
+	return &domain.TransactionInfo{
+		ID:                    tx.ID,
+		Type:                  tx.Type,
+		FromAccountID:         tx.FromAccountID,
+		ToAccountID:           tx.ToAccountID,
+		AmountCents:           tx.AmountCents,
+		Currency:              tx.Currency,
+		ExchangeRate:          tx.ExchangeRate,
+		ConvertedAmountCents:  tx.ConvertedAmountCents,
+		ToCurrency:            tx.ToCurrency,
+		RateSource:            tx.RateSource,
+		RateVersion:           tx.RateVersion,
+		QuoteID:               tx.QuoteID,
+		ReversesTransactionID: tx.ReversesTransactionID,
+		Description:           tx.Description,
+		Status:                tx.Status,
+		ExpiresAt:             tx.ExpiresAt,
+		CapturedAmountCents:   tx.CapturedAmountCents,
+		CreatedAt:             tx.CreatedAt,
+		FromUserEmail:         it.FromUserEmail,
+		ToUserEmail:           it.ToUserEmail,
+	}
 }
 
 func parseRateToFraction(raw string) (int64, int64) {
@@ -468,18 +1050,39 @@ func parseRateToFraction(raw string) (int64, int64) {
 	return n, scale
 }
 
-func convertExchange(amountCents int64, from domain.Currency, to domain.Currency, rateNum int64, rateDen int64) (float64, int64, error) {
-	if rateNum <= 0 || rateDen <= 0 {
+// convertExchange applies rate to an amount in minor units of from, returning minor units of to
+// rounded half up. The rate is quoted in major units and may be in either direction of the pair.
+func convertExchange(amount int64, from domain.Currency, to domain.Currency, rate *domain.ExchangeRate, fromUnits int, toUnits int) (float64, int64, error) {
+	if rate == nil || rate.Num <= 0 || rate.Den <= 0 {
 		return 0, 0, fmt.Errorf("invalid exchange rate")
 	}
 
-	if from == domain.CurrencyUSD && to == domain.CurrencyEUR {
-		converted := (amountCents*rateNum + rateDen/2) / rateDen
-		return float64(rateNum) / float64(rateDen), converted, nil
+	var num, den int64
+	switch {
+	case from == rate.Base && to == rate.Quote:
+		num, den = rate.Num, rate.Den
+	case from == rate.Quote && to == rate.Base:
+		num, den = rate.Den, rate.Num
+	default:
+		return 0, 0, apperr.ErrInvalidCurrency
 	}
-	if from == domain.CurrencyEUR && to == domain.CurrencyUSD {
-		converted := (amountCents*rateDen + rateNum/2) / rateNum
-		return float64(rateDen) / float64(rateNum), converted, nil
+
+	// converted = amount * num * 10^toUnits / (den * 10^fromUnits), in big ints to avoid overflow.
+	n := new(big.Int).Mul(big.NewInt(amount), big.NewInt(num))
+	n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(toUnits)), nil))
+	d := new(big.Int).Mul(big.NewInt(den), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fromUnits)), nil))
+	n.Add(n.Mul(n, big.NewInt(2)), d)
+	q := n.Quo(n, d.Mul(d, big.NewInt(2)))
+	if !q.IsInt64() {
+		return 0, 0, apperr.BadRequest("amount is too large to convert")
+	}
+	return float64(num) / float64(den), q.Int64(), nil
+}
+
+// applySpread deducts the bank's spread (in basis points) from a converted amount, rounding down.
+func applySpread(convertedCents int64, spreadBps int64) int64 {
+	if spreadBps <= 0 {
+		return convertedCents
 	}
-	return 0, 0, apperr.ErrInvalidCurrency
+	return convertedCents * (10_000 - spreadBps) / 10_000
 }
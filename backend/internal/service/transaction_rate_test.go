@@ -1,6 +1,10 @@
 package service
 
-import "testing"
+import (
+	"testing"
+
+	"banking-platform/internal/domain"
+)
 
 func TestParseRateToFraction(t *testing.T) {
 	testCases := []struct {
@@ -44,11 +48,10 @@ func TestConvertExchange_InvalidRate(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, _, err := convertExchange(100, "USD", "EUR", tc.num, tc.den)
+			_, _, err := convertExchange(100, "USD", "EUR", &domain.ExchangeRate{Base: "USD", Quote: "EUR", Num: tc.num, Den: tc.den}, 2, 2)
 			if (err != nil) != tc.wantErr {
 				t.Fatalf("err=%v wantErr=%v", err, tc.wantErr)
 			}
 		})
 	}
 }
-
@@ -43,6 +43,7 @@ func TestConvertExchange(t *testing.T) {
 	rateNum := int64(92)
 	rateDen := int64(100)
 	rate := float64(rateNum) / float64(rateDen)
+	usdToEUR := &domain.ExchangeRate{Base: domain.CurrencyUSD, Quote: domain.CurrencyEUR, Num: rateNum, Den: rateDen}
 
 	tests := []struct {
 		name     string
@@ -63,7 +64,7 @@ func TestConvertExchange(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRate, got, err := convertExchange(tt.amount, tt.from, tt.to, rateNum, rateDen)
+			gotRate, got, err := convertExchange(tt.amount, tt.from, tt.to, usdToEUR, 2, 2)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("err=%v wantErr=%v", err, tt.wantErr)
 			}
@@ -79,3 +80,55 @@ func TestConvertExchange(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertExchange_MinorUnits(t *testing.T) {
+	usdToJPY := &domain.ExchangeRate{Base: "USD", Quote: "JPY", Num: 15025, Den: 100}
+
+	tests := []struct {
+		name      string
+		amount    int64
+		from      domain.Currency
+		to        domain.Currency
+		fromUnits int
+		toUnits   int
+		want      int64
+	}{
+		{name: "usd_cents_to_yen_rounds_half_up", amount: 1000, from: "USD", to: "JPY", fromUnits: 2, toUnits: 0, want: 1503},
+		{name: "yen_to_usd_cents", amount: 1503, from: "JPY", to: "USD", fromUnits: 0, toUnits: 2, want: 1000},
+		{name: "one_yen_to_usd_cents", amount: 1, from: "JPY", to: "USD", fromUnits: 0, toUnits: 2, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := convertExchange(tt.amount, tt.from, tt.to, usdToJPY, tt.fromUnits, tt.toUnits)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got=%d want=%d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySpread(t *testing.T) {
+	testCases := []struct {
+		name      string
+		converted int64
+		spreadBps int64
+		want      int64
+	}{
+		{name: "no_spread", converted: 9200, spreadBps: 0, want: 9200},
+		{name: "fifty_bps", converted: 10000, spreadBps: 50, want: 9950},
+		{name: "rounds_down", converted: 9201, spreadBps: 25, want: 9177},
+		{name: "one_cent", converted: 1, spreadBps: 10, want: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := applySpread(tc.converted, tc.spreadBps); got != tc.want {
+				t.Fatalf("got=%d want=%d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+const maxTransferTierLength = 32
+
+// TransferLimiter enforces per-user outgoing transfer limits. A user's limits are their tier's,
+// with any per-currency overrides laid over them. Usage is read from the ledger inside the
+// transfer's transaction, so there is no counter to drift from the books.
+type TransferLimiter struct {
+	repo       TransferLimitRepo
+	txRunner   TxRunner
+	currencies *domain.CurrencyRegistry
+	now        func() time.Time
+}
+
+func NewTransferLimiter(repo TransferLimitRepo, txRunner TxRunner, currencies *domain.CurrencyRegistry) *TransferLimiter {
+	return &TransferLimiter{repo: repo, txRunner: txRunner, currencies: currencies, now: time.Now}
+}
+
+// CheckTx takes a per-user lock first: concurrent transfers from two accounts of the same user
+// would otherwise both see usage without the other. Amount limits count the currency sent; the
+// hourly count is velocity across all of the user's currencies, checked against the limit of the
+// currency sent. Monthly is checked before daily and daily before hourly, so the error names the
+// limit that stays in the way longest.
+func (l *TransferLimiter) CheckTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency, amountCents int64) error {
+	if err := l.repo.LockUsageTx(ctx, tx, userID); err != nil {
+		return fmt.Errorf("transfer_limit.check: lock usage: %w", err)
+	}
+	limits, err := l.repo.GetEffectiveTx(ctx, tx, userID, currency)
+	if err != nil {
+		return fmt.Errorf("transfer_limit.check: get limits: %w", err)
+	}
+
+	if limits.MaxSingleCents != nil && amountCents > *limits.MaxSingleCents {
+		return limitError(domain.TransferLimitSingle, currency, *limits.MaxSingleCents, nil)
+	}
+	if limits.DailyCents == nil && limits.MonthlyCents == nil && limits.HourlyCount == nil {
+		return nil
+	}
+
+	now := l.now().UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	hourStart := now.Add(-time.Hour)
+
+	usage, err := l.repo.GetUsageTx(ctx, tx, userID, currency, dayStart, monthStart, hourStart)
+	if err != nil {
+		return fmt.Errorf("transfer_limit.check: get usage: %w", err)
+	}
+
+	if limits.MonthlyCents != nil && usage.MonthCents+amountCents > *limits.MonthlyCents {
+		resetsAt := monthStart.AddDate(0, 1, 0)
+		return limitError(domain.TransferLimitMonthly, currency, *limits.MonthlyCents, &resetsAt)
+	}
+	if limits.DailyCents != nil && usage.DayCents+amountCents > *limits.DailyCents {
+		resetsAt := dayStart.AddDate(0, 0, 1)
+		return limitError(domain.TransferLimitDaily, currency, *limits.DailyCents, &resetsAt)
+	}
+	if limits.HourlyCount != nil && usage.HourCount >= *limits.HourlyCount {
+		// A slot frees up when the oldest transfer of the last hour falls out of the window.
+		resetsAt := now.Add(time.Hour)
+		if usage.OldestInHour != nil {
+			resetsAt = usage.OldestInHour.UTC().Add(time.Hour)
+		}
+		return limitError(domain.TransferLimitHourlyCount, currency, int64(*limits.HourlyCount), &resetsAt)
+	}
+	return nil
+}
+
+func limitError(kind domain.TransferLimitKind, currency domain.Currency, max int64, resetsAt *time.Time) error {
+	return &apperr.TransferLimitError{Limit: string(kind), Currency: string(currency), Max: max, ResetsAt: resetsAt}
+}
+
+// UserLimits returns the user's tier, overrides and effective limits.
+func (l *TransferLimiter) UserLimits(ctx context.Context, userID uuid.UUID) (*domain.UserTransferLimits, error) {
+	tier, err := l.repo.GetTier(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("transfer_limit.user_limits: get tier: %w", err)
+	}
+	overrides, err := l.repo.ListOverrides(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("transfer_limit.user_limits: list overrides: %w", err)
+	}
+	effective, err := l.repo.ListEffective(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("transfer_limit.user_limits: list effective: %w", err)
+	}
+	return &domain.UserTransferLimits{UserID: userID, Tier: tier, Overrides: overrides, Effective: effective}, nil
+}
+
+// SetUserLimits moves the user to in.Tier and replaces the user's overrides with in.Overrides.
+// Usage already booked this day or month keeps counting against the new limits.
+func (l *TransferLimiter) SetUserLimits(ctx context.Context, userID uuid.UUID, in *domain.SetTransferLimitsInput) (*domain.UserTransferLimits, error) {
+	tier := strings.TrimSpace(in.Tier)
+	if tier == "" || len(tier) > maxTransferTierLength {
+		return nil, apperr.BadRequest("tier must be between 1 and 32 characters")
+	}
+	seen := make(map[domain.Currency]bool, len(in.Overrides))
+	for _, o := range in.Overrides {
+		if !l.currencies.IsEnabled(o.Currency) {
+			return nil, apperr.ErrInvalidCurrency
+		}
+		if seen[o.Currency] {
+			return nil, apperr.BadRequest("only one override per currency is allowed")
+		}
+		seen[o.Currency] = true
+		if negative(o.MaxSingleCents) || negative(o.DailyCents) || negative(o.MonthlyCents) || (o.HourlyCount != nil && *o.HourlyCount < 0) {
+			return nil, apperr.BadRequest("limits cannot be negative")
+		}
+	}
+
+	if err := l.txRunner.WithTx(ctx, func(tx Tx) error {
+		exists, err := l.repo.TierExistsTx(ctx, tx, tier)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return apperr.BadRequest("unknown transfer tier " + tier)
+		}
+		if err := l.repo.SetTierTx(ctx, tx, userID, tier); err != nil {
+			return err
+		}
+		return l.repo.ReplaceOverridesTx(ctx, tx, userID, in.Overrides)
+	}); err != nil {
+		return nil, fmt.Errorf("transfer_limit.set_user_limits: %w", err)
+	}
+	return l.UserLimits(ctx, userID)
+}
+
+func negative(cents *int64) bool {
+	return cents != nil && *cents < 0
+}
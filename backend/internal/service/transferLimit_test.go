@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"banking-platform/internal/apperr"
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryTransferLimitRepo returns fixed limits and records what the limiter asked for. Usage is
+// fixed, or summed from sent the way the ledger query does when sent is set.
+type memoryTransferLimitRepo struct {
+	tier      string
+	tiers     map[string]bool
+	limits    *domain.TransferLimits
+	overrides []*domain.TransferLimits
+	usage     *domain.TransferUsage
+	sent      []sentTransfer
+
+	locked      bool
+	usageReads  int
+	windowStart [3]time.Time
+}
+
+type sentTransfer struct {
+	currency    domain.Currency
+	amountCents int64
+	at          time.Time
+}
+
+func (r *memoryTransferLimitRepo) LockUsageTx(ctx context.Context, tx Tx, userID uuid.UUID) error {
+	r.locked = true
+	return nil
+}
+
+func (r *memoryTransferLimitRepo) GetEffectiveTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency) (*domain.TransferLimits, error) {
+	return r.limits, nil
+}
+
+func (r *memoryTransferLimitRepo) GetUsageTx(ctx context.Context, tx Tx, userID uuid.UUID, currency domain.Currency, dayStart time.Time, monthStart time.Time, hourStart time.Time) (*domain.TransferUsage, error) {
+	r.usageReads++
+	r.windowStart = [3]time.Time{dayStart, monthStart, hourStart}
+	if r.sent == nil {
+		return r.usage, nil
+	}
+	usage := &domain.TransferUsage{}
+	for _, s := range r.sent {
+		if s.currency == currency && !s.at.Before(dayStart) {
+			usage.DayCents += s.amountCents
+		}
+		if s.currency == currency && !s.at.Before(monthStart) {
+			usage.MonthCents += s.amountCents
+		}
+		if !s.at.Before(hourStart) {
+			usage.HourCount++
+			if usage.OldestInHour == nil || s.at.Before(*usage.OldestInHour) {
+				at := s.at
+				usage.OldestInHour = &at
+			}
+		}
+	}
+	return usage, nil
+}
+
+func (r *memoryTransferLimitRepo) GetTier(ctx context.Context, userID uuid.UUID) (string, error) {
+	return r.tier, nil
+}
+
+func (r *memoryTransferLimitRepo) ListOverrides(ctx context.Context, userID uuid.UUID) ([]*domain.TransferLimits, error) {
+	return r.overrides, nil
+}
+
+func (r *memoryTransferLimitRepo) ListEffective(ctx context.Context, userID uuid.UUID) ([]*domain.TransferLimits, error) {
+	return []*domain.TransferLimits{r.limits}, nil
+}
+
+func (r *memoryTransferLimitRepo) TierExistsTx(ctx context.Context, tx Tx, tier string) (bool, error) {
+	return r.tiers[tier], nil
+}
+
+func (r *memoryTransferLimitRepo) SetTierTx(ctx context.Context, tx Tx, userID uuid.UUID, tier string) error {
+	r.tier = tier
+	return nil
+}
+
+func (r *memoryTransferLimitRepo) ReplaceOverridesTx(ctx context.Context, tx Tx, userID uuid.UUID, overrides []*domain.TransferLimits) error {
+	r.overrides = overrides
+	return nil
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+func intPtr(v int) *int       { return &v }
+
+func newTestTransferLimiter(repo *memoryTransferLimitRepo, now time.Time) *TransferLimiter {
+	currencies := domain.NewCurrencyRegistry([]domain.CurrencyInfo{
+		{Code: domain.CurrencyUSD, MinorUnits: 2, Enabled: true},
+		{Code: domain.CurrencyEUR, MinorUnits: 2, Enabled: true},
+		{Code: "GBP", MinorUnits: 2, Enabled: false},
+	})
+	l := NewTransferLimiter(repo, inlineTxRunner{}, currencies)
+	l.now = func() time.Time { return now }
+	return l
+}
+
+func TestTransferLimiterCheck(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	oldest := time.Date(2026, 3, 15, 9, 45, 0, 0, time.UTC)
+	limits := &domain.TransferLimits{
+		Currency:       domain.CurrencyUSD,
+		MaxSingleCents: int64Ptr(1_000_00),
+		DailyCents:     int64Ptr(5_000_00),
+		MonthlyCents:   int64Ptr(20_000_00),
+		HourlyCount:    intPtr(3),
+	}
+	nextDay := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	slotFree := oldest.Add(time.Hour)
+
+	testCases := []struct {
+		name         string
+		limits       *domain.TransferLimits
+		usage        domain.TransferUsage
+		amount       int64
+		wantLimit    domain.TransferLimitKind
+		wantMax      int64
+		wantResetsAt *time.Time
+	}{
+		{name: "within_limits", limits: limits, usage: domain.TransferUsage{DayCents: 1_000_00, MonthCents: 5_000_00, HourCount: 1, OldestInHour: &oldest}, amount: 500_00},
+		{name: "exactly_daily_limit", limits: limits, usage: domain.TransferUsage{DayCents: 4_000_00, MonthCents: 4_000_00}, amount: 1_000_00},
+		{name: "single", limits: limits, amount: 1_000_01, wantLimit: domain.TransferLimitSingle, wantMax: 1_000_00},
+		{name: "daily", limits: limits, usage: domain.TransferUsage{DayCents: 4_500_00, MonthCents: 4_500_00}, amount: 1_000_00,
+			wantLimit: domain.TransferLimitDaily, wantMax: 5_000_00, wantResetsAt: &nextDay},
+		{name: "monthly_reported_before_daily", limits: limits, usage: domain.TransferUsage{DayCents: 4_500_00, MonthCents: 19_500_00}, amount: 1_000_00,
+			wantLimit: domain.TransferLimitMonthly, wantMax: 20_000_00, wantResetsAt: &nextMonth},
+		{name: "hourly_count", limits: limits, usage: domain.TransferUsage{DayCents: 300, MonthCents: 300, HourCount: 3, OldestInHour: &oldest}, amount: 100,
+			wantLimit: domain.TransferLimitHourlyCount, wantMax: 3, wantResetsAt: &slotFree},
+		{name: "unlimited", limits: &domain.TransferLimits{Currency: domain.CurrencyUSD}, usage: domain.TransferUsage{DayCents: 1 << 40, HourCount: 1000}, amount: 1 << 40},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			usage := tc.usage
+			repo := &memoryTransferLimitRepo{limits: tc.limits, usage: &usage}
+			l := newTestTransferLimiter(repo, now)
+
+			err := l.CheckTx(context.Background(), nil, uuid.New(), domain.CurrencyUSD, tc.amount)
+			if !repo.locked {
+				t.Fatalf("usage was read without the per-user lock")
+			}
+			if tc.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("unexpected err: %v", err)
+				}
+				return
+			}
+
+			var limitErr *apperr.TransferLimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("err=%v want TransferLimitError", err)
+			}
+			if !errors.Is(err, apperr.ErrTransferLimitExceeded) {
+				t.Fatalf("err=%v does not wrap ErrTransferLimitExceeded", err)
+			}
+			if limitErr.Limit != string(tc.wantLimit) || limitErr.Currency != string(domain.CurrencyUSD) || limitErr.Max != tc.wantMax {
+				t.Fatalf("got %+v want limit=%s max=%d", limitErr, tc.wantLimit, tc.wantMax)
+			}
+			switch {
+			case tc.wantResetsAt == nil && limitErr.ResetsAt != nil:
+				t.Fatalf("resets_at=%v want none", limitErr.ResetsAt)
+			case tc.wantResetsAt != nil && (limitErr.ResetsAt == nil || !limitErr.ResetsAt.Equal(*tc.wantResetsAt)):
+				t.Fatalf("resets_at=%v want=%v", limitErr.ResetsAt, tc.wantResetsAt)
+			}
+		})
+	}
+}
+
+func TestTransferLimiterCheckWindows(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 20, 0, 0, time.UTC)
+
+	t.Run("usage_is_not_read_without_period_limits", func(t *testing.T) {
+		repo := &memoryTransferLimitRepo{limits: &domain.TransferLimits{Currency: domain.CurrencyUSD, MaxSingleCents: int64Ptr(100)}}
+		if err := newTestTransferLimiter(repo, now).CheckTx(context.Background(), nil, uuid.New(), domain.CurrencyUSD, 100); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if repo.usageReads != 0 {
+			t.Fatalf("usage reads=%d want 0", repo.usageReads)
+		}
+	})
+
+	t.Run("utc_day_month_and_rolling_hour", func(t *testing.T) {
+		repo := &memoryTransferLimitRepo{
+			limits: &domain.TransferLimits{Currency: domain.CurrencyUSD, HourlyCount: intPtr(10)},
+			usage:  &domain.TransferUsage{},
+		}
+		if err := newTestTransferLimiter(repo, now).CheckTx(context.Background(), nil, uuid.New(), domain.CurrencyUSD, 100); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		want := [3]time.Time{
+			time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 2, 28, 23, 20, 0, 0, time.UTC),
+		}
+		for i := range want {
+			if !repo.windowStart[i].Equal(want[i]) {
+				t.Fatalf("window %d start=%v want=%v", i, repo.windowStart[i], want[i])
+			}
+		}
+	})
+}
+
+func TestTransferLimiterHourlyCountSpansCurrencies(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	repo := &memoryTransferLimitRepo{
+		limits: &domain.TransferLimits{Currency: domain.CurrencyEUR, DailyCents: int64Ptr(1_000_00), HourlyCount: intPtr(3)},
+		sent: []sentTransfer{
+			{currency: domain.CurrencyUSD, amountCents: 900_00, at: now.Add(-50 * time.Minute)},
+			{currency: domain.CurrencyUSD, amountCents: 900_00, at: now.Add(-20 * time.Minute)},
+			{currency: domain.CurrencyEUR, amountCents: 100_00, at: now.Add(-10 * time.Minute)},
+		},
+	}
+	l := newTestTransferLimiter(repo, now)
+
+	// Two USD transfers and one EUR transfer in the last hour use up an hourly count of 3, even
+	// though the EUR daily amount has room: USD amounts do not count against EUR.
+	err := l.CheckTx(context.Background(), nil, uuid.New(), domain.CurrencyEUR, 100_00)
+	var limitErr *apperr.TransferLimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != string(domain.TransferLimitHourlyCount) {
+		t.Fatalf("err=%v want hourly_count", err)
+	}
+	if want := now.Add(10 * time.Minute); limitErr.ResetsAt == nil || !limitErr.ResetsAt.Equal(want) {
+		t.Fatalf("resets_at=%v want=%v", limitErr.ResetsAt, want)
+	}
+
+	// Once the oldest USD transfer leaves the window, EUR can be sent again.
+	repo.sent = repo.sent[1:]
+	if err := l.CheckTx(context.Background(), nil, uuid.New(), domain.CurrencyEUR, 100_00); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+}
+
+func TestTransferLimiterSetUserLimits(t *testing.T) {
+	testCases := []struct {
+		name      string
+		in        *domain.SetTransferLimitsInput
+		wantErr   error
+		wantTier  string
+		overrides int
+	}{
+		{name: "tier_and_override", in: &domain.SetTransferLimitsInput{Tier: "premium", Overrides: []*domain.TransferLimits{
+			{Currency: domain.CurrencyUSD, DailyCents: int64Ptr(50_000_00)},
+		}}, wantTier: "premium", overrides: 1},
+		{name: "clears_overrides", in: &domain.SetTransferLimitsInput{Tier: "standard"}, wantTier: "standard"},
+		{name: "unknown_tier", in: &domain.SetTransferLimitsInput{Tier: "gold"}, wantErr: &apperr.PublicError{}},
+		{name: "empty_tier", in: &domain.SetTransferLimitsInput{Tier: " "}, wantErr: &apperr.PublicError{}},
+		{name: "disabled_currency", in: &domain.SetTransferLimitsInput{Tier: "standard", Overrides: []*domain.TransferLimits{
+			{Currency: "GBP", DailyCents: int64Ptr(1)},
+		}}, wantErr: apperr.ErrInvalidCurrency},
+		{name: "duplicate_currency", in: &domain.SetTransferLimitsInput{Tier: "standard", Overrides: []*domain.TransferLimits{
+			{Currency: domain.CurrencyUSD}, {Currency: domain.CurrencyUSD},
+		}}, wantErr: &apperr.PublicError{}},
+		{name: "negative", in: &domain.SetTransferLimitsInput{Tier: "standard", Overrides: []*domain.TransferLimits{
+			{Currency: domain.CurrencyUSD, HourlyCount: intPtr(-1)},
+		}}, wantErr: &apperr.PublicError{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &memoryTransferLimitRepo{
+				tier:      domain.DefaultTransferTier,
+				tiers:     map[string]bool{"standard": true, "premium": true},
+				limits:    &domain.TransferLimits{Currency: domain.CurrencyUSD},
+				overrides: []*domain.TransferLimits{{Currency: domain.CurrencyEUR, DailyCents: int64Ptr(1)}},
+			}
+			got, err := newTestTransferLimiter(repo, time.Now()).SetUserLimits(context.Background(), uuid.New(), tc.in)
+
+			if tc.wantErr != nil {
+				var pub *apperr.PublicError
+				if _, isPublic := tc.wantErr.(*apperr.PublicError); isPublic {
+					if !errors.As(err, &pub) || pub.Status != 400 {
+						t.Fatalf("err=%v want 400 PublicError", err)
+					}
+				} else if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err=%v want=%v", err, tc.wantErr)
+				}
+				if repo.tier != domain.DefaultTransferTier || len(repo.overrides) != 1 {
+					t.Fatalf("limits changed on error: tier=%s overrides=%d", repo.tier, len(repo.overrides))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if got.Tier != tc.wantTier || len(got.Overrides) != tc.overrides {
+				t.Fatalf("tier=%s overrides=%d want tier=%s overrides=%d", got.Tier, len(got.Overrides), tc.wantTier, tc.overrides)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"banking-platform/internal/domain"
+)
+
+// camt053Namespace is the ISO 20022 Bank-to-Customer Statement schema this writer targets.
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+type camtDocument struct {
+	XMLName xml.Name     `xml:"Document"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Stmt    camtBkToCstm `xml:"BkToCstmrStmt"`
+}
+
+type camtBkToCstm struct {
+	GrpHdr camtGrpHdr    `xml:"GrpHdr"`
+	Stmt   camtStatement `xml:"Stmt"`
+}
+
+type camtGrpHdr struct {
+	MsgID   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+type camtStatement struct {
+	ID      string        `xml:"Id"`
+	CreDtTm string        `xml:"CreDtTm"`
+	FrToDt  camtFrToDt    `xml:"FrToDt"`
+	Acct    camtAccount   `xml:"Acct"`
+	Bal     []camtBalance `xml:"Bal"`
+	Summary camtTxsSummry `xml:"TxsSummry"`
+	Ntry    []camtEntry   `xml:"Ntry"`
+}
+
+type camtFrToDt struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camtAccount struct {
+	ID  string `xml:"Id>Othr>Id"`
+	Ccy string `xml:"Ccy"`
+	Nm  string `xml:"Nm,omitempty"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	DtTm      string     `xml:"Dt>DtTm"`
+}
+
+type camtTxsSummry struct {
+	NbOfNtries int `xml:"TtlNtries>NbOfNtries"`
+}
+
+type camtEntry struct {
+	NtryRef     string     `xml:"NtryRef"`
+	Amt         camtAmount `xml:"Amt"`
+	CdtDbtInd   string     `xml:"CdtDbtInd"`
+	Sts         string     `xml:"Sts"`
+	BookgDt     string     `xml:"BookgDt>DtTm"`
+	ValDt       string     `xml:"ValDt>DtTm"`
+	AcctSvcrRef string     `xml:"AcctSvcrRef"`
+	BkTxCd      string     `xml:"BkTxCd>Prtry>Cd"`
+	TxID        string     `xml:"NtryDtls>TxDtls>Refs>TxId"`
+	AddtlInf    string     `xml:"NtryDtls>TxDtls>AddtlTxInf,omitempty"`
+}
+
+// writeCamt053 emits a camt.053.001.02 document with opening (OPBD) and closing (CLBD) booked balances.
+func writeCamt053(w io.Writer, st *domain.Statement) error {
+	ccy := string(st.Currency)
+	id := st.AccountID.String() + "-" + st.From.UTC().Format("20060102") + "-" + st.To.UTC().Format("20060102")
+
+	doc := camtDocument{
+		Xmlns: camt053Namespace,
+		Stmt: camtBkToCstm{
+			GrpHdr: camtGrpHdr{MsgID: id, CreDtTm: timestamp(st.GeneratedAt)},
+			Stmt: camtStatement{
+				ID:      id,
+				CreDtTm: timestamp(st.GeneratedAt),
+				FrToDt:  camtFrToDt{FrDtTm: timestamp(st.From), ToDtTm: timestamp(st.To)},
+				Acct:    camtAccount{ID: st.AccountID.String(), Ccy: ccy, Nm: st.AccountName},
+				Bal: []camtBalance{
+					camtBalanceOf(st, "OPBD", st.OpeningBalanceCents, timestamp(st.From)),
+					camtBalanceOf(st, "CLBD", st.ClosingBalanceCents, timestamp(st.To)),
+				},
+				Summary: camtTxsSummry{NbOfNtries: len(st.Lines)},
+			},
+		},
+	}
+	for _, l := range st.Lines {
+		value, ind := camtSigned(st, l.AmountCents)
+		doc.Stmt.Stmt.Ntry = append(doc.Stmt.Stmt.Ntry, camtEntry{
+			NtryRef:     l.EntryID.String(),
+			Amt:         camtAmount{Ccy: ccy, Value: value},
+			CdtDbtInd:   ind,
+			Sts:         "BOOK",
+			BookgDt:     timestamp(l.BookedAt),
+			ValDt:       timestamp(l.BookedAt),
+			AcctSvcrRef: l.EntryID.String(),
+			BkTxCd:      strings.ToUpper(string(l.Type)),
+			TxID:        l.TransactionID.String(),
+			AddtlInf:    l.Description,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func camtBalanceOf(st *domain.Statement, code string, cents int64, at string) camtBalance {
+	value, ind := camtSigned(st, cents)
+	return camtBalance{Code: code, Amt: camtAmount{Ccy: string(st.Currency), Value: value}, CdtDbtInd: ind, DtTm: at}
+}
+
+// camtSigned splits a signed amount into the unsigned value and credit/debit indicator camt expects.
+func camtSigned(st *domain.Statement, cents int64) (string, string) {
+	if cents < 0 {
+		return amount(st, -cents), "DBIT"
+	}
+	return amount(st, cents), "CRDT"
+}
@@ -0,0 +1,35 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+
+	"banking-platform/internal/domain"
+)
+
+// writeCSV emits one row per ledger line framed by opening and closing balance rows.
+func writeCSV(w io.Writer, st *domain.Statement) error {
+	cw := csv.NewWriter(w)
+
+	rows := [][]string{
+		{"booked_at", "transaction_id", "type", "description", "amount", "balance", "currency"},
+		{timestamp(st.From), "", "opening_balance", "Opening balance", "", amount(st, st.OpeningBalanceCents), string(st.Currency)},
+	}
+	for _, l := range st.Lines {
+		rows = append(rows, []string{
+			timestamp(l.BookedAt),
+			l.TransactionID.String(),
+			string(l.Type),
+			l.Description,
+			amount(st, l.AmountCents),
+			amount(st, l.BalanceCents),
+			string(st.Currency),
+		})
+	}
+	rows = append(rows, []string{timestamp(st.To), "", "closing_balance", "Closing balance", "", amount(st, st.ClosingBalanceCents), string(st.Currency)})
+
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
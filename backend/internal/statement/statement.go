@@ -0,0 +1,62 @@
+// Package statement renders account statements in the export formats offered to customers.
+package statement
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"banking-platform/internal/domain"
+)
+
+// Write renders st in the given format.
+func Write(w io.Writer, format domain.StatementFormat, st *domain.Statement) error {
+	switch format {
+	case domain.StatementFormatCSV:
+		return writeCSV(w, st)
+	case domain.StatementFormatText:
+		return writeText(w, st)
+	case domain.StatementFormatCamt053:
+		return writeCamt053(w, st)
+	default:
+		return fmt.Errorf("unsupported statement format %q", format)
+	}
+}
+
+// IsSupported reports whether a format can be rendered.
+func IsSupported(format domain.StatementFormat) bool {
+	switch format {
+	case domain.StatementFormatCSV, domain.StatementFormatText, domain.StatementFormatCamt053:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of a rendered statement.
+func ContentType(format domain.StatementFormat) string {
+	switch format {
+	case domain.StatementFormatCSV:
+		return "text/csv; charset=utf-8"
+	case domain.StatementFormatCamt053:
+		return "application/xml; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// FileName returns a download name such as "statement-<account>-2024-01-01-2024-02-01.csv".
+func FileName(format domain.StatementFormat, st *domain.Statement) string {
+	ext := string(format)
+	if format == domain.StatementFormatCamt053 {
+		ext = "xml"
+	}
+	return fmt.Sprintf("statement-%s-%s-%s.%s", st.AccountID, st.From.UTC().Format("2006-01-02"), st.To.UTC().Format("2006-01-02"), ext)
+}
+
+func amount(st *domain.Statement, cents int64) string {
+	return domain.FormatMinorUnits(cents, st.MinorUnits)
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
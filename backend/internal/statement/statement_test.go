@@ -0,0 +1,115 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"banking-platform/internal/domain"
+	"github.com/google/uuid"
+)
+
+func testStatement() *domain.Statement {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.Statement{
+		AccountID:           uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		AccountName:         "Main EUR",
+		Currency:            domain.CurrencyEUR,
+		MinorUnits:          2,
+		From:                from,
+		To:                  from.AddDate(0, 1, 0),
+		OpeningBalanceCents: 10000,
+		ClosingBalanceCents: 7550,
+		Lines: []*domain.StatementLine{
+			{EntryID: uuid.New(), TransactionID: uuid.New(), Type: domain.TransactionTypeTransfer, Description: "rent, January", AmountCents: -5000, BalanceCents: 5000, BookedAt: from.Add(time.Hour)},
+			{EntryID: uuid.New(), TransactionID: uuid.New(), Type: domain.TransactionTypeExchange, Description: "fx", AmountCents: 2550, BalanceCents: 7550, BookedAt: from.Add(2 * time.Hour)},
+		},
+		GeneratedAt: from.AddDate(0, 1, 1),
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, domain.StatementFormatCSV, testStatement()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("rows got=%d want=%d", len(rows), 5)
+	}
+
+	testCases := []struct {
+		row     int
+		col     int
+		want    string
+		comment string
+	}{
+		{row: 1, col: 5, want: "100.00", comment: "opening balance"},
+		{row: 2, col: 3, want: "rent, January", comment: "description with comma"},
+		{row: 2, col: 4, want: "-50.00", comment: "debit amount"},
+		{row: 2, col: 5, want: "50.00", comment: "running balance"},
+		{row: 4, col: 5, want: "75.50", comment: "closing balance"},
+	}
+	for _, tc := range testCases {
+		if got := rows[tc.row][tc.col]; got != tc.want {
+			t.Fatalf("%s: got=%q want=%q", tc.comment, got, tc.want)
+		}
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, domain.StatementFormatText, testStatement()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Main EUR", "Opening balance", "100.00", "-50.00", "Closing balance", "75.50", "Entries: 2"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteCamt053(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, domain.StatementFormatCamt053, testStatement()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var doc camtDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	stmt := doc.Stmt.Stmt
+	if doc.XMLName.Space != camt053Namespace {
+		t.Fatalf("namespace got=%q want=%q", doc.XMLName.Space, camt053Namespace)
+	}
+	if len(stmt.Bal) != 2 || stmt.Bal[0].Code != "OPBD" || stmt.Bal[1].Code != "CLBD" {
+		t.Fatalf("unexpected balances: %+v", stmt.Bal)
+	}
+	if stmt.Bal[1].Amt.Value != "75.50" || stmt.Bal[1].Amt.Ccy != "EUR" {
+		t.Fatalf("closing got=%+v", stmt.Bal[1].Amt)
+	}
+	if len(stmt.Ntry) != 2 {
+		t.Fatalf("entries got=%d want=%d", len(stmt.Ntry), 2)
+	}
+	if stmt.Ntry[0].Amt.Value != "50.00" || stmt.Ntry[0].CdtDbtInd != "DBIT" {
+		t.Fatalf("debit entry got=%+v", stmt.Ntry[0])
+	}
+	if stmt.Ntry[1].CdtDbtInd != "CRDT" {
+		t.Fatalf("credit entry got=%+v", stmt.Ntry[1])
+	}
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, domain.StatementFormat("pdf"), testStatement()); err == nil {
+		t.Fatalf("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,53 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"banking-platform/internal/domain"
+)
+
+// writeText emits a human readable, fixed-column statement.
+func writeText(w io.Writer, st *domain.Statement) error {
+	ew := &errWriter{w: w}
+
+	ew.printf("ACCOUNT STATEMENT\n")
+	ew.printf("Account:   %s (%s)\n", st.AccountName, st.AccountID)
+	ew.printf("Currency:  %s\n", st.Currency)
+	ew.printf("Period:    %s - %s\n", timestamp(st.From), timestamp(st.To))
+	ew.printf("Generated: %s\n\n", timestamp(st.GeneratedAt))
+
+	tw := tabwriter.NewWriter(ew, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Booked at\tType\tAmount\tBalance\tDescription\t\n")
+	fmt.Fprintf(tw, "%s\t%s\t\t%s\t%s\t\n", timestamp(st.From), "opening", amount(st, st.OpeningBalanceCents), "Opening balance")
+	for _, l := range st.Lines {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t\n", timestamp(l.BookedAt), l.Type, amount(st, l.AmountCents), amount(st, l.BalanceCents), l.Description)
+	}
+	fmt.Fprintf(tw, "%s\t%s\t\t%s\t%s\t\n", timestamp(st.To), "closing", amount(st, st.ClosingBalanceCents), "Closing balance")
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	ew.printf("\nEntries: %d\n", len(st.Lines))
+	return ew.err
+}
+
+// errWriter keeps the first write error so formatting code can stay linear.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n, err := e.w.Write(p)
+	e.err = err
+	return n, err
+}
+
+func (e *errWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(e, format, args...)
+}
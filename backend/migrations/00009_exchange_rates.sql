@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    valid_from TIMESTAMP NOT NULL DEFAULT NOW(),
+    valid_to TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (base_currency <> quote_currency),
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_pair_valid_from ON exchange_rates(base_currency, quote_currency, valid_from DESC);
+
+INSERT INTO exchange_rates (base_currency, quote_currency, rate, valid_from)
+VALUES ('USD', 'EUR', 0.92, NOW());
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS rate_source VARCHAR(32);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS rate_version VARCHAR(64);
+
+-- +goose Down
+ALTER TABLE transactions DROP COLUMN IF EXISTS rate_version;
+ALTER TABLE transactions DROP COLUMN IF EXISTS rate_source;
+
+DROP INDEX IF EXISTS idx_exchange_rates_pair_valid_from;
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS exchange_quotes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_currency VARCHAR(3) NOT NULL,
+    to_currency VARCHAR(3) NOT NULL,
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    converted_amount DECIMAL(15, 2) NOT NULL CHECK (converted_amount >= 0),
+    rate_num BIGINT NOT NULL CHECK (rate_num > 0),
+    rate_den BIGINT NOT NULL CHECK (rate_den > 0),
+    spread_bps BIGINT NOT NULL DEFAULT 0,
+    rate_source VARCHAR(32) NOT NULL,
+    rate_version VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_quotes_user_id ON exchange_quotes(user_id);
+CREATE INDEX IF NOT EXISTS idx_exchange_quotes_expires_at ON exchange_quotes(expires_at);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS quote_id UUID REFERENCES exchange_quotes(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE transactions DROP COLUMN IF EXISTS quote_id;
+
+DROP INDEX IF EXISTS idx_exchange_quotes_expires_at;
+DROP INDEX IF EXISTS idx_exchange_quotes_user_id;
+DROP TABLE IF EXISTS exchange_quotes;
@@ -0,0 +1,114 @@
+-- +goose Up
+
+-- Currency registry. Amounts are stored in major units as DECIMAL(15,2),
+-- so only currencies with at most 2 minor units are supported.
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(3) PRIMARY KEY CHECK (code ~ '^[A-Z]{3}$'),
+    name VARCHAR(64) NOT NULL,
+    minor_units SMALLINT NOT NULL CHECK (minor_units BETWEEN 0 AND 2),
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO currencies (code, name, minor_units, enabled)
+VALUES
+    ('USD', 'US Dollar', 2, TRUE),
+    ('EUR', 'Euro', 2, TRUE),
+    ('GBP', 'Pound Sterling', 2, FALSE),
+    ('CHF', 'Swiss Franc', 2, FALSE),
+    ('JPY', 'Yen', 0, FALSE)
+ON CONFLICT (code) DO NOTHING;
+
+-- Replace the hardcoded USD/EUR check constraints with foreign keys to the registry.
+-- +goose StatementBegin
+DO $$
+DECLARE r record;
+BEGIN
+  FOR r IN
+    SELECT conrelid::regclass AS tbl, conname
+    FROM pg_constraint
+    WHERE conrelid IN ('accounts'::regclass, 'transactions'::regclass)
+      AND contype = 'c'
+      AND pg_get_constraintdef(oid) ILIKE '%currency%'
+  LOOP
+    EXECUTE format('ALTER TABLE %s DROP CONSTRAINT %I', r.tbl, r.conname);
+  END LOOP;
+END $$;
+-- +goose StatementEnd
+
+ALTER TABLE accounts
+  ADD CONSTRAINT accounts_currency_fkey FOREIGN KEY (currency) REFERENCES currencies(code);
+ALTER TABLE transactions
+  ADD CONSTRAINT transactions_currency_fkey FOREIGN KEY (currency) REFERENCES currencies(code);
+ALTER TABLE exchange_rates
+  ADD CONSTRAINT exchange_rates_base_currency_fkey FOREIGN KEY (base_currency) REFERENCES currencies(code);
+ALTER TABLE exchange_rates
+  ADD CONSTRAINT exchange_rates_quote_currency_fkey FOREIGN KEY (quote_currency) REFERENCES currencies(code);
+
+-- Cross rates record both legs' versions.
+ALTER TABLE transactions ALTER COLUMN rate_version TYPE VARCHAR(128);
+ALTER TABLE exchange_quotes ALTER COLUMN rate_version TYPE VARCHAR(128);
+
+-- System bank liquidity and equity accounts for the additional seeded currencies.
+INSERT INTO accounts (id, user_id, currency, balance, created_at, updated_at)
+VALUES
+    ('00000000-0000-0000-0000-000000000013', '00000000-0000-0000-0000-000000000001', 'GBP', 0.00, NOW(), NOW()),
+    ('00000000-0000-0000-0000-000000000014', '00000000-0000-0000-0000-000000000001', 'CHF', 0.00, NOW(), NOW()),
+    ('00000000-0000-0000-0000-000000000015', '00000000-0000-0000-0000-000000000001', 'JPY', 0.00, NOW(), NOW()),
+    ('00000000-0000-0000-0000-000000000023', '00000000-0000-0000-0000-000000000002', 'GBP', 0.00, NOW(), NOW()),
+    ('00000000-0000-0000-0000-000000000024', '00000000-0000-0000-0000-000000000002', 'CHF', 0.00, NOW(), NOW()),
+    ('00000000-0000-0000-0000-000000000025', '00000000-0000-0000-0000-000000000002', 'JPY', 0.00, NOW(), NOW())
+ON CONFLICT (user_id, currency) DO NOTHING;
+
+-- Fund the new bank accounts from equity through the ledger.
+WITH
+funding AS (
+  SELECT bank.id AS bank_id, equity.id AS equity_id, bank.currency, 1000000000.00::numeric(15,2) AS amount
+  FROM accounts bank
+  JOIN accounts equity
+    ON equity.currency = bank.currency
+   AND equity.user_id = '00000000-0000-0000-0000-000000000002'::uuid
+  WHERE bank.user_id = '00000000-0000-0000-0000-000000000001'::uuid
+    AND bank.currency IN ('GBP', 'CHF', 'JPY')
+    AND bank.balance = 0.00
+),
+ins AS (
+  INSERT INTO transactions (id, type, from_account_id, to_account_id, amount, currency, exchange_rate, converted_amount, description, created_at)
+  SELECT gen_random_uuid(), 'transfer', f.equity_id, f.bank_id, f.amount, f.currency, NULL, NULL, 'currency_liquidity_seed', NOW()
+  FROM funding f
+  RETURNING id, from_account_id, to_account_id, amount, created_at
+)
+INSERT INTO ledger (id, transaction_id, account_id, amount, created_at)
+SELECT gen_random_uuid(), id, from_account_id, -amount, created_at FROM ins
+UNION ALL
+SELECT gen_random_uuid(), id, to_account_id, amount, created_at FROM ins;
+
+UPDATE accounts a
+SET balance = ls.sum_amount, updated_at = NOW()
+FROM (
+  SELECT account_id, COALESCE(SUM(amount), 0.00)::numeric(15,2) AS sum_amount
+  FROM ledger
+  GROUP BY account_id
+) ls
+WHERE a.id = ls.account_id
+  AND a.currency IN ('GBP', 'CHF', 'JPY')
+  AND a.user_id IN ('00000000-0000-0000-0000-000000000001'::uuid, '00000000-0000-0000-0000-000000000002'::uuid);
+
+-- +goose Down
+
+-- Best-effort rollback: only USD/EUR data can satisfy the restored check constraints.
+DELETE FROM transactions WHERE description = 'currency_liquidity_seed';
+DELETE FROM accounts WHERE currency NOT IN ('USD', 'EUR');
+
+ALTER TABLE exchange_quotes ALTER COLUMN rate_version TYPE VARCHAR(64);
+ALTER TABLE transactions ALTER COLUMN rate_version TYPE VARCHAR(64);
+
+ALTER TABLE exchange_rates DROP CONSTRAINT IF EXISTS exchange_rates_quote_currency_fkey;
+ALTER TABLE exchange_rates DROP CONSTRAINT IF EXISTS exchange_rates_base_currency_fkey;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_currency_fkey;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_currency_fkey;
+
+ALTER TABLE accounts ADD CONSTRAINT accounts_currency_check CHECK (currency IN ('USD', 'EUR'));
+ALTER TABLE transactions ADD CONSTRAINT transactions_currency_check CHECK (currency IN ('USD', 'EUR'));
+
+DROP TABLE IF EXISTS currencies;
@@ -0,0 +1,46 @@
+-- +goose Up
+
+-- Accounts get a display name, a status lifecycle and a per-currency default flag.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'frozen', 'closed'));
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS is_default BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP;
+
+-- Until now every account was the only one for its (user, currency).
+UPDATE accounts SET is_default = TRUE, name = 'Main ' || currency WHERE name = '';
+
+-- Drop UNIQUE(user_id, currency); at most one default account per (user, currency) remains.
+-- +goose StatementBegin
+DO $$
+DECLARE r record;
+BEGIN
+  FOR r IN
+    SELECT conname
+    FROM pg_constraint
+    WHERE conrelid = 'accounts'::regclass
+      AND contype = 'u'
+      AND pg_get_constraintdef(oid) ILIKE '%(user_id, currency)%'
+  LOOP
+    EXECUTE format('ALTER TABLE accounts DROP CONSTRAINT %I', r.conname);
+  END LOOP;
+END $$;
+-- +goose StatementEnd
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_user_currency_default ON accounts(user_id, currency) WHERE is_default;
+CREATE INDEX IF NOT EXISTS idx_accounts_user_currency ON accounts(user_id, currency);
+
+-- +goose Down
+
+-- Best-effort rollback: only default accounts satisfy UNIQUE(user_id, currency).
+DELETE FROM accounts WHERE NOT is_default;
+
+DROP INDEX IF EXISTS idx_accounts_user_currency;
+DROP INDEX IF EXISTS idx_accounts_user_currency_default;
+
+ALTER TABLE accounts ADD CONSTRAINT accounts_user_id_currency_key UNIQUE (user_id, currency);
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE accounts DROP COLUMN IF EXISTS is_default;
+ALTER TABLE accounts DROP COLUMN IF EXISTS status;
+ALTER TABLE accounts DROP COLUMN IF EXISTS name;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- Keyset pagination over (created_at, id), newest first.
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at_id ON transactions(created_at DESC, id DESC);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_transactions_created_at_id;
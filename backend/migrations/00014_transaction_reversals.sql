@@ -0,0 +1,40 @@
+-- +goose Up
+
+-- Reversals are compensating transactions that point at the transaction they (partially) undo.
+-- +goose StatementBegin
+DO $$
+DECLARE r record;
+BEGIN
+  FOR r IN
+    SELECT conname
+    FROM pg_constraint
+    WHERE conrelid = 'transactions'::regclass
+      AND contype = 'c'
+      AND pg_get_constraintdef(oid) ILIKE '%type%'
+  LOOP
+    EXECUTE format('ALTER TABLE transactions DROP CONSTRAINT %I', r.conname);
+  END LOOP;
+END $$;
+-- +goose StatementEnd
+
+ALTER TABLE transactions
+  ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'exchange', 'reversal'));
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reverses_transaction_id UUID REFERENCES transactions(id);
+ALTER TABLE transactions
+  ADD CONSTRAINT transactions_reversal_link_check
+  CHECK ((type = 'reversal') = (reverses_transaction_id IS NOT NULL));
+
+CREATE INDEX IF NOT EXISTS idx_transactions_reverses ON transactions(reverses_transaction_id) WHERE reverses_transaction_id IS NOT NULL;
+
+-- +goose Down
+
+DELETE FROM transactions WHERE type = 'reversal';
+
+DROP INDEX IF EXISTS idx_transactions_reverses;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_reversal_link_check;
+ALTER TABLE transactions DROP COLUMN IF EXISTS reverses_transaction_id;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+  ADD CONSTRAINT transactions_type_check CHECK (type IN ('transfer', 'exchange'));
@@ -0,0 +1,54 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_account_id UUID REFERENCES accounts(id),
+    to_user_id UUID REFERENCES users(id),
+    to_account_id UUID REFERENCES accounts(id),
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    description VARCHAR(140) NOT NULL DEFAULT '',
+
+    schedule_kind VARCHAR(16) NOT NULL CHECK (schedule_kind IN ('once', 'weekly', 'monthly')),
+    start_at TIMESTAMP NOT NULL,
+    day_of_month SMALLINT CHECK (day_of_month BETWEEN 1 AND 31),
+    end_at TIMESTAMP,
+
+    status VARCHAR(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'cancelled', 'failed')),
+    next_run_at TIMESTAMP NOT NULL,
+    next_attempt_at TIMESTAMP NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    locked_until TIMESTAMP,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CHECK ((to_user_id IS NULL) <> (to_account_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_user_id ON scheduled_transfers(user_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_due ON scheduled_transfers(next_attempt_at) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS scheduled_transfer_runs (
+    id UUID PRIMARY KEY,
+    scheduled_transfer_id UUID NOT NULL REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+    occurrence_at TIMESTAMP NOT NULL,
+    attempt INT NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfer_runs_scheduled ON scheduled_transfer_runs(scheduled_transfer_id, created_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_scheduled_transfer_runs_scheduled;
+DROP TABLE IF EXISTS scheduled_transfer_runs;
+DROP INDEX IF EXISTS idx_scheduled_transfers_due;
+DROP INDEX IF EXISTS idx_scheduled_transfers_user_id;
+DROP TABLE IF EXISTS scheduled_transfers;
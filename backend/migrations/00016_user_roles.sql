@@ -0,0 +1,14 @@
+-- +goose Up
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'customer';
+ALTER TABLE users
+  ADD CONSTRAINT users_role_check CHECK (role IN ('customer', 'support', 'admin', 'auditor', 'system'));
+
+-- System bank liquidity and equity users own internal accounts and must never log in.
+UPDATE users SET role = 'system'
+WHERE id IN ('00000000-0000-0000-0000-000000000001'::uuid, '00000000-0000-0000-0000-000000000002'::uuid);
+
+-- +goose Down
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,26 @@
+-- +goose Up
+
+-- Individually revoked access tokens (by jti), kept until the token would have expired anyway.
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_revoked_at ON revoked_access_tokens(revoked_at);
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
+
+-- "Log out everywhere": access tokens of the user issued at or before revoked_before are invalid.
+CREATE TABLE IF NOT EXISTS access_token_cutoffs (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_token_cutoffs_updated_at ON access_token_cutoffs(updated_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS access_token_cutoffs;
+DROP TABLE IF EXISTS revoked_access_tokens;
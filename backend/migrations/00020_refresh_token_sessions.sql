@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- A session is a refresh token family. Client details are captured at login and updated on every
+-- refresh; session_created_at is copied from the parent so the live token carries the login time.
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_label VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_created_at TIMESTAMP;
+
+UPDATE refresh_tokens SET session_created_at = created_at WHERE session_created_at IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN session_created_at SET NOT NULL;
+
+-- Access tokens carry their session in the sid claim; revoking a session rejects them all.
+CREATE TABLE IF NOT EXISTS revoked_sessions (
+    session_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_sessions_revoked_at ON revoked_sessions(revoked_at);
+CREATE INDEX IF NOT EXISTS idx_revoked_sessions_expires_at ON revoked_sessions(expires_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS revoked_sessions;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_created_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS device_label;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS ip;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS user_agent;
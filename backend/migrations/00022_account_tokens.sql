@@ -0,0 +1,24 @@
+-- +goose Up
+
+-- NULL until the user follows the link of a verification email.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+
+-- Single-use links sent by email. Only the SHA-256 of the token is stored, as for refresh_tokens;
+-- used_at is set when the link is followed or a newer link of the same purpose replaces it.
+CREATE TABLE IF NOT EXISTS account_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL CHECK (purpose IN ('email_verification', 'password_reset')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_tokens_user_purpose ON account_tokens(user_id, purpose);
+CREATE INDEX IF NOT EXISTS idx_account_tokens_expires_at ON account_tokens(expires_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS account_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- Failed login attempts per normalized email and per client IP. An email row slows down and then
+-- locks further attempts for that address, registered or not; an IP row blocks a client that fails
+-- against many addresses. A successful login deletes the email row.
+CREATE TABLE IF NOT EXISTS login_throttles (
+    scope VARCHAR(16) NOT NULL CHECK (scope IN ('email', 'ip')),
+    key VARCHAR(255) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    first_failed_at TIMESTAMP NOT NULL,
+    last_failed_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_throttles_last_failed_at ON login_throttles(last_failed_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS login_throttles;
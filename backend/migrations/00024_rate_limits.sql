@@ -0,0 +1,16 @@
+-- +goose Up
+
+-- Shared rate limit state for RATE_LIMIT_STORE=postgres. Each key (policy, then user ID or client
+-- IP) holds the theoretical arrival time of the generic cell rate algorithm: the request is allowed
+-- if tat + interval - burst * interval <= now, and then tat moves forward by one interval. Rows whose
+-- tat is in the past carry no state and are deleted periodically.
+CREATE TABLE IF NOT EXISTS rate_limits (
+    key VARCHAR(255) PRIMARY KEY,
+    tat TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limits_tat ON rate_limits(tat);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS rate_limits;
@@ -0,0 +1,64 @@
+-- +goose Up
+
+-- Outgoing transfer limits. Every user is on a tier; a tier has one row of limits per currency and
+-- a user can have per-currency overrides on top of it. A NULL limit is unlimited, and so is a
+-- currency without a row. Usage is counted from the ledger when a transfer is booked.
+CREATE TABLE IF NOT EXISTS transfer_tiers (
+    name VARCHAR(32) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS transfer_limit_tiers (
+    tier VARCHAR(32) NOT NULL REFERENCES transfer_tiers(name) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    max_single_amount DECIMAL(15, 2) CHECK (max_single_amount >= 0),
+    daily_amount DECIMAL(15, 2) CHECK (daily_amount >= 0),
+    monthly_amount DECIMAL(15, 2) CHECK (monthly_amount >= 0),
+    hourly_count INT CHECK (hourly_count >= 0),
+    PRIMARY KEY (tier, currency)
+);
+
+CREATE TABLE IF NOT EXISTS user_transfer_limits (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    max_single_amount DECIMAL(15, 2) CHECK (max_single_amount >= 0),
+    daily_amount DECIMAL(15, 2) CHECK (daily_amount >= 0),
+    monthly_amount DECIMAL(15, 2) CHECK (monthly_amount >= 0),
+    hourly_count INT CHECK (hourly_count >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, currency)
+);
+
+INSERT INTO transfer_tiers (name, description)
+VALUES
+    ('standard', 'Default tier for new customers'),
+    ('premium', 'Verified customers with higher limits')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO transfer_limit_tiers (tier, currency, max_single_amount, daily_amount, monthly_amount, hourly_count)
+VALUES
+    ('standard', 'USD', 10000.00, 25000.00, 100000.00, 20),
+    ('standard', 'EUR', 10000.00, 25000.00, 100000.00, 20),
+    ('standard', 'GBP', 10000.00, 25000.00, 100000.00, 20),
+    ('standard', 'CHF', 10000.00, 25000.00, 100000.00, 20),
+    ('standard', 'JPY', 1500000, 3750000, 15000000, 20),
+    ('premium', 'USD', 100000.00, 250000.00, 1000000.00, 60),
+    ('premium', 'EUR', 100000.00, 250000.00, 1000000.00, 60),
+    ('premium', 'GBP', 100000.00, 250000.00, 1000000.00, 60),
+    ('premium', 'CHF', 100000.00, 250000.00, 1000000.00, 60),
+    ('premium', 'JPY', 15000000, 37500000, 150000000, 60)
+ON CONFLICT (tier, currency) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS transfer_tier VARCHAR(32) NOT NULL DEFAULT 'standard'
+    REFERENCES transfer_tiers(name);
+
+-- The usage query reads one user's entries of the current month.
+CREATE INDEX IF NOT EXISTS idx_ledger_account_id_created_at ON ledger(account_id, created_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_ledger_account_id_created_at;
+ALTER TABLE users DROP COLUMN IF EXISTS transfer_tier;
+DROP TABLE IF EXISTS user_transfer_limits;
+DROP TABLE IF EXISTS transfer_limit_tiers;
+DROP TABLE IF EXISTS transfer_tiers;
@@ -0,0 +1,32 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS risk_reviews (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('transfer', 'exchange')),
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    to_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    rule VARCHAR(64) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    request JSONB NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL DEFAULT '',
+
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'approved', 'rejected')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_note TEXT NOT NULL DEFAULT '',
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_reviews_idempotency
+    ON risk_reviews(user_id, type, idempotency_key) WHERE idempotency_key <> '';
+CREATE INDEX IF NOT EXISTS idx_risk_reviews_status ON risk_reviews(status, created_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_risk_reviews_status;
+DROP INDEX IF EXISTS idx_risk_reviews_idempotency;
+DROP TABLE IF EXISTS risk_reviews;
@@ -0,0 +1,24 @@
+-- +goose Up
+
+-- Authorized transfers are pending until captured (posted) or released/expired (voided); they have
+-- no ledger entries before they are posted. Everything booked so far is posted.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'posted' CHECK (status IN ('pending', 'posted', 'voided')),
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_pending_expiry_check CHECK (status <> 'pending' OR expires_at IS NOT NULL);
+
+-- Held amounts are summed per sending account on every account read; expiry sweeps scan by time.
+CREATE INDEX IF NOT EXISTS idx_transactions_pending_from ON transactions(from_account_id, expires_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_transactions_pending_expiry ON transactions(expires_at) WHERE status = 'pending';
+
+-- +goose Down
+
+DELETE FROM transactions WHERE status <> 'posted';
+
+DROP INDEX IF EXISTS idx_transactions_pending_expiry;
+DROP INDEX IF EXISTS idx_transactions_pending_from;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_pending_expiry_check;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,30 @@
+-- +goose Up
+
+-- A requester asks a payer for money. Accepting books a regular transfer from the payer to the
+-- requester's default account, referenced by transaction_id. Pending rows past expires_at are
+-- reported as expired and can no longer be accepted.
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id UUID PRIMARY KEY,
+    requester_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    amount DECIMAL(15, 2) NOT NULL CHECK (amount > 0),
+    memo VARCHAR(140) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'paid', 'declined', 'cancelled', 'expired')),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CHECK (requester_id <> payer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_requests_requester ON payment_requests(requester_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_payer ON payment_requests(payer_id, created_at DESC);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_payment_requests_payer;
+DROP INDEX IF EXISTS idx_payment_requests_requester;
+DROP TABLE IF EXISTS payment_requests;
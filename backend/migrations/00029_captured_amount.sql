@@ -0,0 +1,15 @@
+-- +goose Up
+
+-- A captured authorization keeps the authorized amount in amount; captured_amount is what was
+-- actually posted, which may be less. It is NULL for everything else.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS captured_amount DECIMAL(15,2);
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_captured_amount_check
+    CHECK (captured_amount IS NULL OR (status = 'posted' AND captured_amount > 0 AND captured_amount <= amount));
+
+-- +goose Down
+
+UPDATE transactions SET amount = captured_amount WHERE captured_amount IS NOT NULL;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_captured_amount_check;
+ALTER TABLE transactions DROP COLUMN IF EXISTS captured_amount;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- An accepted request whose transfer is held for review stays processing with the review it waits
+-- on; approving the review pays it, rejecting it returns it to pending and clears review_id.
+ALTER TABLE payment_requests
+    ADD COLUMN IF NOT EXISTS review_id UUID REFERENCES risk_reviews(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payment_requests_review ON payment_requests(review_id) WHERE review_id IS NOT NULL;
+
+-- +goose Down
+
+DROP INDEX IF EXISTS idx_payment_requests_review;
+ALTER TABLE payment_requests DROP COLUMN IF EXISTS review_id;
@@ -0,0 +1,8 @@
+-- +goose Up
+
+-- DECIMAL(10,4) rounds small cross rates such as JPY->GBP to zero; match exchange_rates.rate.
+ALTER TABLE transactions ALTER COLUMN exchange_rate TYPE NUMERIC(20, 10);
+
+-- +goose Down
+
+ALTER TABLE transactions ALTER COLUMN exchange_rate TYPE DECIMAL(10, 4);
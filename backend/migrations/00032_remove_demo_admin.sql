@@ -0,0 +1,12 @@
+-- +goose Up
+
+-- Earlier versions of 00016 seeded admin@test.com with the well-known password "password123". Admins
+-- are now created with cmd/createadmin; remove the demo one unless its password was changed.
+DELETE FROM users
+WHERE id = '99999999-9999-9999-9999-999999999999'
+  AND password = '$2a$10$MDqECt0NP5tsJXlvQo5.wubSUQV5I7GdLv7CBj/7szgLI4yVopkyi'
+  AND NOT EXISTS (SELECT 1 FROM scheduled_transfers WHERE to_user_id = '99999999-9999-9999-9999-999999999999');
+
+-- +goose Down
+
+-- The demo admin is not restored.
@@ -0,0 +1,154 @@
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// ErrPasswordMismatch is returned by PasswordHasher.Verify for a wrong password.
+var ErrPasswordMismatch = errors.New("password does not match")
+
+// Argon2Params are the argon2id cost parameters. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params is the second recommended option of RFC 9106: 64 MiB, 3 passes, 4 lanes.
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// PasswordHasher hashes new passwords with one algorithm and verifies hashes of either algorithm,
+// so existing bcrypt hashes keep working after switching to argon2id and are replaced on login.
+type PasswordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon      Argon2Params
+}
+
+func NewPasswordHasher(algorithm string, bcryptCost int, argon Argon2Params) (*PasswordHasher, error) {
+	switch algorithm {
+	case AlgorithmBcrypt:
+		if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case AlgorithmArgon2id:
+		if argon.Memory == 0 || argon.Iterations == 0 || argon.Parallelism == 0 || argon.SaltLength == 0 || argon.KeyLength == 0 {
+			return nil, errors.New("argon2id parameters must be positive")
+		}
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q", algorithm)
+	}
+	return &PasswordHasher{algorithm: algorithm, bcryptCost: bcryptCost, argon: argon}, nil
+}
+
+// Hash returns the encoded hash of password with the configured algorithm. argon2id hashes use the
+// PHC string format: $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>.
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.algorithm == AlgorithmBcrypt {
+		b, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("bcrypt: %w", err)
+		}
+		return string(b), nil
+	}
+
+	salt := make([]byte, h.argon.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	p := h.argon
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify returns nil if password matches encoded, ErrPasswordMismatch if it does not, and another
+// error if encoded cannot be parsed.
+func (h *PasswordHasher) Verify(encoded string, password string) error {
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrPasswordMismatch
+		}
+		return err
+	}
+
+	p, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return err
+	}
+	got := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(got, key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// NeedsRehash reports whether encoded was made with another algorithm or weaker parameters than the
+// configured ones. Call it after a successful Verify, when the plaintext is at hand.
+func (h *PasswordHasher) NeedsRehash(encoded string) bool {
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		if h.algorithm != AlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost < h.bcryptCost
+	}
+
+	if h.algorithm != AlgorithmArgon2id {
+		return true
+	}
+	p, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return p.Memory < h.argon.Memory || p.Iterations < h.argon.Iterations || p.Parallelism < h.argon.Parallelism ||
+		uint32(len(salt)) < h.argon.SaltLength || uint32(len(key)) < h.argon.KeyLength
+}
+
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, errors.New("malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errors.New("unsupported argon2id version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, fmt.Errorf("malformed argon2id parameters: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("malformed argon2id salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("malformed argon2id key: %w", err)
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
@@ -0,0 +1,87 @@
+package hash
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2Params keeps the tests fast; production uses DefaultArgon2Params.
+var testArgon2Params = Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func mustHasher(t *testing.T, algorithm string, cost int, argon Argon2Params) *PasswordHasher {
+	t.Helper()
+	h, err := NewPasswordHasher(algorithm, cost, argon)
+	if err != nil {
+		t.Fatalf("new hasher: %v", err)
+	}
+	return h
+}
+
+func TestPasswordHasherVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher *PasswordHasher
+	}{
+		{name: "bcrypt", hasher: mustHasher(t, AlgorithmBcrypt, bcrypt.MinCost, testArgon2Params)},
+		{name: "argon2id", hasher: mustHasher(t, AlgorithmArgon2id, bcrypt.MinCost, testArgon2Params)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("hash: %v", err)
+			}
+			if err := tt.hasher.Verify(encoded, "correct horse"); err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if err := tt.hasher.Verify(encoded, "wrong horse"); !errors.Is(err, ErrPasswordMismatch) {
+				t.Fatalf("wrong password err=%v want=%v", err, ErrPasswordMismatch)
+			}
+			if tt.hasher.NeedsRehash(encoded) {
+				t.Fatalf("fresh hash needs rehash")
+			}
+		})
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	cheapBcrypt := mustHasher(t, AlgorithmBcrypt, bcrypt.MinCost, testArgon2Params)
+	bcryptHash, err := cheapBcrypt.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	argonHash, err := mustHasher(t, AlgorithmArgon2id, bcrypt.MinCost, testArgon2Params).Hash("correct horse")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	stronger := testArgon2Params
+	stronger.Iterations++
+
+	tests := []struct {
+		name    string
+		hasher  *PasswordHasher
+		encoded string
+		want    bool
+	}{
+		{name: "bcrypt_same_cost", hasher: cheapBcrypt, encoded: bcryptHash, want: false},
+		{name: "bcrypt_lower_cost", hasher: mustHasher(t, AlgorithmBcrypt, bcrypt.MinCost+1, testArgon2Params), encoded: bcryptHash, want: true},
+		{name: "bcrypt_to_argon2id", hasher: mustHasher(t, AlgorithmArgon2id, bcrypt.MinCost, testArgon2Params), encoded: bcryptHash, want: true},
+		{name: "argon2id_weaker_params", hasher: mustHasher(t, AlgorithmArgon2id, bcrypt.MinCost, stronger), encoded: argonHash, want: true},
+		{name: "argon2id_to_bcrypt", hasher: cheapBcrypt, encoded: argonHash, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.encoded); got != tt.want {
+				t.Fatalf("needs rehash=%v want=%v", got, tt.want)
+			}
+			// Whatever the configured algorithm, both kinds of hash still verify.
+			if err := tt.hasher.Verify(tt.encoded, "correct horse"); err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors ("12345678901234567890").
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("code: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("t=%d got=%s want=%s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	prev, _ := Code(rfcSecret, Step(now)-1)
+	stale, _ := Code(rfcSecret, Step(now)-2)
+
+	if step, ok := Validate(rfcSecret, prev, now, 1); !ok || step != Step(now)-1 {
+		t.Fatalf("previous step: ok=%v step=%d", ok, step)
+	}
+	if _, ok := Validate(rfcSecret, stale, now, 1); ok {
+		t.Fatalf("code two steps old accepted")
+	}
+	if _, ok := Validate(rfcSecret, "12345", now, 1); ok {
+		t.Fatalf("short code accepted")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Mini Bank", "alice@test.com", "ABC")
+	if !strings.HasPrefix(uri, "otpauth://totp/Mini%20Bank:alice@test.com?") || !strings.Contains(uri, "secret=ABC") {
+		t.Fatalf("uri=%s", uri)
+	}
+}
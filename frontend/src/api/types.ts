@@ -1,11 +1,15 @@
 export type Currency = 'USD' | 'EUR'
-export type TransactionType = 'transfer' | 'exchange'
+export type TransactionType = 'transfer' | 'exchange' | 'reversal'
+
+export type Role = 'customer' | 'support' | 'admin' | 'auditor' | 'system'
 
 export type User = {
   id: string
   email: string
   first_name: string
   last_name: string
+  role: Role
+  email_verified: boolean
   created_at: string
   updated_at: string
 }
@@ -16,17 +20,117 @@ export type AuthResponse = {
   user: User
 }
 
+export type MFAChallengeResponse = {
+  mfa_required: true
+  challenge_token: string
+  expires_at: string
+}
+
+export type MFAStatus = {
+  enabled: boolean
+  enabled_at?: string
+  recovery_codes_remaining: number
+}
+
+export type MFAEnrollment = {
+  secret: string
+  otpauth_uri: string
+}
+
 export type TokenResponse = {
   access_token: string
   refresh_token: string
 }
 
+export type Session = {
+  id: string
+  device_label: string
+  user_agent: string
+  ip: string
+  created_at: string
+  last_used_at: string
+  expires_at: string
+  current: boolean
+}
+
+export type LoginLockout = {
+  email: string
+  user_id?: string
+  failures: number
+  last_failed_at: string
+  locked_until?: string
+}
+
+// Limits on outgoing transfers in one currency; null is unlimited.
+export type TransferLimits = {
+  currency: string
+  max_single_cents: number | null
+  daily_cents: number | null
+  monthly_cents: number | null
+  hourly_count: number | null
+}
+
+export type UserTransferLimits = {
+  user_id: string
+  tier: string
+  overrides: TransferLimits[]
+  limits: TransferLimits[]
+}
+
+// Body of a 422 from a transfer that would exceed a limit.
+export type TransferLimitError = {
+  error: string
+  limit: 'single' | 'daily' | 'monthly' | 'hourly_count'
+  currency: string
+  max: number
+  resets_at?: string
+}
+
+// Body of a 202 from a transfer or exchange held by a risk rule; nothing is booked yet.
+export type HeldForReview = {
+  status: 'held_for_review'
+  review_id: string
+}
+
+export type RiskReviewStatus = 'pending' | 'processing' | 'approved' | 'rejected'
+
+export type RiskReview = {
+  id: string
+  user_id: string
+  type: 'transfer' | 'exchange'
+  currency: string
+  to_currency?: string
+  amount_cents: number
+  to_user_id?: string
+  quote_id?: string
+  rule: string
+  reason: string
+  status: RiskReviewStatus
+  reviewer_id?: string
+  review_note?: string
+  transaction_id?: string
+  created_at: string
+  reviewed_at?: string
+}
+
+export type AccountStatus = 'active' | 'frozen' | 'closed'
+
 export type Account = {
   id: string
   currency: Currency
+  name: string
+  status: AccountStatus
+  is_default: boolean
   balance_cents: number
+  ledger_balance_cents: number
+  available_balance_cents: number
+  held_cents: number
+  created_at: string
+  closed_at?: string
 }
 
+export type TransactionStatus = 'pending' | 'posted' | 'voided'
+
 export type Transaction = {
   id: string
   type: TransactionType
@@ -36,12 +140,73 @@ export type Transaction = {
   currency: Currency
   exchange_rate?: number
   converted_amount_cents?: number
+  reverses_transaction_id?: string
   description: string
+  status: TransactionStatus
+  expires_at?: string
+  captured_amount_cents?: number
   created_at: string
   from_user_email?: string
   to_user_email?: string
 }
 
+export type TransactionDirection = 'incoming' | 'outgoing'
+
+export type TransactionPage = {
+  items: Transaction[]
+  next_cursor: string | null
+  total_count: number
+}
+
+export type ScheduleKind = 'once' | 'weekly' | 'monthly'
+
+export type ScheduledTransferStatus = 'active' | 'completed' | 'cancelled' | 'failed'
+
+export type ScheduledTransfer = {
+  id: string
+  from_account_id?: string
+  to_user_id?: string
+  to_account_id?: string
+  currency: Currency
+  amount_cents: number
+  description: string
+  kind: ScheduleKind
+  start_at: string
+  day_of_month?: number
+  end_at?: string
+  status: ScheduledTransferStatus
+  next_run_at?: string
+  attempts: number
+  last_error?: string
+  last_transaction_id?: string
+  created_at: string
+  updated_at: string
+}
+
+export type PaymentRequestStatus = 'pending' | 'processing' | 'paid' | 'declined' | 'cancelled' | 'expired'
+
+export type PaymentRequest = {
+  id: string
+  requester_id: string
+  requester_email: string
+  payer_id: string
+  payer_email: string
+  currency: Currency
+  amount_cents: number
+  memo: string
+  status: PaymentRequestStatus
+  transaction_id?: string
+  review_id?: string
+  expires_at: string
+  created_at: string
+  updated_at: string
+}
+
+export type PaymentRequestAcceptResult = {
+  payment_request: PaymentRequest
+  transaction: Transaction
+}
+
 export type ApiError =
   | { error: string }
   | { error: string; fields: Array<{ field: string; message: string }> }
@@ -1,7 +1,7 @@
 import { useEffect, useMemo, useState } from 'react'
 import { apiFetch, HttpError } from '../api/client'
 import { useAuth } from '../auth/AuthContext'
-import type { Account, Transaction } from '../api/types'
+import type { Account, Transaction, TransactionPage } from '../api/types'
 import { centsToDecimal } from '../lib/money'
 import { Card, ErrorBox, Grid2, Section, Subtitle, Title } from '../ui/ui'
 
@@ -13,7 +13,7 @@ export function DashboardPage() {
 
   const byCurrency = useMemo(() => {
     const map: Record<string, Account | undefined> = {}
-    for (const a of accounts ?? []) map[a.currency] = a
+    for (const a of accounts ?? []) if (a.is_default) map[a.currency] = a
     return map
   }, [accounts])
 
@@ -25,11 +25,11 @@ export function DashboardPage() {
       try {
         const [a, t] = await Promise.all([
           apiFetch<Account[]>('/accounts', { token }),
-          apiFetch<Transaction[]>('/transactions', { token, query: { page: 1, limit: 5 } }),
+          apiFetch<TransactionPage>('/transactions', { token, query: { limit: 5 } }),
         ])
         if (cancelled) return
         setAccounts(a)
-        setTxs(t)
+        setTxs(t.items)
       } catch (e: any) {
         if (cancelled) return
         setError(e instanceof HttpError ? e.message : 'load_failed')
@@ -92,7 +92,7 @@ export function DashboardPage() {
                 <td style={{ padding: '0.5rem 0.75rem' }}>{new Date(t.created_at).toLocaleString()}</td>
                 <td style={{ padding: '0.5rem 0.75rem' }}>{t.type}</td>
                 <td style={{ padding: '0.5rem 0.75rem' }}>
-                  {t.currency} {centsToDecimal(t.amount_cents)}
+                  {t.currency} {centsToDecimal(t.captured_amount_cents ?? t.amount_cents)}
                   {t.converted_amount_cents != null ? ` → ${centsToDecimal(t.converted_amount_cents)}` : ''}
                 </td>
                 <td style={{ padding: '0.5rem 0.75rem', color: 'rgb(71 85 105)' }}>{t.description}</td>
@@ -1,14 +1,18 @@
 import { useEffect, useState } from 'react'
 import { apiFetch, HttpError } from '../api/client'
 import { useAuth } from '../auth/AuthContext'
-import type { Transaction, TransactionType } from '../api/types'
+import type { Transaction, TransactionDirection, TransactionPage, TransactionType } from '../api/types'
 import { centsToDecimal } from '../lib/money'
 
 export function TransactionsPage() {
   const { token } = useAuth()
   const [type, setType] = useState<TransactionType | ''>('')
-  const [page, setPage] = useState(1)
+  const [direction, setDirection] = useState<TransactionDirection | ''>('')
   const [limit, setLimit] = useState(20)
+  // cursors[i] is the cursor that loads page i; the first page has none.
+  const [cursors, setCursors] = useState<string[]>([''])
+  const [nextCursor, setNextCursor] = useState<string | null>(null)
+  const [totalCount, setTotalCount] = useState(0)
   const [items, setItems] = useState<Transaction[]>([])
   const [loading, setLoading] = useState(false)
   const [error, setError] = useState<string | null>(null)
@@ -20,9 +24,12 @@ export function TransactionsPage() {
       setLoading(true)
       setError(null)
       try {
-        const t = await apiFetch<Transaction[]>('/transactions', { token, query: { type, page, limit } })
+        const cursor = cursors[cursors.length - 1]
+        const t = await apiFetch<TransactionPage>('/transactions', { token, query: { type, direction, limit, cursor } })
         if (cancelled) return
-        setItems(t)
+        setItems(t.items)
+        setNextCursor(t.next_cursor)
+        setTotalCount(t.total_count)
       } catch (e: any) {
         if (cancelled) return
         setError(e instanceof HttpError ? e.message : 'load_failed')
@@ -34,7 +41,7 @@ export function TransactionsPage() {
     return () => {
       cancelled = true
     }
-  }, [token, type, page, limit])
+  }, [token, type, direction, limit, cursors])
 
   return (
     <div className="space-y-4">
@@ -51,7 +58,7 @@ export function TransactionsPage() {
               className="mt-1 rounded border px-3 py-2 text-sm"
               value={type}
               onChange={(e) => {
-                setPage(1)
+                setCursors([''])
                 setType(e.target.value as any)
               }}
             >
@@ -60,19 +67,41 @@ export function TransactionsPage() {
               <option value="exchange">exchange</option>
             </select>
           </label>
+          <label className="block">
+            <div className="text-xs font-medium text-slate-600">Direction</div>
+            <select
+              className="mt-1 rounded border px-3 py-2 text-sm"
+              value={direction}
+              onChange={(e) => {
+                setCursors([''])
+                setDirection(e.target.value as any)
+              }}
+            >
+              <option value="">All</option>
+              <option value="incoming">incoming</option>
+              <option value="outgoing">outgoing</option>
+            </select>
+          </label>
           <label className="block">
             <div className="text-xs font-medium text-slate-600">Limit</div>
-            <select className="mt-1 rounded border px-3 py-2 text-sm" value={limit} onChange={(e) => setLimit(Number(e.target.value))}>
+            <select
+              className="mt-1 rounded border px-3 py-2 text-sm"
+              value={limit}
+              onChange={(e) => {
+                setCursors([''])
+                setLimit(Number(e.target.value))
+              }}
+            >
               <option value={10}>10</option>
               <option value={20}>20</option>
               <option value={50}>50</option>
             </select>
           </label>
           <div className="flex items-end gap-2">
-            <button className="rounded border px-3 py-2 text-sm disabled:opacity-50" disabled={page <= 1 || loading} onClick={() => setPage((p) => p - 1)}>
+            <button className="rounded border px-3 py-2 text-sm disabled:opacity-50" disabled={cursors.length <= 1 || loading} onClick={() => setCursors((c) => c.slice(0, -1))}>
               Prev
             </button>
-            <button className="rounded border px-3 py-2 text-sm disabled:opacity-50" disabled={loading || items.length < limit} onClick={() => setPage((p) => p + 1)}>
+            <button className="rounded border px-3 py-2 text-sm disabled:opacity-50" disabled={loading || !nextCursor} onClick={() => nextCursor && setCursors((c) => [...c, nextCursor])}>
               Next
             </button>
           </div>
@@ -97,7 +126,7 @@ export function TransactionsPage() {
                 <td className="px-3 py-2">{new Date(t.created_at).toLocaleString()}</td>
                 <td className="px-3 py-2">{t.type}</td>
                 <td className="px-3 py-2">
-                  {t.currency} {centsToDecimal(t.amount_cents)}
+                  {t.currency} {centsToDecimal(t.captured_amount_cents ?? t.amount_cents)}
                   {t.converted_amount_cents != null ? ` → ${centsToDecimal(t.converted_amount_cents)}` : ''}
                 </td>
                 <td className="px-3 py-2 text-slate-600">{t.description}</td>
@@ -121,7 +150,9 @@ export function TransactionsPage() {
         </table>
       </div>
 
-      <div className="text-sm text-slate-600">Page: {page}</div>
+      <div className="text-sm text-slate-600">
+        Page: {cursors.length} · {totalCount} matching transactions
+      </div>
     </div>
   )
 }